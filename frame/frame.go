@@ -0,0 +1,227 @@
+// The frame package pairs a matrix with column names and an index column of
+// row keys, for working with tabular data such as CSV files. Frames can be
+// narrowed to some columns, filtered by row, and joined on their index, and
+// then converted to a labeled matrix for linear algebra.
+package frame
+
+import (
+	"encoding/csv"
+	"fmt"
+	"github.com/jesand/numgo/matrix"
+	"io"
+	"math"
+	"strconv"
+)
+
+// A Frame is a matrix whose columns have names and whose rows have keys,
+// which are stored in the index. Frames are immutable: operations return new
+// frames.
+type Frame struct {
+	index   []string
+	columns []string
+	data    matrix.Matrix
+	rowPos  map[string]int
+	colPos  map[string]int
+}
+
+// Create a frame from a matrix, its row keys and its column names. A nil
+// index numbers the rows from "0".
+func New(data matrix.Matrix, index, columns []string) (*Frame, error) {
+	if index == nil {
+		index = make([]string, data.Rows())
+		for i := range index {
+			index[i] = strconv.Itoa(i)
+		}
+	}
+	if len(index) != data.Rows() {
+		return nil, matrix.ErrShapeMismatch{Op: "frame.New", Got: []int{len(index)}, Want: []int{data.Rows()}}
+	}
+	if len(columns) != data.Cols() {
+		return nil, matrix.ErrShapeMismatch{Op: "frame.New", Got: []int{len(columns)}, Want: []int{data.Cols()}}
+	}
+	f := &Frame{
+		index:   index,
+		columns: columns,
+		data:    data,
+		rowPos:  make(map[string]int, len(index)),
+		colPos:  make(map[string]int, len(columns)),
+	}
+	for i := len(index) - 1; i >= 0; i-- {
+		f.rowPos[index[i]] = i
+	}
+	for i, name := range columns {
+		if _, ok := f.colPos[name]; ok {
+			return nil, fmt.Errorf("Duplicate column name %q", name)
+		}
+		f.colPos[name] = i
+	}
+	return f, nil
+}
+
+// Read a frame from CSV data with a header row. The column named indexCol
+// holds the row keys; if indexCol is empty, the rows are numbered from "0".
+// All other columns must be numeric, and empty cells are read as NaN.
+func ReadCSV(r io.Reader, indexCol string) (*Frame, error) {
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("CSV data has no header row")
+	}
+	header := records[0]
+	indexPos := -1
+	var columns []string
+	for i, name := range header {
+		if name == indexCol && indexCol != "" {
+			indexPos = i
+		} else {
+			columns = append(columns, name)
+		}
+	}
+	if indexCol != "" && indexPos < 0 {
+		return nil, matrix.ErrUnknownLabel{Axis: "column", Label: indexCol}
+	}
+
+	var (
+		index  []string
+		values = make([]float64, 0, (len(records)-1)*len(columns))
+	)
+	for line, record := range records[1:] {
+		for i, cell := range record {
+			if i == indexPos {
+				index = append(index, cell)
+				continue
+			}
+			if cell == "" {
+				values = append(values, math.NaN())
+				continue
+			}
+			v, err := strconv.ParseFloat(cell, 64)
+			if err != nil {
+				return nil, fmt.Errorf("CSV line %d, column %q: %v", line+2, header[i], err)
+			}
+			values = append(values, v)
+		}
+	}
+	return New(matrix.M(len(records)-1, len(columns), values...), index, columns)
+}
+
+// Write the frame as CSV data with a header row. The index is written as the
+// first column, under the name indexCol.
+func (f *Frame) WriteCSV(w io.Writer, indexCol string) error {
+	out := csv.NewWriter(w)
+	if err := out.Write(append([]string{indexCol}, f.columns...)); err != nil {
+		return err
+	}
+	record := make([]string, 1+len(f.columns))
+	for row, key := range f.index {
+		record[0] = key
+		for col := range f.columns {
+			record[1+col] = strconv.FormatFloat(f.data.Item(row, col), 'g', -1, 64)
+		}
+		if err := out.Write(record); err != nil {
+			return err
+		}
+	}
+	out.Flush()
+	return out.Error()
+}
+
+// Get the row keys
+func (f *Frame) Index() []string {
+	return f.index
+}
+
+// Get the column names
+func (f *Frame) Columns() []string {
+	return f.columns
+}
+
+// Get the number of rows
+func (f *Frame) Rows() int {
+	return len(f.index)
+}
+
+// Get the data as a matrix, labeled with the row keys and column names
+func (f *Frame) Matrix() *matrix.LabeledMatrix {
+	return matrix.WithLabels(f.data, f.index, f.columns)
+}
+
+// Get the values of a column
+func (f *Frame) Col(name string) ([]float64, error) {
+	col, ok := f.colPos[name]
+	if !ok {
+		return nil, matrix.ErrUnknownLabel{Axis: "column", Label: name}
+	}
+	return f.data.Col(col), nil
+}
+
+// Get the values of the first row with the given key
+func (f *Frame) Loc(key string) ([]float64, error) {
+	row, ok := f.rowPos[key]
+	if !ok {
+		return nil, matrix.ErrUnknownLabel{Axis: "row", Label: key}
+	}
+	return f.data.Row(row), nil
+}
+
+// Get a frame with only the named columns, in the order given
+func (f *Frame) Select(columns ...string) (*Frame, error) {
+	positions := make([]int, len(columns))
+	for i, name := range columns {
+		col, ok := f.colPos[name]
+		if !ok {
+			return nil, matrix.ErrUnknownLabel{Axis: "column", Label: name}
+		}
+		positions[i] = col
+	}
+	values := make([]float64, 0, f.Rows()*len(columns))
+	for row := range f.index {
+		for _, col := range positions {
+			values = append(values, f.data.Item(row, col))
+		}
+	}
+	return New(matrix.M(f.Rows(), len(columns), values...), f.index, columns)
+}
+
+// Get a frame with only the rows for which keep returns true. The function is
+// given each row's key and values.
+func (f *Frame) Filter(keep func(key string, row []float64) bool) *Frame {
+	var (
+		index  = []string{}
+		values []float64
+	)
+	for row, key := range f.index {
+		if r := f.data.Row(row); keep(key, r) {
+			index = append(index, key)
+			values = append(values, r...)
+		}
+	}
+	// The columns are already known to be valid, so this can't fail
+	result, _ := New(matrix.M(len(index), len(f.columns), values...), index, f.columns)
+	return result
+}
+
+// Get the inner join of two frames on their indexes. The result has a row for
+// each pair of rows with the same key, in the order of f, and has the columns
+// of f followed by the columns of other. The frames can't share column names.
+func (f *Frame) Join(other *Frame) (*Frame, error) {
+	columns := append(append([]string{}, f.columns...), other.columns...)
+	matches := make(map[string][]int)
+	for row, key := range other.index {
+		matches[key] = append(matches[key], row)
+	}
+	var (
+		index  = []string{}
+		values []float64
+	)
+	for row, key := range f.index {
+		for _, row2 := range matches[key] {
+			index = append(index, key)
+			values = append(values, f.data.Row(row)...)
+			values = append(values, other.data.Row(row2)...)
+		}
+	}
+	return New(matrix.M(len(index), len(columns), values...), index, columns)
+}
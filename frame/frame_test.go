@@ -0,0 +1,109 @@
+package frame
+
+import (
+	"bytes"
+	"errors"
+	"github.com/jesand/numgo/matrix"
+	. "github.com/smartystreets/goconvey/convey"
+	"math"
+	"strings"
+	"testing"
+)
+
+const prices = `id,open,close,volume
+aapl,1.5,2,100
+goog,3,2.5,
+msft,4,4.5,300
+`
+
+const sectors = `sector,id
+1,msft
+2,aapl
+3,aapl
+`
+
+func TestFrame(t *testing.T) {
+	Convey("Given a frame read from CSV", t, func() {
+		f, err := ReadCSV(strings.NewReader(prices), "id")
+		So(err, ShouldBeNil)
+
+		Convey("The index, columns and values are read", func() {
+			So(f.Index(), ShouldResemble, []string{"aapl", "goog", "msft"})
+			So(f.Columns(), ShouldResemble, []string{"open", "close", "volume"})
+			row, err := f.Loc("msft")
+			So(err, ShouldBeNil)
+			So(row, ShouldResemble, []float64{4, 4.5, 300})
+			col, err := f.Col("close")
+			So(err, ShouldBeNil)
+			So(col, ShouldResemble, []float64{2, 2.5, 4.5})
+			vol, _ := f.Col("volume")
+			So(math.IsNaN(vol[1]), ShouldBeTrue)
+		})
+
+		Convey("Unknown names give ErrUnknownLabel", func() {
+			var labelErr matrix.ErrUnknownLabel
+			_, err := f.Col("high")
+			So(errors.As(err, &labelErr), ShouldBeTrue)
+			_, err = f.Loc("ibm")
+			So(errors.As(err, &labelErr), ShouldBeTrue)
+			So(labelErr.Axis, ShouldEqual, "row")
+			_, err = f.Select("open", "high")
+			So(errors.As(err, &labelErr), ShouldBeTrue)
+		})
+
+		Convey("Select picks and reorders columns", func() {
+			s, err := f.Select("close", "open")
+			So(err, ShouldBeNil)
+			So(s.Columns(), ShouldResemble, []string{"close", "open"})
+			So(s.Matrix().Equal(matrix.M(3, 2,
+				2, 1.5,
+				2.5, 3,
+				4.5, 4)), ShouldBeTrue)
+		})
+
+		Convey("Filter keeps matching rows", func() {
+			s := f.Filter(func(key string, row []float64) bool {
+				return row[1] > row[0]
+			})
+			So(s.Index(), ShouldResemble, []string{"aapl", "msft"})
+			So(f.Filter(func(string, []float64) bool { return false }).Rows(), ShouldEqual, 0)
+		})
+
+		Convey("Join combines rows with the same key", func() {
+			g, err := ReadCSV(strings.NewReader(sectors), "id")
+			So(err, ShouldBeNil)
+			j, err := f.Join(g)
+			So(err, ShouldBeNil)
+			So(j.Index(), ShouldResemble, []string{"aapl", "aapl", "msft"})
+			So(j.Columns(), ShouldResemble, []string{"open", "close", "volume", "sector"})
+			m := j.Matrix()
+			So(m.Col(3), ShouldResemble, []float64{2, 3, 1})
+			So(m.RowByName("msft"), ShouldResemble, []float64{4, 4.5, 300, 1})
+
+			_, err = f.Join(f)
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("The frame can be written back as CSV", func() {
+			var buf bytes.Buffer
+			So(f.WriteCSV(&buf, "id"), ShouldBeNil)
+			So(buf.String(), ShouldEqual, strings.Replace(prices, "2.5,\n", "2.5,NaN\n", 1))
+		})
+	})
+
+	Convey("Given CSV data without an index column", t, func() {
+		f, err := ReadCSV(strings.NewReader("a,b\n1,2\n3,4\n"), "")
+		So(err, ShouldBeNil)
+		So(f.Index(), ShouldResemble, []string{"0", "1"})
+		So(f.Matrix().ColByName("b"), ShouldResemble, []float64{2, 4})
+	})
+
+	Convey("Given invalid CSV data", t, func() {
+		_, err := ReadCSV(strings.NewReader("a,b\n1,x\n"), "")
+		So(err, ShouldNotBeNil)
+		_, err = ReadCSV(strings.NewReader("a,b\n1,2\n"), "id")
+		So(err, ShouldNotBeNil)
+		_, err = ReadCSV(strings.NewReader(""), "")
+		So(err, ShouldNotBeNil)
+	})
+}
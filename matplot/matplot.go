@@ -0,0 +1,134 @@
+// The matplot package draws matrices using gonum/plot, for visually debugging
+// intermediate results. Heatmaps and spy plots are drawn in matrix
+// orientation, with row 0 at the top.
+package matplot
+
+import (
+	"fmt"
+	"github.com/jesand/numgo/matrix"
+	"gonum.org/v1/plot"
+	"gonum.org/v1/plot/palette"
+	"gonum.org/v1/plot/plotter"
+	"gonum.org/v1/plot/plotutil"
+	"gonum.org/v1/plot/vg"
+	"gonum.org/v1/plot/vg/draw"
+)
+
+// The number of colors used for heatmaps
+const HeatmapColors = 64
+
+// Create a heatmap of the values of m. Each element is drawn as a cell
+// centered on its (column, row) coordinates.
+func Heatmap(m matrix.Matrix) *plot.Plot {
+	p := matrixPlot(m)
+	p.Add(plotter.NewHeatMap(grid{m}, palette.Heat(HeatmapColors, 1)))
+	return p
+}
+
+// Create a spy plot of the sparsity pattern of m, with a point drawn for
+// each nonzero element.
+func Spy(m matrix.Matrix) (*plot.Plot, error) {
+	p := matrixPlot(m)
+	xys := nonzeroXYs(m)
+	if len(xys) == 0 {
+		return p, nil
+	}
+	points, err := plotter.NewScatter(xys)
+	if err != nil {
+		return nil, err
+	}
+	points.GlyphStyle.Shape = draw.BoxGlyph{}
+	points.GlyphStyle.Radius = vg.Points(1)
+	p.Add(points)
+	return p, nil
+}
+
+// Create a line plot of the selected rows of m, with column indices on the
+// X axis. If no rows are given, every row is plotted.
+func RowLines(m matrix.Matrix, rows ...int) (*plot.Plot, error) {
+	if len(rows) == 0 {
+		rows = make([]int, m.Rows())
+		for row := range rows {
+			rows[row] = row
+		}
+	}
+	p := plot.New()
+	p.X.Label.Text = "Column"
+	for idx, row := range rows {
+		if row < 0 || row >= m.Rows() {
+			panic(fmt.Sprintf("Can't plot row %d of a %dx%d matrix", row, m.Rows(), m.Cols()))
+		}
+		line, err := plotter.NewLine(rowXYs(m, row))
+		if err != nil {
+			return nil, err
+		}
+		line.LineStyle.Color = plotutil.Color(idx)
+		p.Add(line)
+		p.Legend.Add(fmt.Sprintf("Row %d", row), line)
+	}
+	return p, nil
+}
+
+// Save a plot to a file, with the format chosen by the file extension (for
+// example .png, .svg or .pdf). The size is given in inches.
+func Save(p *plot.Plot, width, height float64, path string) error {
+	return p.Save(vg.Length(width)*vg.Inch, vg.Length(height)*vg.Inch, path)
+}
+
+// Create a plot whose axes span the cells of m, with row 0 at the top
+func matrixPlot(m matrix.Matrix) *plot.Plot {
+	p := plot.New()
+	p.X.Label.Text = "Column"
+	p.X.Min, p.X.Max = -0.5, float64(m.Cols())-0.5
+	p.Y.Label.Text = "Row"
+	p.Y.Min, p.Y.Max = -0.5, float64(m.Rows())-0.5
+	p.Y.Scale = plot.InvertedScale{Normalizer: p.Y.Scale}
+	return p
+}
+
+// Get the (column, row) coordinates of the nonzero elements of m
+func nonzeroXYs(m matrix.Matrix) plotter.XYs {
+	var xys plotter.XYs
+	m.VisitNonzero(func(pos []int, value float64) bool {
+		if value != 0 {
+			xys = append(xys, plotter.XY{X: float64(pos[1]), Y: float64(pos[0])})
+		}
+		return true
+	})
+	return xys
+}
+
+// Get the (column, value) points of a row of m
+func rowXYs(m matrix.Matrix, row int) plotter.XYs {
+	xys := make(plotter.XYs, m.Cols())
+	for col := range xys {
+		xys[col] = plotter.XY{X: float64(col), Y: m.Item(row, col)}
+	}
+	return xys
+}
+
+// Adapts a matrix to plotter.GridXYZ, with columns on the X axis and rows on
+// the Y axis
+type grid struct {
+	m matrix.Matrix
+}
+
+// Get the number of columns and rows
+func (g grid) Dims() (c, r int) {
+	return g.m.Cols(), g.m.Rows()
+}
+
+// Get the value of the element at column c, row r
+func (g grid) Z(c, r int) float64 {
+	return g.m.Item(r, c)
+}
+
+// Get the X coordinate of column c
+func (g grid) X(c int) float64 {
+	return float64(c)
+}
+
+// Get the Y coordinate of row r
+func (g grid) Y(r int) float64 {
+	return float64(r)
+}
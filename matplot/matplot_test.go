@@ -0,0 +1,52 @@
+package matplot
+
+import (
+	"github.com/jesand/numgo/matrix"
+	. "github.com/smartystreets/goconvey/convey"
+	"gonum.org/v1/plot/plotter"
+	"sort"
+	"testing"
+)
+
+func TestMatplot(t *testing.T) {
+	Convey("Given a sparse matrix", t, func() {
+		m := matrix.SparseCoo(2, 3,
+			0, 2, 0,
+			3, 0, 4)
+
+		Convey("The heatmap grid matches the matrix", func() {
+			g := grid{m}
+			c, r := g.Dims()
+			So(c, ShouldEqual, 3)
+			So(r, ShouldEqual, 2)
+			So(g.Z(1, 0), ShouldEqual, 2)
+			So(g.Z(2, 1), ShouldEqual, 4)
+			So(g.X(2), ShouldEqual, 2)
+			So(g.Y(1), ShouldEqual, 1)
+			So(Heatmap(m), ShouldNotBeNil)
+		})
+
+		Convey("The spy plot marks nonzero elements", func() {
+			xys := nonzeroXYs(m)
+			sort.Slice(xys, func(i, j int) bool { return xys[i].X < xys[j].X })
+			So(xys, ShouldResemble, plotter.XYs{{X: 0, Y: 1}, {X: 1, Y: 0}, {X: 2, Y: 1}})
+
+			p, err := Spy(m)
+			So(err, ShouldBeNil)
+			So(p.Y.Max, ShouldEqual, 1.5)
+
+			p, err = Spy(matrix.SparseCoo(2, 2))
+			So(err, ShouldBeNil)
+			So(p, ShouldNotBeNil)
+		})
+
+		Convey("Row lines plot row values by column", func() {
+			So(rowXYs(m, 1), ShouldResemble, plotter.XYs{{X: 0, Y: 3}, {X: 1, Y: 0}, {X: 2, Y: 4}})
+
+			p, err := RowLines(m)
+			So(err, ShouldBeNil)
+			So(p, ShouldNotBeNil)
+			So(func() { RowLines(m, 2) }, ShouldPanic)
+		})
+	})
+}
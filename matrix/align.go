@@ -0,0 +1,93 @@
+package matrix
+
+import (
+	"fmt"
+)
+
+// How AlignRows() chooses the row labels of its results
+type JoinKind int
+
+const (
+	// Keep the labels found in both matrices, in the order of the first
+	InnerJoin JoinKind = iota
+
+	// Keep the labels found in either matrix: those of the first, followed by
+	// the new labels of the second
+	OuterJoin
+
+	// Keep the labels of the first matrix
+	LeftJoin
+
+	// Keep the labels of the second matrix
+	RightJoin
+)
+
+// Reindex the rows of two row-labeled matrices to a shared set of labels, so
+// that row i of each result has the same label. The labels are chosen by how.
+// Rows whose label is missing from a matrix are filled with fill, which is
+// usually math.NaN() or zero. Each label appears once in the results; a
+// matrix with repeated labels contributes the first row with each label.
+//
+// The results are new matrices which keep the column labels of a and b. They
+// are dense if the input is dense or fill is nonzero, and sparse coo matrices
+// otherwise. Panics if either matrix has no row labels.
+func AlignRows(a, b Matrix, how JoinKind, fill float64) (*LabeledMatrix, *LabeledMatrix) {
+	debugCheck("AlignRows", a, b)
+	aLabels, bLabels := RowLabels(a), RowLabels(b)
+	if aLabels == nil || bLabels == nil {
+		panic("AlignRows: both matrices need row labels")
+	}
+	bIndex := labelIndex(bLabels)
+	var labels []string
+	switch how {
+	case InnerJoin:
+		for _, label := range distinctLabels(aLabels) {
+			if _, ok := bIndex[label]; ok {
+				labels = append(labels, label)
+			}
+		}
+	case OuterJoin:
+		labels = distinctLabels(append(append([]string(nil), aLabels...), bLabels...))
+	case LeftJoin:
+		labels = distinctLabels(aLabels)
+	case RightJoin:
+		labels = distinctLabels(bLabels)
+	default:
+		panic(fmt.Sprintf("Unknown join kind %d", how))
+	}
+	return reindexRows(a, labels, fill), reindexRows(b, labels, fill)
+}
+
+// Get the labels without repeats, in the order they first appear
+func distinctLabels(labels []string) []string {
+	seen := make(map[string]bool, len(labels))
+	result := make([]string, 0, len(labels))
+	for _, label := range labels {
+		if !seen[label] {
+			seen[label] = true
+			result = append(result, label)
+		}
+	}
+	return result
+}
+
+// Get a copy of a row-labeled matrix whose rows have the given labels, taken
+// from the first row of m with each label or filled with fill
+func reindexRows(m Matrix, labels []string, fill float64) *LabeledMatrix {
+	index := labelIndex(RowLabels(m))
+	var result Matrix
+	if m.Sparsity() == DenseArray || fill != 0 {
+		result = Dense(len(labels), m.Cols()).M()
+		if fill != 0 {
+			result.Fill(fill)
+		}
+	} else {
+		result = SparseCoo(len(labels), m.Cols())
+	}
+	for i, label := range labels {
+		if row, ok := index[label]; ok {
+			result.RowSet(i, m.Row(row))
+		}
+	}
+	return WithLabels(result, labels, ColLabels(m))
+}
@@ -0,0 +1,61 @@
+package matrix
+
+import (
+	. "github.com/smartystreets/goconvey/convey"
+	"math"
+	"testing"
+)
+
+func TestAlignRows(t *testing.T) {
+	Convey("Given two matrices with overlapping row labels", t, func() {
+		a := WithLabels(M(3, 2,
+			1, 2,
+			3, 4,
+			5, 6), []string{"x", "y", "z"}, []string{"p", "q"})
+		b := WithLabels(M(3, 1,
+			10,
+			20,
+			30), []string{"w", "z", "x"}, nil)
+
+		Convey("An inner join keeps the shared labels in the order of a", func() {
+			ra, rb := AlignRows(a, b, InnerJoin, math.NaN())
+			So(ra.RowLabels(), ShouldResemble, []string{"x", "z"})
+			So(rb.RowLabels(), ShouldResemble, []string{"x", "z"})
+			So(ra.Array(), ShouldResemble, []float64{1, 2, 5, 6})
+			So(rb.Array(), ShouldResemble, []float64{30, 20})
+			So(ra.ColLabels(), ShouldResemble, []string{"p", "q"})
+			So(rb.ColLabels(), ShouldBeNil)
+		})
+
+		Convey("An outer join fills missing rows", func() {
+			ra, rb := AlignRows(a, b, OuterJoin, math.NaN())
+			So(ra.RowLabels(), ShouldResemble, []string{"x", "y", "z", "w"})
+			So(ra.Row(2), ShouldResemble, []float64{5, 6})
+			So(math.IsNaN(ra.Item(3, 0)), ShouldBeTrue)
+			So(math.IsNaN(rb.Item(1, 0)), ShouldBeTrue)
+			So(rb.Item(3, 0), ShouldEqual, 10)
+		})
+
+		Convey("Left and right joins keep the labels of one matrix", func() {
+			ra, rb := AlignRows(a, b, LeftJoin, 0)
+			So(rb.RowLabels(), ShouldResemble, []string{"x", "y", "z"})
+			So(rb.Array(), ShouldResemble, []float64{30, 0, 20})
+			So(ra.Add(rb.MProd(M(1, 2, 1, 1))).Array(), ShouldResemble, []float64{31, 32, 3, 4, 25, 26})
+
+			ra, _ = AlignRows(a, b, RightJoin, -1)
+			So(ra.RowLabels(), ShouldResemble, []string{"w", "z", "x"})
+			So(ra.Array(), ShouldResemble, []float64{-1, -1, 5, 6, 1, 2})
+		})
+
+		Convey("Sparse matrices filled with zeros stay sparse", func() {
+			sa := WithLabels(a.Matrix.SparseCoo(), a.RowLabels(), nil)
+			ra, _ := AlignRows(sa, b, OuterJoin, 0)
+			So(ra.Matrix.Sparsity(), ShouldEqual, SparseCooMatrix)
+			So(ra.Row(3), ShouldResemble, []float64{0, 0})
+		})
+
+		Convey("Unlabeled matrices panic", func() {
+			So(func() { AlignRows(a, M(1, 1, 1), OuterJoin, 0) }, ShouldPanic)
+		})
+	})
+}
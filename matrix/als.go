@@ -0,0 +1,216 @@
+package matrix
+
+import (
+	"fmt"
+	"gonum.org/v1/gonum/mat"
+	"math"
+	"math/rand"
+)
+
+// An ALSOption configures ALS()
+type ALSOption func(*alsOptions)
+
+// The configuration built up by a list of options
+type alsOptions struct {
+	lambda  float64
+	maxIter int
+	tol     float64
+	rng     *rand.Rand
+}
+
+// Penalize the squared size of the factors by lambda, scaled by the number of
+// observations in each row or column; 0.1 by default. Must not be negative.
+func ALSLambda(lambda float64) ALSOption {
+	return func(o *alsOptions) { o.lambda = lambda }
+}
+
+// Stop after at most n sweeps over the rows and columns; 20 by default
+func ALSMaxIter(n int) ALSOption {
+	return func(o *alsOptions) { o.maxIter = n }
+}
+
+// Stop once a sweep improves the root mean squared error on the observed
+// entries by no more than tol; 1e-6 by default
+func ALSTol(tol float64) ALSOption {
+	return func(o *alsOptions) { o.tol = tol }
+}
+
+// Use src as the source of random numbers for the initial factors, in place of
+// the global source. Seed it to get reproducible factors.
+func ALSRand(src rand.Source) ALSOption {
+	return func(o *alsOptions) { o.rng = rand.New(src) }
+}
+
+// A low-rank approximation U * V' of a partially observed matrix, found by
+// ALS()
+type Factorization struct {
+	// A rows x rank matrix with a factor vector for each row
+	U Matrix
+
+	// A cols x rank matrix with a factor vector for each column
+	V Matrix
+
+	// The root mean squared error of the approximation on the observed entries
+	RMSE float64
+}
+
+// Predict the value at (row, col)
+func (f *Factorization) Predict(row, col int) float64 {
+	if row < 0 || row >= f.U.Rows() || col < 0 || col >= f.V.Rows() {
+		panic(ErrIndexOutOfRange{Op: "Predict", Index: []int{row, col}, Shape: []int{f.U.Rows(), f.V.Rows()}})
+	}
+	var sum float64
+	for k := 0; k < f.U.Cols(); k++ {
+		sum += f.U.Item(row, k) * f.V.Item(col, k)
+	}
+	return sum
+}
+
+// Get the dense matrix of predictions for every entry, U * V'
+func (f *Factorization) Reconstruct() Matrix {
+	return f.U.MProd(f.V.T())
+}
+
+// Factor a partially observed matrix into rank-dimensional row and column
+// factors by alternating least squares. The stored (nonzero) entries of m are
+// the observations and its zeros are missing values, so m is typically a
+// sparse coo matrix, such as the ratings users have given items. Each sweep
+// fixes V and solves a regularized least squares problem for each row of U,
+// then does the same for V with U fixed. Rows and columns with no
+// observations get zero factors.
+func ALS(m Matrix, rank int, opts ...ALSOption) *Factorization {
+	debugCheck("ALS", m)
+	o := alsOptions{lambda: 0.1, maxIter: 20, tol: 1e-6}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	rows, cols := m.Rows(), m.Cols()
+	if rank < 1 {
+		panic(fmt.Sprintf("ALS: can't find factors of rank %d", rank))
+	}
+	if o.lambda < 0 {
+		panic(fmt.Sprintf("ALS: regularization %f should not be negative", o.lambda))
+	}
+
+	// Index the observations by row and by column
+	byRow := make([]map[int]float64, rows)
+	byCol := make([]map[int]float64, cols)
+	var count int
+	m.VisitNonzero(func(pos []int, value float64) bool {
+		row, col := pos[0], pos[1]
+		if byRow[row] == nil {
+			byRow[row] = make(map[int]float64)
+		}
+		if byCol[col] == nil {
+			byCol[col] = make(map[int]float64)
+		}
+		byRow[row][col] = value
+		byCol[col][row] = value
+		count++
+		return true
+	})
+
+	// Start from small random factors, so the first sweep isn't degenerate
+	norm := rand.NormFloat64
+	if o.rng != nil {
+		norm = o.rng.NormFloat64
+	}
+	u := alsInit(rows, rank, norm)
+	v := alsInit(cols, rank, norm)
+
+	rmse := math.Inf(1)
+	for iter := 0; iter < o.maxIter; iter++ {
+		alsSweep(u, v, byRow, rank, o.lambda)
+		alsSweep(v, u, byCol, rank, o.lambda)
+		prev := rmse
+		rmse = alsRMSE(u, v, byRow, count)
+		if prev-rmse <= o.tol {
+			break
+		}
+	}
+
+	f := &Factorization{U: Dense(rows, rank).M(), V: Dense(cols, rank).M(), RMSE: rmse}
+	for i, vec := range u {
+		f.U.RowSet(i, vec)
+	}
+	for i, vec := range v {
+		f.V.RowSet(i, vec)
+	}
+	return f
+}
+
+// Create n random factor vectors of the given rank
+func alsInit(n, rank int, norm func() float64) [][]float64 {
+	scale := 1 / math.Sqrt(float64(rank))
+	factors := make([][]float64, n)
+	for i := range factors {
+		factors[i] = make([]float64, rank)
+		for k := range factors[i] {
+			factors[i][k] = scale * norm()
+		}
+	}
+	return factors
+}
+
+// Solve for each factor vector in solve, holding the vectors in fixed
+// constant. obs[i] holds the observations for solve[i], keyed by the index of
+// the fixed vector they pair with.
+func alsSweep(solve, fixed [][]float64, obs []map[int]float64, rank int, lambda float64) {
+	a := mat.NewSymDense(rank, nil)
+	b := mat.NewVecDense(rank, nil)
+	var (
+		chol mat.Cholesky
+		x    mat.VecDense
+	)
+	for i := range solve {
+		if len(obs[i]) == 0 {
+			for k := range solve[i] {
+				solve[i][k] = 0
+			}
+			continue
+		}
+
+		// Build the normal equations (F'F + lambda n I) x = F'r
+		a.Zero()
+		b.Zero()
+		for j, value := range obs[i] {
+			vec := fixed[j]
+			for p := 0; p < rank; p++ {
+				b.SetVec(p, b.AtVec(p)+vec[p]*value)
+				for q := p; q < rank; q++ {
+					a.SetSym(p, q, a.At(p, q)+vec[p]*vec[q])
+				}
+			}
+		}
+		for p := 0; p < rank; p++ {
+			a.SetSym(p, p, a.At(p, p)+lambda*float64(len(obs[i])))
+		}
+
+		if !chol.Factorize(a) {
+			panic(fmt.Errorf("ALS: %w: too few observations for rank %d; try a larger ALSLambda()", ErrSingular, rank))
+		}
+		// An ill-conditioned system still has a usable solution
+		_ = chol.SolveVecTo(&x, b)
+		for k := range solve[i] {
+			solve[i][k] = x.AtVec(k)
+		}
+	}
+}
+
+// Get the root mean squared error of u * v' on the observations
+func alsRMSE(u, v [][]float64, byRow []map[int]float64, count int) float64 {
+	if count == 0 {
+		return 0
+	}
+	var sum float64
+	for i, obs := range byRow {
+		for j, value := range obs {
+			var pred float64
+			for k := range u[i] {
+				pred += u[i][k] * v[j][k]
+			}
+			sum += (pred - value) * (pred - value)
+		}
+	}
+	return math.Sqrt(sum / float64(count))
+}
@@ -0,0 +1,46 @@
+package matrix
+
+import (
+	. "github.com/smartystreets/goconvey/convey"
+	"math/rand"
+	"testing"
+)
+
+func TestALS(t *testing.T) {
+	Convey("Given a rank-2 matrix with some entries missing", t, func() {
+		u := M(6, 2, 1, 2, 2, 1, 3, 1, 1, 3, 2, 2, 1, 1)
+		v := M(5, 2, 1, 1, 2, 1, 1, 2, 3, 1, 1, 3)
+		full := u.MProd(v.T())
+		observed := full.SparseCoo()
+		missing := [][]int{{0, 1}, {1, 4}, {2, 2}, {3, 0}, {4, 3}, {5, 4}}
+		for _, pos := range missing {
+			observed.ItemSet(0, pos...)
+		}
+
+		Convey("ALS recovers the missing entries", func() {
+			f := ALS(observed, 2, ALSLambda(1e-6), ALSMaxIter(500), ALSTol(1e-12),
+				ALSRand(rand.NewSource(1)))
+			So(f.U.Shape(), ShouldResemble, []int{6, 2})
+			So(f.V.Shape(), ShouldResemble, []int{5, 2})
+			So(f.RMSE, ShouldBeLessThan, 1e-3)
+			for _, pos := range missing {
+				So(f.Predict(pos[0], pos[1]), ShouldAlmostEqual, full.Item(pos...), 0.01)
+			}
+			So(f.Reconstruct().Item(2, 2), ShouldAlmostEqual, f.Predict(2, 2), 1e-12)
+		})
+
+		Convey("Unobserved rows get zero factors", func() {
+			observed.RowSet(5, make([]float64, 5))
+			f := ALS(observed, 2, ALSRand(rand.NewSource(1)))
+			So(f.U.Row(5), ShouldResemble, []float64{0, 0})
+			So(f.Predict(5, 0), ShouldEqual, 0)
+		})
+
+		Convey("Invalid arguments panic", func() {
+			So(func() { ALS(observed, 0) }, ShouldPanic)
+			So(func() { ALS(observed, 2, ALSLambda(-1)) }, ShouldPanic)
+			f := ALS(observed, 1, ALSMaxIter(1))
+			So(func() { f.Predict(6, 0) }, ShouldPanic)
+		})
+	})
+}
@@ -0,0 +1,43 @@
+package matrix
+
+import (
+	"math"
+)
+
+// Returns true if the matrices have the same shape, and every element of a is
+// close to the corresponding element of b: |a - b| <= atol + rtol * |b|. As
+// with NumPy's allclose, the test is not symmetric in a and b, and NaN values
+// are never close to anything.
+func ApproxEqual(a, b Matrix, atol, rtol float64) bool {
+	return AllClose(a, b, atol, rtol) == nil
+}
+
+// Check that the matrices are equal within the tolerances, as ApproxEqual
+// does. Returns nil if they are, ErrShapeMismatch if their shapes differ, or
+// ErrNotClose describing the first element which isn't close enough.
+func AllClose(a, b Matrix, atol, rtol float64) error {
+	if err := SameShape(b, a); err != nil {
+		return err
+	}
+	for row := 0; row < a.Rows(); row++ {
+		for col := 0; col < a.Cols(); col++ {
+			got, want := a.Item(row, col), b.Item(row, col)
+			if !isClose(got, want, atol, rtol) {
+				return ErrNotClose{Index: []int{row, col}, Got: got, Want: want}
+			}
+		}
+	}
+	return nil
+}
+
+// Returns true if |got - want| <= atol + rtol * |want|. Infinities are close
+// only to themselves.
+func isClose(got, want, atol, rtol float64) bool {
+	if got == want {
+		return true
+	}
+	if math.IsInf(got, 0) || math.IsInf(want, 0) {
+		return false
+	}
+	return math.Abs(got-want) <= atol+rtol*math.Abs(want)
+}
@@ -0,0 +1,64 @@
+package matrix
+
+import (
+	"errors"
+	. "github.com/smartystreets/goconvey/convey"
+	"math"
+	"testing"
+)
+
+func TestApproxEqual(t *testing.T) {
+	Convey("Given matrices which differ slightly", t, func() {
+		a := M(2, 2,
+			1, 2,
+			3, 4)
+		b := M(2, 2,
+			1, 2.001,
+			3, 4)
+
+		Convey("ApproxEqual respects the absolute tolerance", func() {
+			So(ApproxEqual(a, b, 0.01, 0), ShouldBeTrue)
+			So(ApproxEqual(a, b, 1e-4, 0), ShouldBeFalse)
+		})
+
+		Convey("ApproxEqual respects the relative tolerance", func() {
+			So(ApproxEqual(a, b, 0, 1e-3), ShouldBeTrue)
+			So(ApproxEqual(a, b, 0, 1e-4), ShouldBeFalse)
+		})
+
+		Convey("ApproxEqual compares across storage formats", func() {
+			So(ApproxEqual(Eye(3), Eye(3).Dense().M(), 0, 0), ShouldBeTrue)
+			So(ApproxEqual(Eye(3), Eye(3).SparseCoo(), 0, 0), ShouldBeTrue)
+		})
+
+		Convey("AllClose reports the first differing element", func() {
+			err := AllClose(a, b, 1e-6, 1e-6)
+			var notClose ErrNotClose
+			So(errors.As(err, &notClose), ShouldBeTrue)
+			So(notClose.Index, ShouldResemble, []int{0, 1})
+			So(notClose.Got, ShouldEqual, 2)
+			So(notClose.Want, ShouldEqual, 2.001)
+			So(AllClose(a, b, Eps, 0.01), ShouldBeNil)
+		})
+
+		Convey("AllClose reports mismatched shapes", func() {
+			var shapeErr ErrShapeMismatch
+			So(errors.As(AllClose(a, Dense(2, 3).M(), 1, 1), &shapeErr), ShouldBeTrue)
+		})
+	})
+
+	Convey("Given special values", t, func() {
+		inf := M(1, 2, math.Inf(1), 0)
+		nan := M(1, 2, math.NaN(), 0)
+
+		Convey("Infinities are only close to themselves", func() {
+			So(ApproxEqual(inf, inf, 0, 0), ShouldBeTrue)
+			So(ApproxEqual(inf, M(1, 2, math.MaxFloat64, 0), 1, 1), ShouldBeFalse)
+			So(ApproxEqual(inf, M(1, 2, math.Inf(-1), 0), 1, 1), ShouldBeFalse)
+		})
+
+		Convey("NaN is never close", func() {
+			So(ApproxEqual(nan, nan, 1, 1), ShouldBeFalse)
+		})
+	})
+}
@@ -0,0 +1,146 @@
+package matrix
+
+import (
+	"errors"
+	"fmt"
+	"gonum.org/v1/gonum/mat"
+	"math"
+	"sort"
+	"sync"
+)
+
+// A Backend implements the linear algebra routines behind Inverse, LDivide
+// (and Solve) and Norm. Alternative backends, such as ones calling OpenBLAS
+// or Accelerate, can be registered with RegisterBackend and selected at
+// runtime with UseBackend. A backend which only replaces some routines can
+// embed GonumBackend to inherit the rest.
+type Backend interface {
+	// The name the backend is registered under
+	Name() string
+
+	// Get the matrix inverse
+	Inverse(a Matrix) (Matrix, error)
+
+	// Solve for x, where ax = b
+	Solve(a, b Matrix) (Matrix, error)
+
+	// Get the matrix norm of the specified ordinality (1, 2, infinity, ...),
+	// or NuclearNorm. The 2-norm is the induced 2-norm: the largest singular
+	// value.
+	Norm(m Matrix, ord float64) float64
+}
+
+// The default backend, which uses gonum's pure Go implementations. It is
+// registered under the name "gonum".
+type GonumBackend struct{}
+
+// The name the backend is registered under
+func (GonumBackend) Name() string {
+	return "gonum"
+}
+
+// Get the matrix inverse
+func (GonumBackend) Inverse(a Matrix) (Matrix, error) {
+	var inv mat.Dense
+	err := inv.Inverse(ToMat(a))
+	if err != nil {
+		return nil, singularError(err)
+	}
+	return ToMatrix(&inv), nil
+}
+
+// Solve for x, where ax = b
+func (GonumBackend) Solve(a, b Matrix) (Matrix, error) {
+	var x mat.Dense
+	err := x.Solve(ToMat(a), ToMat(b))
+	if err != nil {
+		return nil, singularError(err)
+	}
+	return ToMatrix(&x), nil
+}
+
+// Wrap gonum's ill-conditioning errors as ErrSingular, keeping the original
+// error so the condition number is still available through errors.As.
+func singularError(err error) error {
+	var cond mat.Condition
+	if errors.As(err, &cond) {
+		return fmt.Errorf("%w: %w", ErrSingular, err)
+	}
+	return err
+}
+
+// Get the matrix norm of the specified ordinality
+func (GonumBackend) Norm(m Matrix, ord float64) float64 {
+	if m.Size() == 0 {
+		// gonum panics on empty matrices
+		return 0
+	} else if ord == 2 || ord == NuclearNorm {
+		var svd mat.SVD
+		if !svd.Factorize(AsMat(m), mat.SVDNone) {
+			return math.NaN()
+		}
+		values := svd.Values(nil)
+		if ord == 2 {
+			return values[0]
+		}
+		var sum float64
+		for _, v := range values {
+			sum += v
+		}
+		return sum
+	}
+	return mat.Norm(AsMat(m), ord)
+}
+
+// The registered backends, and the one in use
+var backends = struct {
+	sync.RWMutex
+	byName  map[string]Backend
+	current Backend
+}{
+	byName:  map[string]Backend{"gonum": GonumBackend{}},
+	current: GonumBackend{},
+}
+
+// Register a backend under its name, so it can be selected with UseBackend.
+// Panics if a backend with the same name is already registered.
+func RegisterBackend(b Backend) {
+	backends.Lock()
+	defer backends.Unlock()
+	if _, ok := backends.byName[b.Name()]; ok {
+		panic(fmt.Sprintf("A backend named %q is already registered", b.Name()))
+	}
+	backends.byName[b.Name()] = b
+}
+
+// Select the registered backend with the given name for all subsequent
+// linear algebra calls
+func UseBackend(name string) error {
+	backends.Lock()
+	defer backends.Unlock()
+	b, ok := backends.byName[name]
+	if !ok {
+		return fmt.Errorf("No backend named %q is registered", name)
+	}
+	backends.current = b
+	return nil
+}
+
+// Get the backend in use
+func CurrentBackend() Backend {
+	backends.RLock()
+	defer backends.RUnlock()
+	return backends.current
+}
+
+// Get the names of the registered backends, in sorted order
+func Backends() []string {
+	backends.RLock()
+	defer backends.RUnlock()
+	names := make([]string, 0, len(backends.byName))
+	for name := range backends.byName {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
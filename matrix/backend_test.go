@@ -0,0 +1,68 @@
+package matrix
+
+import (
+	"errors"
+	. "github.com/smartystreets/goconvey/convey"
+	"sync"
+	"testing"
+)
+
+// A backend which counts calls to Solve and fails them, and otherwise
+// delegates to gonum
+type countingBackend struct {
+	GonumBackend
+	solves int
+}
+
+func (b *countingBackend) Name() string {
+	return "counting"
+}
+
+func (b *countingBackend) Solve(a, x Matrix) (Matrix, error) {
+	b.solves++
+	return nil, errors.New("no solver")
+}
+
+var (
+	testBackend         = &countingBackend{}
+	registerTestBackend sync.Once
+)
+
+func TestBackend(t *testing.T) {
+	registerTestBackend.Do(func() { RegisterBackend(testBackend) })
+
+	Convey("Given the default backend", t, func() {
+		So(CurrentBackend().Name(), ShouldEqual, "gonum")
+		So(Backends(), ShouldResemble, []string{"counting", "gonum"})
+
+		Convey("Unknown and duplicate backends are rejected", func() {
+			So(UseBackend("missing"), ShouldNotBeNil)
+			So(CurrentBackend().Name(), ShouldEqual, "gonum")
+			So(func() { RegisterBackend(GonumBackend{}) }, ShouldPanic)
+		})
+	})
+
+	Convey("Given a registered backend", t, func() {
+		So(UseBackend("counting"), ShouldBeNil)
+		defer UseBackend("gonum")
+		a := M(2, 2,
+			2, 1,
+			1, 3)
+
+		Convey("Calls are routed to it", func() {
+			before := testBackend.solves
+			x := a.LDivide(M(2, 1, 3, 5))
+			So(testBackend.solves, ShouldEqual, before+1)
+			So(x.Shape(), ShouldResemble, []int{2, 1})
+			So(x.AnyNaN(), ShouldBeTrue)
+		})
+
+		Convey("Routines it doesn't replace use the embedded backend", func() {
+			inv, err := a.Inverse()
+			So(err, ShouldBeNil)
+			So(inv.Item(0, 0), ShouldBeBetween, 0.6-Eps, 0.6+Eps)
+			So(inv.Item(0, 1), ShouldBeBetween, -0.2-Eps, -0.2+Eps)
+			So(a.Norm(1), ShouldEqual, 4)
+		})
+	})
+}
@@ -0,0 +1,93 @@
+package matrix
+
+import (
+	"fmt"
+)
+
+// Get a copy of the lower triangular part of m, on and below its kth
+// diagonal, with zeros elsewhere. k = 0 is the main diagonal, k > 0 is above
+// it and k < 0 is below it. Dense matrices give a dense result, sparse
+// diagonal matrices a sparse diagonal result, and sparse coo matrices a sparse
+// coo result.
+func Tril(m Matrix, k int) Matrix {
+	return band("Tril", m, m.Rows(), k)
+}
+
+// Get a copy of the upper triangular part of m, on and above its kth
+// diagonal, with zeros elsewhere. Diagonals are numbered and results stored
+// as for Tril().
+func Triu(m Matrix, k int) Matrix {
+	return band("Triu", m, -k, m.Cols())
+}
+
+// Get a copy of the band of m made up of the main diagonal, kl diagonals
+// below it and ku diagonals above it, with zeros elsewhere. Results are
+// stored as for Tril().
+func Band(m Matrix, kl, ku int) Matrix {
+	if kl < 0 || ku < 0 {
+		panic(fmt.Sprintf("Band: the numbers of diagonals %d and %d can't be negative", kl, ku))
+	}
+	return band("Band", m, kl, ku)
+}
+
+// Keep the elements of m with -kl <= col - row <= ku
+func band(op string, m Matrix, kl, ku int) Matrix {
+	debugCheck(op, m)
+	if m.Sparsity() == SparseDiagMatrix {
+		if kl >= 0 && ku >= 0 {
+			return m.Copy().M()
+		}
+		return SparseDiag(m.Rows(), m.Cols())
+	}
+	return remap(m, m.Rows(), m.Cols(), func(r, c int) (int, int, bool) {
+		return r, c, c-r >= -kl && c-r <= ku
+	})
+}
+
+// Create a sparse matrix from its diagonals, as SciPy's diags() does.
+// diags[k] holds the elements of the diagonal with offset offsets[k], from
+// top-left to bottom-right: offset 0 is the main diagonal, positive offsets
+// are above it and negative offsets below it. Each diagonal must have one
+// value per element, or a single value to repeat along its length. For
+// example, the n x n second difference matrix is
+//
+//	FromDiags([][]float64{{1}, {-2}, {1}}, []int{-1, 0, 1}, n, n)
+//
+// The result is a sparse diagonal matrix if the only offset is 0, and a
+// sparse coo matrix otherwise.
+func FromDiags(diags [][]float64, offsets []int, rows, cols int) Matrix {
+	if len(diags) != len(offsets) {
+		panic(ErrShapeMismatch{Op: "FromDiags", Got: []int{len(offsets)}, Want: []int{len(diags)}})
+	}
+	var result Matrix
+	if len(offsets) == 1 && offsets[0] == 0 {
+		result = SparseDiag(rows, cols)
+	} else {
+		result = SparseCoo(rows, cols)
+	}
+	seen := make(map[int]bool, len(offsets))
+	for k, offset := range offsets {
+		if offset <= -rows || offset >= cols {
+			panic(fmt.Sprintf("FromDiags: offset %d is outside a %dx%d matrix", offset, rows, cols))
+		} else if seen[offset] {
+			panic(fmt.Sprintf("FromDiags: offset %d is repeated", offset))
+		}
+		seen[offset] = true
+		row, col := max(0, -offset), max(0, offset)
+		length := min(rows-row, cols-col)
+		diag := diags[k]
+		if len(diag) != 1 && len(diag) != length {
+			panic(ErrShapeMismatch{Op: "FromDiags", Got: []int{len(diag)}, Want: []int{length}})
+		}
+		for i := 0; i < length; i++ {
+			v := diag[0]
+			if len(diag) > 1 {
+				v = diag[i]
+			}
+			if v != 0 {
+				result.ItemSet(v, row+i, col+i)
+			}
+		}
+	}
+	return result
+}
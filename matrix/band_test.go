@@ -0,0 +1,99 @@
+package matrix
+
+import (
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+func TestBand(t *testing.T) {
+	Convey("Given dense and sparse matrices", t, func() {
+		values := []float64{
+			1, 2, 3, 4,
+			5, 6, 7, 8,
+			9, 10, 11, 12,
+		}
+		matrices := []Matrix{M(3, 4, values...), SparseCoo(3, 4, values...)}
+
+		Convey("Tril keeps the lower triangle", func() {
+			for _, m := range matrices {
+				So(Tril(m, 0).Equal(M(3, 4,
+					1, 0, 0, 0,
+					5, 6, 0, 0,
+					9, 10, 11, 0)), ShouldBeTrue)
+				So(Tril(m, -1).Equal(M(3, 4,
+					0, 0, 0, 0,
+					5, 0, 0, 0,
+					9, 10, 0, 0)), ShouldBeTrue)
+				So(Tril(m, 1).Sparsity(), ShouldEqual, m.Sparsity())
+			}
+		})
+
+		Convey("Triu keeps the upper triangle", func() {
+			for _, m := range matrices {
+				So(Triu(m, 0).Equal(M(3, 4,
+					1, 2, 3, 4,
+					0, 6, 7, 8,
+					0, 0, 11, 12)), ShouldBeTrue)
+				So(Triu(m, 2).Equal(M(3, 4,
+					0, 0, 3, 4,
+					0, 0, 0, 8,
+					0, 0, 0, 0)), ShouldBeTrue)
+				So(Add(Tril(m, -1), Triu(m, 0)).Equal(m), ShouldBeTrue)
+			}
+		})
+
+		Convey("Band keeps diagonals near the main diagonal", func() {
+			for _, m := range matrices {
+				So(Band(m, 1, 0).Equal(M(3, 4,
+					1, 0, 0, 0,
+					5, 6, 0, 0,
+					0, 10, 11, 0)), ShouldBeTrue)
+				So(Band(m, 0, 0).Equal(Diag(1, 6, 11).Concat(1, Dense(3, 1))), ShouldBeTrue)
+			}
+			So(func() { Band(matrices[0], -1, 0) }, ShouldPanic)
+		})
+
+		Convey("Sparse diagonal matrices stay diagonal", func() {
+			m := Diag(1, 2, 3)
+			So(Tril(m, 0).Sparsity(), ShouldEqual, SparseDiagMatrix)
+			So(Tril(m, 0).Equal(m), ShouldBeTrue)
+			So(Triu(m, 1).CountNonzero(), ShouldEqual, 0)
+			So(Band(m, 1, 1).Equal(m), ShouldBeTrue)
+		})
+	})
+}
+
+func TestFromDiags(t *testing.T) {
+	Convey("FromDiags places each diagonal at its offset", t, func() {
+		m := FromDiags([][]float64{{1, 2}, {3, 4, 5}, {6}}, []int{-1, 0, 2}, 3, 4)
+		So(m.Sparsity(), ShouldEqual, SparseCooMatrix)
+		So(m.Equal(M(3, 4,
+			3, 0, 6, 0,
+			1, 4, 0, 6,
+			0, 2, 5, 0)), ShouldBeTrue)
+	})
+
+	Convey("FromDiags repeats single values", t, func() {
+		m := FromDiags([][]float64{{1}, {-2}, {1}}, []int{-1, 0, 1}, 4, 4)
+		So(m.Equal(M(4, 4,
+			-2, 1, 0, 0,
+			1, -2, 1, 0,
+			0, 1, -2, 1,
+			0, 0, 1, -2)), ShouldBeTrue)
+		So(Band(m, 1, 1).Equal(m), ShouldBeTrue)
+	})
+
+	Convey("FromDiags gives a sparse diagonal matrix for the main diagonal", t, func() {
+		m := FromDiags([][]float64{{1, 2}}, []int{0}, 2, 3)
+		So(m.Sparsity(), ShouldEqual, SparseDiagMatrix)
+		So(m.Equal(M(2, 3, 1, 0, 0, 0, 2, 0)), ShouldBeTrue)
+	})
+
+	Convey("FromDiags panics on invalid diagonals", t, func() {
+		So(func() { FromDiags([][]float64{{1}}, []int{0, 1}, 2, 2) }, ShouldPanic)
+		So(func() { FromDiags([][]float64{{1}}, []int{2}, 2, 2) }, ShouldPanic)
+		So(func() { FromDiags([][]float64{{1}, {2}}, []int{1, 1}, 2, 2) }, ShouldPanic)
+		So(try(func() { FromDiags([][]float64{{1, 2}}, []int{1}, 2, 2) }), ShouldResemble,
+			ErrShapeMismatch{Op: "FromDiags", Got: []int{2}, Want: []int{1}})
+	})
+}
@@ -9,12 +9,12 @@ import (
 // an index of -1 refers to the final array element.
 func ndToFlat(shape []int, index []int) int {
 	if len(index) != len(shape) {
-		panic(fmt.Sprintf("Indices %v invalid for array shape %v", index, shape))
+		panic(ErrIndexOutOfRange{Index: index, Shape: shape})
 	}
 	flat := 0
 	for i := range shape {
 		if index[i] >= shape[i] || index[i] < -shape[i] {
-			panic(fmt.Sprintf("Indices %v invalid for array shape %v", index, shape))
+			panic(ErrIndexOutOfRange{Index: index, Shape: shape})
 		} else if index[i] < 0 {
 			flat += index[i] + shape[i]
 		} else {
@@ -34,7 +34,7 @@ func flatToNd(shape []int, flat int) []int {
 		size *= v
 	}
 	if flat >= size || flat < -size {
-		panic(fmt.Sprintf("Flat index %v invalid for array shape %v", flat, shape))
+		panic(ErrIndexOutOfRange{Op: "flat index", Index: []int{flat}, Shape: []int{size}})
 	}
 	if flat < 0 {
 		flat += size
@@ -52,30 +52,17 @@ func flatToNd(shape []int, flat int) []int {
 
 // Return the element-wise sum of this array and one or more others
 func Add(array NDArray, others ...NDArray) NDArray {
+	debugCheck("Add", array, others...)
 	var result NDArray
-	sp := array.Sparsity()
-	sh := array.Shape()
-	for _, o := range others {
-		switch o.Sparsity() {
-		case DenseArray:
-			sp = DenseArray
-		case SparseCooMatrix:
-			if sp == SparseDiagMatrix {
-				sp = SparseCooMatrix
-			}
-		}
-		sh2 := o.Shape()
-		if len(sh2) != len(sh) {
-			panic(fmt.Sprintf("Can't add arrays with shapes %v and %v", sh, sh2))
-		}
-		for i := range sh {
-			if sh[i] != sh2[i] {
-				panic(fmt.Sprintf("Can't add arrays with shapes %v and %v", sh, sh2))
-			}
-		}
+	sp := sumSparsity("Add", array, others)
+	if sp == SparseDiagMatrix {
+		return diagSum(array, others, 1)
 	}
+	sh := array.Shape()
 
 	switch sp {
+	case SparseCSRMatrix, SparseCSCMatrix:
+		result = array.M().SparseCoo()
 	case array.Sparsity():
 		result = array.Copy()
 	case DenseArray:
@@ -93,6 +80,50 @@ func Add(array NDArray, others ...NDArray) NDArray {
 			return true
 		})
 	}
+	return compressAs(result, sp)
+}
+
+// Add (sign 1) or subtract (sign -1) the diagonals of diagonal matrices,
+// without visiting their elements one at a time
+func diagSum(array NDArray, others []NDArray, sign float64) NDArray {
+	sh := array.Shape()
+	diag := array.M().Diag().Array()
+	for _, o := range others {
+		for idx, v := range o.M().Diag().Array() {
+			diag[idx] += sign * v
+		}
+	}
+	return SparseDiag(sh[0], sh[1], diag...)
+}
+
+// Get the storage format for the sum or difference of arrays, checking that
+// their shapes match. The result is dense if any array is dense, and sparse
+// otherwise: in the arrays' format if they share one, and in coo format if
+// they don't. Sums of compressed matrices are accumulated in coo format and
+// converted afterwards.
+func sumSparsity(op string, array NDArray, others []NDArray) ArraySparsity {
+	sp := array.Sparsity()
+	sh := array.Shape()
+	for _, o := range others {
+		switch osp := o.Sparsity(); {
+		case osp == DenseArray:
+			sp = DenseArray
+		case sp != DenseArray && osp != sp:
+			sp = SparseCooMatrix
+		}
+		checkSameShape(op, o.Shape(), sh)
+	}
+	return sp
+}
+
+// Convert a coo result to a compressed format, if sp is one
+func compressAs(result NDArray, sp ArraySparsity) NDArray {
+	switch sp {
+	case SparseCSRMatrix:
+		return result.M().SparseCSR()
+	case SparseCSCMatrix:
+		return result.M().SparseCSC()
+	}
 	return result
 }
 
@@ -126,14 +157,7 @@ func AllF(array NDArray, f func(v float64) bool) bool {
 func AllF2(array NDArray, f func(v1, v2 float64) bool, other NDArray) bool {
 	sh1 := array.Shape()
 	sh2 := other.Shape()
-	if len(sh1) != len(sh2) {
-		panic("AllF2() requires two arrays of the same shape")
-	}
-	for i := 0; i < len(sh1); i++ {
-		if sh1[i] != sh2[i] {
-			panic("AllF2() requires two arrays of the same shape")
-		}
-	}
+	checkSameShape("AllF2", sh2, sh1)
 	size := array.Size()
 	for i := 0; i < size; i++ {
 		if !f(array.FlatItem(i), other.FlatItem(i)) {
@@ -143,6 +167,32 @@ func AllF2(array NDArray, f func(v1, v2 float64) bool, other NDArray) bool {
 	return true
 }
 
+// Returns true if and only if no items are NaN or infinite. Only nonzero
+// elements are visited, and iteration stops at the first non-finite item.
+func AllFinite(array NDArray) bool {
+	return array.VisitNonzero(func(pos []int, value float64) bool {
+		return !math.IsNaN(value) && !math.IsInf(value, 0)
+	})
+}
+
+// Returns, for each vector along the specified axis, whether f is true for all
+// of its elements. For axis 0 there is one result per column; for axis 1,
+// one per row.
+func AllFAxis(m Matrix, f func(v float64) bool, axis int) []bool {
+	vecs := axisVectors(m, axis)
+	result := make([]bool, len(vecs))
+	for idx, vec := range vecs {
+		result[idx] = true
+		for _, v := range vec {
+			if !f(v) {
+				result[idx] = false
+				break
+			}
+		}
+	}
+	return result
+}
+
 // Returns true if and only if any item is nonzero
 func Any(array NDArray) bool {
 	return !array.VisitNonzero(func(pos []int, value float64) bool {
@@ -170,14 +220,7 @@ func AnyF(array NDArray, f func(v float64) bool) bool {
 func AnyF2(array NDArray, f func(v1, v2 float64) bool, other NDArray) bool {
 	sh1 := array.Shape()
 	sh2 := other.Shape()
-	if len(sh1) != len(sh2) {
-		panic("AnyF2() requires two arrays of the same shape")
-	}
-	for i := 0; i < len(sh1); i++ {
-		if sh1[i] != sh2[i] {
-			panic("AnyF2() requires two arrays of the same shape")
-		}
-	}
+	checkSameShape("AnyF2", sh2, sh1)
 	size := array.Size()
 	for i := 0; i < size; i++ {
 		if f(array.FlatItem(i), other.FlatItem(i)) {
@@ -187,6 +230,31 @@ func AnyF2(array NDArray, f func(v1, v2 float64) bool, other NDArray) bool {
 	return false
 }
 
+// Returns, for each vector along the specified axis, whether f is true for any
+// of its elements. For axis 0 there is one result per column; for axis 1,
+// one per row.
+func AnyFAxis(m Matrix, f func(v float64) bool, axis int) []bool {
+	vecs := axisVectors(m, axis)
+	result := make([]bool, len(vecs))
+	for idx, vec := range vecs {
+		for _, v := range vec {
+			if f(v) {
+				result[idx] = true
+				break
+			}
+		}
+	}
+	return result
+}
+
+// Returns true if and only if any item is NaN. Only nonzero elements are
+// visited, and iteration stops at the first NaN.
+func AnyNaN(array NDArray) bool {
+	return !array.VisitNonzero(func(pos []int, value float64) bool {
+		return !math.IsNaN(value)
+	})
+}
+
 // Return the result of applying a function to all elements
 func Apply(array NDArray, f func(float64) float64) NDArray {
 	result := array.Dense()
@@ -202,6 +270,7 @@ func Apply(array NDArray, f func(float64) float64) NDArray {
 // specified axis. The array shapes must be equal along all other axes.
 // It is legal to add a new axis.
 func Concat(axis int, array NDArray, others ...NDArray) NDArray {
+	debugCheck("Concat", array, others...)
 	if len(others) < 1 {
 		return array.Copy()
 	}
@@ -215,7 +284,7 @@ func Concat(axis int, array NDArray, others ...NDArray) NDArray {
 	for i := 1; i < len(shs); i++ {
 		shs[i] = others[i-1].Shape()
 		if len(shs[0]) != len(shs[i]) {
-			panic(fmt.Sprintf("Can't concat arrays with %d and %d dims", len(shs[0]), len(shs[i])))
+			panic(ErrShapeMismatch{Op: "Concat", Got: shs[i], Want: shs[0]})
 		}
 	}
 
@@ -229,7 +298,11 @@ func Concat(axis int, array NDArray, others ...NDArray) NDArray {
 		if i != axis {
 			for j := 1; j < len(shs); j++ {
 				if shs[0][i] != shs[j][i] {
-					panic(fmt.Sprintf("Can't concat arrays along axis %d with unequal size on axis %d", axis, i))
+					want := append([]int(nil), shs[0]...)
+					if axis < len(want) {
+						want[axis] = -1
+					}
+					panic(ErrShapeMismatch{Op: "Concat", Got: shs[j], Want: want})
 				}
 			}
 			shOut[i] = shs[0][i]
@@ -276,46 +349,202 @@ func Concat(axis int, array NDArray, others ...NDArray) NDArray {
 	return result
 }
 
+// Count the number of nonzero elements along the specified axis: one count
+// per column for axis 0, and one per row for axis 1. Only nonzero elements are
+// visited, so this takes time proportional to the number of stored elements
+// for sparse matrices.
+func CountNonzeroAxis(m Matrix, axis int) []int {
+	var counts []int
+	switch axis {
+	case 0:
+		counts = make([]int, m.Cols())
+	case 1:
+		counts = make([]int, m.Rows())
+	default:
+		panic(fmt.Sprintf("Invalid axis %d for a 2-dim matrix", axis))
+	}
+	m.VisitNonzero(func(pos []int, value float64) bool {
+		if value != 0 {
+			counts[pos[1-axis]]++
+		}
+		return true
+	})
+	return counts
+}
+
 // Treat the rows as points, and get the pairwise distance between them.
 // Returns a distance matrix D such that D_i,j is the distance between
-// rows i and j.
+// rows i and j. Large matrices are split into blocks of rows, which are
+// compared in parallel by NumWorkers() goroutines; custom distances must be
+// safe to call concurrently.
 func Dist(m Matrix, t DistType) Matrix {
-	var dist = Dense(m.Rows(), m.Rows()).M()
-	for i := 1; i < m.Rows(); i++ {
-		ri := m.Row(i)
-		for j := 0; j <= i; j++ {
-			rj := m.Row(j)
+	debugCheck("Dist", m)
+	kernel := distKernelFor(t)
+	dist := Dense(m.Rows(), m.Rows()).M()
+	rows := make([][]float64, m.Rows())
+	for i := range rows {
+		rows[i] = kernel.prepare(m.Row(i))
+	}
+
+	// Each block fills the lower triangle of its rows, and their mirror
+	// images in the upper triangle, so blocks never write the same element
+	parallelRows(m.Rows(), func(lo, hi int) {
+		for i := max(lo, 1); i < hi; i++ {
+			for j := 0; j <= i; j++ {
+				v := kernel.dist(rows[i], rows[j])
+				dist.ItemSet(v, i, j)
+				dist.ItemSet(v, j, i)
+			}
+		}
+	})
+	return dist
+}
+
+// Treat the rows of m and other as points, and get the distance between each
+// pair. Returns an m.Rows() x other.Rows() distance matrix D such that D_i,j
+// is the distance between row i of m and row j of other, which is what
+// nearest-neighbor search against a reference set needs. The matrices must
+// have the same number of columns. The rows of m are compared in parallel, as
+// for Dist().
+func DistTo(m, other Matrix, t DistType) Matrix {
+	debugCheck("DistTo", m, other)
+	if m.Cols() != other.Cols() {
+		panic(ErrShapeMismatch{Op: "DistTo", Got: other.Shape(), Want: []int{-1, m.Cols()}})
+	}
+	kernel := distKernelFor(t)
+	dist := Dense(m.Rows(), other.Rows()).M()
+	cols := make([][]float64, other.Rows())
+	for j := range cols {
+		cols[j] = kernel.prepare(other.Row(j))
+	}
+	rows := make([][]float64, m.Rows())
+	for i := range rows {
+		rows[i] = kernel.prepare(m.Row(i))
+	}
+	parallelRows(m.Rows(), func(lo, hi int) {
+		for i := lo; i < hi; i++ {
+			for j, col := range cols {
+				dist.ItemSet(kernel.dist(rows[i], col), i, j)
+			}
+		}
+	})
+	return dist
+}
+
+// A way to compute the distance between rows. Each row is passed through
+// prepare once, and dist then compares pairs of prepared rows.
+type distKernel struct {
+	prepare func(row []float64) []float64
+	dist    func(a, b []float64) float64
+}
+
+// Get the kernel which computes a type of distance
+func distKernelFor(t DistType) distKernel {
+	switch t {
+	case EuclideanDist:
+		return distKernel{
+			prepare: func(row []float64) []float64 { return row },
+			dist: func(a, b []float64) float64 {
+				var v float64
+				for idx, av := range a {
+					v += math.Pow(av-b[idx], 2)
+				}
+				return math.Sqrt(v)
+			},
+		}
+	case CorrelationDist:
+		return distKernel{
+			prepare: centerNormalize,
+			dist: func(a, b []float64) float64 {
+				return 1 - normedCorr(a, b)
+			},
+		}
+	case ManhattanDist:
+		return rowDistKernel(func(a, b []float64) float64 {
+			var v float64
+			for idx, av := range a {
+				v += math.Abs(av - b[idx])
+			}
+			return v
+		})
+	case ChebyshevDist:
+		return rowDistKernel(func(a, b []float64) float64 {
 			var v float64
-			switch t {
-			case EuclideanDist:
-				for idx, riv := range ri {
-					v += math.Pow(riv-rj[idx], 2)
+			for idx, av := range a {
+				v = math.Max(v, math.Abs(av-b[idx]))
+			}
+			return v
+		})
+	case CosineDist:
+		return distKernel{
+			prepare: unitNormalize,
+			dist: func(a, b []float64) float64 {
+				var dot float64
+				for idx, av := range a {
+					dot += av * b[idx]
+				}
+				return 1 - dot
+			},
+		}
+	case HammingDist:
+		return rowDistKernel(func(a, b []float64) float64 {
+			var count int
+			for idx, av := range a {
+				if av != b[idx] {
+					count++
 				}
-				v = math.Sqrt(v)
-			default:
-				panic(fmt.Sprintf("Can't calculate distance of invalid type %v", t))
 			}
-			dist.ItemSet(v, i, j)
-			dist.ItemSet(v, j, i)
-		}
+			return float64(count) / float64(len(a))
+		})
+	case SqEuclideanDist:
+		return rowDistKernel(func(a, b []float64) float64 {
+			var v float64
+			for idx, av := range a {
+				v += (av - b[idx]) * (av - b[idx])
+			}
+			return v
+		})
 	}
-	return dist
+	if f := registeredDist(t); f != nil {
+		return rowDistKernel(f)
+	}
+	panic(fmt.Sprintf("Can't calculate distance of invalid type %v", t))
+}
+
+// Get a kernel which compares rows directly, without preparing them
+func rowDistKernel(dist func(a, b []float64) float64) distKernel {
+	return distKernel{
+		prepare: func(row []float64) []float64 { return row },
+		dist:    dist,
+	}
+}
+
+// Get a copy of a vector scaled to unit length, or the vector itself if it is
+// all zeros
+func unitNormalize(vec []float64) []float64 {
+	var norm float64
+	for _, v := range vec {
+		norm += v * v
+	}
+	if norm == 0 {
+		return vec
+	}
+	norm = math.Sqrt(norm)
+	result := make([]float64, len(vec))
+	for idx, v := range vec {
+		result[idx] = v / norm
+	}
+	return result
 }
 
 // Return the element-wise quotient of this array and one or more others.
 // This function defines 0 / 0 = 0, so it's useful for sparse arrays.
 func Div(array NDArray, others ...NDArray) NDArray {
+	debugCheck("Div", array, others...)
 	sh := array.Shape()
 	for _, o := range others {
 		sh2 := o.Shape()
-		if len(sh2) != len(sh) {
-			panic(fmt.Sprintf("Can't divide arrays with shapes %v and %v", sh, sh2))
-		}
-		for i := range sh {
-			if sh[i] != sh2[i] {
-				panic(fmt.Sprintf("Can't divide arrays with shapes %v and %v", sh, sh2))
-			}
-		}
+		checkSameShape("Div", sh2, sh)
 	}
 
 	result := array.Copy()
@@ -328,17 +557,18 @@ func Div(array NDArray, others ...NDArray) NDArray {
 	return result
 }
 
-// Returns true if and only if all elements in the two arrays are equal
+// Returns true if and only if all elements in the two arrays are equal. The
+// comparison is over the logical contents of the arrays, so arrays with
+// different storage formats can be equal.
 func Equal(array, other NDArray) bool {
-	sh1 := array.Shape()
-	sh2 := other.Shape()
-	if len(sh1) != len(sh2) {
+	debugCheck("Equal", array, other)
+	if !shapeMatches(array.Shape(), other.Shape()) {
 		return false
 	}
-	for d := 0; d < len(sh1); d++ {
-		if sh1[d] != sh2[d] {
-			return false
-		}
+
+	// Compare sparse arrays by their nonzero elements
+	if array.Sparsity() != DenseArray && other.Sparsity() != DenseArray {
+		return containsNonzero(array, other) && containsNonzero(other, array)
 	}
 
 	size := array.Size()
@@ -350,6 +580,13 @@ func Equal(array, other NDArray) bool {
 	return true
 }
 
+// Returns true if every nonzero element of array has the same value in other
+func containsNonzero(array, other NDArray) bool {
+	return array.VisitNonzero(func(pos []int, value float64) bool {
+		return value == 0 || other.Item(pos...) == value
+	})
+}
+
 // Set all array elements to the given value
 func Fill(array NDArray, value float64) {
 	if array.Sparsity() != DenseArray {
@@ -418,7 +655,18 @@ func ItemSub(array NDArray, value float64) NDArray {
 // be aligned correctly for multiplication.
 // If A is m x p and B is p x n, then C = A.MProd(B) is the m x n matrix
 // with C[i, j] = \sum_{k=1}^p A[i,k] * B[k,j].
+//
+// Sparse matrices are never made dense. A product with a sparse diagonal
+// matrix keeps the format of the other matrix. Other products of two sparse
+// matrices are sparse coo matrices if both are coo, and CSR matrices
+// otherwise, or CSC if both are CSC. The product of a sparse and a dense
+// matrix is dense, and takes time proportional to the number of nonzero
+// values times the size of the other dimension.
 func MProd(array Matrix, others ...Matrix) Matrix {
+	debugCheck("MProd", array)
+	for _, o := range others {
+		debugCheck("MProd", o)
+	}
 	if len(others) < 1 {
 		return array.Copy().M()
 	}
@@ -432,73 +680,139 @@ func MProd(array Matrix, others ...Matrix) Matrix {
 		rightSh := right.Shape()
 		rightSp := right.Sparsity()
 		if leftSh[1] != rightSh[0] {
-			panic(fmt.Sprintf("Can't MProd a %dx%d to a %dx%d array; inner dimensions must match", leftSh[0], leftSh[1], rightSh[0], rightSh[1]))
+			panic(ErrShapeMismatch{Op: "MProd", Got: rightSh, Want: []int{leftSh[1], -1}})
 		}
 
-		if leftSp == SparseDiagMatrix {
+		if lp, ok := left.(*Permutation); ok {
+			if rp, ok := right.(*Permutation); ok {
+				result = lp.Compose(rp)
+			} else {
+				result = PermuteRows(right, lp.perm)
+			}
+
+		} else if rp, ok := right.(*Permutation); ok {
+			result = PermuteCols(left, rp.inv)
+
+		} else if leftSp == SparseDiagMatrix {
 			lDiag := left.Diag().Array()
 			switch rightSp {
 			case SparseDiagMatrix:
 				rDiag := right.Diag().Array()
-				resDiag := make([]float64, len(lDiag))
-				for idx, v := range lDiag {
-					resDiag[idx] = v * rDiag[idx]
+				resDiag := make([]float64, min(len(lDiag), len(rDiag)))
+				for idx := range resDiag {
+					resDiag[idx] = lDiag[idx] * rDiag[idx]
+				}
+				result = SparseDiag(leftSh[0], rightSh[1], resDiag...)
+			case DenseArray:
+				// Row i of the result is row i of right, scaled by lDiag[i]
+				result = Dense(leftSh[0], rightSh[1]).M()
+				resArr := result.Array()
+				for i, v := range lDiag {
+					row := right.Row(i)
+					for j := range row {
+						resArr[i*rightSh[1]+j] = v * row[j]
+					}
 				}
-				result = Diag(resDiag...)
-			case SparseCooMatrix:
+			default:
 				result = SparseCoo(leftSh[0], rightSh[1])
 				spRes := result.(*sparseCooF64Matrix)
 				right.VisitNonzero(func(pos []int, value float64) bool {
-					spRes.values[pos[0]][pos[1]] += lDiag[pos[0]] * value
+					if pos[0] < len(lDiag) {
+						if v := lDiag[pos[0]] * value; v != 0 {
+							spRes.values[pos[0]][pos[1]] = v
+						}
+					}
 					return true
 				})
-			default:
-				result = Dense(leftSh[0], rightSh[1]).M()
-				resArr := result.Array()
-				rArr := right.Array()
-				for i := 0; i < leftSh[0]; i++ {
-					for j := 0; j < rightSh[1]; j++ {
-						resArr[i*rightSh[1]+j] = lDiag[i] * rArr[i*rightSh[1]+j]
-					}
-				}
+				result = compressAs(result, rightSp).M()
 			}
 
 		} else if leftSp == SparseCooMatrix && rightSp == SparseCooMatrix {
 			result = SparseCoo(leftSh[0], rightSh[1])
 			spRes := result.(*sparseCooF64Matrix)
-			spRight := right.(*sparseCooF64Matrix)
+			rightRows := make([]map[int]float64, rightSh[0])
+			right.VisitNonzero(func(pos []int, value float64) bool {
+				if rightRows[pos[0]] == nil {
+					rightRows[pos[0]] = make(map[int]float64)
+				}
+				rightRows[pos[0]][pos[1]] = value
+				return true
+			})
 			left.VisitNonzero(func(pos []int, value float64) bool {
-				for j := 0; j < rightSh[1]; j++ {
-					spRes.values[pos[0]][j] += value * spRight.values[pos[1]][j]
+				resRow := spRes.values[pos[0]]
+				for j, rValue := range rightRows[pos[1]] {
+					if v := resRow[j] + value*rValue; v != 0 {
+						resRow[j] = v
+					} else {
+						delete(resRow, j)
+					}
 				}
 				return true
 			})
 
 		} else if rightSp == SparseDiagMatrix {
 			rDiag := right.Diag().Array()
-			if leftSp == SparseCooMatrix {
-				resArr := make([]float64, leftSh[0]*rightSh[1])
+			if leftSp != DenseArray {
+				result = SparseCoo(leftSh[0], rightSh[1])
+				spRes := result.(*sparseCooF64Matrix)
 				left.VisitNonzero(func(pos []int, value float64) bool {
-					resArr[pos[0]*rightSh[1]+pos[1]] += value * rDiag[pos[1]]
+					if pos[1] < len(rDiag) {
+						if v := value * rDiag[pos[1]]; v != 0 {
+							spRes.values[pos[0]][pos[1]] = v
+						}
+					}
 					return true
 				})
-				result = SparseCoo(leftSh[0], rightSh[1])
-				for idx, v := range resArr {
-					if v != 0 {
-						result.FlatItemSet(v, idx)
-					}
-				}
+				result = compressAs(result, leftSp).M()
 			} else {
+				// Column j of the result is column j of left, scaled by rDiag[j]
 				result = Dense(leftSh[0], rightSh[1]).M()
 				resArr := result.Array()
-				lArr := left.Array()
 				for i := 0; i < leftSh[0]; i++ {
-					for j := 0; j < rightSh[1]; j++ {
-						resArr[i*rightSh[1]+j] = lArr[i*leftSh[1]+j] * rDiag[j]
+					row := left.Row(i)
+					for j, v := range rDiag {
+						resArr[i*rightSh[1]+j] = row[j] * v
 					}
 				}
 			}
 
+		} else if leftSp == SparseCSCMatrix && rightSp == SparseCSCMatrix {
+			// The transposes are CSR matrices sharing storage, and
+			// (AB)^T = B^T A^T
+			result = csrMProd(asCSR(right.T()), asCSR(left.T())).T()
+
+		} else if leftSp != DenseArray && rightSp != DenseArray {
+			result = csrMProd(asCSR(left), asCSR(right))
+
+		} else if leftSp != DenseArray {
+			// Add each nonzero value times the matching row of right to its
+			// row of the result
+			result = Dense(leftSh[0], rightSh[1]).M()
+			resArr := result.Array()
+			rArr := right.Array()
+			n := rightSh[1]
+			left.VisitNonzero(func(pos []int, value float64) bool {
+				resRow := resArr[pos[0]*n : (pos[0]+1)*n]
+				for j, rValue := range rArr[pos[1]*n : (pos[1]+1)*n] {
+					resRow[j] += value * rValue
+				}
+				return true
+			})
+
+		} else if rightSp != DenseArray {
+			// Add each nonzero value times the matching column of left to its
+			// column of the result
+			result = Dense(leftSh[0], rightSh[1]).M()
+			resArr := result.Array()
+			lArr := left.Array()
+			n, p := rightSh[1], leftSh[1]
+			right.VisitNonzero(func(pos []int, value float64) bool {
+				for i := 0; i < leftSh[0]; i++ {
+					resArr[i*n+pos[1]] += lArr[i*p+pos[0]] * value
+				}
+				return true
+			})
+
 		} else {
 			result = Dense(leftSh[0], rightSh[1]).M()
 			resArr := result.Array()
@@ -568,17 +882,11 @@ func Normalize(array NDArray) NDArray {
 
 // Return the element-wise product of this array and one or more others
 func Prod(array NDArray, others ...NDArray) NDArray {
+	debugCheck("Prod", array, others...)
 	sh := array.Shape()
 	for _, o := range others {
 		sh2 := o.Shape()
-		if len(sh2) != len(sh) {
-			panic(fmt.Sprintf("Can't multiply arrays with shapes %v and %v", sh, sh2))
-		}
-		for i := range sh {
-			if sh[i] != sh2[i] {
-				panic(fmt.Sprintf("Can't multiply arrays with shapes %v and %v", sh, sh2))
-			}
-		}
+		checkSameShape("Prod", sh2, sh)
 	}
 
 	result := array.Copy()
@@ -609,6 +917,7 @@ func Ravel(array NDArray) NDArray {
 // distance from the end of the array, where -1 represents the element just past
 // the end of the array.
 func Slice(array NDArray, from []int, to []int) NDArray {
+	debugCheck("Slice", array)
 	sh := array.Shape()
 	if len(from) != len(sh) || len(to) != len(sh) {
 		panic("Invalid Slice() indices: the arguments should have the same length as the array")
@@ -664,30 +973,17 @@ func Slice(array NDArray, from []int, to []int) NDArray {
 
 // Return the element-wise difference of this array and one or more others
 func Sub(array NDArray, others ...NDArray) NDArray {
+	debugCheck("Sub", array, others...)
 	var result NDArray
-	sp := array.Sparsity()
-	sh := array.Shape()
-	for _, o := range others {
-		switch o.Sparsity() {
-		case DenseArray:
-			sp = DenseArray
-		case SparseCooMatrix:
-			if sp == SparseDiagMatrix {
-				sp = SparseCooMatrix
-			}
-		}
-		sh2 := o.Shape()
-		if len(sh2) != len(sh) {
-			panic(fmt.Sprintf("Can't add arrays with shapes %v and %v", sh, sh2))
-		}
-		for i := range sh {
-			if sh[i] != sh2[i] {
-				panic(fmt.Sprintf("Can't add arrays with shapes %v and %v", sh, sh2))
-			}
-		}
+	sp := sumSparsity("Sub", array, others)
+	if sp == SparseDiagMatrix {
+		return diagSum(array, others, -1)
 	}
+	sh := array.Shape()
 
 	switch sp {
+	case SparseCSRMatrix, SparseCSCMatrix:
+		result = array.M().SparseCoo()
 	case array.Sparsity():
 		result = array.Copy()
 	case DenseArray:
@@ -705,7 +1001,7 @@ func Sub(array NDArray, others ...NDArray) NDArray {
 			return true
 		})
 	}
-	return result
+	return compressAs(result, sp)
 }
 
 // Return the sum of all array elements
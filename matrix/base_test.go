@@ -398,6 +398,102 @@ func TestDist(t *testing.T) {
 				math.Sqrt(8), math.Sqrt(20), 0,
 			})
 		})
+
+		Convey("Correlation distance works", func() {
+			d := M(3, 3,
+				1, 2, 3,
+				3, 2, 1,
+				2, 4, 6,
+			).Dist(CorrelationDist)
+			So(d.Shape(), ShouldResemble, []int{3, 3})
+			So(d.Item(0, 1), ShouldBeBetween, 2-Eps, 2+Eps)
+			So(d.Item(0, 2), ShouldBeBetween, -Eps, Eps)
+			So(d.Item(2, 1), ShouldBeBetween, 2-Eps, 2+Eps)
+			So(d.Item(1, 1), ShouldBeBetween, -Eps, Eps)
+		})
+
+		Convey("Manhattan, Chebyshev and squared Euclidean distances work", func() {
+			So(m.Dist(ManhattanDist).Array(), ShouldResemble, []float64{
+				0, 2, 4,
+				2, 0, 6,
+				4, 6, 0,
+			})
+			So(m.Dist(ChebyshevDist).Array(), ShouldResemble, []float64{
+				0, 2, 2,
+				2, 0, 4,
+				2, 4, 0,
+			})
+			So(m.Dist(SqEuclideanDist).Array(), ShouldResemble, []float64{
+				0, 4, 8,
+				4, 0, 20,
+				8, 20, 0,
+			})
+		})
+
+		Convey("Cosine and Hamming distances work", func() {
+			d := M(3, 2, 1, 0, 0, 2, 3, 3).Dist(CosineDist)
+			So(d.Item(0, 1), ShouldAlmostEqual, 1, 1e-12)
+			So(d.Item(0, 2), ShouldAlmostEqual, 1-math.Sqrt(0.5), 1e-12)
+			So(m.Dist(HammingDist).Array(), ShouldResemble, []float64{
+				0, .5, 1,
+				.5, 0, 1,
+				1, 1, 0,
+			})
+		})
+
+		Convey("Minkowski distances match the special cases", func() {
+			So(m.Dist(MinkowskiDist(1)).Equal(m.Dist(ManhattanDist)), ShouldBeTrue)
+			So(m.Dist(MinkowskiDist(math.Inf(1))).Equal(m.Dist(ChebyshevDist)), ShouldBeTrue)
+			So(MinkowskiDist(3), ShouldEqual, MinkowskiDist(3))
+			So(m.Dist(MinkowskiDist(3)).Item(1, 2), ShouldAlmostEqual, math.Cbrt(4*4*4+2*2*2), 1e-12)
+			So(func() { MinkowskiDist(0.5) }, ShouldPanic)
+		})
+
+		Convey("Custom distances can be registered", func() {
+			custom := RegisterDist(func(a, b []float64) float64 { return math.Abs(a[0] - b[0]) })
+			So(m.Dist(custom).Array(), ShouldResemble, []float64{
+				0, 2, 2,
+				2, 0, 4,
+				2, 4, 0,
+			})
+			So(RegisterDist(func(a, b []float64) float64 { return 0 }), ShouldNotEqual, custom)
+			So(func() { m.Dist(custom + 1000) }, ShouldPanic)
+		})
+	})
+}
+
+func TestDistTo(t *testing.T) {
+	Convey("Given query points and a reference set", t, func() {
+		queries := M(2, 2,
+			0, 0,
+			3, 4)
+		refs := M(3, 2,
+			0, 1,
+			3, 0,
+			6, 8)
+
+		Convey("DistTo gives the cross-distance matrix", func() {
+			d := queries.DistTo(refs, EuclideanDist)
+			So(d.Shape(), ShouldResemble, []int{2, 3})
+			So(d.Array(), ShouldResemble, []float64{
+				1, 3, 10,
+				math.Sqrt(18), 4, 5,
+			})
+		})
+
+		Convey("It agrees with Dist on the stacked points", func() {
+			all := Concat(0, queries, refs).M()
+			for _, dt := range []DistType{EuclideanDist, ManhattanDist, ChebyshevDist, SqEuclideanDist} {
+				want := Slice(all.Dist(dt), []int{0, 2}, []int{2, 5})
+				So(ApproxEqual(queries.SparseCSR().DistTo(refs.SparseCoo(), dt), want.M(), 1e-12, 1e-12), ShouldBeTrue)
+			}
+		})
+
+		Convey("The matrices must have the same number of columns", func() {
+			err := try(func() { queries.DistTo(M(1, 3, 1, 2, 3), EuclideanDist) })
+			So(err, ShouldHaveSameTypeAs, ErrShapeMismatch{})
+			So(func() { queries.DistTo(refs, DistType(-1)) }, ShouldPanic)
+		})
 	})
 }
 
@@ -1452,3 +1548,100 @@ func BenchmarkMProdDiagDiag(b *testing.B) {
 		l.MProd(r)
 	}
 }
+
+func TestAllFiniteAnyNaN(t *testing.T) {
+	var (
+		nan = math.NaN()
+		inf = math.Inf(+1)
+	)
+
+	Convey("Given dense arrays", t, func() {
+		ok := A1(1, 0, -2)
+		hasNaN := A1(1, nan, 2)
+		hasInf := A1(1, -inf, 2)
+
+		Convey("AllFinite works", func() {
+			So(ok.AllFinite(), ShouldBeTrue)
+			So(hasNaN.AllFinite(), ShouldBeFalse)
+			So(hasInf.AllFinite(), ShouldBeFalse)
+			So(AllFinite(hasInf), ShouldBeFalse)
+		})
+
+		Convey("AnyNaN works", func() {
+			So(ok.AnyNaN(), ShouldBeFalse)
+			So(hasNaN.AnyNaN(), ShouldBeTrue)
+			So(hasInf.AnyNaN(), ShouldBeFalse)
+			So(AnyNaN(hasNaN), ShouldBeTrue)
+		})
+	})
+
+	Convey("Given sparse arrays", t, func() {
+		coo := SparseCoo(3, 3)
+		coo.ItemSet(inf, 1, 2)
+		diag := Diag(1, nan, 3)
+
+		Convey("AllFinite works", func() {
+			So(coo.AllFinite(), ShouldBeFalse)
+			So(diag.AllFinite(), ShouldBeFalse)
+			So(SparseCoo(3, 3).AllFinite(), ShouldBeTrue)
+			So(Eye(3).AllFinite(), ShouldBeTrue)
+		})
+
+		Convey("AnyNaN works", func() {
+			So(coo.AnyNaN(), ShouldBeFalse)
+			So(diag.AnyNaN(), ShouldBeTrue)
+		})
+	})
+}
+
+func TestAllFAxisAnyFAxis(t *testing.T) {
+	Convey("Given a matrix", t, func() {
+		m := M(3, 3,
+			1, 0, 3,
+			1, 0, -1,
+			1, 0, 2)
+		pos := func(v float64) bool { return v > 0 }
+
+		Convey("AllFAxis works along both axes", func() {
+			So(AllFAxis(m, pos, 0), ShouldResemble, []bool{true, false, false})
+			So(AllFAxis(m, pos, 1), ShouldResemble, []bool{false, false, false})
+			So(AllFAxis(m.SparseCoo(), math.IsNaN, 0), ShouldResemble, []bool{false, false, false})
+		})
+
+		Convey("AnyFAxis works along both axes", func() {
+			So(AnyFAxis(m, pos, 0), ShouldResemble, []bool{true, false, true})
+			So(AnyFAxis(m, pos, 1), ShouldResemble, []bool{true, true, true})
+		})
+
+		Convey("Invalid axes panic", func() {
+			So(func() { AnyFAxis(m, pos, 2) }, ShouldPanic)
+			So(func() { AllFAxis(m, pos, -1) }, ShouldPanic)
+		})
+	})
+}
+
+func TestCountNonzeroAxis(t *testing.T) {
+	Convey("Given a dense matrix", t, func() {
+		m := M(3, 4,
+			1, 0, 0, 2,
+			0, 0, 0, 3,
+			4, 0, 5, 6)
+
+		Convey("CountNonzeroAxis works along both axes", func() {
+			So(CountNonzeroAxis(m, 0), ShouldResemble, []int{2, 0, 1, 3})
+			So(CountNonzeroAxis(m, 1), ShouldResemble, []int{2, 1, 3})
+			So(CountNonzeroAxis(m.T(), 0), ShouldResemble, []int{2, 1, 3})
+		})
+
+		Convey("Sparse representations give the same counts", func() {
+			coo := m.SparseCoo()
+			So(CountNonzeroAxis(coo, 0), ShouldResemble, []int{2, 0, 1, 3})
+			So(CountNonzeroAxis(coo.T(), 1), ShouldResemble, []int{2, 0, 1, 3})
+			So(CountNonzeroAxis(Diag(1, 0, 2), 1), ShouldResemble, []int{1, 0, 1})
+		})
+
+		Convey("Invalid axes panic", func() {
+			So(func() { CountNonzeroAxis(m, 2) }, ShouldPanic)
+		})
+	})
+}
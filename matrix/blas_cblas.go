@@ -0,0 +1,16 @@
+//go:build cblas && cgo
+
+package matrix
+
+import (
+	"gonum.org/v1/gonum/blas/blas64"
+	"gonum.org/v1/netlib/blas/netlib"
+)
+
+// The name of the BLAS implementation used for linear algebra. This build
+// uses a C BLAS library through cgo.
+const blasImplementation = "netlib"
+
+func init() {
+	blas64.Use(netlib.Implementation{})
+}
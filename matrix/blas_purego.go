@@ -0,0 +1,8 @@
+//go:build !cblas || !cgo
+
+package matrix
+
+// The name of the BLAS implementation used for linear algebra. This build
+// uses gonum's pure Go implementation, which works without cgo and under
+// GOOS=js GOARCH=wasm.
+const blasImplementation = "gonum"
@@ -0,0 +1,18 @@
+//go:build !cblas || !cgo
+
+package matrix
+
+import (
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+func TestPureGoBuild(t *testing.T) {
+	Convey("Without the cblas tag, linear algebra runs in pure Go", t, func() {
+		So(BLASImplementation(), ShouldEqual, "gonum")
+
+		x := Solve(M(2, 2, 2, 1, 1, 3), M(2, 1, 3, 5))
+		So(x.Item(0, 0), ShouldBeBetween, 0.8-Eps, 0.8+Eps)
+		So(x.Item(1, 0), ShouldBeBetween, 1.4-Eps, 1.4+Eps)
+	})
+}
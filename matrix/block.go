@@ -0,0 +1,101 @@
+package matrix
+
+import (
+	"fmt"
+)
+
+// Create a block-diagonal matrix, with the blocks along its diagonal and zeros
+// elsewhere. The blocks needn't be square: the result has as many rows and
+// columns as the blocks together. The result is a sparse coo matrix.
+func BlockDiag(blocks ...Matrix) Matrix {
+	var rows, cols int
+	for _, b := range blocks {
+		debugCheck("BlockDiag", b)
+		rows += b.Rows()
+		cols += b.Cols()
+	}
+	result := SparseCoo(rows, cols)
+	var row, col int
+	for _, b := range blocks {
+		result.SetSubmatrix(row, col, b)
+		row += b.Rows()
+		col += b.Cols()
+	}
+	return result
+}
+
+// Assemble a matrix from a grid of blocks, given row by row. For example, the
+// saddle-point system [H A'; A 0] is
+//
+//	Block([][]Matrix{{h, a.T()}, {a, nil}})
+//
+// Blocks in the same row of the grid must have the same number of rows, and
+// blocks in the same column the same number of columns. A nil block is a
+// block of zeros, whose size is taken from the other blocks in its row and
+// column. The result is dense if any block is dense, and sparse coo
+// otherwise.
+func Block(grid [][]Matrix) Matrix {
+	if len(grid) == 0 {
+		return SparseCoo(0, 0)
+	}
+	heights := make([]int, len(grid))
+	widths := make([]int, len(grid[0]))
+	for i := range heights {
+		heights[i] = -1
+	}
+	for j := range widths {
+		widths[j] = -1
+	}
+	dense := false
+	for i, row := range grid {
+		if len(row) != len(widths) {
+			panic(fmt.Sprintf("Block: row %d of the grid has %d blocks, but row 0 has %d", i, len(row), len(widths)))
+		}
+		for j, b := range row {
+			if b == nil {
+				continue
+			}
+			debugCheck("Block", b)
+			if b.Sparsity() == DenseArray {
+				dense = true
+			}
+			if (heights[i] >= 0 && b.Rows() != heights[i]) || (widths[j] >= 0 && b.Cols() != widths[j]) {
+				panic(ErrShapeMismatch{Op: "Block", Got: b.Shape(), Want: []int{heights[i], widths[j]}})
+			}
+			heights[i], widths[j] = b.Rows(), b.Cols()
+		}
+	}
+
+	var rows, cols int
+	for i, h := range heights {
+		if h < 0 {
+			panic(fmt.Sprintf("Block: can't find the height of row %d of the grid, since all its blocks are nil", i))
+		}
+		rows += h
+	}
+	for j, w := range widths {
+		if w < 0 {
+			panic(fmt.Sprintf("Block: can't find the width of column %d of the grid, since all its blocks are nil", j))
+		}
+		cols += w
+	}
+
+	var result Matrix
+	if dense {
+		result = Dense(rows, cols).M()
+	} else {
+		result = SparseCoo(rows, cols)
+	}
+	row := 0
+	for i, blocks := range grid {
+		col := 0
+		for j, b := range blocks {
+			if b != nil {
+				result.SetSubmatrix(row, col, b)
+			}
+			col += widths[j]
+		}
+		row += heights[i]
+	}
+	return result
+}
@@ -0,0 +1,57 @@
+package matrix
+
+import (
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+func TestBlockDiag(t *testing.T) {
+	Convey("BlockDiag places its blocks along the diagonal", t, func() {
+		m := BlockDiag(M(1, 2, 1, 2), Diag(3, 4), SparseCoo(2, 1, 5, 0))
+		So(m.Sparsity(), ShouldEqual, SparseCooMatrix)
+		So(m.Equal(M(5, 5,
+			1, 2, 0, 0, 0,
+			0, 0, 3, 0, 0,
+			0, 0, 0, 4, 0,
+			0, 0, 0, 0, 5,
+			0, 0, 0, 0, 0)), ShouldBeTrue)
+	})
+
+	Convey("BlockDiag with no blocks is empty", t, func() {
+		So(BlockDiag().Shape(), ShouldResemble, []int{0, 0})
+	})
+}
+
+func TestBlock(t *testing.T) {
+	Convey("Given blocks which fit together", t, func() {
+		h := M(2, 2, 1, 2, 2, 3)
+		a := SparseCoo(1, 2, 4, 5)
+
+		Convey("Block assembles them into one matrix", func() {
+			m := Block([][]Matrix{{h, a.T()}, {a, nil}})
+			So(m.Sparsity(), ShouldEqual, DenseArray)
+			So(m.Equal(M(3, 3,
+				1, 2, 4,
+				2, 3, 5,
+				4, 5, 0)), ShouldBeTrue)
+		})
+
+		Convey("Block gives a sparse result for sparse blocks", func() {
+			m := Block([][]Matrix{{Eye(2), nil}, {a, Eye(1)}})
+			So(m.Sparsity(), ShouldEqual, SparseCooMatrix)
+			So(m.Equal(M(3, 3,
+				1, 0, 0,
+				0, 1, 0,
+				4, 5, 1)), ShouldBeTrue)
+		})
+
+		Convey("Block panics if the blocks don't fit", func() {
+			So(try(func() { Block([][]Matrix{{h, a}}) }), ShouldResemble,
+				ErrShapeMismatch{Op: "Block", Got: []int{1, 2}, Want: []int{2, -1}})
+			So(try(func() { Block([][]Matrix{{h}, {a.T()}}) }), ShouldResemble,
+				ErrShapeMismatch{Op: "Block", Got: []int{2, 1}, Want: []int{-1, 2}})
+			So(func() { Block([][]Matrix{{h, nil}, {a}}) }, ShouldPanic)
+			So(func() { Block([][]Matrix{{h, nil}}) }, ShouldPanic)
+		})
+	})
+}
@@ -0,0 +1,189 @@
+package matrix
+
+// A read-only matrix whose rows (or columns) all equal the same vector,
+// created by RowBroadcast() or ColBroadcast()
+type broadcastMatrix struct {
+	lazyMatrix
+	values     []float64
+	rows, cols int
+	byRow      bool
+}
+
+// Get a rows x len(v) matrix in which every row is v, without storing the
+// repeated rows. Use it to combine a per-column vector with a matrix
+// element-wise; for example, to center the columns of x:
+//
+//	centered := Sub(x, RowBroadcast(means, x.Rows()))
+//
+// The result is read-only, as for Freeze(). Element access, Row(), Col() and
+// the element-wise operations take no extra memory; methods which need the
+// full matrix, such as Array() and MProd(), create a dense copy once. v is
+// copied.
+func RowBroadcast(v []float64, rows int) Matrix {
+	if rows < 0 {
+		panic(ErrIndexOutOfRange{Op: "RowBroadcast", Index: []int{rows}, Shape: []int{rows}})
+	}
+	return newBroadcast(append([]float64(nil), v...), rows, len(v), true)
+}
+
+// Get a len(v) x cols matrix in which every column is v, without storing the
+// repeated columns. It is stored as for RowBroadcast().
+func ColBroadcast(v []float64, cols int) Matrix {
+	if cols < 0 {
+		panic(ErrIndexOutOfRange{Op: "ColBroadcast", Index: []int{cols}, Shape: []int{cols}})
+	}
+	return newBroadcast(append([]float64(nil), v...), len(v), cols, false)
+}
+
+// Create a broadcast matrix, without copying values
+func newBroadcast(values []float64, rows, cols int, byRow bool) *broadcastMatrix {
+	b := &broadcastMatrix{values: values, rows: rows, cols: cols, byRow: byRow}
+	b.build = func() Matrix {
+		return FromFunc(rows, cols, b.item)
+	}
+	return b
+}
+
+// Get the element at a valid position
+func (b *broadcastMatrix) item(row, col int) float64 {
+	if b.byRow {
+		return b.values[col]
+	}
+	return b.values[row]
+}
+
+// Get a copy of a column
+func (b *broadcastMatrix) Col(col int) []float64 {
+	if col < 0 || col >= b.cols {
+		panic(ErrIndexOutOfRange{Op: "Col", Index: []int{col}, Shape: []int{b.cols}})
+	}
+	if !b.byRow {
+		return append([]float64(nil), b.values...)
+	}
+	result := make([]float64, b.rows)
+	for i := range result {
+		result[i] = b.values[col]
+	}
+	return result
+}
+
+// Get the number of columns
+func (b *broadcastMatrix) Cols() int {
+	return b.cols
+}
+
+// Counts the nonzero elements
+func (b *broadcastMatrix) CountNonzero() int {
+	count := 0
+	for _, v := range b.values {
+		if v != 0 {
+			count++
+		}
+	}
+	if b.byRow {
+		return count * b.rows
+	}
+	return count * b.cols
+}
+
+// Get an array element in a flattened version of this array
+func (b *broadcastMatrix) FlatItem(index int) float64 {
+	if index < 0 || index >= b.rows*b.cols {
+		panic(ErrIndexOutOfRange{Op: "FlatItem", Index: []int{index}, Shape: []int{b.rows * b.cols}})
+	}
+	return b.item(index/b.cols, index%b.cols)
+}
+
+// Get an array element
+func (b *broadcastMatrix) Item(index ...int) float64 {
+	if len(index) != 2 || index[0] < 0 || index[0] >= b.rows || index[1] < 0 || index[1] >= b.cols {
+		panic(ErrIndexOutOfRange{Op: "Item", Index: index, Shape: b.Shape()})
+	}
+	return b.item(index[0], index[1])
+}
+
+// A broadcast matrix is already a matrix
+func (b *broadcastMatrix) M() Matrix {
+	return b
+}
+
+// Get the number of array dimensions
+func (b *broadcastMatrix) NDim() int {
+	return 2
+}
+
+// Get a copy of a row
+func (b *broadcastMatrix) Row(row int) []float64 {
+	if row < 0 || row >= b.rows {
+		panic(ErrIndexOutOfRange{Op: "Row", Index: []int{row}, Shape: []int{b.rows}})
+	}
+	if b.byRow {
+		return append([]float64(nil), b.values...)
+	}
+	result := make([]float64, b.cols)
+	for i := range result {
+		result[i] = b.values[row]
+	}
+	return result
+}
+
+// Get the number of rows
+func (b *broadcastMatrix) Rows() int {
+	return b.rows
+}
+
+// Get the array dimensions
+func (b *broadcastMatrix) Shape() []int {
+	return []int{b.rows, b.cols}
+}
+
+// Get the number of elements
+func (b *broadcastMatrix) Size() int {
+	return b.rows * b.cols
+}
+
+// Broadcast matrices behave as dense matrices
+func (b *broadcastMatrix) Sparsity() ArraySparsity {
+	return DenseArray
+}
+
+// Get the sum of the elements
+func (b *broadcastMatrix) Sum() float64 {
+	var sum float64
+	for _, v := range b.values {
+		sum += v
+	}
+	if b.byRow {
+		return sum * float64(b.rows)
+	}
+	return sum * float64(b.cols)
+}
+
+// Get the transpose, which broadcasts the same vector along the other axis
+func (b *broadcastMatrix) T() Matrix {
+	return newBroadcast(b.values, b.cols, b.rows, !b.byRow)
+}
+
+// Get the matrix itself, since it can't be modified
+func (b *broadcastMatrix) View() NDArray {
+	return b
+}
+
+// Visit all matrix elements, in row-major order
+func (b *broadcastMatrix) Visit(f func(pos []int, value float64) bool) bool {
+	for row := 0; row < b.rows; row++ {
+		for col := 0; col < b.cols; col++ {
+			if !f([]int{row, col}, b.item(row, col)) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Visit just the nonzero elements, in row-major order
+func (b *broadcastMatrix) VisitNonzero(f func(pos []int, value float64) bool) bool {
+	return b.Visit(func(pos []int, value float64) bool {
+		return value == 0 || f(pos, value)
+	})
+}
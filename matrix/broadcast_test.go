@@ -0,0 +1,48 @@
+package matrix
+
+import (
+	"errors"
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+func TestBroadcast(t *testing.T) {
+	Convey("Given broadcast matrices", t, func() {
+		v := []float64{1, 0, 3}
+		rows := RowBroadcast(v, 2)
+		cols := ColBroadcast(v, 2)
+
+		Convey("RowBroadcast repeats v in every row", func() {
+			So(rows.Shape(), ShouldResemble, []int{2, 3})
+			So(rows.Equal(M(2, 3, 1, 0, 3, 1, 0, 3)), ShouldBeTrue)
+			So(rows.Row(1), ShouldResemble, []float64{1, 0, 3})
+			So(rows.Col(2), ShouldResemble, []float64{3, 3})
+			So(rows.FlatItem(5), ShouldEqual, 3)
+			So(rows.Sum(), ShouldEqual, 8)
+			So(rows.CountNonzero(), ShouldEqual, 4)
+			So(CheckInvariants(rows), ShouldBeNil)
+		})
+
+		Convey("ColBroadcast repeats v in every column", func() {
+			So(cols.Equal(M(3, 2, 1, 1, 0, 0, 3, 3)), ShouldBeTrue)
+			So(cols.T().Equal(rows), ShouldBeTrue)
+			So(cols.Row(2), ShouldResemble, []float64{3, 3})
+			So(cols.Col(0), ShouldResemble, []float64{1, 0, 3})
+		})
+
+		Convey("Element-wise operations combine them with other matrices", func() {
+			x := M(2, 3, 2, 4, 6, 8, 10, 12)
+			means := []float64{5, 7, 9}
+			So(Sub(x, RowBroadcast(means, 2)).Equal(M(2, 3, -3, -3, -3, 3, 3, 3)), ShouldBeTrue)
+			So(Prod(x, ColBroadcast([]float64{1, 2}, 3)).Equal(M(2, 3, 2, 4, 6, 16, 20, 24)), ShouldBeTrue)
+			So(rows.MProd(cols).Equal(M(2, 2, 10, 10, 10, 10)), ShouldBeTrue)
+		})
+
+		Convey("They don't share or allow changes to v", func() {
+			v[0] = 100
+			So(rows.Item(0, 0), ShouldEqual, 1)
+			So(errors.Is(TryItemSet(rows, 5, 0, 0), ErrFrozen), ShouldBeTrue)
+			So(func() { rows.Item(2, 0) }, ShouldPanic)
+		})
+	})
+}
@@ -0,0 +1,126 @@
+package matrix
+
+// Matrices built with a density (the fraction of nonzero elements) below this
+// value are stored in sparse coo format by Builder.Matrix().
+const BuilderSparseDensity = 0.1
+
+// A Builder constructs a matrix incrementally, for when its size isn't known
+// in advance. The matrix grows as rows, columns and elements are added; new
+// elements are zero. Only the nonzero elements are stored while building.
+type Builder struct {
+	rows, cols int
+	values     []map[int]float64
+	nnz        int
+}
+
+// Create a builder for a matrix with the given initial size
+func NewBuilder(rows, cols int) *Builder {
+	b := &Builder{}
+	b.grow(rows, cols)
+	return b
+}
+
+// Get the number of rows added so far
+func (b *Builder) Rows() int {
+	return b.rows
+}
+
+// Get the number of columns added so far
+func (b *Builder) Cols() int {
+	return b.cols
+}
+
+// Add a row after the last row. If values is longer than the matrix is wide,
+// the matrix grows to fit, and if it's shorter the rest of the row is zero.
+func (b *Builder) AppendRow(values ...float64) *Builder {
+	row := b.rows
+	b.grow(row+1, len(values))
+	for col, v := range values {
+		b.Set(row, col, v)
+	}
+	return b
+}
+
+// Add a column after the last column. If values is longer than the matrix is
+// tall, the matrix grows to fit, and if it's shorter the rest of the column
+// is zero.
+func (b *Builder) AppendCol(values ...float64) *Builder {
+	col := b.cols
+	b.grow(len(values), col+1)
+	for row, v := range values {
+		b.Set(row, col, v)
+	}
+	return b
+}
+
+// Set an element, growing the matrix if the position is beyond its bounds
+func (b *Builder) Set(row, col int, value float64) *Builder {
+	if row < 0 || col < 0 {
+		panic(ErrIndexOutOfRange{Op: "Builder.Set", Index: []int{row, col}, Shape: []int{b.rows, b.cols}})
+	}
+	b.grow(row+1, col+1)
+	_, had := b.values[row][col]
+	if value != 0 {
+		b.values[row][col] = value
+		if !had {
+			b.nnz++
+		}
+	} else if had {
+		delete(b.values[row], col)
+		b.nnz--
+	}
+	return b
+}
+
+// Get the fraction of elements which are nonzero
+func (b *Builder) Density() float64 {
+	if b.rows == 0 || b.cols == 0 {
+		return 0
+	}
+	return float64(b.nnz) / float64(b.rows*b.cols)
+}
+
+// Get the matrix, stored in sparse coo format if its density is below
+// BuilderSparseDensity or in dense format otherwise
+func (b *Builder) Matrix() Matrix {
+	if b.Density() < BuilderSparseDensity {
+		return b.SparseCoo()
+	}
+	return b.Dense()
+}
+
+// Get the matrix in dense format
+func (b *Builder) Dense() Matrix {
+	array := &denseF64Array{
+		shape: []int{b.rows, b.cols},
+		array: make([]float64, b.rows*b.cols),
+	}
+	for row, values := range b.values {
+		for col, v := range values {
+			array.array[row*b.cols+col] = v
+		}
+	}
+	return array
+}
+
+// Get the matrix in sparse coo format
+func (b *Builder) SparseCoo() Matrix {
+	array := SparseCoo(b.rows, b.cols)
+	for row, values := range b.values {
+		for col, v := range values {
+			array.ItemSet(v, row, col)
+		}
+	}
+	return array
+}
+
+// Grow the matrix to at least the given size
+func (b *Builder) grow(rows, cols int) {
+	for b.rows < rows {
+		b.values = append(b.values, make(map[int]float64))
+		b.rows++
+	}
+	if cols > b.cols {
+		b.cols = cols
+	}
+}
@@ -0,0 +1,68 @@
+package matrix
+
+import (
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+func TestBuilder(t *testing.T) {
+	Convey("Given an empty builder", t, func() {
+		b := NewBuilder(0, 0)
+		So(b.Rows(), ShouldEqual, 0)
+		So(b.Cols(), ShouldEqual, 0)
+
+		Convey("Appending rows grows the matrix", func() {
+			b.AppendRow(1, 2).AppendRow(3, 4, 5).AppendRow()
+			So(b.Rows(), ShouldEqual, 3)
+			So(b.Cols(), ShouldEqual, 3)
+			So(b.Matrix().Equal(M(3, 3,
+				1, 2, 0,
+				3, 4, 5,
+				0, 0, 0)), ShouldBeTrue)
+		})
+
+		Convey("Appending columns grows the matrix", func() {
+			b.AppendCol(1, 2).AppendCol(3)
+			So(b.Rows(), ShouldEqual, 2)
+			So(b.Cols(), ShouldEqual, 2)
+			So(b.Matrix().Equal(M(2, 2,
+				1, 3,
+				2, 0)), ShouldBeTrue)
+		})
+
+		Convey("Set grows the matrix to fit", func() {
+			b.Set(2, 4, 7)
+			So(b.Rows(), ShouldEqual, 3)
+			So(b.Cols(), ShouldEqual, 5)
+			So(b.Matrix().Item(2, 4), ShouldEqual, 7)
+			So(func() { b.Set(-1, 0, 1) }, ShouldPanic)
+		})
+
+		Convey("Setting zero removes an element", func() {
+			b.Set(1, 1, 2).Set(1, 1, 0)
+			So(b.Density(), ShouldEqual, 0)
+			So(b.Rows(), ShouldEqual, 2)
+		})
+	})
+
+	Convey("Given builders of differing density", t, func() {
+		Convey("Dense contents give a dense matrix", func() {
+			b := NewBuilder(0, 2).AppendRow(1, 2).AppendRow(3, 4)
+			So(b.Density(), ShouldEqual, 1)
+			m := b.Matrix()
+			So(m.Sparsity(), ShouldEqual, DenseArray)
+			So(m.Equal(M(2, 2, 1, 2, 3, 4)), ShouldBeTrue)
+		})
+
+		Convey("Sparse contents give a sparse coo matrix", func() {
+			b := NewBuilder(100, 100).Set(3, 7, 1).Set(99, 0, 2)
+			m := b.Matrix()
+			So(m.Sparsity(), ShouldEqual, SparseCooMatrix)
+			So(m.Shape(), ShouldResemble, []int{100, 100})
+			So(m.Item(3, 7), ShouldEqual, 1)
+			So(m.Item(99, 0), ShouldEqual, 2)
+			So(m.CountNonzero(), ShouldEqual, 2)
+			So(b.Dense().Equal(m), ShouldBeTrue)
+		})
+	})
+}
@@ -0,0 +1,132 @@
+package matrix
+
+// A MatrixChain applies a sequence of operations to a matrix, stopping at the
+// first failure. Create one with Chain(); each method returns the chain, so
+// steps can be written one after another:
+//
+//	x, err := Chain(a).MProd(b).Add(c).Inverse().Result()
+//
+// Operations which would panic, such as multiplying matrices with mismatched
+// shapes, instead record the error, and later operations do nothing. The
+// recorded error is one of the package's typed errors, such as
+// ErrShapeMismatch or ErrSingular.
+type MatrixChain struct {
+	m   Matrix
+	err error
+}
+
+// Start a chain of operations on m
+func Chain(m Matrix) *MatrixChain {
+	return &MatrixChain{m: m}
+}
+
+// Get the result of the operations, or the first error
+func (c *MatrixChain) Result() (Matrix, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	return c.m, nil
+}
+
+// Get the first error, or nil if all operations succeeded
+func (c *MatrixChain) Err() error {
+	return c.err
+}
+
+// Apply an operation to the matrix, unless an earlier operation failed
+func (c *MatrixChain) Then(f func(m Matrix) (Matrix, error)) *MatrixChain {
+	if c.err != nil {
+		return c
+	}
+	var (
+		result Matrix
+		err    error
+	)
+	if panicErr := try(func() { result, err = f(c.m) }); panicErr != nil {
+		err = panicErr
+	}
+	if err != nil {
+		c.err = err
+	} else {
+		c.m = result
+	}
+	return c
+}
+
+// Apply an operation which can't fail on its own, except by panicking
+func (c *MatrixChain) then(f func(m Matrix) Matrix) *MatrixChain {
+	return c.Then(func(m Matrix) (Matrix, error) {
+		return f(m), nil
+	})
+}
+
+// Add other arrays element-wise
+func (c *MatrixChain) Add(others ...NDArray) *MatrixChain {
+	return c.then(func(m Matrix) Matrix { return Add(m, others...).M() })
+}
+
+// Apply a function to each element
+func (c *MatrixChain) Apply(f func(float64) float64) *MatrixChain {
+	return c.then(func(m Matrix) Matrix { return m.Apply(f).M() })
+}
+
+// Divide by other arrays element-wise
+func (c *MatrixChain) Div(others ...NDArray) *MatrixChain {
+	return c.then(func(m Matrix) Matrix { return Div(m, others...).M() })
+}
+
+// Replace the matrix with its inverse
+func (c *MatrixChain) Inverse() *MatrixChain {
+	return c.Then(Inverse)
+}
+
+// Add a scalar to each element
+func (c *MatrixChain) ItemAdd(value float64) *MatrixChain {
+	return c.then(func(m Matrix) Matrix { return m.ItemAdd(value).M() })
+}
+
+// Divide each element by a scalar
+func (c *MatrixChain) ItemDiv(value float64) *MatrixChain {
+	return c.then(func(m Matrix) Matrix { return m.ItemDiv(value).M() })
+}
+
+// Multiply each element by a scalar
+func (c *MatrixChain) ItemProd(value float64) *MatrixChain {
+	return c.then(func(m Matrix) Matrix { return m.ItemProd(value).M() })
+}
+
+// Subtract a scalar from each element
+func (c *MatrixChain) ItemSub(value float64) *MatrixChain {
+	return c.then(func(m Matrix) Matrix { return m.ItemSub(value).M() })
+}
+
+// Replace the matrix x with the solution to (this)x = b, as LDivide() does.
+// Fails if the system can't be solved.
+func (c *MatrixChain) LDivide(b Matrix) *MatrixChain {
+	return c.Then(func(m Matrix) (Matrix, error) { return TryLDivide(m, b) })
+}
+
+// Multiply on the right by other matrices
+func (c *MatrixChain) MProd(others ...Matrix) *MatrixChain {
+	return c.then(func(m Matrix) Matrix { return MProd(m, others...) })
+}
+
+// Replace the matrix with its pseudo-inverse
+func (c *MatrixChain) Pinv(tol float64) *MatrixChain {
+	return c.Then(func(m Matrix) (Matrix, error) { return Pinv(m, tol) })
+}
+
+// Multiply by other arrays element-wise
+func (c *MatrixChain) Prod(others ...NDArray) *MatrixChain {
+	return c.then(func(m Matrix) Matrix { return Prod(m, others...).M() })
+}
+
+// Subtract other arrays element-wise
+func (c *MatrixChain) Sub(others ...NDArray) *MatrixChain {
+	return c.then(func(m Matrix) Matrix { return Sub(m, others...).M() })
+}
+
+// Transpose the matrix
+func (c *MatrixChain) T() *MatrixChain {
+	return c.then(func(m Matrix) Matrix { return m.T() })
+}
@@ -0,0 +1,82 @@
+package matrix
+
+import (
+	"errors"
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+func TestChain(t *testing.T) {
+	Convey("Given compatible matrices", t, func() {
+		a := M(2, 3,
+			1, 2, 3,
+			4, 5, 6)
+		b := M(3, 2,
+			1, 0,
+			0, 1,
+			1, 1)
+		c := Eye(2)
+
+		Convey("Operations are applied in order", func() {
+			x, err := Chain(a).MProd(b).Add(c).ItemProd(2).Result()
+			So(err, ShouldBeNil)
+			So(x.Equal(M(2, 2,
+				10, 10,
+				20, 24)), ShouldBeTrue)
+		})
+
+		Convey("Inverse and LDivide chain with other operations", func() {
+			x, err := Chain(M(2, 2, 2, 0, 0, 4)).Inverse().T().Result()
+			So(err, ShouldBeNil)
+			So(x.Equal(M(2, 2, 0.5, 0, 0, 0.25)), ShouldBeTrue)
+
+			x, err = Chain(M(2, 2, 2, 0, 0, 4)).LDivide(M(2, 1, 2, 4)).Result()
+			So(err, ShouldBeNil)
+			So(ApproxEqual(x, M(2, 1, 1, 1), Eps, 0), ShouldBeTrue)
+		})
+
+		Convey("LDivide matches the package function, including for diagonals", func() {
+			b := M(3, 2, 1, 2, 3, 4, 5, 6)
+			d := Diag(2, 4, 0.5)
+			x, err := Chain(d).LDivide(b).Result()
+			So(err, ShouldBeNil)
+			So(x.Equal(LDivide(d, b)), ShouldBeTrue)
+			So(x.Sparsity(), ShouldEqual, LDivide(d, b).Sparsity())
+
+			_, err = Chain(Diag(1, 0, 2)).LDivide(b).Result()
+			So(errors.Is(err, ErrSingular), ShouldBeTrue)
+		})
+	})
+
+	Convey("Given an operation which fails", t, func() {
+		a := M(2, 3,
+			1, 2, 3,
+			4, 5, 6)
+
+		Convey("The first error is kept and later steps are skipped", func() {
+			calls := 0
+			chain := Chain(a).MProd(a).Sub(a).Then(func(m Matrix) (Matrix, error) {
+				calls++
+				return m, nil
+			})
+			x, err := chain.Result()
+			So(x, ShouldBeNil)
+			So(calls, ShouldEqual, 0)
+			var shapeErr ErrShapeMismatch
+			So(errors.As(err, &shapeErr), ShouldBeTrue)
+			So(shapeErr.Op, ShouldEqual, "MProd")
+			So(chain.Err(), ShouldResemble, err)
+		})
+
+		Convey("Errors from Then and Inverse are recorded", func() {
+			_, err := Chain(M(2, 2, 1, 2, 2, 4)).Inverse().Result()
+			So(errors.Is(err, ErrSingular), ShouldBeTrue)
+
+			myErr := errors.New("my error")
+			_, err = Chain(a).Then(func(m Matrix) (Matrix, error) {
+				return nil, myErr
+			}).T().Result()
+			So(err, ShouldEqual, myErr)
+		})
+	})
+}
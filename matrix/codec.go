@@ -0,0 +1,260 @@
+package matrix
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+)
+
+// The version of the binary array format written by this package
+const binaryFormatVersion = 1
+
+// The storage formats recorded in binary-encoded arrays
+const (
+	binaryDense byte = iota
+	binarySparseCoo
+	binarySparseDiag
+	binarySparseCSR
+	binarySparseCSC
+)
+
+// Writes a stream of arrays to an io.Writer. Each array is written as a frame
+// holding its length in bytes, as a little-endian uint64, followed by its
+// binary encoding. Sparse matrices keep their storage format, so only their
+// nonzero values are written.
+type Encoder struct {
+	w io.Writer
+}
+
+// Create an encoder which writes arrays to w
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{w: w}
+}
+
+// Write an array to the stream
+func (e *Encoder) Encode(array NDArray) error {
+	data := appendBinary(nil, array)
+	var header [8]byte
+	binary.LittleEndian.PutUint64(header[:], uint64(len(data)))
+	if _, err := e.w.Write(header[:]); err != nil {
+		return err
+	}
+	_, err := e.w.Write(data)
+	return err
+}
+
+// Reads a stream of arrays written by an Encoder from an io.Reader
+type Decoder struct {
+	r io.Reader
+}
+
+// Create a decoder which reads arrays from r
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{r: r}
+}
+
+// Read the next array from the stream. Returns io.EOF when the stream ends
+// cleanly between arrays, and io.ErrUnexpectedEOF if it ends within one.
+func (d *Decoder) Decode() (NDArray, error) {
+	var header [8]byte
+	if _, err := io.ReadFull(d.r, header[:]); err != nil {
+		return nil, err
+	}
+	size := binary.LittleEndian.Uint64(header[:])
+
+	// Let the buffer grow as data arrives rather than trusting the length
+	// enough to allocate it up front
+	var buf bytes.Buffer
+	if n, err := io.CopyN(&buf, d.r, int64(size)); err != nil {
+		if err == io.EOF && uint64(n) < size {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	return parseBinary(buf.Bytes())
+}
+
+// Append the binary encoding of an array to buf. The encoding is a format
+// version byte, a storage format byte, the shape as uvarints, and the values:
+// all of them in row-major order for dense arrays, the count of nonzero
+// values followed by (row, col, value) triples for sparse coo, CSR and CSC
+// matrices, and the main diagonal for sparse diag matrices. Values are little-endian IEEE
+// 754 doubles.
+func appendBinary(buf []byte, array NDArray) []byte {
+	buf = append(buf, binaryFormatVersion)
+	switch array := array.(type) {
+	case *sparseCooF64Matrix:
+		buf = appendBinaryTriples(append(buf, binarySparseCoo), array)
+	case *sparseCompressedF64Matrix:
+		format := binarySparseCSR
+		if array.byCol {
+			format = binarySparseCSC
+		}
+		buf = appendBinaryTriples(append(buf, format), array)
+	case *sparseDiagF64Matrix:
+		buf = appendBinaryShape(append(buf, binarySparseDiag), array.Shape())
+		for _, v := range array.diag {
+			buf = binary.LittleEndian.AppendUint64(buf, math.Float64bits(v))
+		}
+	default:
+		buf = appendBinaryShape(append(buf, binaryDense), array.Shape())
+		for _, v := range array.Array() {
+			buf = binary.LittleEndian.AppendUint64(buf, math.Float64bits(v))
+		}
+	}
+	return buf
+}
+
+// Append the shape of a sparse matrix and its nonzero values as
+// (row, col, value) triples in row-major order
+func appendBinaryTriples(buf []byte, m Matrix) []byte {
+	buf = appendBinaryShape(buf, m.Shape())
+	indptr, ind, data := compressNonzero(m, false)
+	buf = binary.AppendUvarint(buf, uint64(len(data)))
+	for row := 0; row < m.Rows(); row++ {
+		for idx := indptr[row]; idx < indptr[row+1]; idx++ {
+			buf = binary.AppendUvarint(buf, uint64(row))
+			buf = binary.AppendUvarint(buf, uint64(ind[idx]))
+			buf = binary.LittleEndian.AppendUint64(buf, math.Float64bits(data[idx]))
+		}
+	}
+	return buf
+}
+
+// Append the number of dimensions and their sizes
+func appendBinaryShape(buf []byte, shape []int) []byte {
+	buf = binary.AppendUvarint(buf, uint64(len(shape)))
+	for _, dim := range shape {
+		buf = binary.AppendUvarint(buf, uint64(dim))
+	}
+	return buf
+}
+
+// Decode an array from the format written by appendBinary
+func parseBinary(data []byte) (NDArray, error) {
+	p := binaryParser{data: data}
+	if version := p.byte(); p.err == nil && version != binaryFormatVersion {
+		return nil, fmt.Errorf("Unsupported binary array format version %d", version)
+	}
+	format := p.byte()
+	ndim := p.uvarint()
+	if p.err != nil {
+		return nil, p.err
+	} else if ndim == 0 || ndim > uint64(len(p.data)) {
+		return nil, fmt.Errorf("Invalid binary array with %d dimensions", ndim)
+	}
+	shape := make([]int, ndim)
+	size := uint64(1)
+	for idx := range shape {
+		dim := p.uvarint()
+		size *= dim
+		if dim > math.MaxInt32 || size > math.MaxInt32 {
+			return nil, fmt.Errorf("Invalid binary array with dimension %d of size %d", idx, dim)
+		}
+		shape[idx] = int(dim)
+	}
+	if p.err != nil {
+		return nil, p.err
+	}
+
+	var array NDArray
+	switch format {
+	case binaryDense:
+		if uint64(len(p.data)) != 8*size {
+			return nil, fmt.Errorf("Binary array of shape %v has %d bytes of values", shape, len(p.data))
+		}
+		values := make([]float64, size)
+		for idx := range values {
+			values[idx] = p.float()
+		}
+		array = &denseF64Array{shape: shape, array: values}
+	case binarySparseCoo, binarySparseCSR, binarySparseCSC:
+		if len(shape) != 2 {
+			return nil, fmt.Errorf("Invalid binary sparse matrix of shape %v", shape)
+		}
+		m := SparseCoo(shape[0], shape[1])
+		for count := p.uvarint(); count > 0 && p.err == nil; count-- {
+			row, col, value := p.uvarint(), p.uvarint(), p.float()
+			if p.err == nil && (row >= uint64(shape[0]) || col >= uint64(shape[1])) {
+				return nil, fmt.Errorf("Invalid binary sparse matrix with element (%d, %d) outside shape %v",
+					row, col, shape)
+			}
+			m.ItemSet(value, int(row), int(col))
+		}
+		switch format {
+		case binarySparseCSR:
+			m = m.SparseCSR()
+		case binarySparseCSC:
+			m = m.SparseCSC()
+		}
+		array = m
+	case binarySparseDiag:
+		if len(shape) != 2 {
+			return nil, fmt.Errorf("Invalid binary sparse diag matrix of shape %v", shape)
+		}
+		m := SparseDiag(shape[0], shape[1]).(*sparseDiagF64Matrix)
+		if len(p.data) != 8*len(m.diag) {
+			return nil, fmt.Errorf("Binary sparse diag matrix of shape %v has %d bytes of values", shape, len(p.data))
+		}
+		for idx := range m.diag {
+			m.diag[idx] = p.float()
+		}
+		array = m
+	default:
+		return nil, fmt.Errorf("Unknown binary array storage format %d", format)
+	}
+	if p.err != nil {
+		return nil, p.err
+	} else if len(p.data) > 0 {
+		return nil, fmt.Errorf("Binary array has %d trailing bytes", len(p.data))
+	}
+	return array, nil
+}
+
+// Reads values from binary-encoded arrays, recording the first error
+type binaryParser struct {
+	data []byte
+	err  error
+}
+
+// Read a single byte
+func (p *binaryParser) byte() byte {
+	if p.err != nil {
+		return 0
+	} else if len(p.data) < 1 {
+		p.err = io.ErrUnexpectedEOF
+		return 0
+	}
+	b := p.data[0]
+	p.data = p.data[1:]
+	return b
+}
+
+// Read an unsigned varint
+func (p *binaryParser) uvarint() uint64 {
+	if p.err != nil {
+		return 0
+	}
+	v, n := binary.Uvarint(p.data)
+	if n <= 0 {
+		p.err = io.ErrUnexpectedEOF
+		return 0
+	}
+	p.data = p.data[n:]
+	return v
+}
+
+// Read a little-endian float64
+func (p *binaryParser) float() float64 {
+	if p.err != nil {
+		return 0
+	} else if len(p.data) < 8 {
+		p.err = io.ErrUnexpectedEOF
+		return 0
+	}
+	v := math.Float64frombits(binary.LittleEndian.Uint64(p.data))
+	p.data = p.data[8:]
+	return v
+}
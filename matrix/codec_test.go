@@ -0,0 +1,72 @@
+package matrix
+
+import (
+	"bytes"
+	. "github.com/smartystreets/goconvey/convey"
+	"io"
+	"testing"
+)
+
+func TestCodec(t *testing.T) {
+	Convey("Given arrays of each storage format", t, func() {
+		dense := M(2, 3,
+			1, 2, 3,
+			4, 5, 6)
+		coo := SparseCoo(3, 4,
+			0, 2, 0, 1,
+			0, 0, 0, 0,
+			3, 0, 4, 0)
+		diag := SparseDiag(2, 3, 7, 8)
+		cube := Rand(2, 3, 4)
+
+		Convey("An Encoder and Decoder stream them", func() {
+			var buf bytes.Buffer
+			enc := NewEncoder(&buf)
+			for _, array := range []NDArray{dense, dense.T(), coo, coo.T(), diag, cube} {
+				So(enc.Encode(array), ShouldBeNil)
+			}
+
+			dec := NewDecoder(&buf)
+			for _, array := range []NDArray{dense, dense.T(), coo, coo.T(), diag, cube} {
+				result, err := dec.Decode()
+				So(err, ShouldBeNil)
+				So(result.Shape(), ShouldResemble, array.Shape())
+				So(result.Equal(array), ShouldBeTrue)
+			}
+			_, err := dec.Decode()
+			So(err, ShouldEqual, io.EOF)
+		})
+
+		Convey("Sparse matrices keep their storage format", func() {
+			var buf bytes.Buffer
+			So(NewEncoder(&buf).Encode(coo), ShouldBeNil)
+			So(NewEncoder(&buf).Encode(diag), ShouldBeNil)
+			dec := NewDecoder(&buf)
+			result, _ := dec.Decode()
+			So(result, ShouldHaveSameTypeAs, coo)
+			result, _ = dec.Decode()
+			So(result, ShouldHaveSameTypeAs, diag)
+		})
+
+		Convey("Truncated streams are an error", func() {
+			var buf bytes.Buffer
+			So(NewEncoder(&buf).Encode(dense), ShouldBeNil)
+			data := buf.Bytes()
+			for _, n := range []int{4, 12, len(data) - 1} {
+				_, err := NewDecoder(bytes.NewReader(data[:n])).Decode()
+				So(err, ShouldEqual, io.ErrUnexpectedEOF)
+			}
+		})
+
+		Convey("Corrupt frames are an error", func() {
+			data := appendBinary(nil, dense)
+			data[1] = 9
+			_, err := parseBinary(data)
+			So(err, ShouldNotBeNil)
+
+			data = appendBinary(nil, coo)
+			_, err = parseBinary(append(data, 0))
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
@@ -0,0 +1,608 @@
+package matrix
+
+import (
+	"database/sql/driver"
+	"math"
+	"sync/atomic"
+)
+
+// A dense array whose elements all have the same value, created by
+// WithValue(). Only the value is stored until the array is modified or its
+// storage is requested, as by Array(), when its elements are stored densely.
+// Views created by M(), T() and View() share the state, so they see the
+// modification.
+type constF64Array struct {
+	shape     []int
+	transpose bool
+	state     *constState
+}
+
+// The values of a constant array and its views. Methods which only read the
+// array may store its elements, as Array() does, so the elements are
+// published atomically to keep concurrent reads safe.
+type constState struct {
+	value float64
+
+	// The stored elements, in row-major order for the shape of the array
+	// which created the state, or nil while they are all value
+	values atomic.Pointer[[]float64]
+
+	// Whether the stored elements have been handed out, so they must be kept
+	// up to date rather than discarded
+	exposed atomic.Bool
+}
+
+// Create a constant array which doesn't share its state
+func newConst(value float64, shape []int) *constF64Array {
+	return &constF64Array{
+		shape: append([]int(nil), shape...),
+		state: &constState{value: value},
+	}
+}
+
+// Get a dense view of the stored elements, or nil if they aren't stored
+func (a *constF64Array) stored() *denseF64Array {
+	values := a.state.values.Load()
+	if values == nil {
+		return nil
+	}
+	return &denseF64Array{shape: a.shape, array: *values, transpose: a.transpose}
+}
+
+// Get a dense view of the elements, storing them first if needed. If several
+// goroutines store them at once, all of them use the first one's elements.
+func (a *constF64Array) densify() *denseF64Array {
+	if a.state.values.Load() == nil {
+		values := filled(a.state.value, a.Size())
+		a.state.values.CompareAndSwap(nil, &values)
+	}
+	return a.stored()
+}
+
+// Get a dense view of m's storage, storing the elements of a constant array
+// first. Returns false if m isn't a dense or constant array.
+func denseStorage(m NDArray) (*denseF64Array, bool) {
+	switch m := m.(type) {
+	case *denseF64Array:
+		return m, true
+	case *constF64Array:
+		return m.expose(), true
+	}
+	return nil, false
+}
+
+// Get a dense view of the elements for a caller which may keep or modify its
+// storage, which is then used from now on
+func (a *constF64Array) expose() *denseF64Array {
+	a.state.exposed.Store(true)
+	return a.densify()
+}
+
+// Get a dense view of the stored elements, or a dense copy which isn't kept
+// if they aren't stored
+func (a *constF64Array) read() *denseF64Array {
+	if d := a.stored(); d != nil {
+		return d
+	}
+	return &denseF64Array{shape: a.shape, array: filled(a.state.value, a.Size())}
+}
+
+// Return the element-wise sum of this array and one or more others
+func (a *constF64Array) Add(others ...NDArray) NDArray {
+	return Add(a, others...)
+}
+
+// Returns true if and only if all items are nonzero
+func (a *constF64Array) All() bool {
+	return a.AllF(func(v float64) bool { return v != 0 })
+}
+
+// Returns true if f is true for all array elements
+func (a *constF64Array) AllF(f func(v float64) bool) bool {
+	if d := a.stored(); d != nil {
+		return d.AllF(f)
+	}
+	return a.Size() == 0 || f(a.state.value)
+}
+
+// Returns true if f is true for all pairs of array elements in the same position
+func (a *constF64Array) AllF2(f func(v1, v2 float64) bool, other NDArray) bool {
+	return AllF2(a, f, other)
+}
+
+// Returns true if and only if no items are NaN or infinite
+func (a *constF64Array) AllFinite() bool {
+	return a.AllF(func(v float64) bool { return !math.IsNaN(v) && !math.IsInf(v, 0) })
+}
+
+// Returns true if and only if any item is nonzero
+func (a *constF64Array) Any() bool {
+	return a.AnyF(func(v float64) bool { return v != 0 })
+}
+
+// Returns true if f is true for any array element
+func (a *constF64Array) AnyF(f func(v float64) bool) bool {
+	if d := a.stored(); d != nil {
+		return d.AnyF(f)
+	}
+	return a.Size() > 0 && f(a.state.value)
+}
+
+// Returns true if f is true for any pair of array elements in the same position
+func (a *constF64Array) AnyF2(f func(v1, v2 float64) bool, other NDArray) bool {
+	return AnyF2(a, f, other)
+}
+
+// Returns true if and only if any item is NaN
+func (a *constF64Array) AnyNaN() bool {
+	return a.AnyF(math.IsNaN)
+}
+
+// Return the result of applying a function to all elements. The result of
+// an unmodified array is also constant.
+func (a *constF64Array) Apply(f func(float64) float64) NDArray {
+	if d := a.stored(); d != nil {
+		return d.Apply(f)
+	}
+	return newConst(f(a.state.value), a.shape)
+}
+
+// Get the array data as a flattened 1D array. As for other dense arrays,
+// this is the storage of a row-major array, which is stored first if needed,
+// and a copy for a transpose.
+func (a *constF64Array) Array() []float64 {
+	return a.expose().Array()
+}
+
+// Returns a deep copy of this array
+func (a *constF64Array) Clone() NDArray {
+	return a.Copy()
+}
+
+// Set the values of the items on a given column
+func (a *constF64Array) ColSet(col int, values []float64) {
+	a.densify().ColSet(col, values)
+}
+
+// Get a copy of a particular column
+func (a *constF64Array) Col(col int) []float64 {
+	if d := a.stored(); d != nil {
+		return d.Col(col)
+	}
+	if col < 0 || col >= a.shape[1] {
+		panic(ErrIndexOutOfRange{Op: "Col", Index: []int{col}, Shape: a.shape[1:]})
+	}
+	return filled(a.state.value, a.shape[0])
+}
+
+// Get the number of columns
+func (a *constF64Array) Cols() int {
+	return a.shape[1]
+}
+
+// Create a new array by concatenating this with another array along the
+// specified axis. The array shapes must be equal along all other axes.
+// It is legal to add a new axis.
+func (a *constF64Array) Concat(axis int, others ...NDArray) NDArray {
+	return Concat(axis, a, others...)
+}
+
+// Get the condition number for the matrix norm of the specified ordinality
+func (a *constF64Array) Cond(ord float64) float64 {
+	return Cond(a, ord)
+}
+
+// Returns a duplicate of this array. The duplicate of an unmodified array is
+// also constant.
+func (a *constF64Array) Copy() NDArray {
+	if d := a.stored(); d != nil {
+		return d.Copy()
+	}
+	return newConst(a.state.value, a.shape)
+}
+
+// Counts the number of nonzero elements in the array
+func (a *constF64Array) CountNonzero() int {
+	if d := a.stored(); d != nil {
+		return d.CountNonzero()
+	} else if a.state.value == 0 {
+		return 0
+	}
+	return a.Size()
+}
+
+// Returns a dense copy of the array
+func (a *constF64Array) Dense() NDArray {
+	return a.read().Dense()
+}
+
+// Get the determinant of a square matrix
+func (a *constF64Array) Det() float64 {
+	return Det(a)
+}
+
+// Get a column vector containing the main diagonal elements of the matrix
+func (a *constF64Array) Diag() Matrix {
+	return a.read().Diag()
+}
+
+// Treat the rows as points, and get the pairwise distance between them.
+// Returns a distance matrix D such that D_i,j is the distance between
+// rows i and j.
+func (a *constF64Array) Dist(t DistType) Matrix {
+	return Dist(a, t)
+}
+
+// Treat the rows of this and other as points, and get the distance between
+// each pair. Returns an m x n distance matrix D such that D_i,j is the
+// distance between row i of this and row j of other.
+func (a *constF64Array) DistTo(other Matrix, t DistType) Matrix {
+	return DistTo(a, other, t)
+}
+
+// Return the element-wise quotient of this array and one or more others.
+// This function defines 0 / 0 = 0, so it's useful for sparse arrays.
+func (a *constF64Array) Div(others ...NDArray) NDArray {
+	return Div(a, others...)
+}
+
+// Returns true if and only if all elements in the two arrays are equal
+func (a *constF64Array) Equal(other NDArray) bool {
+	return Equal(a, other)
+}
+
+// Set all array elements to the given value. The array stops storing its
+// elements, as do its views, unless its storage has been handed out by
+// Array().
+func (a *constF64Array) Fill(value float64) {
+	a.state.value = value
+	if a.state.exposed.Load() {
+		a.stored().Fill(value)
+	} else {
+		a.state.values.Store(nil)
+	}
+}
+
+// Get the coordinates for the item at the specified flat position
+func (a *constF64Array) FlatCoord(index int) []int {
+	return flatToNd(a.shape, index)
+}
+
+// Get an array element in a flattened verison of this array
+func (a *constF64Array) FlatItem(index int) float64 {
+	if d := a.stored(); d != nil {
+		return d.FlatItem(index)
+	}
+	if index < 0 || index >= a.Size() {
+		panic(ErrIndexOutOfRange{Op: "FlatItem", Index: []int{index}, Shape: []int{a.Size()}})
+	}
+	return a.state.value
+}
+
+// Set an array element in a flattened version of this array
+func (a *constF64Array) FlatItemSet(value float64, index int) {
+	a.densify().FlatItemSet(value, index)
+}
+
+// Get the matrix inverse
+func (a *constF64Array) Inverse() (Matrix, error) {
+	return Inverse(a)
+}
+
+// Get an array element
+func (a *constF64Array) Item(index ...int) float64 {
+	if d := a.stored(); d != nil {
+		return d.Item(index...)
+	}
+	ndToFlat(a.shape, index)
+	return a.state.value
+}
+
+// Add a scalar value to each array element
+func (a *constF64Array) ItemAdd(value float64) NDArray {
+	return a.Apply(func(v float64) float64 { return v + value })
+}
+
+// Divide each array element by a scalar value
+func (a *constF64Array) ItemDiv(value float64) NDArray {
+	return a.Apply(func(v float64) float64 { return v / value })
+}
+
+// Multiply each array element by a scalar value
+func (a *constF64Array) ItemProd(value float64) NDArray {
+	return a.Apply(func(v float64) float64 { return v * value })
+}
+
+// Subtract a scalar value from each array element
+func (a *constF64Array) ItemSub(value float64) NDArray {
+	return a.Apply(func(v float64) float64 { return v - value })
+}
+
+// Set an array element
+func (a *constF64Array) ItemSet(value float64, index ...int) {
+	a.densify().ItemSet(value, index...)
+}
+
+// Solve for x, where ax = b.
+func (a *constF64Array) LDivide(b Matrix) Matrix {
+	return LDivide(a, b)
+}
+
+// Get the result of matrix multiplication between this and some other
+// array(s). All arrays must have two dimensions, and the dimensions must
+// be aligned correctly for multiplication.
+// If A is m x p and B is p x n, then C = A.MProd(B) is the m x n matrix
+// with C[i, j] = \sum_{k=1}^p A[i,k] * B[k,j].
+func (a *constF64Array) MProd(others ...Matrix) Matrix {
+	return MProd(a.read(), others...)
+}
+
+// Get the value of the largest array element
+func (a *constF64Array) Max() float64 {
+	if a.stored() != nil || a.Size() == 0 {
+		return a.read().Max()
+	}
+	return a.state.value
+}
+
+// Get the value of the smallest array element
+func (a *constF64Array) Min() float64 {
+	if a.stored() != nil || a.Size() == 0 {
+		return a.read().Min()
+	}
+	return a.state.value
+}
+
+// The number of dimensions in the array
+func (a *constF64Array) NDim() int {
+	return len(a.shape)
+}
+
+// Get the matrix norm of the specified ordinality (1, 2, infinity, ...)
+func (a *constF64Array) Norm(ord float64) float64 {
+	return Norm(a.read(), ord)
+}
+
+// Return a copy of the array, normalized to sum to 1
+func (a *constF64Array) Normalize() NDArray {
+	return Normalize(a)
+}
+
+// Get the Moore-Penrose pseudo-inverse, treating singular values no larger
+// than tol as zero
+func (a *constF64Array) Pinv(tol float64) (Matrix, error) {
+	return Pinv(a, tol)
+}
+
+// Return the element-wise product of this array and one or more others
+func (a *constF64Array) Prod(others ...NDArray) NDArray {
+	return Prod(a, others...)
+}
+
+// Get the number of singular values larger than tol
+func (a *constF64Array) Rank(tol float64) int {
+	return Rank(a, tol)
+}
+
+// Get a 1D copy of the array, in 'C' order: rightmost axes change fastest
+func (a *constF64Array) Ravel() NDArray {
+	if d := a.stored(); d != nil {
+		return d.Ravel()
+	}
+	return newConst(a.state.value, []int{a.Size()})
+}
+
+// Set the values of the items on a given row
+func (a *constF64Array) RowSet(row int, values []float64) {
+	a.densify().RowSet(row, values)
+}
+
+// Get a copy of a particular row
+func (a *constF64Array) Row(row int) []float64 {
+	if d := a.stored(); d != nil {
+		return d.Row(row)
+	}
+	if row < 0 || row >= a.shape[0] {
+		panic(ErrIndexOutOfRange{Op: "Row", Index: []int{row}, Shape: a.shape[:1]})
+	}
+	return filled(a.state.value, a.shape[1])
+}
+
+// Get the number of rows
+func (a *constF64Array) Rows() int {
+	return a.shape[0]
+}
+
+// Replace the array with one read from a database column, implementing
+// sql.Scanner. The array stores its new elements, and no longer shares them
+// with its views.
+func (a *constF64Array) Scan(src interface{}) error {
+	var d denseF64Array
+	if err := scanDense(&d, src); err != nil {
+		return err
+	}
+	state := &constState{}
+	state.values.Store(&d.array)
+	*a = constF64Array{shape: d.shape, state: state}
+	return nil
+}
+
+// Copy src into this matrix, with the top-left element of src at (row, col)
+func (a *constF64Array) SetSubmatrix(row, col int, src Matrix) {
+	a.densify().SetSubmatrix(row, col, src)
+}
+
+// A slice giving the size of all array dimensions
+func (a *constF64Array) Shape() []int {
+	return a.shape
+}
+
+// The total number of elements in the array
+func (a *constF64Array) Size() int {
+	size := 1
+	for _, d := range a.shape {
+		size *= d
+	}
+	return size
+}
+
+// Get an array containing a rectangular slice of this array.
+// `from` and `to` should both have one index per axis. The indices
+// in `from` and `to` define the first and just-past-last indices you wish
+// to select along each axis.
+func (a *constF64Array) Slice(from []int, to []int) NDArray {
+	return Slice(a, from, to)
+}
+
+// Return a sparse coo copy of the matrix
+func (a *constF64Array) SparseCoo() Matrix {
+	return a.read().SparseCoo()
+}
+
+// Return a compressed sparse column copy of the matrix
+func (a *constF64Array) SparseCSC() Matrix {
+	return a.read().SparseCSC()
+}
+
+// Return a compressed sparse row copy of the matrix
+func (a *constF64Array) SparseCSR() Matrix {
+	return a.read().SparseCSR()
+}
+
+// Return a sparse diag copy of the matrix. The method will panic
+// if any off-diagonal elements are nonzero.
+func (a *constF64Array) SparseDiag() Matrix {
+	return a.read().SparseDiag()
+}
+
+// Constant arrays are dense
+func (a *constF64Array) Sparsity() ArraySparsity {
+	return DenseArray
+}
+
+// Return the element-wise difference of this array and one or more others
+func (a *constF64Array) Sub(others ...NDArray) NDArray {
+	return Sub(a, others...)
+}
+
+// Return the sum of all array elements
+func (a *constF64Array) Sum() float64 {
+	if d := a.stored(); d != nil {
+		return d.Sum()
+	} else if a.state.value == 0 {
+		return 0
+	}
+	return a.state.value * float64(a.Size())
+}
+
+// Exchange the values of two columns in place
+func (a *constF64Array) SwapCols(i, j int) {
+	if d := a.stored(); d != nil {
+		d.SwapCols(i, j)
+		return
+	}
+	checkSwap("SwapCols", i, j, a.shape[1])
+}
+
+// Exchange the values of two rows in place
+func (a *constF64Array) SwapRows(i, j int) {
+	if d := a.stored(); d != nil {
+		d.SwapRows(i, j)
+		return
+	}
+	checkSwap("SwapRows", i, j, a.shape[0])
+}
+
+// Returns the array as a matrix. This is only possible for 1D and 2D arrays;
+// 1D arrays of length n are converted into n x 1 vectors. The result is a
+// view: it shares state with this array.
+func (a *constF64Array) M() Matrix {
+	switch a.NDim() {
+	default:
+		panic(ErrShapeMismatch{Op: "M", Got: a.shape, Want: []int{-1, -1}})
+
+	case 1:
+		return &constF64Array{
+			shape:     []int{a.shape[0], 1},
+			transpose: a.transpose,
+			state:     a.state,
+		}
+
+	case 2:
+		return a
+	}
+}
+
+// Return the same matrix, but with axes transposed. The result is a view
+// which shares state with this matrix.
+func (a *constF64Array) T() Matrix {
+	return &constF64Array{
+		shape:     []int{a.shape[1], a.shape[0]},
+		transpose: !a.transpose,
+		state:     a.state,
+	}
+}
+
+// Get the sum of the elements on the main diagonal
+func (a *constF64Array) Trace() float64 {
+	return Trace(a, 0)
+}
+
+// Get the sum of the elements on a diagonal offset from the main diagonal.
+// Positive offsets select superdiagonals and negative offsets select
+// subdiagonals.
+func (a *constF64Array) TraceOffset(offset int) float64 {
+	return Trace(a, offset)
+}
+
+// Encode the array in the package's binary format for storage in a
+// database column, implementing driver.Valuer
+func (a *constF64Array) Value() (driver.Value, error) {
+	return Value(a)
+}
+
+// Returns a view of this array, which shares its state
+func (a *constF64Array) View() NDArray {
+	return &constF64Array{
+		shape:     append([]int(nil), a.shape...),
+		transpose: a.transpose,
+		state:     a.state,
+	}
+}
+
+// Visit all array elements, invoking a method on each. If the method
+// returns false, iteration is aborted and Visit() returns false.
+// Otherwise, it returns true.
+func (a *constF64Array) Visit(f func(pos []int, value float64) bool) bool {
+	if d := a.stored(); d != nil {
+		return d.Visit(f)
+	}
+	size := a.Size()
+	for flat := 0; flat < size; flat++ {
+		if !f(flatToNd(a.shape, flat), a.state.value) {
+			return false
+		}
+	}
+	return true
+}
+
+// Visit just nonzero elements, invoking a method on each. If the method
+// returns false, iteration is aborted and VisitNonzero() returns false.
+// Otherwise, it returns true.
+func (a *constF64Array) VisitNonzero(f func(pos []int, value float64) bool) bool {
+	if d := a.stored(); d != nil {
+		return d.VisitNonzero(f)
+	} else if a.state.value == 0 {
+		return true
+	}
+	return a.Visit(f)
+}
+
+// Get a slice of n copies of value
+func filled(value float64, n int) []float64 {
+	result := make([]float64, n)
+	for i := range result {
+		result[i] = value
+	}
+	return result
+}
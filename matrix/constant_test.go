@@ -0,0 +1,130 @@
+package matrix
+
+import (
+	. "github.com/smartystreets/goconvey/convey"
+	"gonum.org/v1/gonum/mat"
+	"math"
+	"sync"
+	"testing"
+)
+
+func TestWithValueConstant(t *testing.T) {
+	Convey("Given a large constant array", t, func() {
+		a := WithValue(math.NaN(), 100000, 100000)
+
+		Convey("Its values are available without storing them", func() {
+			c := a.(*constF64Array)
+			So(c.state.values.Load(), ShouldBeNil)
+			So(math.IsNaN(a.Item(99999, 5)), ShouldBeTrue)
+			So(a.AnyNaN(), ShouldBeTrue)
+			So(a.CountNonzero(), ShouldEqual, 100000*100000)
+			So(a.Sparsity(), ShouldEqual, DenseArray)
+			So(func() { a.Item(100000, 0) }, ShouldPanic)
+		})
+
+		Convey("Scalar operations give constant arrays", func() {
+			b := Ones(100000, 100000).ItemProd(2).ItemAdd(1)
+			So(b.(*constF64Array).state.values.Load(), ShouldBeNil)
+			So(b.Sum(), ShouldEqual, 3e10)
+			So(b.Max(), ShouldEqual, 3)
+		})
+	})
+
+	Convey("Given a small constant matrix", t, func() {
+		m := WithValue(2, 2, 3).M()
+
+		Convey("Setting an element stores the values", func() {
+			view := m.T()
+			m.ItemSet(5, 0, 1)
+			So(m.Array(), ShouldResemble, []float64{2, 5, 2, 2, 2, 2})
+			So(view.Item(1, 0), ShouldEqual, 5)
+			So(m.Sum(), ShouldEqual, 15)
+			So(CheckInvariants(m), ShouldBeNil)
+			So(CheckInvariants(view), ShouldBeNil)
+		})
+
+		Convey("Setting an element of a transposed view works", func() {
+			view := m.T()
+			view.ItemSet(7, 2, 1)
+			So(m.Item(1, 2), ShouldEqual, 7)
+			So(view.Row(2), ShouldResemble, []float64{2, 7})
+		})
+
+		Convey("Fill makes the matrix constant again", func() {
+			m.ItemSet(5, 0, 1)
+			m.Fill(1)
+			So(m.(*constF64Array).state.values.Load(), ShouldBeNil)
+			So(m.Array(), ShouldResemble, []float64{1, 1, 1, 1, 1, 1})
+		})
+
+		Convey("Copies don't share the values", func() {
+			c := m.Copy()
+			m.ItemSet(0, 0, 0)
+			So(c.Item(0, 0), ShouldEqual, 2)
+		})
+
+		Convey("It works with other arrays", func() {
+			So(m.Add(M(2, 3, 1, 2, 3, 4, 5, 6)).Array(), ShouldResemble, []float64{3, 4, 5, 6, 7, 8})
+			So(m.MProd(Ones(3, 1).M()).Array(), ShouldResemble, []float64{6, 6})
+			So(m.Equal(M(2, 3, 2, 2, 2, 2, 2, 2)), ShouldBeTrue)
+		})
+
+		Convey("Writes through Array() are kept", func() {
+			values := m.Array()
+			values[4] = 9
+			So(m.Item(1, 1), ShouldEqual, 9)
+			So(m.T().Item(1, 1), ShouldEqual, 9)
+			m.Fill(3)
+			So(values, ShouldResemble, []float64{3, 3, 3, 3, 3, 3})
+			values[0] = 1
+			So(m.Item(0, 0), ShouldEqual, 1)
+
+			a := WithValue(1, 4)
+			a.Array()[2] = 0
+			So(a.Array(), ShouldResemble, []float64{1, 1, 0, 1})
+		})
+
+		Convey("Its storage is shared like a dense matrix's", func() {
+			raw, order, ok := RawOrder(m)
+			So(ok, ShouldBeTrue)
+			So(order, ShouldEqual, RowMajor)
+			raw[1] = 4
+			So(m.Item(0, 1), ShouldEqual, 4)
+
+			AsMat(m).(mat.Mutable).Set(1, 2, 6)
+			So(m.Item(1, 2), ShouldEqual, 6)
+
+			for start, window := range WindowRows(m, 1, 1) {
+				window.ItemSet(-1, 0, 0)
+				So(m.Item(start, 0), ShouldEqual, -1)
+			}
+		})
+
+		Convey("Concurrent reads are safe while the values are stored", func() {
+			var wg sync.WaitGroup
+			results := make([]Matrix, 8)
+			for g := range results {
+				wg.Add(1)
+				go func(g int) {
+					defer wg.Done()
+					results[g] = m.MProd(Ones(3, 50).M())
+					m.Array()
+					RawOrder(m)
+				}(g)
+			}
+			wg.Wait()
+			for _, result := range results {
+				So(result.Equal(WithValue(6, 2, 50)), ShouldBeTrue)
+			}
+			So(m.Array(), ShouldResemble, []float64{2, 2, 2, 2, 2, 2})
+		})
+
+		Convey("A 1D array converts to a column vector view", func() {
+			v := WithValue(4, 3)
+			col := v.M()
+			So(col.Shape(), ShouldResemble, []int{3, 1})
+			col.ItemSet(1, 2, 0)
+			So(v.Array(), ShouldResemble, []float64{4, 4, 1})
+		})
+	})
+}
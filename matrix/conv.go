@@ -0,0 +1,188 @@
+package matrix
+
+import (
+	"fmt"
+)
+
+// The padding used by Conv2D() and Correlate2D(), which determines the size
+// of the result
+type PadMode int
+
+const (
+	// Only use positions where the kernel lies entirely inside the matrix. The
+	// result is (rows - kernel rows + 1) x (cols - kernel cols + 1).
+	PadValid PadMode = iota
+
+	// Pad with zeros so the result has the same shape as the matrix, with the
+	// kernel centered on each element
+	PadSame
+
+	// Pad with zeros to use every position where the kernel overlaps the
+	// matrix. The result is (rows + kernel rows - 1) x (cols + kernel cols - 1).
+	PadFull
+)
+
+// Convolve m with kernel, flipping the kernel as in the mathematical
+// definition of convolution. With a stride above 1, only every stride-th
+// row and column of the result are kept, starting with the first. The
+// result is dense, and matches scipy.signal.convolve2d with the same mode.
+func Conv2D(m, kernel Matrix, pad PadMode, stride int) Matrix {
+	debugCheck("Conv2D", m, kernel)
+	kh, kw := kernel.Rows(), kernel.Cols()
+	flipped := FromFunc(kh, kw, func(row, col int) float64 {
+		return kernel.Item(kh-1-row, kw-1-col)
+	})
+
+	// With an even kernel, "same" convolution keeps the top left of the full
+	// result, so the flipped kernel is offset from the center the other way
+	top, left := padding("Conv2D", m, kernel, pad)
+	if pad == PadSame {
+		top, left = kh/2, kw/2
+	}
+	return correlate2D("Conv2D", m, flipped, pad, top, left, stride)
+}
+
+// Cross-correlate m with kernel: slide the kernel over m without flipping it,
+// taking the sum of the products at each position. This is the "convolution"
+// of convolutional neural networks. Padding and stride are as for Conv2D().
+func Correlate2D(m, kernel Matrix, pad PadMode, stride int) Matrix {
+	debugCheck("Correlate2D", m, kernel)
+	top, left := padding("Correlate2D", m, kernel, pad)
+	return correlate2D("Correlate2D", m, kernel, pad, top, left, stride)
+}
+
+// Get the number of rows and columns of zeros to pad above and to the left of
+// m for a padding mode
+func padding(op string, m, kernel Matrix, pad PadMode) (top, left int) {
+	kh, kw := kernel.Rows(), kernel.Cols()
+	switch pad {
+	case PadValid:
+		if kh > m.Rows() || kw > m.Cols() {
+			panic(ErrShapeMismatch{Op: op, Got: kernel.Shape(), Want: m.Shape()})
+		}
+		return 0, 0
+	case PadSame:
+		return (kh - 1) / 2, (kw - 1) / 2
+	case PadFull:
+		return kh - 1, kw - 1
+	default:
+		panic(fmt.Sprintf("%s: unknown padding mode %v", op, pad))
+	}
+}
+
+// Cross-correlate m with kernel, with the given zeros above and to the left
+func correlate2D(op string, m, kernel Matrix, pad PadMode, top, left, stride int) Matrix {
+	if stride < 1 {
+		panic(fmt.Sprintf("%s: stride %d should be positive", op, stride))
+	}
+	rows, cols := m.Rows(), m.Cols()
+	kh, kw := kernel.Rows(), kernel.Cols()
+	outRows, outCols := rows, cols
+	switch pad {
+	case PadValid:
+		outRows, outCols = rows-kh+1, cols-kw+1
+	case PadFull:
+		outRows, outCols = rows+kh-1, cols+kw-1
+	}
+	outRows = (outRows + stride - 1) / stride
+	outCols = (outCols + stride - 1) / stride
+
+	data := m.Array()
+	weights := kernel.Array()
+	out := make([]float64, outRows*outCols)
+	for i := 0; i < outRows; i++ {
+		for j := 0; j < outCols; j++ {
+			var sum float64
+			for a := 0; a < kh; a++ {
+				row := i*stride + a - top
+				if row < 0 || row >= rows {
+					continue
+				}
+				for b := 0; b < kw; b++ {
+					col := j*stride + b - left
+					if col < 0 || col >= cols {
+						continue
+					}
+					sum += data[row*cols+col] * weights[a*kw+b]
+				}
+			}
+			out[i*outCols+j] = sum
+		}
+	}
+	return M(outRows, outCols, out...)
+}
+
+// Unfold the kh x kw windows of m, taken every stride rows and columns, into
+// the columns of a (kh * kw) x (number of windows) dense matrix. Each column
+// is a window flattened in row-major order, and the windows are in row-major
+// order of their top left corners. Only windows lying entirely inside m are
+// used, as for PadValid. This turns convolution into matrix multiplication:
+//
+//	windows := Im2Col(m, kh, kw, stride)
+//	flat := M(1, kh*kw, kernel.Array()...).MProd(windows)
+//
+// has the same values as Correlate2D(m, kernel, PadValid, stride).
+func Im2Col(m Matrix, kh, kw, stride int) Matrix {
+	debugCheck("Im2Col", m)
+	outRows, outCols := windowCount("Im2Col", m, kh, kw, stride)
+	cols := m.Cols()
+	data := m.Array()
+	n := outRows * outCols
+	out := make([]float64, kh*kw*n)
+	for i := 0; i < outRows; i++ {
+		for j := 0; j < outCols; j++ {
+			window := i*outCols + j
+			for a := 0; a < kh; a++ {
+				for b := 0; b < kw; b++ {
+					out[(a*kw+b)*n+window] = data[(i*stride+a)*cols+j*stride+b]
+				}
+			}
+		}
+	}
+	return M(kh*kw, n, out...)
+}
+
+// Take the maximum of each kh x kw window of m, taken every stride rows and
+// columns. Only windows lying entirely inside m are used.
+func MaxPool(m Matrix, kh, kw, stride int) Matrix {
+	debugCheck("MaxPool", m)
+	return pool("MaxPool", m, kh, kw, stride, func(window []float64) float64 {
+		return window[argMaxVec(window)]
+	})
+}
+
+// Take the mean of each kh x kw window of m, taken every stride rows and
+// columns. Only windows lying entirely inside m are used.
+func AvgPool(m Matrix, kh, kw, stride int) Matrix {
+	debugCheck("AvgPool", m)
+	return pool("AvgPool", m, kh, kw, stride, func(window []float64) float64 {
+		var sum float64
+		for _, v := range window {
+			sum += v
+		}
+		return sum / float64(len(window))
+	})
+}
+
+// Reduce each window of m to a single value
+func pool(op string, m Matrix, kh, kw, stride int, reduce func(window []float64) float64) Matrix {
+	outRows, outCols := windowCount(op, m, kh, kw, stride)
+	windows := Im2Col(m, kh, kw, stride)
+	out := make([]float64, outRows*outCols)
+	for i := range out {
+		out[i] = reduce(windows.Col(i))
+	}
+	return M(outRows, outCols, out...)
+}
+
+// Get the number of rows and columns of kh x kw windows which fit inside m
+// when taken every stride rows and columns
+func windowCount(op string, m Matrix, kh, kw, stride int) (rows, cols int) {
+	if kh < 1 || kw < 1 || kh > m.Rows() || kw > m.Cols() {
+		panic(ErrShapeMismatch{Op: op, Got: []int{kh, kw}, Want: m.Shape()})
+	}
+	if stride < 1 {
+		panic(fmt.Sprintf("%s: stride %d should be positive", op, stride))
+	}
+	return (m.Rows()-kh)/stride + 1, (m.Cols()-kw)/stride + 1
+}
@@ -0,0 +1,93 @@
+package matrix
+
+import (
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+func TestConv2D(t *testing.T) {
+	Convey("Given a matrix and a kernel", t, func() {
+		m := M(3, 3, 1, 2, 3, 4, 5, 6, 7, 8, 9)
+		k := M(2, 2, 1, 2, 3, 4)
+
+		Convey("Valid convolution flips the kernel", func() {
+			So(Conv2D(m, k, PadValid, 1).Equal(M(2, 2, 23, 33, 53, 63)), ShouldBeTrue)
+		})
+
+		Convey("Full convolution includes partial overlaps", func() {
+			full := Conv2D(m, k, PadFull, 1)
+			So(full.Shape(), ShouldResemble, []int{4, 4})
+			So(full.Item(0, 0), ShouldEqual, 1)
+			So(full.Item(0, 1), ShouldEqual, 4)
+			So(full.Item(1, 1), ShouldEqual, 23)
+			So(full.Item(3, 3), ShouldEqual, 36)
+		})
+
+		Convey("Same convolution keeps the top left of the full result", func() {
+			same := Conv2D(m, k, PadSame, 1)
+			So(same.Equal(Conv2D(m, k, PadFull, 1).Slice([]int{0, 0}, []int{3, 3})), ShouldBeTrue)
+		})
+
+		Convey("Valid correlation doesn't flip the kernel", func() {
+			So(Correlate2D(m, k, PadValid, 1).Equal(M(2, 2, 37, 47, 67, 77)), ShouldBeTrue)
+			So(Correlate2D(m, k, PadFull, 1).Item(0, 0), ShouldEqual, 4)
+		})
+
+		Convey("Same correlation centers odd kernels", func() {
+			center := M(3, 3, 0, 0, 0, 0, 1, 0, 0, 0, 0)
+			So(Correlate2D(m, center, PadSame, 1).Equal(m), ShouldBeTrue)
+			So(Conv2D(m, center, PadSame, 1).Equal(m), ShouldBeTrue)
+			So(Correlate2D(m, center, PadSame, 2).Equal(M(2, 2, 1, 3, 7, 9)), ShouldBeTrue)
+		})
+
+		Convey("Sparse inputs give dense results", func() {
+			result := Correlate2D(m.SparseCoo(), k, PadValid, 1)
+			So(result.Sparsity(), ShouldEqual, DenseArray)
+			So(result.Equal(M(2, 2, 37, 47, 67, 77)), ShouldBeTrue)
+		})
+
+		Convey("Invalid arguments panic", func() {
+			So(func() { Conv2D(k, m, PadValid, 1) }, ShouldPanic)
+			So(func() { Conv2D(m, k, PadValid, 0) }, ShouldPanic)
+			So(func() { Correlate2D(m, k, PadMode(7), 1) }, ShouldPanic)
+		})
+	})
+}
+
+func TestIm2Col(t *testing.T) {
+	Convey("Given a 3x4 matrix", t, func() {
+		m := M(3, 4,
+			1, 2, 3, 4,
+			5, 6, 7, 8,
+			9, 10, 11, 12)
+
+		Convey("Im2Col unfolds windows into columns", func() {
+			cols := Im2Col(m, 2, 2, 1)
+			So(cols.Shape(), ShouldResemble, []int{4, 6})
+			So(cols.Col(0), ShouldResemble, []float64{1, 2, 5, 6})
+			So(cols.Col(5), ShouldResemble, []float64{7, 8, 11, 12})
+			So(Im2Col(m, 2, 2, 2).Shape(), ShouldResemble, []int{4, 2})
+		})
+
+		Convey("Multiplying by Im2Col correlates", func() {
+			k := M(2, 2, 1, -1, 2, 0.5)
+			for _, stride := range []int{1, 2} {
+				flat := M(1, 4, k.Array()...).MProd(Im2Col(m, 2, 2, stride))
+				So(flat.Array(), ShouldResemble, Correlate2D(m, k, PadValid, stride).Array())
+			}
+		})
+
+		Convey("MaxPool and AvgPool reduce each window", func() {
+			So(MaxPool(m, 2, 2, 2).Equal(M(1, 2, 6, 8)), ShouldBeTrue)
+			So(AvgPool(m, 2, 2, 2).Equal(M(1, 2, 3.5, 5.5)), ShouldBeTrue)
+			So(MaxPool(m, 3, 1, 1).Equal(M(1, 4, 9, 10, 11, 12)), ShouldBeTrue)
+			So(AvgPool(m.SparseCoo(), 1, 4, 1).Equal(M(3, 1, 2.5, 6.5, 10.5)), ShouldBeTrue)
+		})
+
+		Convey("Windows which don't fit panic", func() {
+			So(func() { Im2Col(m, 4, 1, 1) }, ShouldPanic)
+			So(func() { MaxPool(m, 0, 1, 1) }, ShouldPanic)
+			So(func() { AvgPool(m, 2, 2, 0) }, ShouldPanic)
+		})
+	})
+}
@@ -0,0 +1,229 @@
+package matrix
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+// Nonzero when debug mode is on
+var debugMode int32
+
+// Turn debug mode on or off. In debug mode, the package functions which
+// operate on arrays, such as Add(), MProd() and Inverse(), first check that
+// the internal storage of their arguments is consistent, and panic with
+// ErrInvariant if it isn't. This catches corruption, such as a dense array's
+// storage being resized through a view, close to its cause. The checks are
+// slow, so debug mode is off by default.
+func Debug(on bool) {
+	if on {
+		atomic.StoreInt32(&debugMode, 1)
+	} else {
+		atomic.StoreInt32(&debugMode, 0)
+	}
+}
+
+// Returns true if debug mode is on
+func Debugging() bool {
+	return atomic.LoadInt32(&debugMode) != 0
+}
+
+// In debug mode, panic with ErrInvariant if any array is inconsistent
+func debugCheck(op string, array NDArray, others ...NDArray) {
+	if !Debugging() {
+		return
+	}
+	for _, a := range append([]NDArray{array}, others...) {
+		if problem := invariantProblem(a); problem != "" {
+			panic(ErrInvariant{Op: op, Problem: problem})
+		}
+	}
+}
+
+// Check that the internal storage of an array is consistent, whether or not
+// debug mode is on. Returns nil or ErrInvariant.
+//
+// The checks are: the shape has no negative dimensions; dense storage,
+// including that of a modified WithValue() array, holds exactly one value per
+// element; sparse coo storage has one map per stored row, with column keys in
+// range and no stored zeros; sparse diagonal storage holds exactly
+// min(rows, cols) values; and compressed sparse storage has one offset per
+// row or column plus one, starting at zero and never decreasing, with
+// increasing minor indices in range and no stored zeros. A Permutation must
+// match its stored inverse, and a broadcast matrix must hold one value per
+// row or column.
+func CheckInvariants(array NDArray) error {
+	if problem := invariantProblem(array); problem != "" {
+		return ErrInvariant{Op: "CheckInvariants", Problem: problem}
+	}
+	return nil
+}
+
+// Describe the first inconsistency found in an array, or return ""
+func invariantProblem(array NDArray) string {
+	switch a := array.(type) {
+	case *denseF64Array:
+		return denseProblem(a)
+	case *sparseCooF64Matrix:
+		return cooProblem(a)
+	case *sparseDiagF64Matrix:
+		return diagProblem(a)
+	case *sparseCompressedF64Matrix:
+		return compressedProblem(a)
+	case *constF64Array:
+		if d := a.stored(); d != nil {
+			return denseProblem(d)
+		}
+		return shapeProblem(a.shape)
+	case *LabeledMatrix:
+		if problem := invariantProblem(a.Matrix); problem != "" {
+			return problem
+		}
+		if a.rowLabels != nil && len(a.rowLabels) != a.Rows() {
+			return fmt.Sprintf("%d row labels for %d rows", len(a.rowLabels), a.Rows())
+		}
+		if a.colLabels != nil && len(a.colLabels) != a.Cols() {
+			return fmt.Sprintf("%d column labels for %d columns", len(a.colLabels), a.Cols())
+		}
+	case *frozenMatrix:
+		return invariantProblem(a.Matrix)
+	case *broadcastMatrix:
+		want := a.rows
+		if a.byRow {
+			want = a.cols
+		}
+		if len(a.values) != want {
+			return fmt.Sprintf("%d broadcast values for shape %v", len(a.values), a.Shape())
+		}
+	case *rowSubset:
+		for _, row := range a.rows {
+			if row < 0 || row >= a.m.Rows() {
+				return fmt.Sprintf("row %d selected from a matrix with %d rows", row, a.m.Rows())
+			}
+		}
+	case *Permutation:
+		if len(a.inv) != len(a.perm) {
+			return fmt.Sprintf("inverse of length %d for a permutation of length %d", len(a.inv), len(a.perm))
+		}
+		for i, j := range a.perm {
+			if j < 0 || j >= len(a.inv) || a.inv[j] != i {
+				return fmt.Sprintf("permutation %v doesn't match its inverse %v", a.perm, a.inv)
+			}
+		}
+	case *syncMatrix:
+		a.mu.RLock()
+		defer a.mu.RUnlock()
+		return invariantProblem(a.m)
+	}
+	return ""
+}
+
+// Describe a problem with the shape of an array, or return ""
+func shapeProblem(shape []int) string {
+	for _, d := range shape {
+		if d < 0 {
+			return fmt.Sprintf("negative dimension in shape %v", shape)
+		}
+	}
+	return ""
+}
+
+func denseProblem(a *denseF64Array) string {
+	if problem := shapeProblem(a.shape); problem != "" {
+		return problem
+	}
+	size := 1
+	for _, d := range a.shape {
+		size *= d
+	}
+	if len(a.array) != size {
+		return fmt.Sprintf("%d stored values for shape %v", len(a.array), a.shape)
+	}
+	if a.transpose && len(a.shape) != 2 {
+		return fmt.Sprintf("transposed array with shape %v", a.shape)
+	}
+	return ""
+}
+
+func cooProblem(a *sparseCooF64Matrix) string {
+	if len(a.shape) != 2 {
+		return fmt.Sprintf("sparse coo matrix with shape %v", a.shape)
+	}
+	if problem := shapeProblem(a.shape); problem != "" {
+		return problem
+	}
+	rows, cols := a.shape[0], a.shape[1]
+	if a.transpose {
+		rows, cols = cols, rows
+	}
+	if len(a.values) != rows {
+		return fmt.Sprintf("%d stored rows for shape %v", len(a.values), a.shape)
+	}
+	for row, values := range a.values {
+		for col, v := range values {
+			if col < 0 || col >= cols {
+				return fmt.Sprintf("stored column %d in row %d for shape %v", col, row, a.shape)
+			}
+			if v == 0 {
+				return fmt.Sprintf("stored zero at row %d, column %d", row, col)
+			}
+		}
+	}
+	return ""
+}
+
+func compressedProblem(a *sparseCompressedF64Matrix) string {
+	if len(a.shape) != 2 {
+		return fmt.Sprintf("%v matrix with shape %v", a.Sparsity(), a.shape)
+	}
+	if problem := shapeProblem(a.shape); problem != "" {
+		return problem
+	}
+	major, minor := a.shape[0], a.shape[1]
+	if a.byCol {
+		major, minor = minor, major
+	}
+	s := a.store
+	if len(s.indptr) != major+1 {
+		return fmt.Sprintf("%d stored offsets for shape %v", len(s.indptr), a.shape)
+	}
+	if s.indptr[0] != 0 {
+		return fmt.Sprintf("first offset %d isn't zero", s.indptr[0])
+	}
+	if len(s.ind) != len(s.data) || s.indptr[major] != len(s.data) {
+		return fmt.Sprintf("%d indices and %d values for final offset %d", len(s.ind), len(s.data), s.indptr[major])
+	}
+	for i := 0; i < major; i++ {
+		if s.indptr[i] > s.indptr[i+1] {
+			return fmt.Sprintf("decreasing offsets %d and %d at %d", s.indptr[i], s.indptr[i+1], i)
+		}
+		for pos := s.indptr[i]; pos < s.indptr[i+1]; pos++ {
+			if s.ind[pos] < 0 || s.ind[pos] >= minor {
+				return fmt.Sprintf("stored index %d at %d for shape %v", s.ind[pos], i, a.shape)
+			}
+			if pos > s.indptr[i] && s.ind[pos] <= s.ind[pos-1] {
+				return fmt.Sprintf("indices %d and %d out of order at %d", s.ind[pos-1], s.ind[pos], i)
+			}
+			if s.data[pos] == 0 {
+				return fmt.Sprintf("stored zero at index %d of %d", s.ind[pos], i)
+			}
+		}
+	}
+	return ""
+}
+
+func diagProblem(a *sparseDiagF64Matrix) string {
+	if len(a.shape) != 2 {
+		return fmt.Sprintf("sparse diagonal matrix with shape %v", a.shape)
+	}
+	if problem := shapeProblem(a.shape); problem != "" {
+		return problem
+	}
+	size := a.shape[0]
+	if a.shape[1] < size {
+		size = a.shape[1]
+	}
+	if len(a.diag) != size {
+		return fmt.Sprintf("%d stored diagonal values for shape %v", len(a.diag), a.shape)
+	}
+	return ""
+}
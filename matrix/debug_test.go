@@ -0,0 +1,51 @@
+package matrix
+
+import (
+	"errors"
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+func TestDebug(t *testing.T) {
+	Convey("Given corrupted arrays", t, func() {
+		dense := M(2, 2, 1, 2, 3, 4).(*denseF64Array)
+		dense.array = dense.array[:3]
+		coo := SparseCoo(2, 2, 1, 0, 0, 1).(*sparseCooF64Matrix)
+		coo.values[1][0] = 0
+		diag := Eye(3).(*sparseDiagF64Matrix)
+		diag.diag = diag.diag[:2]
+		outOfRange := SparseCoo(2, 2).(*sparseCooF64Matrix)
+		outOfRange.values[0][2] = 1
+
+		Convey("CheckInvariants reports the problems", func() {
+			var invErr ErrInvariant
+			for _, m := range []NDArray{dense, coo, diag, outOfRange, Freeze(coo), WithLabels(diag, nil, nil)} {
+				So(errors.As(CheckInvariants(m), &invErr), ShouldBeTrue)
+			}
+			So(CheckInvariants(coo).Error(), ShouldEqual, "CheckInvariants: broken array invariant: stored zero at row 1, column 0")
+			So(CheckInvariants(M(2, 2, 1, 2, 3, 4)), ShouldBeNil)
+			So(CheckInvariants(M(2, 3, 1, 2, 3, 4, 5, 6).T()), ShouldBeNil)
+			So(CheckInvariants(SparseCoo(2, 3, 1).T()), ShouldBeNil)
+			So(CheckInvariants(Synchronized(SparseDiag(2, 3))), ShouldBeNil)
+		})
+
+		Convey("Operations don't check invariants by default", func() {
+			So(Debugging(), ShouldBeFalse)
+			So(func() { Equal(coo, Eye(2)) }, ShouldNotPanic)
+		})
+
+		Convey("Operations check invariants in debug mode", func() {
+			Debug(true)
+			Reset(func() { Debug(false) })
+			So(Debugging(), ShouldBeTrue)
+
+			var invErr ErrInvariant
+			So(errors.As(try(func() { Add(Eye(2), coo) }), &invErr), ShouldBeTrue)
+			So(invErr.Op, ShouldEqual, "Add")
+			So(errors.As(try(func() { MProd(Eye(3), diag) }), &invErr), ShouldBeTrue)
+			So(invErr.Op, ShouldEqual, "MProd")
+			So(errors.As(try(func() { Norm(dense, 1) }), &invErr), ShouldBeTrue)
+			So(func() { Add(Eye(2), Eye(2)) }, ShouldNotPanic)
+		})
+	})
+}
@@ -0,0 +1,364 @@
+package matrix
+
+import (
+	"fmt"
+	"gonum.org/v1/gonum/mat"
+	"math"
+)
+
+// The size of the factors computed by SVD()
+type SVDKind int
+
+const (
+	// The thin decomposition of an m x n matrix, with k = min(m, n): U is
+	// m x k, S is k x k and V is n x k. It is enough to reconstruct the
+	// matrix, and is much cheaper when m and n differ greatly.
+	SVDThin SVDKind = iota
+
+	// The full decomposition of an m x n matrix: U is m x m, S is m x n and
+	// V is n x n, so U and V are square orthogonal matrices.
+	SVDFull
+)
+
+// Get the singular value decomposition of m, so that m = U S V^T. U and V
+// have orthonormal columns, and S is a sparse diagonal matrix holding the
+// singular values in decreasing order. Returns ErrNoConvergence if the
+// decomposition fails.
+func SVD(m Matrix, kind SVDKind) (U, S, V Matrix, err error) {
+	debugCheck("SVD", m)
+	var gonumKind mat.SVDKind
+	switch kind {
+	case SVDThin:
+		gonumKind = mat.SVDThin
+	case SVDFull:
+		gonumKind = mat.SVDFull
+	default:
+		panic(fmt.Sprintf("Unknown SVD kind %d", kind))
+	}
+	var svd mat.SVD
+	if !svd.Factorize(ToMat(m), gonumKind) {
+		return nil, nil, nil, ErrNoConvergence
+	}
+	var u, v mat.Dense
+	svd.UTo(&u)
+	svd.VTo(&v)
+	rows, cols := u.RawMatrix().Cols, v.RawMatrix().Cols
+	return ToMatrix(&u), SparseDiag(rows, cols, svd.Values(nil)...), ToMatrix(&v), nil
+}
+
+// The LU decomposition of a square matrix A, as P A = L U for a permutation
+// P, unit lower triangular L and upper triangular U. Create it with LU(),
+// and use it to solve several systems with the same coefficients.
+type LUFactors struct {
+	lu mat.LU
+	n  int
+}
+
+// Get the LU decomposition of a square matrix, with partial pivoting
+func LU(m Matrix) *LUFactors {
+	debugCheck("LU", m)
+	if m.Rows() != m.Cols() {
+		panic(ErrShapeMismatch{Op: "LU", Got: m.Shape(), Want: []int{m.Rows(), m.Rows()}})
+	}
+	f := &LUFactors{n: m.Rows()}
+	f.lu.Factorize(ToMat(m))
+	return f
+}
+
+// Get the determinant of A
+func (f *LUFactors) Det() float64 {
+	return f.lu.Det()
+}
+
+// Get the unit lower triangular factor L
+func (f *LUFactors) L() Matrix {
+	var l mat.TriDense
+	f.lu.LTo(&l)
+	return ToMatrix(&l)
+}
+
+// Get the upper triangular factor U
+func (f *LUFactors) U() Matrix {
+	var u mat.TriDense
+	f.lu.UTo(&u)
+	return ToMatrix(&u)
+}
+
+// Get the row permutation P
+func (f *LUFactors) P() *Permutation {
+	return NewPermutation(f.lu.RowPivots(nil)).Invert()
+}
+
+// Solve for x, where A x = b. Returns an error wrapping ErrSingular if A is
+// singular or too ill-conditioned.
+func (f *LUFactors) Solve(b Matrix) (Matrix, error) {
+	if b.Rows() != f.n {
+		panic(ErrShapeMismatch{Op: "Solve", Got: b.Shape(), Want: []int{f.n, -1}})
+	}
+	var x mat.Dense
+	if err := f.lu.SolveTo(&x, false, ToMat(b)); err != nil {
+		return nil, singularError(err)
+	}
+	return ToMatrix(&x), nil
+}
+
+// The QR decomposition of an m x n matrix A with m >= n, as A = Q R for an
+// m x m orthogonal Q and m x n upper triangular R. Create it with QR(), and
+// use it to solve several least squares problems with the same coefficients.
+type QRFactors struct {
+	qr         mat.QR
+	rows, cols int
+}
+
+// Get the QR decomposition of a matrix with at least as many rows as columns
+func QR(m Matrix) *QRFactors {
+	debugCheck("QR", m)
+	if m.Rows() < m.Cols() {
+		panic(ErrShapeMismatch{Op: "QR", Got: m.Shape(), Want: []int{-1, m.Rows()}})
+	}
+	f := &QRFactors{rows: m.Rows(), cols: m.Cols()}
+	f.qr.Factorize(ToMat(m))
+	return f
+}
+
+// Get the determinant of A, which must be square
+func (f *QRFactors) Det() float64 {
+	if f.rows != f.cols {
+		panic(ErrShapeMismatch{Op: "Det", Got: []int{f.rows, f.cols}, Want: []int{f.cols, f.cols}})
+	}
+	var q, r mat.Dense
+	f.qr.QTo(&q)
+	f.qr.RTo(&r)
+	det := mat.Det(&q)
+	for i := 0; i < f.cols; i++ {
+		det *= r.At(i, i)
+	}
+	return det
+}
+
+// Get the orthogonal factor Q
+func (f *QRFactors) Q() Matrix {
+	var q mat.Dense
+	f.qr.QTo(&q)
+	return ToMatrix(&q)
+}
+
+// Get the upper triangular factor R
+func (f *QRFactors) R() Matrix {
+	var r mat.Dense
+	f.qr.RTo(&r)
+	return ToMatrix(&r)
+}
+
+// Solve for x, where A x = b, in the least squares sense if A has more rows
+// than columns. Returns an error wrapping ErrSingular if A doesn't have full
+// rank.
+func (f *QRFactors) Solve(b Matrix) (Matrix, error) {
+	if b.Rows() != f.rows {
+		panic(ErrShapeMismatch{Op: "Solve", Got: b.Shape(), Want: []int{f.rows, -1}})
+	}
+	var x mat.Dense
+	if err := f.qr.SolveTo(&x, false, ToMat(b)); err != nil {
+		return nil, singularError(err)
+	}
+	return ToMatrix(&x), nil
+}
+
+// The Cholesky decomposition of a symmetric positive definite matrix A, as
+// A = L L^T for lower triangular L. Create it with Chol(), and use it to solve
+// several systems with the same coefficients; this is about twice as fast as
+// LU.
+type CholFactors struct {
+	chol mat.Cholesky
+	n    int
+}
+
+// Get the Cholesky decomposition of a symmetric positive definite matrix.
+// Only the upper triangle of m is read. Returns ErrNotPositiveDefinite if m
+// isn't positive definite.
+func Chol(m Matrix) (*CholFactors, error) {
+	debugCheck("Chol", m)
+	n := m.Rows()
+	if m.Cols() != n {
+		panic(ErrShapeMismatch{Op: "Chol", Got: m.Shape(), Want: []int{n, n}})
+	}
+	f := &CholFactors{n: n}
+	if !f.chol.Factorize(mat.NewSymDense(n, m.Array())) {
+		return nil, ErrNotPositiveDefinite
+	}
+	return f, nil
+}
+
+// Solve for x, where a x = b and a is symmetric positive definite, using the
+// Cholesky decomposition of a
+func CholSolve(a, b Matrix) (Matrix, error) {
+	f, err := Chol(a)
+	if err != nil {
+		return nil, err
+	}
+	return f.Solve(b)
+}
+
+// Get the determinant of A
+func (f *CholFactors) Det() float64 {
+	return f.chol.Det()
+}
+
+// Get the log of the determinant of A, which is more accurate than Det() for
+// large matrices
+func (f *CholFactors) LogDet() float64 {
+	return f.chol.LogDet()
+}
+
+// Get the lower triangular factor L
+func (f *CholFactors) L() Matrix {
+	var l mat.TriDense
+	f.chol.LTo(&l)
+	return ToMatrix(&l)
+}
+
+// Solve for x, where A x = b. Returns an error wrapping ErrSingular if A is
+// too ill-conditioned.
+func (f *CholFactors) Solve(b Matrix) (Matrix, error) {
+	if b.Rows() != f.n {
+		panic(ErrShapeMismatch{Op: "Solve", Got: b.Shape(), Want: []int{f.n, -1}})
+	}
+	var x mat.Dense
+	if err := f.chol.SolveTo(&x, ToMat(b)); err != nil {
+		return nil, singularError(err)
+	}
+	return ToMatrix(&x), nil
+}
+
+// A factorization of a coefficient matrix A, which solves A x = b for as many
+// right-hand sides as needed without factoring A again. LUFactors, QRFactors
+// and CholFactors all implement it.
+type Factors interface {
+	// Solve for x, where A x = b
+	Solve(b Matrix) (Matrix, error)
+}
+
+// Factor a so that it can be reused to solve several systems: a square
+// diagonal matrix is inverted directly, other square matrices are factored
+// with LU() and matrices with more rows than columns with QR(), for least
+// squares solutions. Panics if a has more columns than rows.
+func Factor(a Matrix) Factors {
+	debugCheck("Factor", a)
+	switch {
+	case a.Rows() < a.Cols():
+		panic(ErrShapeMismatch{Op: "Factor", Got: a.Shape(), Want: []int{a.Cols(), a.Cols()}})
+	case a.Rows() > a.Cols():
+		return QR(a)
+	}
+	if recip, ok := diagReciprocals(a); ok {
+		return diagSolver{recip: recip, n: a.Rows()}
+	}
+	return LU(a)
+}
+
+// Solves systems with a square diagonal coefficient matrix
+type diagSolver struct {
+	recip []float64 // nil if the matrix is singular
+	n     int
+}
+
+// Solve for x by scaling the rows of b
+func (f diagSolver) Solve(b Matrix) (Matrix, error) {
+	if b.Rows() != f.n {
+		panic(ErrShapeMismatch{Op: "Solve", Got: b.Shape(), Want: []int{f.n, -1}})
+	}
+	if f.recip == nil {
+		return nil, fmt.Errorf("%w: zero on the diagonal", ErrSingular)
+	}
+	return MProd(Diag(f.recip...), b), nil
+}
+
+// Solve for each x_i, where A x_i = b_i, using a factorization of A. The
+// right-hand sides are solved together as the columns of a single matrix, so
+// this is faster than solving them one at a time.
+func SolveAll(f Factors, bs ...Matrix) ([]Matrix, error) {
+	if len(bs) == 0 {
+		return nil, nil
+	}
+	arrays := make([]NDArray, len(bs))
+	for idx, b := range bs {
+		arrays[idx] = b.Dense()
+	}
+	x, err := f.Solve(Concat(1, arrays[0], arrays[1:]...).M())
+	if err != nil {
+		return nil, err
+	}
+	xs := make([]Matrix, len(bs))
+	col := 0
+	for idx, b := range bs {
+		xs[idx] = Slice(x, []int{0, col}, []int{x.Rows(), col + b.Cols()}).M()
+		col += b.Cols()
+	}
+	return xs, nil
+}
+
+// Solve for each x_i, where a x_i = b_i, factoring a only once. If the
+// systems can't be solved, every result is filled with NaN.
+func LDivideAll(a Matrix, bs ...Matrix) []Matrix {
+	xs, err := SolveAll(Factor(a), bs...)
+	if err != nil {
+		xs = make([]Matrix, len(bs))
+		for idx, b := range bs {
+			xs[idx] = WithValue(math.NaN(), a.Cols(), b.Cols()).M()
+		}
+	}
+	return xs
+}
+
+// Get the eigenvalues and right eigenvectors of a square matrix, so that
+// m v_j = w_j v_j for each column v_j of the eigenvectors. The eigenvalues
+// w_j may be complex, so their real and imaginary parts are returned as
+// separate n x 1 vectors, and the eigenvectors as separate n x n matrices,
+// as FFT() does. Complex eigenvalues come in conjugate pairs, with the one
+// having a positive imaginary part first. Returns ErrNoConvergence if the
+// decomposition fails.
+func Eig(m Matrix) (valRe, valIm, vecRe, vecIm Matrix, err error) {
+	debugCheck("Eig", m)
+	n := m.Rows()
+	if m.Cols() != n {
+		panic(ErrShapeMismatch{Op: "Eig", Got: m.Shape(), Want: []int{n, n}})
+	}
+	var eig mat.Eigen
+	if !eig.Factorize(ToMat(m), mat.EigenRight) {
+		return nil, nil, nil, nil, ErrNoConvergence
+	}
+	var vecs mat.CDense
+	eig.VectorsTo(&vecs)
+	valRe, valIm = Dense(n, 1).M(), Dense(n, 1).M()
+	vecRe, vecIm = Dense(n, n).M(), Dense(n, n).M()
+	for j, w := range eig.Values(nil) {
+		valRe.ItemSet(real(w), j, 0)
+		valIm.ItemSet(imag(w), j, 0)
+		for i := 0; i < n; i++ {
+			v := vecs.At(i, j)
+			vecRe.ItemSet(real(v), i, j)
+			vecIm.ItemSet(imag(v), i, j)
+		}
+	}
+	return valRe, valIm, vecRe, vecIm, nil
+}
+
+// Get the eigenvalues and eigenvectors of a symmetric matrix, so that
+// m = V diag(w) V^T. The eigenvalues are returned as an n x 1 vector in
+// increasing order, and the orthonormal eigenvectors as the columns of V.
+// Only the upper triangle of m is read. Returns ErrNoConvergence if the
+// decomposition fails.
+func EigSym(m Matrix) (values, V Matrix, err error) {
+	debugCheck("EigSym", m)
+	n := m.Rows()
+	if m.Cols() != n {
+		panic(ErrShapeMismatch{Op: "EigSym", Got: m.Shape(), Want: []int{n, n}})
+	}
+	var eig mat.EigenSym
+	if !eig.Factorize(mat.NewSymDense(n, m.Array()), true) {
+		return nil, nil, ErrNoConvergence
+	}
+	var v mat.Dense
+	eig.VectorsTo(&v)
+	return A([]int{n, 1}, eig.Values(nil)...).M(), ToMatrix(&v), nil
+}
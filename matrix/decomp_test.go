@@ -0,0 +1,261 @@
+package matrix
+
+import (
+	"errors"
+	. "github.com/smartystreets/goconvey/convey"
+	"math"
+	"testing"
+)
+
+func TestSVD(t *testing.T) {
+	Convey("Given a 4x2 matrix", t, func() {
+		m := M(4, 2,
+			2, 0,
+			1, 3,
+			0, 1,
+			4, -2)
+
+		Convey("The thin SVD reconstructs it", func() {
+			U, S, V, err := SVD(m, SVDThin)
+			So(err, ShouldBeNil)
+			So(U.Shape(), ShouldResemble, []int{4, 2})
+			So(S.Shape(), ShouldResemble, []int{2, 2})
+			So(V.Shape(), ShouldResemble, []int{2, 2})
+			So(S.Sparsity(), ShouldEqual, SparseDiagMatrix)
+			So(ApproxEqual(U.MProd(S, V.T()), m, 1e-12, 1e-12), ShouldBeTrue)
+			So(ApproxEqual(U.T().MProd(U), Eye(2), 1e-12, 1e-12), ShouldBeTrue)
+			So(S.Item(0, 0), ShouldBeGreaterThanOrEqualTo, S.Item(1, 1))
+			So(S.Item(0, 0), ShouldAlmostEqual, Norm(m, 2), 1e-12)
+		})
+
+		Convey("The full SVD has square orthogonal factors", func() {
+			U, S, V, err := SVD(m.SparseCoo(), SVDFull)
+			So(err, ShouldBeNil)
+			So(U.Shape(), ShouldResemble, []int{4, 4})
+			So(S.Shape(), ShouldResemble, []int{4, 2})
+			So(V.Shape(), ShouldResemble, []int{2, 2})
+			So(ApproxEqual(U.MProd(S, V.T()), m, 1e-12, 1e-12), ShouldBeTrue)
+			So(ApproxEqual(U.T().MProd(U), Eye(4), 1e-12, 1e-12), ShouldBeTrue)
+		})
+
+		Convey("An unknown kind panics", func() {
+			So(func() { SVD(m, SVDKind(7)) }, ShouldPanic)
+		})
+	})
+}
+
+func TestLU(t *testing.T) {
+	Convey("Given the LU decomposition of a square matrix", t, func() {
+		a := M(3, 3,
+			1, 2, 0,
+			3, 1, 4,
+			0, 5, 2)
+		f := LU(a)
+
+		Convey("P A = L U", func() {
+			So(ApproxEqual(f.P().MProd(a), f.L().MProd(f.U()), 1e-12, 1e-12), ShouldBeTrue)
+			So(f.L().Item(0, 0), ShouldEqual, 1)
+			So(f.L().Item(0, 2), ShouldEqual, 0)
+			So(f.U().Item(2, 0), ShouldEqual, 0)
+		})
+
+		Convey("The determinant is correct", func() {
+			So(f.Det(), ShouldAlmostEqual, -30, 1e-12)
+		})
+
+		Convey("Solve can be called repeatedly", func() {
+			for _, b := range []Matrix{M(3, 1, 1, 2, 3), M(3, 2, 1, 0, 0, 1, 2, 2)} {
+				x, err := f.Solve(b)
+				So(err, ShouldBeNil)
+				So(ApproxEqual(a.MProd(x), b, 1e-12, 1e-12), ShouldBeTrue)
+			}
+		})
+
+		Convey("Singular matrices give ErrSingular", func() {
+			_, err := LU(M(2, 2, 1, 2, 2, 4)).Solve(M(2, 1, 1, 1))
+			So(errors.Is(err, ErrSingular), ShouldBeTrue)
+		})
+
+		Convey("Non-square matrices panic", func() {
+			So(func() { LU(M(2, 3, 1, 2, 3, 4, 5, 6)) }, ShouldPanic)
+		})
+	})
+}
+
+func TestQR(t *testing.T) {
+	Convey("Given the QR decomposition of a tall matrix", t, func() {
+		a := M(4, 2,
+			1, 1,
+			1, 2,
+			1, 3,
+			1, 4)
+		f := QR(a)
+
+		Convey("A = Q R, with orthogonal Q and triangular R", func() {
+			So(ApproxEqual(f.Q().MProd(f.R()), a, 1e-12, 1e-12), ShouldBeTrue)
+			So(ApproxEqual(f.Q().T().MProd(f.Q()), Eye(4), 1e-12, 1e-12), ShouldBeTrue)
+			So(f.R().Item(1, 0), ShouldEqual, 0)
+		})
+
+		Convey("Solve fits least squares", func() {
+			x, err := f.Solve(M(4, 1, 3, 5, 7, 9))
+			So(err, ShouldBeNil)
+			So(ApproxEqual(x, M(2, 1, 1, 2), 1e-12, 1e-12), ShouldBeTrue)
+		})
+
+		Convey("Det needs a square matrix", func() {
+			So(func() { f.Det() }, ShouldPanic)
+			So(QR(M(2, 2, 4, 1, 2, 3)).Det(), ShouldAlmostEqual, 10, 1e-12)
+			So(QR(M(2, 2, 0, 1, 1, 0)).Det(), ShouldAlmostEqual, -1, 1e-12)
+		})
+	})
+}
+
+func TestChol(t *testing.T) {
+	Convey("Given a symmetric positive definite matrix", t, func() {
+		a := M(3, 3,
+			4, 2, 0,
+			2, 5, 1,
+			0, 1, 3)
+		f, err := Chol(a)
+		So(err, ShouldBeNil)
+
+		Convey("L is lower triangular and L L^T = A", func() {
+			l := f.L()
+			So(l.Item(0, 2), ShouldEqual, 0)
+			So(ApproxEqual(l.MProd(l.T()), a, 1e-12, 1e-12), ShouldBeTrue)
+		})
+
+		Convey("The determinant is correct", func() {
+			So(f.Det(), ShouldAlmostEqual, 44, 1e-12)
+			So(f.LogDet(), ShouldAlmostEqual, math.Log(44), 1e-12)
+		})
+
+		Convey("Solve and CholSolve solve the system", func() {
+			b := M(3, 2, 1, 0, 2, 1, 3, 0)
+			x, err := f.Solve(b)
+			So(err, ShouldBeNil)
+			So(ApproxEqual(a.MProd(x), b, 1e-12, 1e-12), ShouldBeTrue)
+			x2, err := CholSolve(a.SparseCoo(), b)
+			So(err, ShouldBeNil)
+			So(ApproxEqual(x2, x, 1e-12, 1e-12), ShouldBeTrue)
+		})
+
+		Convey("Indefinite matrices give ErrNotPositiveDefinite", func() {
+			_, err := Chol(M(2, 2, 1, 2, 2, 1))
+			So(err, ShouldEqual, ErrNotPositiveDefinite)
+			_, err = CholSolve(M(2, 2, -1, 0, 0, 1), M(2, 1, 1, 1))
+			So(err, ShouldEqual, ErrNotPositiveDefinite)
+		})
+	})
+}
+
+func TestFactor(t *testing.T) {
+	Convey("Given several right-hand sides", t, func() {
+		a := M(3, 3,
+			4, 1, 0,
+			1, 3, 1,
+			0, 1, 2)
+		bs := []Matrix{M(3, 1, 1, 2, 3), M(3, 2, 1, 0, 0, 1, 2, 2), SparseCoo(3, 1, 0, 5, 0)}
+
+		Convey("Factor picks a factorization for the shape", func() {
+			So(Factor(a), ShouldHaveSameTypeAs, &LUFactors{})
+			So(Factor(M(3, 2, 1, 0, 0, 1, 1, 1)), ShouldHaveSameTypeAs, &QRFactors{})
+			So(Factor(Diag(1, 2)), ShouldHaveSameTypeAs, diagSolver{})
+			So(func() { Factor(M(2, 3, 1, 2, 3, 4, 5, 6)) }, ShouldPanic)
+		})
+
+		Convey("SolveAll solves each system", func() {
+			xs, err := SolveAll(Factor(a), bs...)
+			So(err, ShouldBeNil)
+			So(len(xs), ShouldEqual, 3)
+			for idx, x := range xs {
+				So(x.Shape(), ShouldResemble, []int{3, bs[idx].Cols()})
+				So(ApproxEqual(a.MProd(x), bs[idx], 1e-12, 1e-12), ShouldBeTrue)
+			}
+		})
+
+		Convey("SolveAll works with other factorizations", func() {
+			f, err := Chol(a)
+			So(err, ShouldBeNil)
+			xs, err := SolveAll(f, bs...)
+			So(err, ShouldBeNil)
+			So(ApproxEqual(xs[1], LDivide(a, bs[1]), 1e-12, 1e-12), ShouldBeTrue)
+			xs, err = SolveAll(Factor(Diag(2, 4, 5)), bs...)
+			So(err, ShouldBeNil)
+			So(xs[2].Array(), ShouldResemble, []float64{0, 1.25, 0})
+		})
+
+		Convey("LDivideAll fills unsolvable systems with NaN", func() {
+			xs := LDivideAll(a, bs...)
+			So(ApproxEqual(xs[0], LDivide(a, bs[0]), 1e-12, 1e-12), ShouldBeTrue)
+			xs = LDivideAll(Diag(1, 0, 1), bs...)
+			So(len(xs), ShouldEqual, 3)
+			So(math.IsNaN(xs[1].Item(2, 1)), ShouldBeTrue)
+			_, err := SolveAll(Factor(Diag(1, 0, 1)), bs...)
+			So(errors.Is(err, ErrSingular), ShouldBeTrue)
+		})
+	})
+}
+
+func TestEig(t *testing.T) {
+	Convey("Given a matrix with real eigenvalues", t, func() {
+		m := M(2, 2,
+			2, 1,
+			0, 3)
+
+		Convey("Eig finds them", func() {
+			re, im, vre, vim, err := Eig(m)
+			So(err, ShouldBeNil)
+			So(re.Shape(), ShouldResemble, []int{2, 1})
+			So(im.CountNonzero(), ShouldEqual, 0)
+			So(vim.CountNonzero(), ShouldEqual, 0)
+			So(ApproxEqual(m.MProd(vre), vre.MProd(SparseDiag(2, 2, re.Array()...)), 1e-12, 1e-12), ShouldBeTrue)
+		})
+	})
+
+	Convey("Given a rotation", t, func() {
+		m := M(2, 2,
+			0, -1,
+			1, 0)
+
+		Convey("Eig finds the conjugate pair of eigenvalues", func() {
+			re, im, vre, vim, err := Eig(m)
+			So(err, ShouldBeNil)
+			So(ApproxEqual(re, M(2, 1, 0, 0), 1e-12, 1e-12), ShouldBeTrue)
+			So(ApproxEqual(im, M(2, 1, 1, -1), 1e-12, 1e-12), ShouldBeTrue)
+
+			// m v = w v for complex w and v
+			w := complex(re.Item(0, 0), im.Item(0, 0))
+			for i := 0; i < 2; i++ {
+				var mv complex128
+				for k := 0; k < 2; k++ {
+					mv += complex(m.Item(i, k), 0) * complex(vre.Item(k, 0), vim.Item(k, 0))
+				}
+				wv := w * complex(vre.Item(i, 0), vim.Item(i, 0))
+				So(real(mv), ShouldAlmostEqual, real(wv), 1e-12)
+				So(imag(mv), ShouldAlmostEqual, imag(wv), 1e-12)
+			}
+		})
+	})
+
+	Convey("Given a symmetric matrix", t, func() {
+		m := M(3, 3,
+			2, 1, 0,
+			1, 2, 0,
+			0, 0, 5)
+
+		Convey("EigSym finds the eigenvalues in increasing order", func() {
+			w, v, err := EigSym(m)
+			So(err, ShouldBeNil)
+			So(ApproxEqual(w, M(3, 1, 1, 3, 5), 1e-12, 1e-12), ShouldBeTrue)
+			So(ApproxEqual(v.MProd(SparseDiag(3, 3, w.Array()...)).MProd(v.T()), m, 1e-12, 1e-12), ShouldBeTrue)
+			So(ApproxEqual(v.T().MProd(v), Eye(3), 1e-12, 1e-12), ShouldBeTrue)
+		})
+
+		Convey("Non-square matrices panic", func() {
+			So(func() { EigSym(M(2, 3, 1, 2, 3, 4, 5, 6)) }, ShouldPanic)
+			So(func() { Eig(M(2, 3, 1, 2, 3, 4, 5, 6)) }, ShouldPanic)
+		})
+	})
+}
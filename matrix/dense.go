@@ -1,7 +1,8 @@
 package matrix
 
 import (
-	"fmt"
+	"database/sql/driver"
+	"math"
 )
 
 // An n-dimensional NDArray with dense representation
@@ -31,6 +32,16 @@ func (array denseF64Array) AllF2(f func(v1, v2 float64) bool, other NDArray) boo
 	return AllF2(&array, f, other)
 }
 
+// Returns true if and only if no items are NaN or infinite
+func (array denseF64Array) AllFinite() bool {
+	for _, v := range array.array {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return false
+		}
+	}
+	return true
+}
+
 // Returns true if and only if any item is nonzero
 func (array denseF64Array) Any() bool {
 	return Any(&array)
@@ -46,6 +57,16 @@ func (array denseF64Array) AnyF2(f func(v1, v2 float64) bool, other NDArray) boo
 	return AnyF2(&array, f, other)
 }
 
+// Returns true if and only if any item is NaN
+func (array denseF64Array) AnyNaN() bool {
+	for _, v := range array.array {
+		if math.IsNaN(v) {
+			return true
+		}
+	}
+	return false
+}
+
 // Return the result of applying a function to all elements
 func (array denseF64Array) Apply(f func(float64) float64) NDArray {
 	result := array.copy()
@@ -68,19 +89,24 @@ func (array denseF64Array) Array() []float64 {
 // Set the values of the items on a given column
 func (array denseF64Array) ColSet(col int, values []float64) {
 	if col < 0 || col >= array.shape[1] {
-		panic(fmt.Sprintf("ColSet can't set col %d of a %d-col array", col, array.shape[1]))
+		panic(ErrIndexOutOfRange{Op: "ColSet", Index: []int{col}, Shape: array.shape[1:]})
 	} else if len(values) != array.shape[0] {
-		panic(fmt.Sprintf("ColSet has %d rows but got %d values", array.shape[0], len(values)))
+		panic(ErrShapeMismatch{Op: "ColSet", Got: []int{len(values)}, Want: array.shape[:1]})
 	}
 	for row := 0; row < array.shape[0]; row++ {
 		array.ItemSet(values[row], row, col)
 	}
 }
 
-// Get a particular column for read-only access. May or may not be a copy.
+// Get a copy of a particular column
 func (array denseF64Array) Col(col int) []float64 {
 	if col < 0 || col >= array.shape[1] {
-		panic(fmt.Sprintf("Can't get column %d from a %dx%d array", col, array.shape[0], array.shape[1]))
+		panic(ErrIndexOutOfRange{Op: "Col", Index: []int{col}, Shape: array.shape[1:]})
+	}
+	if array.transpose {
+		// Columns are contiguous in column-major storage
+		start := col * array.shape[0]
+		return append([]float64(nil), array.array[start:start+array.shape[0]]...)
 	}
 	result := make([]float64, array.shape[0])
 	for row := 0; row < array.shape[0]; row++ {
@@ -101,6 +127,16 @@ func (array denseF64Array) Concat(axis int, others ...NDArray) NDArray {
 	return Concat(axis, &array, others...)
 }
 
+// Returns a deep copy of this array
+func (array denseF64Array) Clone() NDArray {
+	return array.copy()
+}
+
+// Get the condition number for the matrix norm of the specified ordinality
+func (array denseF64Array) Cond(ord float64) float64 {
+	return Cond(&array, ord)
+}
+
 // Returns a duplicate of this array
 func (array denseF64Array) Copy() NDArray {
 	return array.copy()
@@ -141,6 +177,11 @@ func (array denseF64Array) Dense() NDArray {
 	return array.copy()
 }
 
+// Get the determinant of a square matrix
+func (array denseF64Array) Det() float64 {
+	return Det(&array)
+}
+
 // Get a column vector containing the main diagonal elements of the matrix
 func (array denseF64Array) Diag() Matrix {
 	size := array.shape[0]
@@ -161,6 +202,13 @@ func (array denseF64Array) Dist(t DistType) Matrix {
 	return Dist(&array, t)
 }
 
+// Treat the rows of this and other as points, and get the distance between
+// each pair. Returns an m x n distance matrix D such that D_i,j is the
+// distance between row i of this and row j of other.
+func (array denseF64Array) DistTo(other Matrix, t DistType) Matrix {
+	return DistTo(&array, other, t)
+}
+
 // Return the element-wise quotient of this array and one or more others.
 // This function defines 0 / 0 = 0, so it's useful for sparse arrays.
 func (array denseF64Array) Div(other ...NDArray) NDArray {
@@ -200,6 +248,14 @@ func (array denseF64Array) FlatItemSet(value float64, index int) {
 	array.array[index] = value
 }
 
+// Get the position in storage of a matrix element
+func (array denseF64Array) flatIndex(row, col int) int {
+	if array.transpose {
+		return col*array.shape[0] + row
+	}
+	return row*array.shape[1] + col
+}
+
 // Get the matrix inverse
 func (array denseF64Array) Inverse() (Matrix, error) {
 	return Inverse(&array)
@@ -209,7 +265,7 @@ func (array denseF64Array) Inverse() (Matrix, error) {
 func (array denseF64Array) Item(index ...int) float64 {
 	shape := array.shape
 	if array.transpose {
-		index[0], index[1] = index[1], index[0]
+		index = []int{index[1], index[0]}
 		shape = []int{array.shape[1], array.shape[0]}
 	}
 	return array.array[ndToFlat(shape, index)]
@@ -255,7 +311,7 @@ func (array *denseF64Array) ItemSub(value float64) NDArray {
 func (array denseF64Array) ItemSet(value float64, index ...int) {
 	shape := array.shape
 	if array.transpose {
-		index[0], index[1] = index[1], index[0]
+		index = []int{index[1], index[0]}
 		shape = []int{array.shape[1], array.shape[0]}
 	}
 	array.array[ndToFlat(shape, index)] = value
@@ -300,11 +356,22 @@ func (array *denseF64Array) Normalize() NDArray {
 	return Normalize(array)
 }
 
+// Get the Moore-Penrose pseudo-inverse, treating singular values no larger
+// than tol as zero
+func (array denseF64Array) Pinv(tol float64) (Matrix, error) {
+	return Pinv(&array, tol)
+}
+
 // Return the element-wise product of this array and one or more others
 func (array denseF64Array) Prod(other ...NDArray) NDArray {
 	return Prod(&array, other...)
 }
 
+// Get the number of singular values larger than tol
+func (array denseF64Array) Rank(tol float64) int {
+	return Rank(&array, tol)
+}
+
 // Get a 1D copy of the array, in 'C' order: rightmost axes change fastest
 func (array denseF64Array) Ravel() NDArray {
 	return Ravel(&array)
@@ -313,22 +380,29 @@ func (array denseF64Array) Ravel() NDArray {
 // Set the values of the items on a given row
 func (array denseF64Array) RowSet(row int, values []float64) {
 	if row < 0 || row >= array.shape[0] {
-		panic(fmt.Sprintf("RowSet can't set row %d of a %d-row array", row, array.shape[0]))
+		panic(ErrIndexOutOfRange{Op: "RowSet", Index: []int{row}, Shape: array.shape[:1]})
 	} else if len(values) != array.shape[1] {
-		panic(fmt.Sprintf("RowSet has %d columns but got %d values", array.shape[1], len(values)))
+		panic(ErrShapeMismatch{Op: "RowSet", Got: []int{len(values)}, Want: array.shape[1:]})
 	}
 	for col := 0; col < array.shape[1]; col++ {
 		array.ItemSet(values[col], row, col)
 	}
 }
 
-// Get a particular row for read-only access. May or may not be a copy.
+// Get a copy of a particular row
 func (array denseF64Array) Row(row int) []float64 {
 	if row < 0 || row >= array.shape[0] {
-		panic(fmt.Sprintf("Can't get row %d from a %dx%d array", row, array.shape[0], array.shape[1]))
+		panic(ErrIndexOutOfRange{Op: "Row", Index: []int{row}, Shape: array.shape[:1]})
+	}
+	if array.transpose {
+		result := make([]float64, array.shape[1])
+		for col := 0; col < array.shape[1]; col++ {
+			result[col] = array.Item(row, col)
+		}
+		return result
 	}
-	start := ndToFlat(array.shape, []int{row, 0})
-	return array.array[start : start+array.shape[1]]
+	start := row * array.shape[1]
+	return append([]float64(nil), array.array[start:start+array.shape[1]]...)
 }
 
 // Get the number of rows
@@ -336,6 +410,40 @@ func (array denseF64Array) Rows() int {
 	return array.shape[0]
 }
 
+// Replace the array with one read from a database column, implementing
+// sql.Scanner
+func (array *denseF64Array) Scan(src interface{}) error {
+	return scanDense(array, src)
+}
+
+// Copy src into this matrix, with the top-left element of src at (row, col)
+func (array denseF64Array) SetSubmatrix(row, col int, src Matrix) {
+	checkSubmatrix("SetSubmatrix", &array, row, col, src)
+	if array.transpose {
+		array.T().SetSubmatrix(col, row, src.T())
+		return
+	}
+	cols := array.shape[1]
+	if src.Sparsity() != DenseArray {
+		for i := 0; i < src.Rows(); i++ {
+			start := (row+i)*cols + col
+			block := array.array[start : start+src.Cols()]
+			for j := range block {
+				block[j] = 0
+			}
+		}
+		src.VisitNonzero(func(pos []int, value float64) bool {
+			array.array[(row+pos[0])*cols+col+pos[1]] = value
+			return true
+		})
+		return
+	}
+	for i := 0; i < src.Rows(); i++ {
+		start := (row+i)*cols + col
+		copy(array.array[start:start+src.Cols()], src.Row(i))
+	}
+}
+
 // A slice giving the size of all array dimensions
 func (array denseF64Array) Shape() []int {
 	return array.shape
@@ -376,6 +484,16 @@ func (array denseF64Array) SparseDiag() Matrix {
 	return m
 }
 
+// Return a compressed sparse column copy of the matrix
+func (array denseF64Array) SparseCSC() Matrix {
+	return newCompressed(&array, true)
+}
+
+// Return a compressed sparse row copy of the matrix
+func (array denseF64Array) SparseCSR() Matrix {
+	return newCompressed(&array, false)
+}
+
 // Ask whether the matrix has a sparse representation (useful for optimization)
 func (array denseF64Array) Sparsity() ArraySparsity {
 	return DenseArray
@@ -396,7 +514,7 @@ func (array denseF64Array) Sum() float64 {
 func (array denseF64Array) M() Matrix {
 	switch array.NDim() {
 	default:
-		panic(fmt.Sprintf("Cannot convert a %d-dim array into a matrix", array.NDim()))
+		panic(ErrShapeMismatch{Op: "M", Got: array.shape, Want: []int{-1, -1}})
 
 	case 1:
 		return &denseF64Array{
@@ -410,6 +528,24 @@ func (array denseF64Array) M() Matrix {
 	}
 }
 
+// Exchange the values of two columns in place
+func (array denseF64Array) SwapCols(i, j int) {
+	checkSwap("SwapCols", i, j, array.shape[1])
+	for row := 0; row < array.shape[0]; row++ {
+		a, b := array.flatIndex(row, i), array.flatIndex(row, j)
+		array.array[a], array.array[b] = array.array[b], array.array[a]
+	}
+}
+
+// Exchange the values of two rows in place
+func (array denseF64Array) SwapRows(i, j int) {
+	checkSwap("SwapRows", i, j, array.shape[0])
+	for col := 0; col < array.shape[1]; col++ {
+		a, b := array.flatIndex(i, col), array.flatIndex(j, col)
+		array.array[a], array.array[b] = array.array[b], array.array[a]
+	}
+}
+
 // Return the same matrix, but with axes transposed. The same data is used,
 // for speed and memory efficiency. Use Copy() to create a new array.
 func (array denseF64Array) T() Matrix {
@@ -420,6 +556,33 @@ func (array denseF64Array) T() Matrix {
 	}
 }
 
+// Get the sum of the elements on the main diagonal
+func (array denseF64Array) Trace() float64 {
+	return Trace(&array, 0)
+}
+
+// Get the sum of the elements on a diagonal offset from the main diagonal.
+// Positive offsets select superdiagonals and negative offsets select
+// subdiagonals.
+func (array denseF64Array) TraceOffset(offset int) float64 {
+	return Trace(&array, offset)
+}
+
+// Encode the array in the package's binary format for storage in a
+// database column, implementing driver.Valuer
+func (array denseF64Array) Value() (driver.Value, error) {
+	return Value(&array)
+}
+
+// Returns a view of this array, which shares its storage
+func (array denseF64Array) View() NDArray {
+	return &denseF64Array{
+		shape:     append([]int(nil), array.shape...),
+		array:     array.array,
+		transpose: array.transpose,
+	}
+}
+
 // Visit all matrix elements, invoking a method on each. If the method
 // returns false, iteration is aborted and VisitNonzero() returns false.
 // Otherwise, it returns true.
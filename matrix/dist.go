@@ -0,0 +1,87 @@
+package matrix
+
+import (
+	"fmt"
+	"math"
+	"sync"
+)
+
+// The custom distances added by RegisterDist(), which are numbered from
+// firstCustomDist in the order they were registered
+var distRegistry struct {
+	sync.RWMutex
+	metrics   []func(a, b []float64) float64
+	minkowski map[float64]DistType
+}
+
+// The DistType of the first custom distance
+const firstCustomDist DistType = 1000
+
+// Register a custom distance between rows, and get a DistType which selects
+// it in Dist() and anywhere else a DistType is accepted. dist is passed pairs
+// of rows, which it mustn't modify, and should be symmetric and give zero for
+// equal rows. Custom distances can't be sent to a matserve server, which only
+// knows about the built-in ones. It is safe to register distances from
+// several goroutines.
+func RegisterDist(dist func(a, b []float64) float64) DistType {
+	if dist == nil {
+		panic("Can't register a nil distance")
+	}
+	distRegistry.Lock()
+	defer distRegistry.Unlock()
+	distRegistry.metrics = append(distRegistry.metrics, dist)
+	return firstCustomDist + DistType(len(distRegistry.metrics)-1)
+}
+
+// Get a registered distance, or nil if t wasn't registered
+func registeredDist(t DistType) func(a, b []float64) float64 {
+	distRegistry.RLock()
+	defer distRegistry.RUnlock()
+	if idx := int(t - firstCustomDist); t >= firstCustomDist && idx < len(distRegistry.metrics) {
+		return distRegistry.metrics[idx]
+	}
+	return nil
+}
+
+// Get the DistType for the Minkowski distance of order p >= 1, which is the
+// p-th root of the sum of the p-th powers of the absolute differences. The
+// orders 1, 2 and infinity are the Manhattan, Euclidean and Chebyshev
+// distances. Other orders are registered as custom distances the first time
+// they are used, and later calls with the same p give the same DistType.
+func MinkowskiDist(p float64) DistType {
+	switch {
+	case !(p >= 1):
+		panic(fmt.Sprintf("Can't calculate Minkowski distance of order %g", p))
+	case p == 1:
+		return ManhattanDist
+	case p == 2:
+		return EuclideanDist
+	case math.IsInf(p, 1):
+		return ChebyshevDist
+	}
+
+	distRegistry.RLock()
+	t, ok := distRegistry.minkowski[p]
+	distRegistry.RUnlock()
+	if ok {
+		return t
+	}
+	t = RegisterDist(func(a, b []float64) float64 {
+		var v float64
+		for idx, av := range a {
+			v += math.Pow(math.Abs(av-b[idx]), p)
+		}
+		return math.Pow(v, 1/p)
+	})
+	distRegistry.Lock()
+	defer distRegistry.Unlock()
+	if existing, ok := distRegistry.minkowski[p]; ok {
+		// Another goroutine registered p first
+		return existing
+	}
+	if distRegistry.minkowski == nil {
+		distRegistry.minkowski = make(map[float64]DistType)
+	}
+	distRegistry.minkowski[p] = t
+	return t
+}
@@ -0,0 +1,136 @@
+package matrix
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Encode class labels as the rows of a len(labels) x numClasses sparse coo
+// matrix, in which row i has a single 1, in column labels[i]
+func OneHot(labels []int, numClasses int) Matrix {
+	result := SparseCoo(len(labels), numClasses)
+	for row, label := range labels {
+		if label < 0 || label >= numClasses {
+			panic(ErrIndexOutOfRange{Op: "OneHot", Index: []int{label}, Shape: []int{numClasses}})
+		}
+		result.ItemSet(1, row, label)
+	}
+	return result
+}
+
+// A CategoricalEncoder one-hot encodes columns of categorical data, such as
+// the string fields of CSV records. Each column has a vocabulary of the
+// categories seen by FitCategorical(), and each category becomes one column of
+// the encoded matrix. The fields are exported, so an encoder fit to training
+// data can be saved, for example with encoding/json, and loaded to encode
+// new data consistently.
+type CategoricalEncoder struct {
+	// The name of each input column, used by FeatureNames(). May be nil.
+	Names []string `json:"names,omitempty"`
+
+	// The categories of each input column, in the order of their encoded
+	// columns
+	Vocab [][]string `json:"vocab"`
+}
+
+// Create an encoder for the columns of data, with a vocabulary for each
+// column holding its distinct values in sorted order. names, which may be
+// nil, gives the name of each column for FeatureNames().
+func FitCategorical(data [][]string, names []string) *CategoricalEncoder {
+	cols := categoricalCols("FitCategorical", data)
+	if len(data) == 0 {
+		cols = len(names)
+	}
+	if names != nil && len(names) != cols {
+		panic(ErrShapeMismatch{Op: "FitCategorical", Got: []int{len(names)}, Want: []int{cols}})
+	}
+	e := &CategoricalEncoder{Vocab: make([][]string, cols)}
+	if names != nil {
+		e.Names = append([]string(nil), names...)
+	}
+	for col := range e.Vocab {
+		seen := make(map[string]bool)
+		for _, record := range data {
+			if !seen[record[col]] {
+				seen[record[col]] = true
+				e.Vocab[col] = append(e.Vocab[col], record[col])
+			}
+		}
+		sort.Strings(e.Vocab[col])
+	}
+	return e
+}
+
+// Get the number of columns in the encoded matrix: the total size of the
+// vocabularies
+func (e *CategoricalEncoder) NumFeatures() int {
+	n := 0
+	for _, vocab := range e.Vocab {
+		n += len(vocab)
+	}
+	return n
+}
+
+// Get a name for each column of the encoded matrix, of the form
+// "name=category". Columns without names are named by their index.
+func (e *CategoricalEncoder) FeatureNames() []string {
+	names := make([]string, 0, e.NumFeatures())
+	for col, vocab := range e.Vocab {
+		name := fmt.Sprint(col)
+		if e.Names != nil {
+			name = e.Names[col]
+		}
+		for _, category := range vocab {
+			names = append(names, name+"="+category)
+		}
+	}
+	return names
+}
+
+// One-hot encode data, which must have a column for each vocabulary. Returns
+// a len(data) x NumFeatures() sparse coo matrix with a 1 in each row for the
+// category of each input column. Categories missing from a vocabulary are
+// encoded as all zeros for that column.
+func (e *CategoricalEncoder) Transform(data [][]string) Matrix {
+	cols := categoricalCols("Transform", data)
+	if len(data) > 0 && cols != len(e.Vocab) {
+		panic(ErrShapeMismatch{Op: "Transform", Got: []int{len(data), cols}, Want: []int{-1, len(e.Vocab)}})
+	}
+	offsets := make([]int, len(e.Vocab))
+	index := make([]map[string]int, len(e.Vocab))
+	offset := 0
+	for col, vocab := range e.Vocab {
+		offsets[col] = offset
+		index[col] = make(map[string]int, len(vocab))
+		for i, category := range vocab {
+			index[col][category] = i
+		}
+		offset += len(vocab)
+	}
+
+	result := NewMatrix(len(data), offset,
+		WithStorage(SparseCooMatrix), WithCapacity(len(data)*len(e.Vocab)))
+	for row, record := range data {
+		for col, value := range record {
+			if i, ok := index[col][value]; ok {
+				result.ItemSet(1, row, offsets[col]+i)
+			}
+		}
+	}
+	return result
+}
+
+// Get the number of columns in categorical data, checking that all records
+// have the same number
+func categoricalCols(op string, data [][]string) int {
+	if len(data) == 0 {
+		return 0
+	}
+	cols := len(data[0])
+	for row, record := range data {
+		if len(record) != cols {
+			panic(ErrShapeMismatch{Op: op, Got: []int{row, len(record)}, Want: []int{row, cols}})
+		}
+	}
+	return cols
+}
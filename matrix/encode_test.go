@@ -0,0 +1,69 @@
+package matrix
+
+import (
+	"encoding/json"
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+func TestOneHot(t *testing.T) {
+	Convey("OneHot sets one column per row", t, func() {
+		m := OneHot([]int{2, 0, 2}, 3)
+		So(m.Sparsity(), ShouldEqual, SparseCooMatrix)
+		So(m.Equal(M(3, 3, 0, 0, 1, 1, 0, 0, 0, 0, 1)), ShouldBeTrue)
+		So(OneHot(nil, 2).Shape(), ShouldResemble, []int{0, 2})
+		So(func() { OneHot([]int{3}, 3) }, ShouldPanic)
+		So(func() { OneHot([]int{-1}, 3) }, ShouldPanic)
+	})
+}
+
+func TestCategoricalEncoder(t *testing.T) {
+	Convey("Given an encoder fit to categorical data", t, func() {
+		data := [][]string{
+			{"red", "small"},
+			{"blue", "large"},
+			{"red", "large"},
+		}
+		e := FitCategorical(data, []string{"color", "size"})
+
+		Convey("The vocabularies are sorted", func() {
+			So(e.Vocab, ShouldResemble, [][]string{{"blue", "red"}, {"large", "small"}})
+			So(e.NumFeatures(), ShouldEqual, 4)
+			So(e.FeatureNames(), ShouldResemble,
+				[]string{"color=blue", "color=red", "size=large", "size=small"})
+		})
+
+		Convey("Transform one-hot encodes each column", func() {
+			m := e.Transform(data)
+			So(m.Sparsity(), ShouldEqual, SparseCooMatrix)
+			So(m.Equal(M(3, 4,
+				0, 1, 0, 1,
+				1, 0, 1, 0,
+				0, 1, 1, 0)), ShouldBeTrue)
+		})
+
+		Convey("Unknown categories are encoded as zeros", func() {
+			m := e.Transform([][]string{{"green", "small"}})
+			So(m.Row(0), ShouldResemble, []float64{0, 0, 0, 1})
+		})
+
+		Convey("The vocabulary survives a round trip through JSON", func() {
+			data2, err := json.Marshal(e)
+			So(err, ShouldBeNil)
+			var loaded CategoricalEncoder
+			So(json.Unmarshal(data2, &loaded), ShouldBeNil)
+			So(loaded.Transform(data).Equal(e.Transform(data)), ShouldBeTrue)
+			So(loaded.FeatureNames(), ShouldResemble, e.FeatureNames())
+		})
+
+		Convey("Unnamed columns are named by index", func() {
+			So(FitCategorical(data, nil).FeatureNames()[0], ShouldEqual, "0=blue")
+		})
+
+		Convey("Mismatched records panic", func() {
+			So(func() { FitCategorical([][]string{{"a"}, {"b", "c"}}, nil) }, ShouldPanic)
+			So(func() { FitCategorical(data, []string{"color"}) }, ShouldPanic)
+			So(func() { e.Transform([][]string{{"red"}}) }, ShouldPanic)
+		})
+	})
+}
@@ -0,0 +1,120 @@
+package matrix
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Functions in this package panic with the error types below when given
+// invalid arguments, and the Try functions return them as errors. Callers can
+// use errors.As and errors.Is to branch on the kind of failure rather than
+// parsing messages.
+
+// ErrSingular is returned when a matrix can't be inverted or a system can't
+// be solved because the matrix is singular or too ill-conditioned.
+var ErrSingular = errors.New("matrix is singular")
+
+// ErrNotPositiveDefinite is returned when a Cholesky decomposition is asked
+// for a matrix which isn't symmetric positive definite
+var ErrNotPositiveDefinite = errors.New("matrix is not positive definite")
+
+// ErrShapeMismatch reports an array whose shape doesn't fit the operation. A
+// dimension of -1 in Want means any size is acceptable.
+type ErrShapeMismatch struct {
+	Op   string
+	Got  []int
+	Want []int
+}
+
+func (e ErrShapeMismatch) Error() string {
+	return fmt.Sprintf("%s: got shape %v but want %s", e.Op, e.Got, formatShape(e.Want))
+}
+
+// ErrIndexOutOfRange reports indices which don't address an element of an
+// array with the given shape.
+type ErrIndexOutOfRange struct {
+	Op    string
+	Index []int
+	Shape []int
+}
+
+func (e ErrIndexOutOfRange) Error() string {
+	if e.Op == "" {
+		return fmt.Sprintf("Indices %v invalid for array shape %v", e.Index, e.Shape)
+	}
+	return fmt.Sprintf("%s: indices %v invalid for array shape %v", e.Op, e.Index, e.Shape)
+}
+
+// ErrNotSparse reports an attempt to store a nonzero value at a position
+// which the sparse representation can't hold, such as an off-diagonal element
+// of a sparse diagonal matrix.
+type ErrNotSparse struct {
+	Op       string
+	Index    []int
+	Sparsity ArraySparsity
+}
+
+func (e ErrNotSparse) Error() string {
+	return fmt.Sprintf("%s: can't set element %v of a %v matrix", e.Op, e.Index, e.Sparsity)
+}
+
+// Format a shape for an error message, writing -1 dimensions as "*"
+func formatShape(shape []int) string {
+	dims := make([]string, len(shape))
+	for i, d := range shape {
+		if d < 0 {
+			dims[i] = "*"
+		} else {
+			dims[i] = fmt.Sprint(d)
+		}
+	}
+	return "[" + strings.Join(dims, " ") + "]"
+}
+
+// Panic with ErrShapeMismatch unless got and want have the same shape
+func checkSameShape(op string, got, want []int) {
+	if !shapeMatches(got, want) {
+		panic(ErrShapeMismatch{Op: op, Got: got, Want: want})
+	}
+}
+
+// ErrNotClose reports the first element, in row-major order, at which two
+// matrices differ by more than the tolerance given to AllClose.
+type ErrNotClose struct {
+	Index     []int
+	Got, Want float64
+}
+
+func (e ErrNotClose) Error() string {
+	return fmt.Sprintf("Element %v is %v but want %v", e.Index, e.Got, e.Want)
+}
+
+// ErrUnknownLabel reports a row or column label which a matrix doesn't have
+type ErrUnknownLabel struct {
+	Axis  string
+	Label string
+}
+
+func (e ErrUnknownLabel) Error() string {
+	return fmt.Sprintf("No %s labeled %q", e.Axis, e.Label)
+}
+
+// ErrNoConvergence is returned when an iterative decomposition, such as
+// SVD(), fails to converge
+var ErrNoConvergence = errors.New("decomposition failed to converge")
+
+// ErrFrozen is returned when modifying a matrix made read-only by Freeze()
+var ErrFrozen = errors.New("matrix is frozen")
+
+// ErrInvariant reports an array whose internal storage is inconsistent, which
+// usually means it was modified through a view in an unsupported way. These
+// errors are only detected in debug mode, or by CheckInvariants().
+type ErrInvariant struct {
+	Op      string
+	Problem string
+}
+
+func (e ErrInvariant) Error() string {
+	return fmt.Sprintf("%s: broken array invariant: %s", e.Op, e.Problem)
+}
@@ -0,0 +1,84 @@
+package matrix
+
+import (
+	"errors"
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+func TestErrors(t *testing.T) {
+	Convey("Given arrays with mismatched shapes", t, func() {
+		a := M(2, 3,
+			1, 2, 3,
+			4, 5, 6)
+		b := M(3, 2,
+			1, 2,
+			3, 4,
+			5, 6)
+
+		Convey("Element-wise operations panic with ErrShapeMismatch", func() {
+			So(func() { Add(a, b) }, ShouldPanic)
+			So(try(func() { Add(a, b) }), ShouldResemble, ErrShapeMismatch{Op: "Add", Got: []int{3, 2}, Want: []int{2, 3}})
+			So(try(func() { Sub(a, b) }), ShouldResemble, ErrShapeMismatch{Op: "Sub", Got: []int{3, 2}, Want: []int{2, 3}})
+		})
+
+		Convey("Try functions return ErrShapeMismatch", func() {
+			var shapeErr ErrShapeMismatch
+			_, err := TryProd(a, b)
+			So(errors.As(err, &shapeErr), ShouldBeTrue)
+			So(shapeErr.Op, ShouldEqual, "Prod")
+
+			_, err = TryMProd(a, a)
+			So(errors.As(err, &shapeErr), ShouldBeTrue)
+			So(shapeErr.Got, ShouldResemble, []int{2, 3})
+			So(shapeErr.Want, ShouldResemble, []int{3, -1})
+			So(err.Error(), ShouldEqual, "MProd: got shape [2 3] but want [3 *]")
+
+			_, err = TryConcat(0, a, b)
+			So(errors.As(err, &shapeErr), ShouldBeTrue)
+			So(shapeErr.Want, ShouldResemble, []int{-1, 3})
+
+			_, err = TryM(2, 2, 1, 2, 3)
+			So(errors.As(err, &shapeErr), ShouldBeTrue)
+			So(shapeErr.Got, ShouldResemble, []int{3})
+			So(shapeErr.Want, ShouldResemble, []int{4})
+		})
+	})
+
+	Convey("Given out of range indices, errors are ErrIndexOutOfRange", t, func() {
+		var indexErr ErrIndexOutOfRange
+		for _, m := range []Matrix{Dense(2, 3).M(), SparseCoo(2, 3), SparseDiag(2, 3)} {
+			_, err := TryItem(m, 2, 0)
+			So(errors.As(err, &indexErr), ShouldBeTrue)
+			So(indexErr.Index, ShouldResemble, []int{2, 0})
+
+			err = TryRowSet(m, 5, []float64{0, 0, 0})
+			So(errors.As(err, &indexErr), ShouldBeTrue)
+			So(indexErr.Op, ShouldEqual, "RowSet")
+
+			err = TryColSet(m, 0, []float64{0})
+			var shapeErr ErrShapeMismatch
+			So(errors.As(err, &shapeErr), ShouldBeTrue)
+		}
+	})
+
+	Convey("Given off-diagonal values for a sparse diag matrix, errors are ErrNotSparse", t, func() {
+		var sparseErr ErrNotSparse
+		err := TryItemSet(SparseDiag(3, 3), 1, 0, 2)
+		So(errors.As(err, &sparseErr), ShouldBeTrue)
+		So(sparseErr.Index, ShouldResemble, []int{0, 2})
+		So(sparseErr.Sparsity, ShouldEqual, SparseDiagMatrix)
+		So(err.Error(), ShouldEqual, "ItemSet: can't set element [0 2] of a sparse diagonal matrix")
+
+		err = TryRowSet(SparseDiag(3, 3), 1, []float64{1, 2, 0})
+		So(errors.As(err, &sparseErr), ShouldBeTrue)
+		So(sparseErr.Index, ShouldResemble, []int{1, 0})
+	})
+
+	Convey("Given a singular matrix, Inverse returns ErrSingular", t, func() {
+		_, err := M(2, 2, 1, 2, 2, 4).Inverse()
+		So(errors.Is(err, ErrSingular), ShouldBeTrue)
+		_, err = M(2, 2, 1, 2, 3, 4).Inverse()
+		So(err, ShouldBeNil)
+	})
+}
@@ -0,0 +1,173 @@
+// The featherio package reads and writes matrices in the Arrow IPC file
+// format (Feather v2), for exchanging data with pandas and R. It is kept out
+// of the matrix package so that programs which don't use Feather files don't
+// depend on the Arrow libraries.
+package featherio
+
+import (
+	"fmt"
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/ipc"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/jesand/numgo/matrix"
+	"io"
+	"math"
+	"os"
+	"strconv"
+)
+
+// Write a matrix to w in the Arrow IPC file format (Feather v2), which can be
+// read by pandas.read_feather and R's arrow::read_feather. Each matrix column
+// becomes a float64 column of a single record batch. If names is nil the
+// columns are named "0", "1", and so on.
+func WriteFeather(w io.Writer, m matrix.Matrix, names []string) error {
+	if names == nil {
+		names = make([]string, m.Cols())
+		for col := range names {
+			names[col] = strconv.Itoa(col)
+		}
+	} else if len(names) != m.Cols() {
+		panic(fmt.Sprintf("Can't write %d column names for a %dx%d matrix", len(names), m.Rows(), m.Cols()))
+	}
+
+	mem := memory.NewGoAllocator()
+	fields := make([]arrow.Field, m.Cols())
+	columns := make([]arrow.Array, m.Cols())
+	builder := array.NewFloat64Builder(mem)
+	defer builder.Release()
+	values := make([]float64, m.Rows())
+	for col := range columns {
+		fields[col] = arrow.Field{Name: names[col], Type: arrow.PrimitiveTypes.Float64}
+		for row := range values {
+			values[row] = m.Item(row, col)
+		}
+		builder.AppendValues(values, nil)
+		columns[col] = builder.NewFloat64Array()
+		defer columns[col].Release()
+	}
+	schema := arrow.NewSchema(fields, nil)
+	record := array.NewRecord(schema, columns, int64(m.Rows()))
+	defer record.Release()
+
+	writer, err := ipc.NewFileWriter(w, ipc.WithSchema(schema), ipc.WithAllocator(mem))
+	if err != nil {
+		return err
+	}
+	if err := writer.Write(record); err != nil {
+		writer.Close()
+		return err
+	}
+	return writer.Close()
+}
+
+// Read a dense matrix from an Arrow IPC file (Feather v2), such as one written
+// by pandas.DataFrame.to_feather, returning it with its column names. Rows of
+// all record batches are concatenated. Integer, floating point and boolean
+// columns are converted to float64, and null values are read as NaN; other
+// column types are an error.
+func ReadFeather(r ipc.ReadAtSeeker) (matrix.Matrix, []string, error) {
+	reader, err := ipc.NewFileReader(r, ipc.WithAllocator(memory.NewGoAllocator()))
+	if err != nil {
+		return nil, nil, err
+	}
+	defer reader.Close()
+
+	schema := reader.Schema()
+	names := make([]string, schema.NumFields())
+	for col := range names {
+		names[col] = schema.Field(col).Name
+	}
+	columns := make([][]float64, len(names))
+	for idx := 0; idx < reader.NumRecords(); idx++ {
+		record, err := reader.Record(idx)
+		if err != nil {
+			return nil, nil, err
+		}
+		for col := range columns {
+			columns[col], err = appendArrowColumn(columns[col], record.Column(col))
+			if err != nil {
+				return nil, nil, fmt.Errorf("Can't read Feather column %q: %v", names[col], err)
+			}
+		}
+	}
+
+	rows := 0
+	if len(columns) > 0 {
+		rows = len(columns[0])
+	}
+	result := matrix.Dense(rows, len(columns)).M()
+	for col, values := range columns {
+		for row, v := range values {
+			result.ItemSet(v, row, col)
+		}
+	}
+	return result, names, nil
+}
+
+// Write a matrix to a Feather file at path. See WriteFeather.
+func SaveFeather(path string, m matrix.Matrix, names []string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := WriteFeather(f, m, names); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Read a matrix from a Feather file at path. See ReadFeather.
+func LoadFeather(path string) (matrix.Matrix, []string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+	return ReadFeather(f)
+}
+
+// Append the values of an Arrow array to a slice, converted to float64
+func appendArrowColumn(values []float64, column arrow.Array) ([]float64, error) {
+	var value func(i int) float64
+	switch column := column.(type) {
+	case *array.Float64:
+		value = func(i int) float64 { return column.Value(i) }
+	case *array.Float32:
+		value = func(i int) float64 { return float64(column.Value(i)) }
+	case *array.Int64:
+		value = func(i int) float64 { return float64(column.Value(i)) }
+	case *array.Int32:
+		value = func(i int) float64 { return float64(column.Value(i)) }
+	case *array.Int16:
+		value = func(i int) float64 { return float64(column.Value(i)) }
+	case *array.Int8:
+		value = func(i int) float64 { return float64(column.Value(i)) }
+	case *array.Uint64:
+		value = func(i int) float64 { return float64(column.Value(i)) }
+	case *array.Uint32:
+		value = func(i int) float64 { return float64(column.Value(i)) }
+	case *array.Uint16:
+		value = func(i int) float64 { return float64(column.Value(i)) }
+	case *array.Uint8:
+		value = func(i int) float64 { return float64(column.Value(i)) }
+	case *array.Boolean:
+		value = func(i int) float64 {
+			if column.Value(i) {
+				return 1
+			}
+			return 0
+		}
+	default:
+		return nil, fmt.Errorf("unsupported type %s", column.DataType().Name())
+	}
+	for i := 0; i < column.Len(); i++ {
+		if column.IsNull(i) {
+			values = append(values, math.NaN())
+		} else {
+			values = append(values, value(i))
+		}
+	}
+	return values, nil
+}
@@ -0,0 +1,87 @@
+package featherio
+
+import (
+	"bytes"
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/ipc"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	. "github.com/jesand/numgo/matrix"
+	. "github.com/smartystreets/goconvey/convey"
+	"math"
+	"path/filepath"
+	"testing"
+)
+
+func TestFeather(t *testing.T) {
+	Convey("Given a matrix", t, func() {
+		m := SparseCoo(3, 2,
+			1, 0,
+			0, 2.5,
+			-3, 0)
+
+		Convey("WriteFeather and ReadFeather round trip it", func() {
+			var buf bytes.Buffer
+			So(WriteFeather(&buf, m, []string{"a", "b"}), ShouldBeNil)
+			result, names, err := ReadFeather(bytes.NewReader(buf.Bytes()))
+			So(err, ShouldBeNil)
+			So(names, ShouldResemble, []string{"a", "b"})
+			So(result.Equal(m), ShouldBeTrue)
+		})
+
+		Convey("SaveFeather and LoadFeather use default column names", func() {
+			path := filepath.Join(t.TempDir(), "m.feather")
+			So(SaveFeather(path, m.T(), nil), ShouldBeNil)
+			result, names, err := LoadFeather(path)
+			So(err, ShouldBeNil)
+			So(names, ShouldResemble, []string{"0", "1", "2"})
+			So(result.Equal(m.T()), ShouldBeTrue)
+		})
+
+		Convey("Column names must match the columns", func() {
+			So(func() { WriteFeather(&bytes.Buffer{}, m, []string{"a"}) }, ShouldPanic)
+		})
+	})
+
+	Convey("Given Feather files from other writers", t, func() {
+		mem := memory.NewGoAllocator()
+		write := func(fields []arrow.Field, columns ...arrow.Array) []byte {
+			schema := arrow.NewSchema(fields, nil)
+			var buf bytes.Buffer
+			w, err := ipc.NewFileWriter(&buf, ipc.WithSchema(schema), ipc.WithAllocator(mem))
+			So(err, ShouldBeNil)
+			for _, column := range columns {
+				So(w.Write(array.NewRecord(schema, []arrow.Array{column}, int64(column.Len()))), ShouldBeNil)
+			}
+			So(w.Close(), ShouldBeNil)
+			return buf.Bytes()
+		}
+
+		Convey("Integer columns are converted and nulls become NaN", func() {
+			b := array.NewInt64Builder(mem)
+			b.AppendValues([]int64{4, 0, 6}, []bool{true, false, true})
+			first := b.NewInt64Array()
+			b.AppendValues([]int64{7}, nil)
+			second := b.NewInt64Array()
+
+			result, names, err := ReadFeather(bytes.NewReader(write(
+				[]arrow.Field{{Name: "x", Type: arrow.PrimitiveTypes.Int64, Nullable: true}},
+				first, second)))
+			So(err, ShouldBeNil)
+			So(names, ShouldResemble, []string{"x"})
+			So(result.Shape(), ShouldResemble, []int{4, 1})
+			So(result.Item(0, 0), ShouldEqual, 4)
+			So(math.IsNaN(result.Item(1, 0)), ShouldBeTrue)
+			So(result.Item(3, 0), ShouldEqual, 7)
+		})
+
+		Convey("Non-numeric columns are an error", func() {
+			b := array.NewStringBuilder(mem)
+			b.Append("text")
+			_, _, err := ReadFeather(bytes.NewReader(write(
+				[]arrow.Field{{Name: "s", Type: arrow.BinaryTypes.String}},
+				b.NewStringArray())))
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
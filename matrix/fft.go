@@ -0,0 +1,163 @@
+package matrix
+
+import (
+	"fmt"
+	"gonum.org/v1/gonum/dsp/fourier"
+)
+
+// Complex matrices are represented by pairs of matrices holding the real and
+// imaginary parts. Each transform applies along an axis, as for the other
+// axis functions: for axis 0, each column is transformed, and for axis 1,
+// each row. The transforms are unnormalized, and the inverse transforms divide
+// by the length, so that IFFT undoes FFT, as in numpy.fft.
+
+// Get the discrete Fourier transform along an axis of the complex matrix with
+// real part re and imaginary part im. im may be nil for a real matrix. The
+// result has the same shape as re.
+func FFT(re, im Matrix, axis int) (Matrix, Matrix) {
+	debugCheck("FFT", re)
+	return complexFFT("FFT", re, im, axis, false)
+}
+
+// Get the inverse discrete Fourier transform along an axis of the complex
+// matrix with real part re and imaginary part im, which may be nil
+func IFFT(re, im Matrix, axis int) (Matrix, Matrix) {
+	debugCheck("IFFT", re)
+	return complexFFT("IFFT", re, im, axis, true)
+}
+
+// Get the two-dimensional discrete Fourier transform of the complex matrix
+// with real part re and imaginary part im, which may be nil. This transforms
+// each column and then each row.
+func FFT2(re, im Matrix) (Matrix, Matrix) {
+	debugCheck("FFT2", re)
+	re, im = complexFFT("FFT2", re, im, 0, false)
+	return complexFFT("FFT2", re, im, 1, false)
+}
+
+// Get the inverse two-dimensional discrete Fourier transform of the complex
+// matrix with real part re and imaginary part im, which may be nil
+func IFFT2(re, im Matrix) (Matrix, Matrix) {
+	debugCheck("IFFT2", re)
+	re, im = complexFFT("IFFT2", re, im, 0, true)
+	return complexFFT("IFFT2", re, im, 1, true)
+}
+
+// Get the discrete Fourier transform along an axis of a real matrix. Since
+// the transform of a real vector of length n is conjugate symmetric, only
+// the n/2 + 1 coefficients for the nonnegative frequencies are returned.
+func RFFT(m Matrix, axis int) (re, im Matrix) {
+	debugCheck("RFFT", m)
+	var t *fourier.FFT
+	return alongAxis("RFFT", m, nil, axis, func(vec []complex128) []complex128 {
+		if len(vec) == 0 {
+			return []complex128{}
+		}
+		seq := make([]float64, len(vec))
+		for i, v := range vec {
+			seq[i] = real(v)
+		}
+		if t == nil || t.Len() != len(seq) {
+			t = fourier.NewFFT(len(seq))
+		}
+		return t.Coefficients(nil, seq)
+	})
+}
+
+// Get the inverse of RFFT(): the real matrix whose transform along axis has
+// the nonnegative frequency coefficients re + i im. Since several lengths
+// give the same number of coefficients, n is the length of the result along
+// axis. n must be 2 * (coefficients - 1) or one more than that.
+func IRFFT(re, im Matrix, n, axis int) Matrix {
+	debugCheck("IRFFT", re)
+	var t *fourier.FFT
+	result, _ := alongAxis("IRFFT", re, im, axis, func(coeff []complex128) []complex128 {
+		if n < 1 || n/2+1 != len(coeff) {
+			panic(fmt.Sprintf("IRFFT: can't get %d values from %d coefficients", n, len(coeff)))
+		}
+		if t == nil {
+			t = fourier.NewFFT(n)
+		}
+		seq := t.Sequence(nil, coeff)
+		vec := make([]complex128, n)
+		for i, v := range seq {
+			vec[i] = complex(v/float64(n), 0)
+		}
+		return vec
+	})
+	return result
+}
+
+// Transform each complex vector along an axis
+func complexFFT(op string, re, im Matrix, axis int, inverse bool) (Matrix, Matrix) {
+	var t *fourier.CmplxFFT
+	return alongAxis(op, re, im, axis, func(vec []complex128) []complex128 {
+		if len(vec) == 0 {
+			return vec
+		}
+		if t == nil || t.Len() != len(vec) {
+			t = fourier.NewCmplxFFT(len(vec))
+		}
+		if !inverse {
+			return t.Coefficients(vec, vec)
+		}
+		t.Sequence(vec, vec)
+		scale := complex(1/float64(len(vec)), 0)
+		for i := range vec {
+			vec[i] *= scale
+		}
+		return vec
+	})
+}
+
+// Apply f to each complex vector along an axis of the complex matrix with
+// real part re and imaginary part im, which may be nil. f must return vectors
+// of the same length, which become the vectors along axis of the result.
+func alongAxis(op string, re, im Matrix, axis int, f func(vec []complex128) []complex128) (Matrix, Matrix) {
+	if im != nil && (im.Rows() != re.Rows() || im.Cols() != re.Cols()) {
+		panic(ErrShapeMismatch{Op: op, Got: im.Shape(), Want: re.Shape()})
+	}
+	reVecs := axisVectors(re, axis)
+	var imVecs [][]float64
+	if im != nil {
+		imVecs = axisVectors(im, axis)
+	}
+
+	results := make([][]complex128, len(reVecs))
+	for idx, reVec := range reVecs {
+		vec := make([]complex128, len(reVec))
+		for i, v := range reVec {
+			vec[i] = complex(v, 0)
+			if imVecs != nil {
+				vec[i] += complex(0, imVecs[idx][i])
+			}
+		}
+		results[idx] = f(vec)
+	}
+
+	n := 0
+	if len(results) > 0 {
+		n = len(results[0])
+	} else if axis == 0 {
+		n = re.Rows()
+	} else {
+		n = re.Cols()
+	}
+	var resultRe, resultIm Matrix
+	if axis == 0 {
+		resultRe, resultIm = Dense(n, len(results)).M(), Dense(n, len(results)).M()
+	} else {
+		resultRe, resultIm = Dense(len(results), n).M(), Dense(len(results), n).M()
+	}
+	for idx, vec := range results {
+		for i, v := range vec {
+			row, col := idx, i
+			if axis == 0 {
+				row, col = i, idx
+			}
+			resultRe.ItemSet(real(v), row, col)
+			resultIm.ItemSet(imag(v), row, col)
+		}
+	}
+	return resultRe, resultIm
+}
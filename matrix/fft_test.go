@@ -0,0 +1,68 @@
+package matrix
+
+import (
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+func TestFFT(t *testing.T) {
+	Convey("Given a real matrix", t, func() {
+		m := M(2, 4, 1, 2, 3, 4, 0, 1, 0, 0)
+
+		Convey("FFT transforms each row", func() {
+			re, im := FFT(m, nil, 1)
+			So(re.Row(0), ShouldResemble, []float64{10, -2, -2, -2})
+			So(im.Row(0), ShouldResemble, []float64{0, 2, 0, -2})
+			So(re.Row(1), ShouldResemble, []float64{1, 0, -1, 0})
+			So(im.Row(1), ShouldResemble, []float64{0, -1, 0, 1})
+		})
+
+		Convey("FFT transforms each column", func() {
+			re, im := FFT(m, nil, 0)
+			So(re.Equal(M(2, 4, 1, 3, 3, 4, 1, 1, 3, 4)), ShouldBeTrue)
+			So(im.Equal(Dense(2, 4).M()), ShouldBeTrue)
+		})
+
+		Convey("IFFT undoes FFT", func() {
+			for _, axis := range []int{0, 1} {
+				re, im := FFT(m, nil, axis)
+				re, im = IFFT(re, im, axis)
+				So(ApproxEqual(re, m, 1e-12, 1e-12), ShouldBeTrue)
+				So(ApproxEqual(im, Dense(2, 4).M(), 1e-12, 1e-12), ShouldBeTrue)
+			}
+			re, im := IFFT2(FFT2(m, m.ItemProd(2).M()))
+			So(ApproxEqual(re, m, 1e-12, 1e-12), ShouldBeTrue)
+			So(ApproxEqual(im, m.ItemProd(2).M(), 1e-12, 1e-12), ShouldBeTrue)
+		})
+
+		Convey("FFT2 transforms both axes", func() {
+			re, im := FFT2(M(2, 2, 1, 2, 3, 4), nil)
+			So(re.Equal(M(2, 2, 10, -2, -4, 0)), ShouldBeTrue)
+			So(im.Equal(Dense(2, 2).M()), ShouldBeTrue)
+		})
+
+		Convey("RFFT keeps the nonnegative frequencies", func() {
+			re, im := RFFT(m, 1)
+			So(re.Row(0), ShouldResemble, []float64{10, -2, -2})
+			So(im.Row(0), ShouldResemble, []float64{0, 2, 0})
+			So(ApproxEqual(IRFFT(re, im, 4, 1), m, 1e-12, 1e-12), ShouldBeTrue)
+			So(func() { IRFFT(re, im, 7, 1) }, ShouldPanic)
+		})
+
+		Convey("FFT multiplies by circulant matrices quickly", func() {
+			c := M(4, 1, 1, 2, 0, -1)
+			x := M(4, 1, 3, 1, 4, 1)
+			cRe, cIm := FFT(c, nil, 0)
+			xRe, xIm := FFT(x, nil, 0)
+			re := Sub(Prod(cRe, xRe), Prod(cIm, xIm)).M()
+			im := Add(Prod(cRe, xIm), Prod(cIm, xRe)).M()
+			product, _ := IFFT(re, im, 0)
+			So(ApproxEqual(product, Circulant(c.Col(0)).MProd(x), 1e-12, 1e-12), ShouldBeTrue)
+		})
+
+		Convey("Mismatched parts and invalid axes panic", func() {
+			So(func() { FFT(m, Dense(2, 2).M(), 1) }, ShouldPanic)
+			So(func() { FFT(m, nil, 2) }, ShouldPanic)
+		})
+	})
+}
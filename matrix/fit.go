@@ -0,0 +1,347 @@
+package matrix
+
+import (
+	"fmt"
+	"gonum.org/v1/gonum/optimize"
+	"math"
+)
+
+// The algorithm FitLinear() or FitLogistic() uses to find the coefficients
+type Solver int
+
+const (
+	// The default solver for the model: SolverDirect for FitLinear(), and
+	// SolverIRLS for FitLogistic()
+	SolverDefault Solver = iota
+
+	// Solve the least squares problem directly by factorization. Accurate,
+	// but needs a dense factorization of the design matrix.
+	SolverDirect
+
+	// Solve the normal equations by conjugate gradient. Only multiplies by
+	// the design matrix, so it suits large sparse problems.
+	SolverCG
+
+	// Iteratively reweighted least squares: Newton's method on the
+	// log-likelihood. Converges in few iterations for small problems.
+	SolverIRLS
+
+	// The limited-memory BFGS quasi-Newton method, which only needs
+	// gradients and suits problems with many features
+	SolverLBFGS
+)
+
+// A FitOption configures FitLinear() and FitLogistic()
+type FitOption func(*fitOptions)
+
+// The configuration built up by a list of options
+type fitOptions struct {
+	solver    Solver
+	lambda    float64
+	intercept bool
+	maxIter   int
+	tol       float64
+}
+
+// Find the coefficients with the given solver
+func FitSolver(solver Solver) FitOption {
+	return func(o *fitOptions) { o.solver = solver }
+}
+
+// Add an L2 (ridge) penalty of lambda/2 times the squared length of the
+// coefficients, not counting the intercept; 0 by default
+func FitL2(lambda float64) FitOption {
+	return func(o *fitOptions) { o.lambda = lambda }
+}
+
+// Fit an intercept, which is returned as the first coefficient
+func FitIntercept() FitOption {
+	return func(o *fitOptions) { o.intercept = true }
+}
+
+// Stop iterative solvers after at most n iterations; 100 by default
+func FitMaxIter(n int) FitOption {
+	return func(o *fitOptions) { o.maxIter = n }
+}
+
+// Stop iterative solvers once the solution is within tol; 1e-8 by default.
+// For SolverCG this is relative to the size of the problem, for SolverIRLS it
+// bounds the largest change in a coefficient, and for SolverLBFGS it bounds
+// the length of the gradient.
+func FitTol(tol float64) FitOption {
+	return func(o *fitOptions) { o.tol = tol }
+}
+
+// Fit a linear model y = X * coef by least squares, where each row of X is
+// an observation and y is a column vector. Returns the coefficients as a
+// column vector, with the intercept first if FitIntercept() is given, and a
+// function which predicts the y values for the rows of another matrix with
+// the same columns as X. Use SolverDirect (the default) or SolverCG.
+func FitLinear(X, y Matrix, opts ...FitOption) (coef Matrix, predict func(Matrix) Matrix) {
+	debugCheck("FitLinear", X, y)
+	o := fitConfig("FitLinear", X, y, opts)
+	design := o.design(X)
+	penalty := o.penalty(design.Cols())
+
+	var w []float64
+	switch o.solver {
+	case SolverDefault, SolverDirect:
+		w = fitDirect(design, y, penalty)
+	case SolverCG:
+		w = fitCG(design, y.Col(0), penalty, o.maxIter, o.tol)
+	default:
+		panic(fmt.Sprintf("FitLinear: can't fit with solver %v", o.solver))
+	}
+
+	coef = M(len(w), 1, w...)
+	return coef, func(x Matrix) Matrix {
+		return o.predictor("FitLinear", X, x).MProd(coef)
+	}
+}
+
+// Fit a logistic regression model P(y = 1) = 1 / (1 + exp(-X * coef)) by
+// maximum likelihood, where each row of X is an observation and y is a
+// column vector of 0s and 1s (or probabilities). Returns the coefficients as a
+// column vector, with the intercept first if FitIntercept() is given, and a
+// function which predicts the probability that y = 1 for the rows of another
+// matrix with the same columns as X. Use SolverIRLS (the default) or
+// SolverLBFGS. If the classes are separable, the likelihood has no maximum,
+// so use FitL2() to keep the coefficients finite.
+func FitLogistic(X, y Matrix, opts ...FitOption) (coef Matrix, predict func(Matrix) Matrix) {
+	debugCheck("FitLogistic", X, y)
+	o := fitConfig("FitLogistic", X, y, opts)
+	target := y.Col(0)
+	for row, v := range target {
+		if v < 0 || v > 1 || math.IsNaN(v) {
+			panic(fmt.Sprintf("FitLogistic: y[%d] = %v is not in [0, 1]", row, v))
+		}
+	}
+	design := o.design(X)
+	penalty := o.penalty(design.Cols())
+
+	var w []float64
+	switch o.solver {
+	case SolverDefault, SolverIRLS:
+		w = fitIRLS(design, target, penalty, o.maxIter, o.tol)
+	case SolverLBFGS:
+		w = fitLBFGS(design, target, penalty, o.maxIter, o.tol)
+	default:
+		panic(fmt.Sprintf("FitLogistic: can't fit with solver %v", o.solver))
+	}
+
+	coef = M(len(w), 1, w...)
+	return coef, func(x Matrix) Matrix {
+		return o.predictor("FitLogistic", X, x).MProd(coef).Apply(sigmoid).M()
+	}
+}
+
+// Apply the options and check the shapes of X and y
+func fitConfig(op string, X, y Matrix, opts []FitOption) *fitOptions {
+	o := &fitOptions{maxIter: 100, tol: 1e-8}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if y.Rows() != X.Rows() || y.Cols() != 1 {
+		panic(ErrShapeMismatch{Op: op, Got: y.Shape(), Want: []int{X.Rows(), 1}})
+	}
+	if o.lambda < 0 {
+		panic(fmt.Sprintf("%s: L2 penalty %v should not be negative", op, o.lambda))
+	}
+	return o
+}
+
+// Get the design matrix: X with a column of ones prepended for the intercept
+func (o *fitOptions) design(X Matrix) Matrix {
+	if !o.intercept {
+		return X
+	}
+	ones := make([]float64, X.Rows())
+	for i := range ones {
+		ones[i] = 1
+	}
+	return InsertCol(X, 0, ones)
+}
+
+// Get the design matrix for predicting the rows of x with a model fit to X
+func (o *fitOptions) predictor(op string, X, x Matrix) Matrix {
+	if x.Cols() != X.Cols() {
+		panic(ErrShapeMismatch{Op: op + " predict", Got: x.Shape(), Want: []int{-1, X.Cols()}})
+	}
+	return o.design(x)
+}
+
+// Get the L2 penalty on each coefficient, which is zero for the intercept
+func (o *fitOptions) penalty(n int) []float64 {
+	penalty := make([]float64, n)
+	for i := range penalty {
+		penalty[i] = o.lambda
+	}
+	if o.intercept {
+		penalty[0] = 0
+	}
+	return penalty
+}
+
+// Solve the penalized least squares problem by factorization
+func fitDirect(design, y Matrix, penalty []float64) []float64 {
+	a, b := design, y
+	if anyPositive(penalty) {
+		// Solve the normal equations (X'X + P)w = X'y
+		a = Add(design.T().MProd(design), SparseDiag(len(penalty), len(penalty), penalty...)).M()
+		b = design.T().MProd(y)
+	}
+	w, err := CurrentBackend().Solve(a, b)
+	if err != nil {
+		panic(err)
+	}
+	return w.Col(0)
+}
+
+// Solve the normal equations (X'X + P)w = X'y by conjugate gradient, only
+// multiplying by X and X'
+func fitCG(design Matrix, y, penalty []float64, maxIter int, tol float64) []float64 {
+	apply := func(v []float64) []float64 {
+		result := mulVec(design.T(), mulVec(design, v))
+		for i := range result {
+			result[i] += penalty[i] * v[i]
+		}
+		return result
+	}
+
+	b := mulVec(design.T(), y)
+	w := make([]float64, len(b))
+	r := append([]float64(nil), b...)
+	p := append([]float64(nil), r...)
+	rr := dotVec(r, r)
+	stop := tol * math.Sqrt(dotVec(b, b))
+	for iter := 0; iter < maxIter && math.Sqrt(rr) > stop; iter++ {
+		ap := apply(p)
+		alpha := rr / dotVec(p, ap)
+		for i := range w {
+			w[i] += alpha * p[i]
+			r[i] -= alpha * ap[i]
+		}
+		next := dotVec(r, r)
+		for i := range p {
+			p[i] = r[i] + next/rr*p[i]
+		}
+		rr = next
+	}
+	return w
+}
+
+// Maximize the penalized log-likelihood of a logistic model by Newton's
+// method, solving a weighted least squares problem at each step
+func fitIRLS(design Matrix, y, penalty []float64, maxIter int, tol float64) []float64 {
+	w := make([]float64, design.Cols())
+	for iter := 0; iter < maxIter; iter++ {
+		grad, probs := logisticGrad(design, y, penalty, w)
+		weights := make([]float64, len(probs))
+		for i, p := range probs {
+			weights[i] = p * (1 - p)
+		}
+
+		// The Hessian is X'SX + P, where S holds the weights
+		hessian := Add(
+			design.T().MProd(SparseDiag(len(weights), len(weights), weights...), design),
+			SparseDiag(len(penalty), len(penalty), penalty...)).M()
+		step, err := CurrentBackend().Solve(hessian, M(len(grad), 1, grad...))
+		if err != nil {
+			panic(fmt.Errorf("FitLogistic: %w; the classes may be separable, so try FitL2()", err))
+		}
+
+		var change float64
+		for i, s := range step.Col(0) {
+			w[i] -= s
+			change = math.Max(change, math.Abs(s))
+		}
+		if change <= tol {
+			break
+		}
+	}
+	return w
+}
+
+// Minimize the penalized negative log-likelihood of a logistic model with
+// L-BFGS
+func fitLBFGS(design Matrix, y, penalty []float64, maxIter int, tol float64) []float64 {
+	problem := optimize.Problem{
+		Func: func(w []float64) float64 {
+			var loss float64
+			for i, z := range mulVec(design, w) {
+				loss += softplus(z) - y[i]*z
+			}
+			for i, v := range w {
+				loss += penalty[i] * v * v / 2
+			}
+			return loss
+		},
+		Grad: func(grad, w []float64) {
+			g, _ := logisticGrad(design, y, penalty, w)
+			copy(grad, g)
+		},
+	}
+	settings := &optimize.Settings{GradientThreshold: tol, MajorIterations: maxIter}
+	result, err := optimize.Minimize(problem, make([]float64, design.Cols()), settings, &optimize.LBFGS{})
+	if result == nil {
+		panic(fmt.Sprintf("FitLogistic: L-BFGS failed: %v", err))
+	}
+	return result.X
+}
+
+// Get the gradient of the penalized negative log-likelihood of a logistic
+// model, X'(p - y) + Pw, and the predicted probabilities p
+func logisticGrad(design Matrix, y, penalty, w []float64) (grad, probs []float64) {
+	probs = mulVec(design, w)
+	resid := make([]float64, len(probs))
+	for i, z := range probs {
+		probs[i] = sigmoid(z)
+		resid[i] = probs[i] - y[i]
+	}
+	grad = mulVec(design.T(), resid)
+	for i := range grad {
+		grad[i] += penalty[i] * w[i]
+	}
+	return grad, probs
+}
+
+// Multiply a matrix by a vector
+func mulVec(m Matrix, v []float64) []float64 {
+	return m.MProd(M(len(v), 1, v...)).Col(0)
+}
+
+// Get the dot product of two vectors
+func dotVec(a, b []float64) float64 {
+	var sum float64
+	for i := range a {
+		sum += a[i] * b[i]
+	}
+	return sum
+}
+
+// Check whether any element of a vector is positive
+func anyPositive(vec []float64) bool {
+	for _, v := range vec {
+		if v > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// The logistic function, 1 / (1 + exp(-z))
+func sigmoid(z float64) float64 {
+	if z >= 0 {
+		return 1 / (1 + math.Exp(-z))
+	}
+	e := math.Exp(z)
+	return e / (1 + e)
+}
+
+// log(1 + exp(z)), without overflow for large z
+func softplus(z float64) float64 {
+	if z > 0 {
+		return z + math.Log1p(math.Exp(-z))
+	}
+	return math.Log1p(math.Exp(z))
+}
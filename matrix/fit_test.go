@@ -0,0 +1,82 @@
+package matrix
+
+import (
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+func TestFitLinear(t *testing.T) {
+	Convey("Given data on a plane", t, func() {
+		X := M(6, 2,
+			1, 0,
+			0, 1,
+			2, 1,
+			3, 5,
+			-1, 2,
+			4, -2)
+		y := Dense(6, 1).M()
+		for row := 0; row < 6; row++ {
+			y.ItemSet(3+2*X.Item(row, 0)-X.Item(row, 1), row, 0)
+		}
+
+		Convey("FitLinear recovers the coefficients", func() {
+			for _, solver := range []Solver{SolverDirect, SolverCG} {
+				coef, predict := FitLinear(X, y, FitIntercept(), FitSolver(solver))
+				So(coef.Shape(), ShouldResemble, []int{3, 1})
+				So(ApproxEqual(coef, M(3, 1, 3, 2, -1), 1e-8, 0), ShouldBeTrue)
+				So(predict(M(1, 2, 10, 10)).Item(0, 0), ShouldAlmostEqual, 13, 1e-6)
+			}
+		})
+
+		Convey("Without an intercept there is one coefficient per column", func() {
+			coef, _ := FitLinear(X, y)
+			So(coef.Shape(), ShouldResemble, []int{2, 1})
+		})
+
+		Convey("The solvers agree on ridge regression", func() {
+			direct, _ := FitLinear(X, y, FitIntercept(), FitL2(0.5))
+			cg, _ := FitLinear(X.SparseCoo(), y, FitIntercept(), FitL2(0.5), FitSolver(SolverCG))
+			So(ApproxEqual(direct, cg, 1e-6, 0), ShouldBeTrue)
+			So(direct.Item(1, 0), ShouldBeLessThan, 2)
+		})
+
+		Convey("Invalid arguments panic", func() {
+			So(func() { FitLinear(X, M(2, 1, 1, 2)) }, ShouldPanic)
+			So(func() { FitLinear(X, y, FitSolver(SolverIRLS)) }, ShouldPanic)
+			So(func() { FitLinear(X, y, FitL2(-1)) }, ShouldPanic)
+			_, predict := FitLinear(X, y)
+			So(func() { predict(M(1, 3, 1, 2, 3)) }, ShouldPanic)
+		})
+	})
+}
+
+func TestFitLogistic(t *testing.T) {
+	Convey("Given overlapping classes", t, func() {
+		X := M(8, 1, -3, -2, -1, 0.5, -0.5, 1, 2, 3)
+		y := M(8, 1, 0, 0, 1, 0, 0, 1, 1, 1)
+
+		Convey("IRLS and L-BFGS agree", func() {
+			irls, predict := FitLogistic(X, y, FitIntercept())
+			lbfgs, _ := FitLogistic(X, y, FitIntercept(), FitSolver(SolverLBFGS))
+			So(ApproxEqual(irls, lbfgs, 1e-4, 0), ShouldBeTrue)
+			So(irls.Item(1, 0), ShouldBeGreaterThan, 0)
+
+			// At the maximum, the predicted and actual counts of 1s match
+			So(Sum(predict(X)), ShouldAlmostEqual, Sum(y), 1e-8)
+			p := predict(M(2, 1, -10, 10))
+			So(p.Item(0, 0), ShouldBeLessThan, 0.01)
+			So(p.Item(1, 0), ShouldBeGreaterThan, 0.99)
+		})
+
+		Convey("A penalty keeps separable fits finite", func() {
+			coef, _ := FitLogistic(M(4, 1, -2, -1, 1, 2), M(4, 1, 0, 0, 1, 1), FitL2(1))
+			So(coef.Item(0, 0), ShouldBeGreaterThan, 0)
+			So(coef.Item(0, 0), ShouldBeLessThan, 10)
+		})
+
+		Convey("Invalid arguments panic", func() {
+			So(func() { FitLogistic(X, M(8, 1, 0, 0, 2, 0, 0, 1, 1, 1)) }, ShouldPanic)
+			So(func() { FitLogistic(X, y, FitSolver(SolverCG)) }, ShouldPanic)
+		})
+	})
+}
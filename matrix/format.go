@@ -0,0 +1,122 @@
+package matrix
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Options for formatting a matrix as text with SprintMatrix()
+type FormatOptions struct {
+	// The number of digits after the decimal point, or -1 to use the fewest
+	// digits which represent each value exactly
+	Precision int
+
+	// The largest number of rows and columns to show. Larger matrices are
+	// summarized by showing the first and last rows or columns, with "..."
+	// in place of the rest. Zero means no limit.
+	MaxRows, MaxCols int
+
+	// Use scientific notation, such as 1.5e+03
+	Scientific bool
+
+	// The separator between columns; a space if empty
+	Delimiter string
+}
+
+// Formatting options suited to logging: values with their shortest exact
+// representation, and at most 10 rows and columns.
+var DefaultFormat = FormatOptions{
+	Precision: -1,
+	MaxRows:   10,
+	MaxCols:   10,
+}
+
+// Format a matrix as text, one row per line, in the style of NumPy:
+//
+//	[[1 2 3]
+//	 [4 5 6]]
+//
+// Columns are right-aligned.
+func SprintMatrix(m Matrix, opts FormatOptions) string {
+	var (
+		rows  = shownIndices(m.Rows(), opts.MaxRows)
+		cols  = shownIndices(m.Cols(), opts.MaxCols)
+		cells = make([][]string, len(rows))
+		width = make([]int, len(cols))
+		delim = opts.Delimiter
+	)
+	if delim == "" {
+		delim = " "
+	}
+	for i, row := range rows {
+		if row < 0 {
+			continue
+		}
+		cells[i] = make([]string, len(cols))
+		for j, col := range cols {
+			if col >= 0 {
+				cells[i][j] = opts.formatValue(m.Item(row, col))
+			} else {
+				cells[i][j] = "..."
+			}
+			if len(cells[i][j]) > width[j] {
+				width[j] = len(cells[i][j])
+			}
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString("[")
+	for i, row := range rows {
+		if i > 0 {
+			b.WriteString("\n ")
+		}
+		if row < 0 {
+			b.WriteString("...")
+			continue
+		}
+		b.WriteString("[")
+		for j := range cols {
+			if j > 0 {
+				b.WriteString(delim)
+			}
+			b.WriteString(strings.Repeat(" ", width[j]-len(cells[i][j])))
+			b.WriteString(cells[i][j])
+		}
+		b.WriteString("]")
+	}
+	b.WriteString("]")
+	return b.String()
+}
+
+// Format a single value
+func (opts FormatOptions) formatValue(v float64) string {
+	format := byte('f')
+	if opts.Scientific {
+		format = 'e'
+	} else if opts.Precision < 0 {
+		format = 'g'
+	}
+	return strconv.FormatFloat(v, format, opts.Precision, 64)
+}
+
+// Get the indices to show along an axis of the given size, with -1 marking
+// where indices were left out
+func shownIndices(size, max int) []int {
+	var indices []int
+	if max <= 0 || size <= max {
+		for i := 0; i < size; i++ {
+			indices = append(indices, i)
+		}
+		return indices
+	}
+	head := (max + 1) / 2
+	for i := 0; i < head; i++ {
+		indices = append(indices, i)
+	}
+	indices = append(indices, -1)
+	for i := size - (max - head); i < size; i++ {
+		indices = append(indices, i)
+	}
+	return indices
+}
@@ -0,0 +1,59 @@
+package matrix
+
+import (
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+func TestSprintMatrix(t *testing.T) {
+	Convey("Given a small matrix", t, func() {
+		m := M(2, 3,
+			1, 2.5, -3,
+			40, 5, 6)
+
+		Convey("Columns are aligned", func() {
+			So(SprintMatrix(m, DefaultFormat), ShouldEqual,
+				"[[ 1 2.5 -3]\n"+
+					" [40   5  6]]")
+		})
+
+		Convey("Precision sets the decimal places", func() {
+			So(SprintMatrix(m, FormatOptions{Precision: 2}), ShouldEqual,
+				"[[ 1.00 2.50 -3.00]\n"+
+					" [40.00 5.00  6.00]]")
+			So(SprintMatrix(m, FormatOptions{Precision: 0}), ShouldEqual,
+				"[[ 1 2 -3]\n"+
+					" [40 5  6]]")
+		})
+
+		Convey("Scientific notation and delimiters can be chosen", func() {
+			So(SprintMatrix(M(1, 2, 1500, 0.25), FormatOptions{Precision: 1, Scientific: true, Delimiter: ", "}), ShouldEqual,
+				"[[1.5e+03, 2.5e-01]]")
+		})
+
+		Convey("Sparse and transposed matrices print their logical values", func() {
+			So(SprintMatrix(Eye(2), DefaultFormat), ShouldEqual, "[[1 0]\n [0 1]]")
+			So(SprintMatrix(m.T(), FormatOptions{Precision: -1}), ShouldEqual,
+				"[[  1 40]\n"+
+					" [2.5  5]\n"+
+					" [ -3  6]]")
+		})
+	})
+
+	Convey("Given a large matrix", t, func() {
+		m := Dense(100, 100).M()
+		m.ItemSet(7, 99, 99)
+
+		Convey("Rows and columns are summarized", func() {
+			So(SprintMatrix(m, FormatOptions{Precision: -1, MaxRows: 3, MaxCols: 4}), ShouldEqual,
+				"[[0 0 ... 0 0]\n"+
+					" [0 0 ... 0 0]\n"+
+					" ...\n"+
+					" [0 0 ... 0 7]]")
+		})
+	})
+
+	Convey("Given an empty matrix", t, func() {
+		So(SprintMatrix(Dense(0, 3).M(), DefaultFormat), ShouldEqual, "[]")
+	})
+}
@@ -0,0 +1,113 @@
+package matrix
+
+import (
+	"fmt"
+)
+
+// A read-only matrix, created by Freeze()
+type frozenMatrix struct {
+	Matrix
+}
+
+// Get a read-only view of a matrix, which can be handed out without making a
+// defensive copy. Methods which would modify the matrix panic with an error
+// wrapping ErrFrozen, or return one in the case of Scan(). Methods which
+// would return a view of the storage, such as Row(), Col() and Array(),
+// return copies instead, and T() returns a frozen transpose. Operations which
+// create new arrays, such as Add() or Copy(), return ordinary arrays which
+// can be modified.
+//
+// Freezing doesn't copy m, so changes made through m remain visible in the
+// frozen matrix.
+func Freeze(m Matrix) Matrix {
+	if IsFrozen(m) {
+		return m
+	}
+	return &frozenMatrix{m}
+}
+
+// Returns true if the matrix was made read-only by Freeze()
+func IsFrozen(m NDArray) bool {
+	_, ok := m.(*frozenMatrix)
+	return ok
+}
+
+// The error reported when op tries to modify a frozen matrix
+func frozenError(op string) error {
+	return fmt.Errorf("%s: %w", op, ErrFrozen)
+}
+
+// Get a copy of the array's values, flattened in row-major order
+func (f *frozenMatrix) Array() []float64 {
+	return append([]float64(nil), f.Matrix.Array()...)
+}
+
+// Get a copy of a column
+func (f *frozenMatrix) Col(col int) []float64 {
+	return append([]float64(nil), f.Matrix.Col(col)...)
+}
+
+// Frozen matrices can't be modified
+func (f *frozenMatrix) ColSet(col int, values []float64) {
+	panic(frozenError("ColSet"))
+}
+
+// Frozen matrices can't be modified
+func (f *frozenMatrix) Fill(value float64) {
+	panic(frozenError("Fill"))
+}
+
+// Frozen matrices can't be modified
+func (f *frozenMatrix) FlatItemSet(value float64, index int) {
+	panic(frozenError("FlatItemSet"))
+}
+
+// Frozen matrices can't be modified
+func (f *frozenMatrix) ItemSet(value float64, index ...int) {
+	panic(frozenError("ItemSet"))
+}
+
+// A frozen matrix is already a matrix
+func (f *frozenMatrix) M() Matrix {
+	return f
+}
+
+// Get a copy of a row
+func (f *frozenMatrix) Row(row int) []float64 {
+	return append([]float64(nil), f.Matrix.Row(row)...)
+}
+
+// Frozen matrices can't be modified
+func (f *frozenMatrix) RowSet(row int, values []float64) {
+	panic(frozenError("RowSet"))
+}
+
+// Frozen matrices can't be modified
+func (f *frozenMatrix) Scan(src interface{}) error {
+	return frozenError("Scan")
+}
+
+// Frozen matrices can't be modified
+func (f *frozenMatrix) SetSubmatrix(row, col int, src Matrix) {
+	panic(frozenError("SetSubmatrix"))
+}
+
+// Frozen matrices can't be modified
+func (f *frozenMatrix) SwapCols(i, j int) {
+	panic(frozenError("SwapCols"))
+}
+
+// Frozen matrices can't be modified
+func (f *frozenMatrix) SwapRows(i, j int) {
+	panic(frozenError("SwapRows"))
+}
+
+// Get a frozen transpose of the matrix
+func (f *frozenMatrix) T() Matrix {
+	return &frozenMatrix{f.Matrix.T()}
+}
+
+// Get a frozen view of the matrix
+func (f *frozenMatrix) View() NDArray {
+	return &frozenMatrix{f.Matrix.View().M()}
+}
@@ -0,0 +1,61 @@
+package matrix
+
+import (
+	"errors"
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+func TestFreeze(t *testing.T) {
+	Convey("Given a frozen matrix", t, func() {
+		m := M(2, 2,
+			1, 2,
+			3, 4)
+		f := Freeze(m)
+
+		Convey("It can be read as usual", func() {
+			So(IsFrozen(f), ShouldBeTrue)
+			So(IsFrozen(m), ShouldBeFalse)
+			So(f.Equal(m), ShouldBeTrue)
+			So(f.MProd(Eye(2)).Equal(m), ShouldBeTrue)
+			So(f.Trace(), ShouldEqual, 5)
+			So(Freeze(f), ShouldEqual, f)
+		})
+
+		Convey("Modifying it fails with ErrFrozen", func() {
+			So(func() { f.ItemSet(0, 0, 0) }, ShouldPanic)
+			So(errors.Is(TryItemSet(f, 0, 0, 0), ErrFrozen), ShouldBeTrue)
+			So(errors.Is(TryRowSet(f, 0, []float64{0, 0}), ErrFrozen), ShouldBeTrue)
+			So(errors.Is(TryColSet(f, 0, []float64{0, 0}), ErrFrozen), ShouldBeTrue)
+			So(errors.Is(try(func() { f.Fill(0) }), ErrFrozen), ShouldBeTrue)
+			So(errors.Is(try(func() { f.FlatItemSet(0, 1) }), ErrFrozen), ShouldBeTrue)
+			So(errors.Is(f.Scan(`{"shape":[1,1],"values":[0]}`), ErrFrozen), ShouldBeTrue)
+			So(TryItemSet(f, 0, 0, 0).Error(), ShouldEqual, "ItemSet: matrix is frozen")
+			So(m.Equal(M(2, 2, 1, 2, 3, 4)), ShouldBeTrue)
+		})
+
+		Convey("Views of its storage are copies or frozen", func() {
+			f.Row(0)[0] = 10
+			f.Col(0)[0] = 10
+			f.Array()[0] = 10
+			So(m.Item(0, 0), ShouldEqual, 1)
+			So(IsFrozen(f.T()), ShouldBeTrue)
+			So(IsFrozen(f.M()), ShouldBeTrue)
+			So(errors.Is(TryItemSet(f.T(), 0, 0, 1), ErrFrozen), ShouldBeTrue)
+		})
+
+		Convey("Derived arrays can be modified", func() {
+			c := f.Copy()
+			c.ItemSet(5, 0, 0)
+			So(c.Item(0, 0), ShouldEqual, 5)
+			s := f.Add(f)
+			s.ItemSet(5, 0, 0)
+			So(f.Item(0, 0), ShouldEqual, 1)
+		})
+
+		Convey("Changes to the original are visible", func() {
+			m.ItemSet(7, 1, 1)
+			So(f.Item(1, 1), ShouldEqual, 7)
+		})
+	})
+}
@@ -0,0 +1,55 @@
+// The gorgoniaconv package converts between matrix arrays and Gorgonia
+// tensors, so arrays can be used in Gorgonia computation graphs. It is kept
+// out of the matrix package because Gorgonia's dependencies, which include
+// go4.org/unsafe/assume-no-moving-gc, refuse to initialize on Go releases
+// they haven't been checked against; only programs which import this package
+// need to work around that.
+package gorgoniaconv
+
+import (
+	"fmt"
+	"github.com/jesand/numgo/matrix"
+	"gorgonia.org/tensor"
+)
+
+// Convert an array to a Gorgonia tensor with the same shape and dtype Float64,
+// so it can be used in Gorgonia computation graphs. For non-transposed dense
+// arrays the tensor shares storage with the array; other arrays are copied.
+func ToGorgonia(array matrix.NDArray) tensor.Tensor {
+	shape := make([]int, len(array.Shape()))
+	copy(shape, array.Shape())
+	return tensor.New(tensor.WithShape(shape...), tensor.WithBacking(array.Array()))
+}
+
+// Convert a Gorgonia tensor to a dense array with the same shape. Tensors
+// of dtype Float64 share storage with the result when their data is laid out
+// contiguously; Float32 tensors are converted to float64. Returns an error for
+// other dtypes and for scalar tensors.
+func FromGorgonia(t tensor.Tensor) (matrix.NDArray, error) {
+	if dense, ok := t.(*tensor.Dense); ok && dense.IsMaterializable() {
+		// Views such as transposes must be laid out in memory before we can use
+		// their backing data
+		t = dense.Materialize()
+	}
+	shape := t.Shape()
+	if len(shape) == 0 {
+		return nil, fmt.Errorf("Can't convert a scalar tensor to an array")
+	}
+
+	var values []float64
+	switch data := t.Data().(type) {
+	case []float64:
+		values = data
+	case []float32:
+		values = make([]float64, len(data))
+		for idx, v := range data {
+			values[idx] = float64(v)
+		}
+	default:
+		return nil, fmt.Errorf("Can't convert a tensor of dtype %v to an array", t.Dtype())
+	}
+	if len(values) != shape.TotalSize() {
+		return nil, fmt.Errorf("Tensor of shape %v has %d elements", shape, len(values))
+	}
+	return matrix.WrapA(shape, values), nil
+}
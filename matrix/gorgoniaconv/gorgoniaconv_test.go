@@ -0,0 +1,73 @@
+package gorgoniaconv
+
+import (
+	. "github.com/jesand/numgo/matrix"
+	. "github.com/smartystreets/goconvey/convey"
+	"gorgonia.org/tensor"
+	"testing"
+)
+
+func TestGorgonia(t *testing.T) {
+	Convey("Given a dense matrix", t, func() {
+		m := M(2, 3,
+			1, 2, 3,
+			4, 5, 6)
+
+		Convey("ToGorgonia preserves shape and values", func() {
+			g := ToGorgonia(m)
+			So([]int(g.Shape()), ShouldResemble, []int{2, 3})
+			So(g.Dtype(), ShouldResemble, tensor.Float64)
+			v, err := g.At(1, 2)
+			So(err, ShouldBeNil)
+			So(v, ShouldEqual, 6.0)
+
+			Convey("FromGorgonia converts it back", func() {
+				a, err := FromGorgonia(g)
+				So(err, ShouldBeNil)
+				So(a.Shape(), ShouldResemble, []int{2, 3})
+				So(a.Array(), ShouldResemble, m.Array())
+			})
+		})
+
+		Convey("Transposed and sparse matrices are converted", func() {
+			a, err := FromGorgonia(ToGorgonia(m.T()))
+			So(err, ShouldBeNil)
+			So(a.Shape(), ShouldResemble, []int{3, 2})
+			So(a.Array(), ShouldResemble, []float64{1, 4, 2, 5, 3, 6})
+
+			a, err = FromGorgonia(ToGorgonia(Diag(1, 2)))
+			So(err, ShouldBeNil)
+			So(a.Array(), ShouldResemble, []float64{1, 0, 0, 2})
+		})
+
+		Convey("Arrays of other dimensionality keep their shape", func() {
+			a, err := FromGorgonia(ToGorgonia(Rand(2, 3, 4)))
+			So(err, ShouldBeNil)
+			So(a.Shape(), ShouldResemble, []int{2, 3, 4})
+		})
+	})
+
+	Convey("Given Gorgonia tensors", t, func() {
+		Convey("Transposed views are materialized", func() {
+			g := tensor.New(tensor.WithShape(2, 3), tensor.WithBacking([]float64{1, 2, 3, 4, 5, 6}))
+			So(g.T(), ShouldBeNil)
+			a, err := FromGorgonia(g)
+			So(err, ShouldBeNil)
+			So(a.Shape(), ShouldResemble, []int{3, 2})
+			So(a.Array(), ShouldResemble, []float64{1, 4, 2, 5, 3, 6})
+		})
+
+		Convey("Float32 tensors are converted", func() {
+			g := tensor.New(tensor.WithShape(2), tensor.WithBacking([]float32{1.5, 2}))
+			a, err := FromGorgonia(g)
+			So(err, ShouldBeNil)
+			So(a.Array(), ShouldResemble, []float64{1.5, 2})
+		})
+
+		Convey("Other dtypes are an error", func() {
+			g := tensor.New(tensor.WithShape(2), tensor.WithBacking([]int{1, 2}))
+			_, err := FromGorgonia(g)
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
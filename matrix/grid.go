@@ -0,0 +1,70 @@
+package matrix
+
+import (
+	"fmt"
+	"math"
+)
+
+// Get n evenly spaced values from start to stop, including both ends
+func Linspace(start, stop float64, n int) []float64 {
+	if n < 0 {
+		panic(fmt.Sprintf("Linspace: can't create %d values", n))
+	}
+	result := make([]float64, n)
+	if n == 0 {
+		return result
+	}
+	result[0] = start
+	if n == 1 {
+		return result
+	}
+	step := (stop - start) / float64(n-1)
+	for i := 1; i < n-1; i++ {
+		result[i] = start + float64(i)*step
+	}
+	result[n-1] = stop
+	return result
+}
+
+// Get the values start, start+step, start+2*step, ... which come before stop.
+// The step may be negative, to count down, but not zero.
+func Arange(start, stop, step float64) []float64 {
+	if step == 0 || math.IsNaN(step) {
+		panic(fmt.Sprintf("Arange: invalid step %v", step))
+	}
+	n := int(math.Ceil((stop - start) / step))
+	if n < 0 {
+		n = 0
+	}
+	result := make([]float64, n)
+	for i := range result {
+		result[i] = start + float64(i)*step
+	}
+	return result
+}
+
+// Get n values evenly spaced on a log scale from base^start to base^stop,
+// including both ends
+func Logspace(start, stop float64, n int, base float64) []float64 {
+	result := Linspace(start, stop, n)
+	for i, v := range result {
+		result[i] = math.Pow(base, v)
+	}
+	return result
+}
+
+// Get coordinate matrices for the grid of points (x[j], y[i]). Both results
+// are len(y) x len(x): every row of X is x, and every column of Y is y, so a
+// function of two variables can be evaluated at every point of the grid
+// element-wise. This matches the default "xy" indexing of NumPy's meshgrid.
+func Meshgrid(x, y []float64) (X, Y Matrix) {
+	X = Dense(len(y), len(x)).M()
+	Y = Dense(len(y), len(x)).M()
+	for i, yv := range y {
+		for j, xv := range x {
+			X.ItemSet(xv, i, j)
+			Y.ItemSet(yv, i, j)
+		}
+	}
+	return X, Y
+}
@@ -0,0 +1,43 @@
+package matrix
+
+import (
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+func TestLinspace(t *testing.T) {
+	Convey("Linspace includes both ends", t, func() {
+		So(Linspace(0, 1, 5), ShouldResemble, []float64{0, 0.25, 0.5, 0.75, 1})
+		So(Linspace(2, -2, 3), ShouldResemble, []float64{2, 0, -2})
+		So(Linspace(3, 4, 1), ShouldResemble, []float64{3})
+		So(Linspace(3, 4, 0), ShouldResemble, []float64{})
+		So(Linspace(0, 0.3, 4)[3], ShouldEqual, 0.3)
+		So(func() { Linspace(0, 1, -1) }, ShouldPanic)
+	})
+
+	Convey("Arange excludes its stop value", t, func() {
+		So(Arange(0, 5, 1), ShouldResemble, []float64{0, 1, 2, 3, 4})
+		So(Arange(1, 2, 0.25), ShouldResemble, []float64{1, 1.25, 1.5, 1.75})
+		So(Arange(3, 0, -1.5), ShouldResemble, []float64{3, 1.5})
+		So(Arange(3, 0, 1), ShouldResemble, []float64{})
+		So(func() { Arange(0, 1, 0) }, ShouldPanic)
+	})
+
+	Convey("Logspace spaces values by powers of the base", t, func() {
+		So(Logspace(0, 3, 4, 10), ShouldResemble, []float64{1, 10, 100, 1000})
+		So(Logspace(1, 3, 3, 2), ShouldResemble, []float64{2, 4, 8})
+	})
+}
+
+func TestMeshgrid(t *testing.T) {
+	Convey("Meshgrid repeats x along rows and y along columns", t, func() {
+		X, Y := Meshgrid([]float64{1, 2, 3}, []float64{4, 5})
+		So(X.Equal(M(2, 3,
+			1, 2, 3,
+			1, 2, 3)), ShouldBeTrue)
+		So(Y.Equal(M(2, 3,
+			4, 4, 4,
+			5, 5, 5)), ShouldBeTrue)
+		So(Add(X, Y).Item(1, 2), ShouldEqual, 8)
+	})
+}
@@ -0,0 +1,38 @@
+package matrix
+
+import (
+	"math"
+)
+
+// Get a hash of the logical contents of an array, for deduplication and use
+// as a map key. Arrays which are Equal have the same hash regardless of their
+// storage format or transposition, and the hash is stable across processes.
+// It is not a cryptographic hash.
+func Hash(array NDArray) uint64 {
+	h := uint64(len(array.Shape()))
+	for _, d := range array.Shape() {
+		h = mix64(h ^ uint64(d))
+	}
+
+	// Sum the hashes of the nonzero elements, so the result doesn't depend on
+	// the order in which they are visited
+	var sum uint64
+	shape := array.Shape()
+	array.VisitNonzero(func(pos []int, value float64) bool {
+		if value != 0 {
+			sum += mix64(uint64(ndToFlat(shape, pos)) ^ mix64(math.Float64bits(value)))
+		}
+		return true
+	})
+	return mix64(h ^ sum)
+}
+
+// The splitmix64 finalizer, which scrambles the bits of x
+func mix64(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
@@ -0,0 +1,70 @@
+package matrix
+
+import (
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+func TestEqualAndHash(t *testing.T) {
+	Convey("Given the same matrix in each storage format", t, func() {
+		dense := M(3, 3,
+			1, 0, 0,
+			0, 2, 0,
+			0, 0, 3)
+		coo := dense.SparseCoo()
+		diag := dense.SparseDiag()
+		colMajor := AsOrder(dense, ColMajor)
+		all := []Matrix{dense, coo, diag, colMajor}
+
+		Convey("All pairs are Equal", func() {
+			for _, a := range all {
+				for _, b := range all {
+					So(Equal(a, b), ShouldBeTrue)
+					So(a.Equal(b), ShouldBeTrue)
+				}
+			}
+		})
+
+		Convey("All have the same Hash", func() {
+			for _, a := range all {
+				So(Hash(a), ShouldEqual, Hash(dense))
+			}
+		})
+
+		Convey("Transposes are equal to a transposed copy", func() {
+			m := M(2, 3, 1, 0, 2, 0, 3, 0)
+			So(Equal(m.T(), m.T().Copy()), ShouldBeTrue)
+			So(Equal(m.SparseCoo().T(), m.T()), ShouldBeTrue)
+			So(Hash(m.SparseCoo().T()), ShouldEqual, Hash(m.T().Copy()))
+		})
+	})
+
+	Convey("Given different matrices", t, func() {
+		a := M(2, 2, 1, 0, 0, 2)
+
+		Convey("Different values are not Equal and hash differently", func() {
+			b := M(2, 2, 1, 0, 0, 3).SparseCoo()
+			So(Equal(a.SparseCoo(), b), ShouldBeFalse)
+			So(Equal(a.SparseDiag(), b), ShouldBeFalse)
+			So(Equal(b, a.SparseDiag()), ShouldBeFalse)
+			So(Hash(a), ShouldNotEqual, Hash(b))
+		})
+
+		Convey("Extra nonzeros are detected in either direction", func() {
+			b := M(2, 2, 1, 5, 0, 2).SparseCoo()
+			So(Equal(a.SparseDiag(), b), ShouldBeFalse)
+			So(Equal(b, a.SparseDiag()), ShouldBeFalse)
+			So(Hash(a), ShouldNotEqual, Hash(b))
+		})
+
+		Convey("Different shapes are not Equal and hash differently", func() {
+			So(Equal(a, Dense(4)), ShouldBeFalse)
+			So(Hash(Dense(2, 2)), ShouldNotEqual, Hash(Dense(4)))
+			So(Hash(Dense(2, 3)), ShouldNotEqual, Hash(Dense(3, 2)))
+		})
+
+		Convey("Moving a value changes the hash", func() {
+			So(Hash(M(1, 2, 1, 0)), ShouldNotEqual, Hash(M(1, 2, 0, 1)))
+		})
+	})
+}
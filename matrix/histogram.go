@@ -0,0 +1,121 @@
+package matrix
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Get the index of the histogram bin containing value, or -1 if it falls
+// outside the edges. Bins are half-open [edges[i], edges[i+1]), except for the
+// last bin, which also includes its right edge.
+func binIndex(edges []float64, value float64) int {
+	last := len(edges) - 1
+	if last < 1 || math.IsNaN(value) || value < edges[0] || value > edges[last] {
+		return -1
+	} else if value == edges[last] {
+		return last - 1
+	}
+	return sort.Search(last, func(i int) bool { return edges[i+1] > value })
+}
+
+// Get bins+1 evenly spaced bin edges spanning [lo, hi]. If lo == hi, the
+// range is widened to [lo-0.5, hi+0.5], as in NumPy.
+func evenEdges(lo, hi float64, bins int) []float64 {
+	if bins < 1 {
+		panic(fmt.Sprintf("Can't create a histogram with %d bins", bins))
+	}
+	if lo == hi {
+		lo, hi = lo-0.5, hi+0.5
+	}
+	edges := make([]float64, bins+1)
+	width := (hi - lo) / float64(bins)
+	for i := range edges {
+		edges[i] = lo + float64(i)*width
+	}
+	edges[bins] = hi
+	return edges
+}
+
+// Count the array elements falling in each of bins equal-width bins spanning
+// the range [Min(), Max()]. Returns the counts and the bins+1 bin edges. Only
+// nonzero elements of sparse arrays are visited individually.
+func Histogram(array NDArray, bins int) (counts []int, edges []float64) {
+	edges = evenEdges(array.Min(), array.Max(), bins)
+	return HistogramEdges(array, edges), edges
+}
+
+// Count the array elements falling in each bin defined by edges, which must
+// be monotonically increasing. Bins are half-open [edges[i], edges[i+1]),
+// except for the last bin, which also includes its right edge. Elements
+// outside the edges are not counted.
+func HistogramEdges(array NDArray, edges []float64) []int {
+	if len(edges) < 2 {
+		panic(fmt.Sprintf("Can't create a histogram with %d bin edges", len(edges)))
+	}
+	for i := 1; i < len(edges); i++ {
+		if edges[i] < edges[i-1] {
+			panic(fmt.Sprintf("Histogram bin edges %v are not increasing", edges))
+		}
+	}
+	counts := make([]int, len(edges)-1)
+	nonzero := 0
+	array.VisitNonzero(func(pos []int, value float64) bool {
+		nonzero++
+		if bin := binIndex(edges, value); bin >= 0 {
+			counts[bin]++
+		}
+		return true
+	})
+	if bin := binIndex(edges, 0); bin >= 0 {
+		counts[bin] += array.Size() - nonzero
+	}
+	return counts
+}
+
+// Count the joint occurrences of the values in two matrix columns, using
+// xBins and yBins equal-width bins spanning the range of each column. The
+// counts are returned in an xBins x yBins matrix, along with the bin edges
+// for each column.
+func Histogram2D(m Matrix, xCol, yCol int, xBins, yBins int) (counts Matrix, xEdges, yEdges []float64) {
+	xs := A1(m.Col(xCol)...)
+	ys := A1(m.Col(yCol)...)
+	xEdges = evenEdges(xs.Min(), xs.Max(), xBins)
+	yEdges = evenEdges(ys.Min(), ys.Max(), yBins)
+	counts = Dense(xBins, yBins).M()
+	for row := 0; row < m.Rows(); row++ {
+		x := binIndex(xEdges, xs.FlatItem(row))
+		y := binIndex(yEdges, ys.FlatItem(row))
+		if x >= 0 && y >= 0 {
+			counts.ItemSet(counts.Item(x, y)+1, x, y)
+		}
+	}
+	return counts, xEdges, yEdges
+}
+
+// Count the number of occurrences of each value in an array of non-negative
+// integers. The result has length Max()+1, and result[v] is the number of
+// elements equal to v. Panics if any element is negative, infinite, NaN or
+// not an integer.
+func BinCount(array NDArray) []int {
+	if array.Size() == 0 {
+		return []int{}
+	}
+	maxValue := 0.0
+	array.VisitNonzero(func(pos []int, value float64) bool {
+		if value < 0 || math.IsInf(value, 0) || value != math.Trunc(value) {
+			panic(fmt.Sprintf("Can't BinCount value %v at %v: values should be non-negative integers", value, pos))
+		}
+		maxValue = math.Max(maxValue, value)
+		return true
+	})
+	counts := make([]int, int(maxValue)+1)
+	nonzero := 0
+	array.VisitNonzero(func(pos []int, value float64) bool {
+		nonzero++
+		counts[int(value)]++
+		return true
+	})
+	counts[0] += array.Size() - nonzero
+	return counts
+}
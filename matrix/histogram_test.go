@@ -0,0 +1,107 @@
+package matrix
+
+import (
+	. "github.com/smartystreets/goconvey/convey"
+	"math"
+	"testing"
+)
+
+func TestHistogram(t *testing.T) {
+	Convey("Given a dense array", t, func() {
+		a := A1(1, 2, 2, 3, 3, 3, 4, 4, 4, 4)
+
+		Convey("Histogram counts values in even bins", func() {
+			counts, edges := Histogram(a, 3)
+			So(edges, ShouldResemble, []float64{1, 2, 3, 4})
+			So(counts, ShouldResemble, []int{1, 2, 7})
+		})
+
+		Convey("HistogramEdges uses the given edges", func() {
+			counts := HistogramEdges(a, []float64{0, 2.5, 3.5, 10})
+			So(counts, ShouldResemble, []int{3, 3, 4})
+		})
+
+		Convey("Values outside the edges are ignored", func() {
+			counts := HistogramEdges(a, []float64{2, 3})
+			So(counts, ShouldResemble, []int{5})
+		})
+
+		Convey("Invalid bins panic", func() {
+			So(func() { Histogram(a, 0) }, ShouldPanic)
+			So(func() { HistogramEdges(a, []float64{1}) }, ShouldPanic)
+			So(func() { HistogramEdges(a, []float64{3, 1}) }, ShouldPanic)
+		})
+	})
+
+	Convey("Given a constant array", t, func() {
+		a := WithValue(2, 2, 2)
+
+		Convey("Histogram widens the range", func() {
+			counts, edges := Histogram(a, 2)
+			So(edges, ShouldResemble, []float64{1.5, 2, 2.5})
+			So(counts, ShouldResemble, []int{0, 4})
+		})
+	})
+
+	Convey("Given a sparse coo matrix", t, func() {
+		m := SparseCoo(10, 10)
+		m.ItemSet(-1, 1, 2)
+		m.ItemSet(1, 3, 4)
+
+		Convey("Histogram counts the implicit zeros", func() {
+			counts, _ := Histogram(m, 4)
+			So(counts, ShouldResemble, []int{1, 0, 98, 1})
+		})
+	})
+
+	Convey("Given a matrix with two columns", t, func() {
+		m := M(5, 2,
+			0, 0,
+			1, 0,
+			1, 1,
+			2, 2,
+			2, math.NaN())
+
+		Convey("Histogram2D counts joint occurrences", func() {
+			counts, xEdges, yEdges := Histogram2D(m, 0, 1, 2, 2)
+			So(xEdges, ShouldResemble, []float64{0, 1, 2})
+			So(yEdges, ShouldResemble, []float64{0, 1, 2})
+			So(counts.Array(), ShouldResemble, []float64{
+				1, 0,
+				1, 2,
+			})
+		})
+	})
+}
+
+func TestBinCount(t *testing.T) {
+	Convey("Given an array of small integers", t, func() {
+		a := A1(0, 1, 1, 3, 2, 1, 7)
+
+		Convey("BinCount counts each value", func() {
+			So(BinCount(a), ShouldResemble, []int{1, 3, 1, 1, 0, 0, 0, 1})
+		})
+	})
+
+	Convey("Given a sparse diagonal matrix", t, func() {
+		m := Diag(1, 2, 0)
+
+		Convey("BinCount counts the implicit zeros", func() {
+			So(BinCount(m), ShouldResemble, []int{7, 1, 1})
+		})
+	})
+
+	Convey("BinCount panics on negative or fractional values", t, func() {
+		So(func() { BinCount(A1(1, -1)) }, ShouldPanic)
+		So(func() { BinCount(A1(1, 1.5)) }, ShouldPanic)
+	})
+
+	Convey("BinCount panics on non-finite values as on negative ones", t, func() {
+		So(func() { BinCount(A1(1, math.NaN())) }, ShouldPanicWith,
+			"Can't BinCount value NaN at [1]: values should be non-negative integers")
+		So(func() { BinCount(A1(math.Inf(1), 1)) }, ShouldPanicWith,
+			"Can't BinCount value +Inf at [0]: values should be non-negative integers")
+		So(func() { BinCount(A1(1, math.Inf(-1))) }, ShouldPanicWith,
+			"Can't BinCount value -Inf at [1]: values should be non-negative integers")
+	})
+}
@@ -0,0 +1,166 @@
+package matrix
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// The method used by Resize() to find values between the elements of a
+// matrix
+type InterpMethod int
+
+const (
+	// Use the value of the nearest element
+	InterpNearest InterpMethod = iota
+
+	// Interpolate linearly between the nearest 2 x 2 elements
+	InterpBilinear
+
+	// Interpolate with cubic convolution over the nearest 4 x 4 elements,
+	// which is smoother than bilinear interpolation but may overshoot at sharp
+	// edges
+	InterpBicubic
+)
+
+// Resize m to newRows x newCols, treating it as a grid of samples such as an
+// image. Element (i, j) of the result is taken from position
+// ((i + 0.5) * rows / newRows - 0.5, (j + 0.5) * cols / newCols - 0.5) of m,
+// so that the corners of the grid line up, and positions outside m take the
+// value of the nearest edge. Downsampling doesn't average over the elements
+// between samples, so it can alias; smooth m first to avoid that. The result
+// is dense.
+func Resize(m Matrix, newRows, newCols int, method InterpMethod) Matrix {
+	debugCheck("Resize", m)
+	rows, cols := m.Rows(), m.Cols()
+	if newRows < 0 || newCols < 0 {
+		panic(fmt.Sprintf("Resize: can't resize to %dx%d", newRows, newCols))
+	}
+	if (rows == 0 || cols == 0) && newRows*newCols > 0 {
+		panic(fmt.Sprintf("Resize: can't resize a %dx%d matrix to %dx%d", rows, cols, newRows, newCols))
+	}
+	var weights func(pos float64, n int) ([]int, []float64)
+	switch method {
+	case InterpNearest:
+		weights = nearestWeights
+	case InterpBilinear:
+		weights = linearWeights
+	case InterpBicubic:
+		weights = cubicWeights
+	default:
+		panic(fmt.Sprintf("Resize: unknown interpolation method %v", method))
+	}
+
+	// The weights are separable, so find them once for each row and column
+	rowIdx, rowW := make([][]int, newRows), make([][]float64, newRows)
+	for i := range rowIdx {
+		rowIdx[i], rowW[i] = weights((float64(i)+0.5)*float64(rows)/float64(newRows)-0.5, rows)
+	}
+	colIdx, colW := make([][]int, newCols), make([][]float64, newCols)
+	for j := range colIdx {
+		colIdx[j], colW[j] = weights((float64(j)+0.5)*float64(cols)/float64(newCols)-0.5, cols)
+	}
+
+	data := m.Array()
+	out := make([]float64, newRows*newCols)
+	for i := 0; i < newRows; i++ {
+		for j := 0; j < newCols; j++ {
+			var sum float64
+			for a, row := range rowIdx[i] {
+				for b, col := range colIdx[j] {
+					sum += rowW[i][a] * colW[j][b] * data[row*cols+col]
+				}
+			}
+			out[i*newCols+j] = sum
+		}
+	}
+	return M(newRows, newCols, out...)
+}
+
+// Interpolate linearly along each row of m, whose columns hold samples at the
+// increasing positions xp, to find the values at positions x. Returns a
+// m.Rows() x len(x) dense matrix. Positions outside xp take the value at the
+// nearest end, as in numpy.interp.
+func Interp(m Matrix, xp, x []float64) Matrix {
+	debugCheck("Interp", m)
+	if m.Cols() != len(xp) {
+		panic(ErrShapeMismatch{Op: "Interp", Got: m.Shape(), Want: []int{-1, len(xp)}})
+	}
+	if len(xp) == 0 {
+		panic("Interp: can't interpolate between no samples")
+	}
+	for i := 1; i < len(xp); i++ {
+		if xp[i] <= xp[i-1] {
+			panic(fmt.Sprintf("Interp: sample positions must increase, but xp[%d] = %v follows %v", i, xp[i], xp[i-1]))
+		}
+	}
+
+	result := Dense(m.Rows(), len(x)).M()
+	for j, pos := range x {
+		// Find the samples on either side of pos
+		hi := sort.SearchFloat64s(xp, pos)
+		lo, frac := hi-1, 0.0
+		switch {
+		case hi == 0:
+			lo = 0
+		case hi == len(xp):
+			lo, hi = len(xp)-1, len(xp)-1
+		default:
+			frac = (pos - xp[lo]) / (xp[hi] - xp[lo])
+		}
+		for row := 0; row < m.Rows(); row++ {
+			v := m.Item(row, lo)
+			if frac != 0 {
+				v += frac * (m.Item(row, hi) - v)
+			}
+			result.ItemSet(v, row, j)
+		}
+	}
+	return result
+}
+
+// Get the index of the sample nearest to pos, among n samples
+func nearestWeights(pos float64, n int) ([]int, []float64) {
+	return []int{clampIndex(int(math.Floor(pos+0.5)), n)}, []float64{1}
+}
+
+// Get the indices and weights of the two samples around pos, among n samples
+func linearWeights(pos float64, n int) ([]int, []float64) {
+	lo := math.Floor(pos)
+	frac := pos - lo
+	return []int{clampIndex(int(lo), n), clampIndex(int(lo)+1, n)},
+		[]float64{1 - frac, frac}
+}
+
+// Get the indices and weights of the four samples around pos, among n
+// samples, using the Keys cubic convolution kernel with a = -0.5
+func cubicWeights(pos float64, n int) ([]int, []float64) {
+	lo := math.Floor(pos)
+	frac := pos - lo
+	idx := make([]int, 4)
+	w := make([]float64, 4)
+	for k := range idx {
+		idx[k] = clampIndex(int(lo)+k-1, n)
+		w[k] = cubicKernel(frac - float64(k-1))
+	}
+	return idx, w
+}
+
+// The Keys cubic convolution kernel with a = -0.5
+func cubicKernel(x float64) float64 {
+	const a = -0.5
+	x = math.Abs(x)
+	switch {
+	case x <= 1:
+		return (a+2)*x*x*x - (a+3)*x*x + 1
+	case x < 2:
+		return a*x*x*x - 5*a*x*x + 8*a*x - 4*a
+	default:
+		return 0
+	}
+}
+
+// Clamp an index into [0, n)
+func clampIndex(idx, n int) int {
+	return max(0, min(idx, n-1))
+}
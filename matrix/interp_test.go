@@ -0,0 +1,72 @@
+package matrix
+
+import (
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+func TestResize(t *testing.T) {
+	Convey("Given a small matrix", t, func() {
+		m := M(2, 2, 1, 2, 3, 4)
+
+		Convey("Resizing to the same shape changes nothing", func() {
+			for _, method := range []InterpMethod{InterpNearest, InterpBilinear, InterpBicubic} {
+				So(ApproxEqual(Resize(m, 2, 2, method), m, 1e-12, 0), ShouldBeTrue)
+			}
+		})
+
+		Convey("Nearest neighbor upsampling repeats elements", func() {
+			So(Resize(m, 4, 4, InterpNearest).Equal(M(4, 4,
+				1, 1, 2, 2,
+				1, 1, 2, 2,
+				3, 3, 4, 4,
+				3, 3, 4, 4)), ShouldBeTrue)
+			So(Resize(m, 2, 1, InterpNearest).Col(0), ShouldResemble, []float64{2, 4})
+		})
+
+		Convey("Bilinear upsampling interpolates between elements", func() {
+			So(Resize(M(1, 2, 0, 1), 1, 4, InterpBilinear).Row(0), ShouldResemble,
+				[]float64{0, 0.25, 0.75, 1})
+			So(Resize(m, 1, 1, InterpBilinear).Item(0, 0), ShouldEqual, 2.5)
+		})
+
+		Convey("Bicubic upsampling reproduces linear ramps", func() {
+			ramp := M(1, 4, 0, 1, 2, 3)
+			result := Resize(ramp, 1, 8, InterpBicubic)
+			So(result.Item(0, 3), ShouldAlmostEqual, 1.25, 1e-12)
+			So(result.Item(0, 4), ShouldAlmostEqual, 1.75, 1e-12)
+		})
+
+		Convey("Constant matrices stay constant", func() {
+			c := WithValue(3, 3, 5).M()
+			for _, method := range []InterpMethod{InterpNearest, InterpBilinear, InterpBicubic} {
+				So(ApproxEqual(Resize(c, 7, 2, method), WithValue(3, 7, 2).M(), 1e-12, 0), ShouldBeTrue)
+			}
+		})
+
+		Convey("Invalid arguments panic", func() {
+			So(func() { Resize(m, -1, 2, InterpNearest) }, ShouldPanic)
+			So(func() { Resize(m, 2, 2, InterpMethod(9)) }, ShouldPanic)
+			So(func() { Resize(Dense(0, 2).M(), 2, 2, InterpNearest) }, ShouldPanic)
+		})
+	})
+}
+
+func TestInterp(t *testing.T) {
+	Convey("Given rows sampled at increasing positions", t, func() {
+		m := M(2, 3, 0, 10, 20, 1, 1, 3)
+		xp := []float64{0, 1, 2}
+
+		Convey("Interp interpolates along each row", func() {
+			result := Interp(m, xp, []float64{-1, 0.5, 2, 5, 1.5})
+			So(result.Row(0), ShouldResemble, []float64{0, 5, 20, 20, 15})
+			So(result.Row(1), ShouldResemble, []float64{1, 1, 3, 3, 2})
+		})
+
+		Convey("Invalid samples panic", func() {
+			So(func() { Interp(m, []float64{0, 1}, nil) }, ShouldPanic)
+			So(func() { Interp(m, []float64{0, 2, 1}, nil) }, ShouldPanic)
+			So(func() { Interp(Dense(1, 0).M(), nil, nil) }, ShouldPanic)
+		})
+	})
+}
@@ -0,0 +1,113 @@
+//go:build go1.23
+
+package matrix
+
+import (
+	"fmt"
+	"iter"
+)
+
+// An element of a matrix, as yielded by Entries() and NonzeroEntries()
+type Entry struct {
+	Row, Col int
+	Value    float64
+}
+
+// Iterate over the rows of a matrix, for use with range:
+//
+//	for i, row := range Rows(m) { ... }
+//
+// Each row is a new slice, as returned by m.Row().
+func Rows(m Matrix) iter.Seq2[int, []float64] {
+	return func(yield func(int, []float64) bool) {
+		for i := 0; i < m.Rows(); i++ {
+			if !yield(i, m.Row(i)) {
+				return
+			}
+		}
+	}
+}
+
+// Iterate over the columns of a matrix, for use with range:
+//
+//	for j, col := range Cols(m) { ... }
+//
+// Each column is a new slice, as returned by m.Col().
+func Cols(m Matrix) iter.Seq2[int, []float64] {
+	return func(yield func(int, []float64) bool) {
+		for j := 0; j < m.Cols(); j++ {
+			if !yield(j, m.Col(j)) {
+				return
+			}
+		}
+	}
+}
+
+// Iterate over all elements of a matrix in row-major order, for use with
+// range:
+//
+//	for e := range Entries(m) { ... e.Row, e.Col, e.Value ... }
+func Entries(m Matrix) iter.Seq[Entry] {
+	return func(yield func(Entry) bool) {
+		for i := 0; i < m.Rows(); i++ {
+			for j := 0; j < m.Cols(); j++ {
+				if !yield(Entry{i, j, m.Item(i, j)}) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Iterate over the nonzero elements of a matrix, for use with range. Sparse
+// matrices only visit their stored elements, in no particular order.
+func NonzeroEntries(m Matrix) iter.Seq[Entry] {
+	return func(yield func(Entry) bool) {
+		m.VisitNonzero(func(pos []int, value float64) bool {
+			if value == 0 {
+				return true
+			}
+			return yield(Entry{pos[0], pos[1], value})
+		})
+	}
+}
+
+// Iterate over windows of size consecutive rows of m, starting every step
+// rows, for use with range:
+//
+//	for start, window := range WindowRows(m, 10, 5) { ... }
+//
+// Each window is a size x m.Cols() view of rows start to start+size-1 of m,
+// and no data is copied. Windows of a row-major dense matrix share its
+// storage, so changes made through them are visible in m; other windows are
+// read-only views created by RowSubset(). Trailing rows which don't fill a
+// window are skipped.
+func WindowRows(m Matrix, size, step int) iter.Seq2[int, Matrix] {
+	if size < 1 || step < 1 {
+		panic(fmt.Sprintf("Can't create windows of %d rows with step %d", size, step))
+	}
+	debugCheck("WindowRows", m)
+	return func(yield func(int, Matrix) bool) {
+		for start := 0; start+size <= m.Rows(); start += step {
+			if !yield(start, windowView(m, start, size)) {
+				return
+			}
+		}
+	}
+}
+
+// Get a view of size rows of m, starting at row start
+func windowView(m Matrix, start, size int) Matrix {
+	if d, ok := denseStorage(m); ok && !d.transpose {
+		cols := d.shape[1]
+		return &denseF64Array{
+			shape: []int{size, cols},
+			array: d.array[start*cols : (start+size)*cols : (start+size)*cols],
+		}
+	}
+	rows := make([]int, size)
+	for i := range rows {
+		rows[i] = start + i
+	}
+	return RowSubset(m, rows)
+}
@@ -0,0 +1,118 @@
+//go:build go1.23
+
+package matrix
+
+import (
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+func TestIterators(t *testing.T) {
+	Convey("Given a matrix", t, func() {
+		m := M(2, 3,
+			1, 0, 3,
+			0, 5, 0)
+
+		Convey("Rows yields each row", func() {
+			var rows [][]float64
+			for i, row := range Rows(m) {
+				So(row, ShouldResemble, m.Row(i))
+				rows = append(rows, row)
+			}
+			So(rows, ShouldResemble, [][]float64{{1, 0, 3}, {0, 5, 0}})
+		})
+
+		Convey("Cols yields each column, and stops early", func() {
+			var cols [][]float64
+			for j, col := range Cols(m.SparseCoo()) {
+				cols = append(cols, col)
+				if j == 1 {
+					break
+				}
+			}
+			So(cols, ShouldResemble, [][]float64{{1, 0}, {0, 5}})
+		})
+
+		Convey("Entries yields all elements in row-major order", func() {
+			var values []float64
+			for e := range Entries(m.T()) {
+				So(e.Value, ShouldEqual, m.Item(e.Col, e.Row))
+				values = append(values, e.Value)
+			}
+			So(values, ShouldResemble, []float64{1, 0, 0, 5, 3, 0})
+		})
+
+		Convey("NonzeroEntries yields only nonzero elements", func() {
+			for _, mat := range []Matrix{m, m.SparseCoo(), Diag(1, 0, 3)} {
+				count := 0
+				for e := range NonzeroEntries(mat) {
+					So(e.Value, ShouldNotEqual, 0)
+					So(mat.Item(e.Row, e.Col), ShouldEqual, e.Value)
+					count++
+				}
+				So(count, ShouldEqual, mat.CountNonzero())
+			}
+			count := 0
+			for range NonzeroEntries(m) {
+				count++
+				break
+			}
+			So(count, ShouldEqual, 1)
+		})
+	})
+}
+
+func TestWindowRows(t *testing.T) {
+	Convey("Given a matrix with five rows", t, func() {
+		m := M(5, 2,
+			1, 2,
+			3, 4,
+			5, 6,
+			7, 8,
+			9, 10)
+
+		Convey("WindowRows yields overlapping windows and skips partial ones", func() {
+			var starts []int
+			var windows [][]float64
+			for start, w := range WindowRows(m, 2, 2) {
+				So(w.Shape(), ShouldResemble, []int{2, 2})
+				starts = append(starts, start)
+				windows = append(windows, w.Array())
+			}
+			So(starts, ShouldResemble, []int{0, 2})
+			So(windows, ShouldResemble, [][]float64{{1, 2, 3, 4}, {5, 6, 7, 8}})
+
+			count := 0
+			for range WindowRows(m, 3, 1) {
+				count++
+			}
+			So(count, ShouldEqual, 3)
+		})
+
+		Convey("Windows of a dense matrix share its storage", func() {
+			for start, w := range WindowRows(m, 2, 3) {
+				w.ItemSet(0, 1, 1)
+				So(m.Item(start+1, 1), ShouldEqual, 0)
+			}
+			So(CheckInvariants(m), ShouldBeNil)
+		})
+
+		Convey("Windows of other matrices are read-only views", func() {
+			coo := m.SparseCoo()
+			for start, w := range WindowRows(coo, 2, 1) {
+				So(w.Row(1), ShouldResemble, coo.Row(start+1))
+				if start == 0 {
+					So(func() { w.ItemSet(1, 0, 0) }, ShouldPanic)
+				}
+			}
+			for _, w := range WindowRows(m.T().T(), 4, 4) {
+				So(w.Array(), ShouldResemble, m.Slice([]int{0, 0}, []int{4, 2}).Array())
+			}
+		})
+
+		Convey("Invalid sizes panic", func() {
+			So(func() { WindowRows(m, 0, 1) }, ShouldPanic)
+			So(func() { WindowRows(m, 1, 0) }, ShouldPanic)
+		})
+	})
+}
@@ -0,0 +1,48 @@
+// The jbsparseconv package converts between matrix types and the sparse
+// matrix types of the github.com/james-bowman/sparse package. It is kept out
+// of the matrix package so that programs which don't use james-bowman/sparse
+// don't depend on it.
+package jbsparseconv
+
+import (
+	"github.com/james-bowman/sparse"
+	"github.com/jesand/numgo/matrix"
+)
+
+// Convert a matrix to the compressed sparse row type of the
+// github.com/james-bowman/sparse package. Only nonzero values are stored, and
+// the column indices of each row are sorted.
+func ToSparseCSR(m matrix.Matrix) *sparse.CSR {
+	indptr, ind, data, _, _ := matrix.RawCompressed(m.SparseCSR())
+	return sparse.NewCSR(m.Rows(), m.Cols(), indptr, ind, data)
+}
+
+// Convert a matrix to the compressed sparse column type of the
+// github.com/james-bowman/sparse package. Only nonzero values are stored, and
+// the row indices of each column are sorted.
+func ToSparseCSC(m matrix.Matrix) *sparse.CSC {
+	indptr, ind, data, _, _ := matrix.RawCompressed(m.SparseCSC())
+	return sparse.NewCSC(m.Rows(), m.Cols(), indptr, ind, data)
+}
+
+// Convert a compressed sparse row matrix of the github.com/james-bowman/sparse
+// package to a sparse coo matrix.
+func FromSparseCSR(m *sparse.CSR) matrix.Matrix {
+	rows, cols := m.Dims()
+	result := matrix.SparseCoo(rows, cols)
+	m.DoNonZero(func(i, j int, v float64) {
+		result.ItemSet(v, i, j)
+	})
+	return result
+}
+
+// Convert a compressed sparse column matrix of the github.com/james-bowman/sparse
+// package to a sparse coo matrix.
+func FromSparseCSC(m *sparse.CSC) matrix.Matrix {
+	rows, cols := m.Dims()
+	result := matrix.SparseCoo(rows, cols)
+	m.DoNonZero(func(i, j int, v float64) {
+		result.ItemSet(v, i, j)
+	})
+	return result
+}
@@ -0,0 +1,53 @@
+package jbsparseconv
+
+import (
+	. "github.com/jesand/numgo/matrix"
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+func TestJamesBowmanSparse(t *testing.T) {
+	Convey("Given a sparse matrix", t, func() {
+		m := SparseCoo(3, 4,
+			0, 2, 0, 1,
+			0, 0, 0, 0,
+			3, 0, 4, 0)
+
+		Convey("ToSparseCSR preserves its values", func() {
+			csr := ToSparseCSR(m)
+			rows, cols := csr.Dims()
+			So(rows, ShouldEqual, 3)
+			So(cols, ShouldEqual, 4)
+			So(csr.NNZ(), ShouldEqual, 4)
+			for i := 0; i < 3; i++ {
+				for j := 0; j < 4; j++ {
+					So(csr.At(i, j), ShouldEqual, m.Item(i, j))
+				}
+			}
+			So(FromSparseCSR(csr).Equal(m), ShouldBeTrue)
+		})
+
+		Convey("ToSparseCSC preserves its values", func() {
+			csc := ToSparseCSC(m)
+			rows, cols := csc.Dims()
+			So(rows, ShouldEqual, 3)
+			So(cols, ShouldEqual, 4)
+			So(csc.NNZ(), ShouldEqual, 4)
+			for i := 0; i < 3; i++ {
+				for j := 0; j < 4; j++ {
+					So(csc.At(i, j), ShouldEqual, m.Item(i, j))
+				}
+			}
+			So(FromSparseCSC(csc).Equal(m), ShouldBeTrue)
+		})
+
+		Convey("Transposed and diagonal matrices are converted", func() {
+			So(FromSparseCSR(ToSparseCSR(m.T())).Equal(m.T()), ShouldBeTrue)
+			So(FromSparseCSC(ToSparseCSC(m.Dense().M().T())).Equal(m.T()), ShouldBeTrue)
+
+			d := Diag(1, 0, 3)
+			So(ToSparseCSR(d).NNZ(), ShouldEqual, 2)
+			So(FromSparseCSR(ToSparseCSR(d)).Equal(d), ShouldBeTrue)
+		})
+	})
+}
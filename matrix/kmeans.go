@@ -0,0 +1,182 @@
+package matrix
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+)
+
+// A KMeansOption configures KMeans()
+type KMeansOption func(*kmeansOptions)
+
+// The configuration built up by a list of options
+type kmeansOptions struct {
+	dist    DistType
+	maxIter int
+	tol     float64
+	rng     *rand.Rand
+}
+
+// Measure the distance from points to centroids with t; EuclideanDist by
+// default. Centroids are always the means of their points.
+func KMeansDist(t DistType) KMeansOption {
+	return func(o *kmeansOptions) { o.dist = t }
+}
+
+// Stop after at most n iterations; 300 by default
+func KMeansMaxIter(n int) KMeansOption {
+	return func(o *kmeansOptions) { o.maxIter = n }
+}
+
+// Stop once no centroid moves further than tol in an iteration; 1e-8 by
+// default. KMeans also stops when no point changes cluster.
+func KMeansTol(tol float64) KMeansOption {
+	return func(o *kmeansOptions) { o.tol = tol }
+}
+
+// Use src as the source of random numbers for choosing the initial centroids,
+// in place of the global source. Seed it to get reproducible clusters.
+func KMeansRand(src rand.Source) KMeansOption {
+	return func(o *kmeansOptions) { o.rng = rand.New(src) }
+}
+
+// Cluster the rows of m into k clusters with Lloyd's algorithm, starting from
+// centroids chosen by k-means++. Returns a k x m.Cols() matrix whose rows are
+// the cluster centroids, and the cluster of each row of m. A cluster which
+// loses all its points is restarted at the point furthest from its centroid.
+// Each label is the nearest of the returned centroids, even if the iteration
+// limit stopped the algorithm early.
+func KMeans(m Matrix, k int, opts ...KMeansOption) (centroids Matrix, labels []int) {
+	debugCheck("KMeans", m)
+	o := kmeansOptions{dist: EuclideanDist, maxIter: 300, tol: 1e-8}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if k < 1 || k > m.Rows() {
+		panic(fmt.Sprintf("KMeans: can't find %d clusters among %d rows", k, m.Rows()))
+	}
+	kernel := distKernelFor(o.dist)
+	points := make([][]float64, m.Rows())
+	prepared := make([][]float64, m.Rows())
+	for i := range points {
+		points[i] = m.Row(i)
+		prepared[i] = kernel.prepare(points[i])
+	}
+
+	centers := kmeansPlusPlus(points, prepared, k, kernel, o.rng)
+	labels = make([]int, len(points))
+	for i := range labels {
+		labels[i] = -1
+	}
+	dists := make([]float64, len(points))
+	// Assign each point to its nearest centroid, returning true if any
+	// label changed
+	assign := func() bool {
+		preparedCenters := make([][]float64, k)
+		for c := range centers {
+			preparedCenters[c] = kernel.prepare(centers[c])
+		}
+		changed := false
+		for i, p := range prepared {
+			best, bestDist := 0, math.Inf(1)
+			for c, pc := range preparedCenters {
+				if d := kernel.dist(p, pc); d < bestDist {
+					best, bestDist = c, d
+				}
+			}
+			if labels[i] != best {
+				labels[i] = best
+				changed = true
+			}
+			dists[i] = bestDist
+		}
+		return changed
+	}
+	assigned := false
+	for iter := 0; iter < o.maxIter; iter++ {
+		if !assign() {
+			assigned = true
+			break
+		}
+
+		// Move each centroid to the mean of its points
+		counts := make([]int, k)
+		sums := make([][]float64, k)
+		for c := range sums {
+			sums[c] = make([]float64, m.Cols())
+		}
+		for i, p := range points {
+			counts[labels[i]]++
+			for j, v := range p {
+				sums[labels[i]][j] += v
+			}
+		}
+		var shift float64
+		for c := range centers {
+			if counts[c] == 0 {
+				far := argMaxVec(dists)
+				sums[c], counts[c] = append([]float64(nil), points[far]...), 1
+				dists[far] = 0
+			}
+			var moved float64
+			for j := range sums[c] {
+				sums[c][j] /= float64(counts[c])
+				moved += math.Pow(sums[c][j]-centers[c][j], 2)
+			}
+			shift = math.Max(shift, math.Sqrt(moved))
+			centers[c] = sums[c]
+		}
+		if shift <= o.tol {
+			break
+		}
+	}
+	if !assigned {
+		// The centroids moved after the last assignment, so match the labels
+		// to the returned centroids
+		assign()
+	}
+
+	centroids = Dense(k, m.Cols()).M()
+	for c, center := range centers {
+		centroids.RowSet(c, center)
+	}
+	return centroids, labels
+}
+
+// Choose k initial centroids from points by k-means++: the first uniformly at
+// random, and each of the rest with probability proportional to the squared
+// distance from the point to the nearest centroid chosen so far
+func kmeansPlusPlus(points, prepared [][]float64, k int, kernel distKernel, rng *rand.Rand) [][]float64 {
+	float := rand.Float64
+	if rng != nil {
+		float = rng.Float64
+	}
+	centers := make([][]float64, 0, k)
+	nearest := make([]float64, len(points))
+	next := int(float() * float64(len(points)))
+	for len(centers) < k {
+		centers = append(centers, append([]float64(nil), points[next]...))
+		var total float64
+		for i, p := range prepared {
+			d := kernel.dist(p, prepared[next])
+			if len(centers) == 1 || d*d < nearest[i] {
+				nearest[i] = d * d
+			}
+			total += nearest[i]
+		}
+		if total == 0 {
+			// Every point coincides with a centroid, so take the next point
+			next = (next + 1) % len(points)
+			continue
+		}
+		target := float() * total
+		for i, w := range nearest {
+			target -= w
+			if target < 0 || i == len(nearest)-1 {
+				next = i
+				break
+			}
+		}
+	}
+	return centers
+}
@@ -0,0 +1,80 @@
+package matrix
+
+import (
+	. "github.com/smartystreets/goconvey/convey"
+	"math/rand"
+	"testing"
+)
+
+func TestKMeans(t *testing.T) {
+	Convey("Given three well separated groups of points", t, func() {
+		m := M(9, 2,
+			0, 0, 0.1, 0.2, -0.1, 0.1,
+			10, 10, 10.2, 9.9, 9.8, 10.1,
+			-10, 10, -10.1, 9.8, -9.9, 10.2)
+
+		Convey("KMeans finds the groups", func() {
+			centroids, labels := KMeans(m, 3, KMeansRand(rand.NewSource(1)))
+			So(centroids.Shape(), ShouldResemble, []int{3, 2})
+			for group := 0; group < 3; group++ {
+				So(labels[3*group+1], ShouldEqual, labels[3*group])
+				So(labels[3*group+2], ShouldEqual, labels[3*group])
+			}
+			So(labels[0], ShouldNotEqual, labels[3])
+			So(labels[3], ShouldNotEqual, labels[6])
+			So(labels[0], ShouldNotEqual, labels[6])
+			So(centroids.Item(labels[3], 0), ShouldAlmostEqual, 10, 1e-12)
+			So(centroids.Item(labels[3], 1), ShouldAlmostEqual, 10, 1e-12)
+		})
+
+		Convey("Seeded runs are reproducible", func() {
+			c1, l1 := KMeans(m, 2, KMeansRand(rand.NewSource(5)), KMeansMaxIter(10))
+			c2, l2 := KMeans(m, 2, KMeansRand(rand.NewSource(5)), KMeansMaxIter(10))
+			So(l1, ShouldResemble, l2)
+			So(c1.Equal(c2), ShouldBeTrue)
+		})
+
+		Convey("A single cluster is centered on the mean", func() {
+			centroids, labels := KMeans(m, 1)
+			So(labels, ShouldResemble, make([]int, 9))
+			So(centroids.Item(0, 0), ShouldAlmostEqual, 0, 1e-12)
+		})
+
+		Convey("Other distances can be used", func() {
+			// Rows pointing the same way are close in correlation distance
+			p := M(4, 3,
+				1, 2, 3,
+				2, 4, 6.5,
+				3, 2, 1,
+				6, 4, 2.5)
+			_, labels := KMeans(p, 2, KMeansDist(CorrelationDist), KMeansRand(rand.NewSource(2)))
+			So(labels[0], ShouldEqual, labels[1])
+			So(labels[2], ShouldEqual, labels[3])
+			So(labels[0], ShouldNotEqual, labels[2])
+		})
+
+		Convey("Duplicate points don't break initialization", func() {
+			_, labels := KMeans(M(3, 1, 1, 1, 1), 2, KMeansRand(rand.NewSource(3)))
+			So(len(labels), ShouldEqual, 3)
+		})
+
+		Convey("Labels match the returned centroids when the iterations run out", func() {
+			x := M(6, 2,
+				0, 1, 2, 2,
+				2, 3, 2, 1,
+				0, 2, 3, 2)
+			centroids, labels := KMeans(x, 3, KMeansRand(rand.NewSource(33)), KMeansMaxIter(1))
+			dists := x.DistTo(centroids, EuclideanDist)
+			for row, label := range labels {
+				for c := 0; c < 3; c++ {
+					So(dists.Item(row, label), ShouldBeLessThanOrEqualTo, dists.Item(row, c))
+				}
+			}
+		})
+
+		Convey("Invalid numbers of clusters panic", func() {
+			So(func() { KMeans(m, 0) }, ShouldPanic)
+			So(func() { KMeans(m, 10) }, ShouldPanic)
+		})
+	})
+}
@@ -0,0 +1,218 @@
+package matrix
+
+// A LabeledMatrix is a matrix with optional string labels for its rows and
+// columns, such as the feature names of a data matrix. The labels follow the
+// rows and columns through T(), Slice() and Concat(), and rows and columns can
+// be looked up by name. Other operations return unlabeled results.
+type LabeledMatrix struct {
+	Matrix
+	rowLabels, colLabels []string
+	rowIndex, colIndex   map[string]int
+}
+
+// Attach labels to the rows and columns of a matrix. Either slice of labels
+// may be nil to leave that axis unlabeled; otherwise its length must match
+// the matrix. The result shares storage with m.
+func WithLabels(m Matrix, rowLabels, colLabels []string) *LabeledMatrix {
+	if inner, ok := m.(*LabeledMatrix); ok {
+		m = inner.Matrix
+	}
+	if rowLabels != nil && len(rowLabels) != m.Rows() {
+		panic(ErrShapeMismatch{Op: "WithLabels", Got: []int{len(rowLabels)}, Want: []int{m.Rows()}})
+	}
+	if colLabels != nil && len(colLabels) != m.Cols() {
+		panic(ErrShapeMismatch{Op: "WithLabels", Got: []int{len(colLabels)}, Want: []int{m.Cols()}})
+	}
+	return &LabeledMatrix{
+		Matrix:    m,
+		rowLabels: rowLabels,
+		colLabels: colLabels,
+		rowIndex:  labelIndex(rowLabels),
+		colIndex:  labelIndex(colLabels),
+	}
+}
+
+// Get the row labels of a matrix, or nil if its rows aren't labeled
+func RowLabels(m Matrix) []string {
+	if l, ok := m.(*LabeledMatrix); ok {
+		return l.rowLabels
+	}
+	return nil
+}
+
+// Get the column labels of a matrix, or nil if its columns aren't labeled
+func ColLabels(m Matrix) []string {
+	if l, ok := m.(*LabeledMatrix); ok {
+		return l.colLabels
+	}
+	return nil
+}
+
+// Get the row labels, or nil if the rows aren't labeled
+func (l *LabeledMatrix) RowLabels() []string {
+	return l.rowLabels
+}
+
+// Get the column labels, or nil if the columns aren't labeled
+func (l *LabeledMatrix) ColLabels() []string {
+	return l.colLabels
+}
+
+// Get the position of the first row with the given label
+func (l *LabeledMatrix) RowIndex(label string) (int, bool) {
+	idx, ok := l.rowIndex[label]
+	return idx, ok
+}
+
+// Get the position of the first column with the given label
+func (l *LabeledMatrix) ColIndex(label string) (int, bool) {
+	idx, ok := l.colIndex[label]
+	return idx, ok
+}
+
+// Get the first row with the given label, as Row() does. Panics with
+// ErrUnknownLabel if there is no such row.
+func (l *LabeledMatrix) RowByName(label string) []float64 {
+	idx, ok := l.rowIndex[label]
+	if !ok {
+		panic(ErrUnknownLabel{Axis: "row", Label: label})
+	}
+	return l.Matrix.Row(idx)
+}
+
+// Get the first column with the given label, as Col() does. Panics with
+// ErrUnknownLabel if there is no such column.
+func (l *LabeledMatrix) ColByName(label string) []float64 {
+	idx, ok := l.colIndex[label]
+	if !ok {
+		panic(ErrUnknownLabel{Axis: "column", Label: label})
+	}
+	return l.Matrix.Col(idx)
+}
+
+// Get a labeled deep copy of the matrix
+func (l *LabeledMatrix) Clone() NDArray {
+	return WithLabels(l.Matrix.Clone().M(), l.rowLabels, l.colLabels)
+}
+
+// Get a labeled copy of the matrix
+func (l *LabeledMatrix) Copy() NDArray {
+	return l.Clone()
+}
+
+// Get the labeled matrix
+func (l *LabeledMatrix) M() Matrix {
+	return l
+}
+
+// Exchange two columns and their labels in place. Views of the matrix see
+// the new values, but keep their labels.
+func (l *LabeledMatrix) SwapCols(i, j int) {
+	l.Matrix.SwapCols(i, j)
+	l.colLabels, l.colIndex = swapLabels(l.colLabels, i, j)
+}
+
+// Exchange two rows and their labels in place. Views of the matrix see the
+// new values, but keep their labels.
+func (l *LabeledMatrix) SwapRows(i, j int) {
+	l.Matrix.SwapRows(i, j)
+	l.rowLabels, l.rowIndex = swapLabels(l.rowLabels, i, j)
+}
+
+// Get the transpose, with the row and column labels swapped
+func (l *LabeledMatrix) T() Matrix {
+	return WithLabels(l.Matrix.T(), l.colLabels, l.rowLabels)
+}
+
+// Get a copy of a region of the matrix, keeping the labels of the rows and
+// columns in the region
+func (l *LabeledMatrix) Slice(from []int, to []int) NDArray {
+	result := l.Matrix.Slice(from, to).M()
+	return WithLabels(result,
+		sliceLabels(l.rowLabels, from[0], to[0]),
+		sliceLabels(l.colLabels, from[1], to[1]))
+}
+
+// Concatenate the matrix with others, as Concat() does. Concatenating rows
+// (axis 0) joins the row labels and keeps the column labels of this matrix,
+// and concatenating columns (axis 1) does the reverse. Unlabeled matrices
+// contribute empty labels. Adding a new axis gives an unlabeled array.
+func (l *LabeledMatrix) Concat(axis int, others ...NDArray) NDArray {
+	result := Concat(axis, l.Matrix, others...)
+	if axis > 1 {
+		return result
+	}
+	labels := func(m NDArray) []string {
+		if other, ok := m.(*LabeledMatrix); ok {
+			if axis == 0 {
+				return other.rowLabels
+			}
+			return other.colLabels
+		}
+		return nil
+	}
+	joined := joinLabels(l.Matrix.Shape()[axis], labels(l))
+	for _, o := range others {
+		joined = append(joined, joinLabels(o.Shape()[axis], labels(o))...)
+	}
+	if axis == 0 {
+		return WithLabels(result.M(), emptyLabels(joined), l.colLabels)
+	}
+	return WithLabels(result.M(), l.rowLabels, emptyLabels(joined))
+}
+
+// Get a labeled view of the matrix, which shares its storage
+func (l *LabeledMatrix) View() NDArray {
+	return WithLabels(l.Matrix.View().M(), l.rowLabels, l.colLabels)
+}
+
+// Map each label to the position where it first appears
+func labelIndex(labels []string) map[string]int {
+	index := make(map[string]int, len(labels))
+	for i := len(labels) - 1; i >= 0; i-- {
+		index[labels[i]] = i
+	}
+	return index
+}
+
+// Get the labels in [from, to), using the index conventions of Slice()
+func sliceLabels(labels []string, from, to int) []string {
+	if labels == nil {
+		return nil
+	}
+	if from < 0 {
+		from += len(labels) + 1
+	}
+	if to < 0 {
+		to += len(labels) + 1
+	}
+	return append([]string(nil), labels[from:to]...)
+}
+
+// Get a copy of labels for an axis of the given size, or empty labels for an
+// unlabeled axis
+func joinLabels(size int, labels []string) []string {
+	if labels == nil {
+		return make([]string, size)
+	}
+	return append([]string(nil), labels...)
+}
+
+// Get nil if all labels are empty, or the labels otherwise
+func emptyLabels(labels []string) []string {
+	for _, label := range labels {
+		if label != "" {
+			return labels
+		}
+	}
+	return nil
+}
+
+// Get a copy of labels with labels i and j exchanged, and its index
+func swapLabels(labels []string, i, j int) ([]string, map[string]int) {
+	if labels != nil {
+		labels = append([]string(nil), labels...)
+		labels[i], labels[j] = labels[j], labels[i]
+	}
+	return labels, labelIndex(labels)
+}
@@ -0,0 +1,84 @@
+package matrix
+
+import (
+	"errors"
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+func TestLabels(t *testing.T) {
+	Convey("Given a labeled matrix", t, func() {
+		m := WithLabels(M(2, 3,
+			1, 2, 3,
+			4, 5, 6), []string{"a", "b"}, []string{"x", "y", "z"})
+
+		Convey("Rows and columns can be found by name", func() {
+			So(m.RowByName("b"), ShouldResemble, []float64{4, 5, 6})
+			So(m.ColByName("y"), ShouldResemble, []float64{2, 5})
+			idx, ok := m.ColIndex("z")
+			So(ok, ShouldBeTrue)
+			So(idx, ShouldEqual, 2)
+			_, ok = m.RowIndex("z")
+			So(ok, ShouldBeFalse)
+
+			var labelErr ErrUnknownLabel
+			So(errors.As(try(func() { m.ColByName("w") }), &labelErr), ShouldBeTrue)
+			So(labelErr, ShouldResemble, ErrUnknownLabel{Axis: "column", Label: "w"})
+		})
+
+		Convey("It behaves as the underlying matrix", func() {
+			So(m.Equal(M(2, 3, 1, 2, 3, 4, 5, 6)), ShouldBeTrue)
+			So(m.MProd(Eye(3)).Equal(m), ShouldBeTrue)
+			So(m.M(), ShouldEqual, m)
+		})
+
+		Convey("Labels survive transposition", func() {
+			tr := m.T()
+			So(RowLabels(tr), ShouldResemble, []string{"x", "y", "z"})
+			So(ColLabels(tr), ShouldResemble, []string{"a", "b"})
+			So(tr.(*LabeledMatrix).RowByName("z"), ShouldResemble, []float64{3, 6})
+		})
+
+		Convey("Labels survive slicing", func() {
+			s := m.Slice([]int{1, 1}, []int{2, -1}).M()
+			So(s.Equal(M(1, 2, 5, 6)), ShouldBeTrue)
+			So(RowLabels(s), ShouldResemble, []string{"b"})
+			So(ColLabels(s), ShouldResemble, []string{"y", "z"})
+		})
+
+		Convey("Labels survive concatenation", func() {
+			rows := m.Concat(0, WithLabels(M(1, 3, 7, 8, 9), []string{"c"}, nil), M(1, 3, 0, 0, 0)).M()
+			So(rows.Shape(), ShouldResemble, []int{4, 3})
+			So(RowLabels(rows), ShouldResemble, []string{"a", "b", "c", ""})
+			So(ColLabels(rows), ShouldResemble, []string{"x", "y", "z"})
+
+			cols := m.Concat(1, M(2, 1, 0, 0)).M()
+			So(RowLabels(cols), ShouldResemble, []string{"a", "b"})
+			So(ColLabels(cols), ShouldResemble, []string{"x", "y", "z", ""})
+		})
+
+		Convey("Copies keep labels but not storage", func() {
+			c := m.Copy().M()
+			c.ItemSet(10, 0, 0)
+			So(m.Item(0, 0), ShouldEqual, 1)
+			So(ColLabels(c), ShouldResemble, []string{"x", "y", "z"})
+		})
+	})
+
+	Convey("Given an unlabeled matrix", t, func() {
+		m := M(2, 2, 1, 2, 3, 4)
+		So(RowLabels(m), ShouldBeNil)
+		So(ColLabels(m), ShouldBeNil)
+
+		Convey("Labels can be added to one axis", func() {
+			l := WithLabels(m, nil, []string{"p", "q"})
+			So(l.RowLabels(), ShouldBeNil)
+			So(l.ColByName("q"), ShouldResemble, []float64{2, 4})
+			So(RowLabels(l.Concat(0, m).M()), ShouldBeNil)
+		})
+
+		Convey("Labels must match the matrix size", func() {
+			So(func() { WithLabels(m, []string{"a"}, nil) }, ShouldPanic)
+		})
+	})
+}
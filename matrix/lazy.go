@@ -0,0 +1,384 @@
+package matrix
+
+import (
+	"database/sql/driver"
+	"sync"
+)
+
+// A lazyMatrix is the basis of read-only matrix types, such as Permutation,
+// whose elements are implied by a compact representation. Such a type embeds
+// a lazyMatrix and implements the methods it can answer from its
+// representation, along with M(), T() and View(). The remaining methods act
+// on a frozen copy of the matrix, created by build when first needed, so
+// methods which would modify the matrix panic with an error wrapping
+// ErrFrozen. Types whose elements can change, such as views of other
+// matrices, set volatile so that a new copy is built for each such method.
+type lazyMatrix struct {
+	build    func() Matrix
+	volatile bool
+	once     sync.Once
+	m        Matrix
+}
+
+// Get the frozen copy of the matrix, building it if necessary
+func (l *lazyMatrix) matrix() Matrix {
+	if l.volatile {
+		return Freeze(l.build())
+	}
+	l.once.Do(func() {
+		l.m = Freeze(l.build())
+	})
+	return l.m
+}
+
+// Return the element-wise sum of this array and one or more others
+func (l *lazyMatrix) Add(others ...NDArray) NDArray {
+	return l.matrix().Add(others...)
+}
+
+// Returns true if and only if all items are nonzero
+func (l *lazyMatrix) All() bool {
+	return l.matrix().All()
+}
+
+// Returns true if f is true for all array elements
+func (l *lazyMatrix) AllF(f func(v float64) bool) bool {
+	return l.matrix().AllF(f)
+}
+
+// Returns true if f is true for all pairs of array elements in the same position
+func (l *lazyMatrix) AllF2(f func(v1, v2 float64) bool, other NDArray) bool {
+	return l.matrix().AllF2(f, other)
+}
+
+// Returns true if all elements are finite
+func (l *lazyMatrix) AllFinite() bool {
+	return l.matrix().AllFinite()
+}
+
+// Returns true if and only if any item is nonzero
+func (l *lazyMatrix) Any() bool {
+	return l.matrix().Any()
+}
+
+// Returns true if f is true for any array element
+func (l *lazyMatrix) AnyF(f func(v float64) bool) bool {
+	return l.matrix().AnyF(f)
+}
+
+// Returns true if f is true for any pair of array elements in the same position
+func (l *lazyMatrix) AnyF2(f func(v1, v2 float64) bool, other NDArray) bool {
+	return l.matrix().AnyF2(f, other)
+}
+
+// Returns true if any element is NaN
+func (l *lazyMatrix) AnyNaN() bool {
+	return l.matrix().AnyNaN()
+}
+
+// Return the result of applying a function to all elements
+func (l *lazyMatrix) Apply(f func(float64) float64) NDArray {
+	return l.matrix().Apply(f)
+}
+
+// Get a copy of the array's values, flattened in row-major order
+func (l *lazyMatrix) Array() []float64 {
+	return l.matrix().Array()
+}
+
+// Get a copy of a column
+func (l *lazyMatrix) Col(col int) []float64 {
+	return l.matrix().Col(col)
+}
+
+// Lazy matrices are read-only
+func (l *lazyMatrix) ColSet(col int, values []float64) {
+	l.matrix().ColSet(col, values)
+}
+
+// Get the number of columns
+func (l *lazyMatrix) Cols() int {
+	return l.matrix().Cols()
+}
+
+// Get a copy of the matrix, which can be modified
+func (l *lazyMatrix) Clone() NDArray {
+	return l.matrix().Clone()
+}
+
+// Create a new array by concatenating this with another array along the
+// specified axis
+func (l *lazyMatrix) Concat(axis int, others ...NDArray) NDArray {
+	return l.matrix().Concat(axis, others...)
+}
+
+// Get the condition number for the matrix norm of the specified ordinality
+func (l *lazyMatrix) Cond(ord float64) float64 {
+	return l.matrix().Cond(ord)
+}
+
+// Get a copy of the matrix, which can be modified
+func (l *lazyMatrix) Copy() NDArray {
+	return l.matrix().Copy()
+}
+
+// Counts the nonzero elements in the array
+func (l *lazyMatrix) CountNonzero() int {
+	return l.matrix().CountNonzero()
+}
+
+// Returns a dense copy of the array
+func (l *lazyMatrix) Dense() NDArray {
+	return l.matrix().Dense()
+}
+
+// Get the determinant of a square matrix
+func (l *lazyMatrix) Det() float64 {
+	return l.matrix().Det()
+}
+
+// Get a column vector containing the main diagonal elements of the matrix
+func (l *lazyMatrix) Diag() Matrix {
+	return l.matrix().Diag()
+}
+
+// Get the pairwise distance between the rows
+func (l *lazyMatrix) Dist(t DistType) Matrix {
+	return l.matrix().Dist(t)
+}
+
+// Get the distance between the rows of this and other
+func (l *lazyMatrix) DistTo(other Matrix, t DistType) Matrix {
+	return l.matrix().DistTo(other, t)
+}
+
+// Return the element-wise quotient of this array and one or more others
+func (l *lazyMatrix) Div(others ...NDArray) NDArray {
+	return l.matrix().Div(others...)
+}
+
+// Returns true if and only if all elements in the two arrays are equal
+func (l *lazyMatrix) Equal(other NDArray) bool {
+	return l.matrix().Equal(other)
+}
+
+// Lazy matrices are read-only
+func (l *lazyMatrix) Fill(value float64) {
+	l.matrix().Fill(value)
+}
+
+// Get the coordinates for the item at the specified flat position
+func (l *lazyMatrix) FlatCoord(index int) []int {
+	return l.matrix().FlatCoord(index)
+}
+
+// Get an array element in a flattened version of this array
+func (l *lazyMatrix) FlatItem(index int) float64 {
+	return l.matrix().FlatItem(index)
+}
+
+// Lazy matrices are read-only
+func (l *lazyMatrix) FlatItemSet(value float64, index int) {
+	l.matrix().FlatItemSet(value, index)
+}
+
+// Get the matrix inverse
+func (l *lazyMatrix) Inverse() (Matrix, error) {
+	return l.matrix().Inverse()
+}
+
+// Get an array element
+func (l *lazyMatrix) Item(index ...int) float64 {
+	return l.matrix().Item(index...)
+}
+
+// Add a scalar value to each array element
+func (l *lazyMatrix) ItemAdd(value float64) NDArray {
+	return l.matrix().ItemAdd(value)
+}
+
+// Divide each array element by a scalar value
+func (l *lazyMatrix) ItemDiv(value float64) NDArray {
+	return l.matrix().ItemDiv(value)
+}
+
+// Multiply each array element by a scalar value
+func (l *lazyMatrix) ItemProd(value float64) NDArray {
+	return l.matrix().ItemProd(value)
+}
+
+// Subtract a scalar value from each array element
+func (l *lazyMatrix) ItemSub(value float64) NDArray {
+	return l.matrix().ItemSub(value)
+}
+
+// Lazy matrices are read-only
+func (l *lazyMatrix) ItemSet(value float64, index ...int) {
+	l.matrix().ItemSet(value, index...)
+}
+
+// Solve for x, where ax = b and a is this matrix
+func (l *lazyMatrix) LDivide(b Matrix) Matrix {
+	return l.matrix().LDivide(b)
+}
+
+// Get the value of the largest array element
+func (l *lazyMatrix) Max() float64 {
+	return l.matrix().Max()
+}
+
+// Get the value of the smallest array element
+func (l *lazyMatrix) Min() float64 {
+	return l.matrix().Min()
+}
+
+// Get the result of matrix multiplication between this and other matrices
+func (l *lazyMatrix) MProd(others ...Matrix) Matrix {
+	return l.matrix().MProd(others...)
+}
+
+// Get the number of array dimensions
+func (l *lazyMatrix) NDim() int {
+	return l.matrix().NDim()
+}
+
+// Get the matrix norm of the specified ordinality
+func (l *lazyMatrix) Norm(ord float64) float64 {
+	return l.matrix().Norm(ord)
+}
+
+// Return a copy of the array, normalized to sum to 1
+func (l *lazyMatrix) Normalize() NDArray {
+	return l.matrix().Normalize()
+}
+
+// Get the Moore-Penrose pseudo-inverse, treating singular values no larger
+// than tol as zero
+func (l *lazyMatrix) Pinv(tol float64) (Matrix, error) {
+	return l.matrix().Pinv(tol)
+}
+
+// Return the element-wise product of this array and one or more others
+func (l *lazyMatrix) Prod(others ...NDArray) NDArray {
+	return l.matrix().Prod(others...)
+}
+
+// Get the number of singular values larger than tol
+func (l *lazyMatrix) Rank(tol float64) int {
+	return l.matrix().Rank(tol)
+}
+
+// Return a 1D copy of the array
+func (l *lazyMatrix) Ravel() NDArray {
+	return l.matrix().Ravel()
+}
+
+// Get a copy of a row
+func (l *lazyMatrix) Row(row int) []float64 {
+	return l.matrix().Row(row)
+}
+
+// Lazy matrices are read-only
+func (l *lazyMatrix) RowSet(row int, values []float64) {
+	l.matrix().RowSet(row, values)
+}
+
+// Get the number of rows
+func (l *lazyMatrix) Rows() int {
+	return l.matrix().Rows()
+}
+
+// Lazy matrices are read-only
+func (l *lazyMatrix) Scan(src interface{}) error {
+	return l.matrix().Scan(src)
+}
+
+// Lazy matrices are read-only
+func (l *lazyMatrix) SetSubmatrix(row, col int, src Matrix) {
+	l.matrix().SetSubmatrix(row, col, src)
+}
+
+// Get the array dimensions
+func (l *lazyMatrix) Shape() []int {
+	return l.matrix().Shape()
+}
+
+// Get the number of elements
+func (l *lazyMatrix) Size() int {
+	return l.matrix().Size()
+}
+
+// Return a slice of the array
+func (l *lazyMatrix) Slice(from []int, to []int) NDArray {
+	return l.matrix().Slice(from, to)
+}
+
+// Return a sparse coo copy of the matrix
+func (l *lazyMatrix) SparseCoo() Matrix {
+	return l.matrix().SparseCoo()
+}
+
+// Return a compressed sparse column copy of the matrix
+func (l *lazyMatrix) SparseCSC() Matrix {
+	return l.matrix().SparseCSC()
+}
+
+// Return a compressed sparse row copy of the matrix
+func (l *lazyMatrix) SparseCSR() Matrix {
+	return l.matrix().SparseCSR()
+}
+
+// Return a sparse diag copy of the matrix
+func (l *lazyMatrix) SparseDiag() Matrix {
+	return l.matrix().SparseDiag()
+}
+
+// Get the storage format of the copy
+func (l *lazyMatrix) Sparsity() ArraySparsity {
+	return l.matrix().Sparsity()
+}
+
+// Return the element-wise difference of this array and one or more others
+func (l *lazyMatrix) Sub(others ...NDArray) NDArray {
+	return l.matrix().Sub(others...)
+}
+
+// Get the sum of all array elements
+func (l *lazyMatrix) Sum() float64 {
+	return l.matrix().Sum()
+}
+
+// Lazy matrices are read-only
+func (l *lazyMatrix) SwapCols(i, j int) {
+	l.matrix().SwapCols(i, j)
+}
+
+// Lazy matrices are read-only
+func (l *lazyMatrix) SwapRows(i, j int) {
+	l.matrix().SwapRows(i, j)
+}
+
+// Get the sum of the elements on the main diagonal
+func (l *lazyMatrix) Trace() float64 {
+	return l.matrix().Trace()
+}
+
+// Get the sum of the elements on a diagonal offset from the main diagonal
+func (l *lazyMatrix) TraceOffset(offset int) float64 {
+	return l.matrix().TraceOffset(offset)
+}
+
+// Get the database value of the array
+func (l *lazyMatrix) Value() (driver.Value, error) {
+	return l.matrix().Value()
+}
+
+// Visit all matrix elements
+func (l *lazyMatrix) Visit(f func(pos []int, value float64) bool) bool {
+	return l.matrix().Visit(f)
+}
+
+// Visit just the nonzero elements
+func (l *lazyMatrix) VisitNonzero(f func(pos []int, value float64) bool) bool {
+	return l.matrix().VisitNonzero(f)
+}
@@ -2,9 +2,10 @@ package matrix
 
 import (
 	"fmt"
-	"github.com/gonum/matrix/mat64"
+	"gonum.org/v1/gonum/blas/blas64"
+	"gonum.org/v1/gonum/mat"
 	"math"
-	"math/rand"
+	"sort"
 )
 
 // Distance calculations we support
@@ -12,6 +13,24 @@ type DistType int
 
 const (
 	EuclideanDist DistType = iota
+
+	// One minus the Pearson correlation between the rows
+	CorrelationDist
+
+	// The sum of the absolute differences, or L1 distance
+	ManhattanDist
+
+	// The largest absolute difference, or L-infinity distance
+	ChebyshevDist
+
+	// One minus the cosine of the angle between the rows
+	CosineDist
+
+	// The fraction of elements which differ
+	HammingDist
+
+	// The sum of the squared differences
+	SqEuclideanDist
 )
 
 // A two dimensional array with some special functionality
@@ -21,12 +40,21 @@ type Matrix interface {
 	// Set the values of the items on a given column
 	ColSet(col int, values []float64)
 
-	// Get a particular column for read-only access. May or may not be a copy.
+	// Get a copy of a particular column. Changes to the slice don't affect the
+	// matrix.
 	Col(col int) []float64
 
 	// Get the number of columns
 	Cols() int
 
+	// Get the condition number for the matrix norm of the specified
+	// ordinality (1, 2, infinity, ...), or FrobeniusNorm, MaxAbsNorm or
+	// NuclearNorm
+	Cond(ord float64) float64
+
+	// Get the determinant of a square matrix
+	Det() float64
+
 	// Get a column vector containing the main diagonal elements of the matrix
 	Diag() Matrix
 
@@ -35,6 +63,11 @@ type Matrix interface {
 	// rows i and j.
 	Dist(t DistType) Matrix
 
+	// Treat the rows of this and other as points, and get the distance
+	// between each pair. Returns an m x n distance matrix D such that D_i,j
+	// is the distance between row i of this and row j of other.
+	DistTo(other Matrix, t DistType) Matrix
+
 	// Get the matrix inverse
 	Inverse() (Matrix, error)
 
@@ -50,19 +83,49 @@ type Matrix interface {
 	// Get the matrix norm of the specified ordinality (1, 2, infinity, ...)
 	Norm(ord float64) float64
 
+	// Get the Moore-Penrose pseudo-inverse, treating singular values no
+	// larger than tol as zero
+	Pinv(tol float64) (Matrix, error)
+
+	// Get the rank: the number of singular values larger than tol, or than a
+	// default tolerance if tol is zero
+	Rank(tol float64) int
+
 	// Set the values of the items on a given row
 	RowSet(row int, values []float64)
 
-	// Get a particular column for read-only access. May or may not be a copy.
+	// Get a copy of a particular row. Changes to the slice don't affect the
+	// matrix.
 	Row(row int) []float64
 
 	// Get the number of rows
 	Rows() int
 
-	// Return the same matrix, but with axes transposed. The same data is used,
-	// for speed and memory efficiency. Use Copy() to create a new array.
+	// Copy src into this matrix, with the top-left element of src at (row,
+	// col). Elements of this matrix outside the block are unchanged. Panics if
+	// src doesn't fit, or if a sparse diagonal matrix would get a nonzero
+	// value off its diagonal. src shouldn't share storage with this matrix.
+	SetSubmatrix(row, col int, src Matrix)
+
+	// Exchange the values of two columns in place
+	SwapCols(i, j int)
+
+	// Exchange the values of two rows in place
+	SwapRows(i, j int)
+
+	// Return the same matrix, but with axes transposed. The result is a view
+	// which shares storage with this matrix, for speed and memory efficiency.
+	// Use Clone() to create a new array.
 	T() Matrix
 
+	// Get the sum of the elements on the main diagonal
+	Trace() float64
+
+	// Get the sum of the elements on a diagonal offset from the main diagonal.
+	// Positive offsets select superdiagonals and negative offsets select
+	// subdiagonals; an offset of zero is the same as Trace().
+	TraceOffset(offset int) float64
+
 	// Return a sparse coo copy of the matrix. The method will panic
 	// if any off-diagonal elements are nonzero.
 	SparseCoo() Matrix
@@ -70,6 +133,12 @@ type Matrix interface {
 	// Return a sparse diag copy of the matrix. The method will panic
 	// if any off-diagonal elements are nonzero.
 	SparseDiag() Matrix
+
+	// Return a compressed sparse row copy of the matrix
+	SparseCSR() Matrix
+
+	// Return a compressed sparse column copy of the matrix
+	SparseCSC() Matrix
 }
 
 // Create a square matrix with the specified elements on the main diagonal, and
@@ -126,13 +195,13 @@ func SparseCoo(rows, cols int, array ...float64) Matrix {
 // all off-diagonal values are zero. The matrix is initialized from diag, or
 // to all zeros.
 func SparseDiag(rows, cols int, diag ...float64) Matrix {
-	if len(diag) > rows || len(diag) > cols {
-		panic(fmt.Sprintf("Can't use %d diag elements in a %dx%d matrix", len(diag), rows, cols))
-	}
 	size := rows
 	if cols < rows {
 		size = cols
 	}
+	if len(diag) > size {
+		panic(ErrShapeMismatch{Op: "SparseDiag", Got: []int{len(diag)}, Want: []int{size}})
+	}
 	array := &sparseDiagF64Matrix{
 		shape: []int{rows, cols},
 		diag:  make([]float64, size),
@@ -143,6 +212,53 @@ func SparseDiag(rows, cols int, diag ...float64) Matrix {
 	return array
 }
 
+// Create a sparse matrix of the specified dimensionality, stored in
+// compressed sparse row (CSR) format: the nonzero values are stored row by
+// row, so getting a row or multiplying by a vector is fast. The first
+// len(array) elements of the matrix will be initialized to the corresponding
+// nonzero values of array.
+func SparseCSR(rows, cols int, array ...float64) Matrix {
+	return SparseCoo(rows, cols, array...).SparseCSR()
+}
+
+// Create a sparse matrix of the specified dimensionality, stored in
+// compressed sparse column (CSC) format: the nonzero values are stored column
+// by column, so getting a column is fast. The first len(array) elements of
+// the matrix will be initialized to the corresponding nonzero values of
+// array.
+func SparseCSC(rows, cols int, array ...float64) Matrix {
+	return SparseCoo(rows, cols, array...).SparseCSC()
+}
+
+// Create a CSR matrix using existing storage, without copying it. The values
+// of row i are data[indptr[i]:indptr[i+1]], in the columns given by the same
+// range of ind, which must be increasing. indptr has rows+1 entries, starting
+// at 0, and data may not contain zeros. Panics if the storage is invalid.
+func WrapCSR(rows, cols int, indptr, ind []int, data []float64) Matrix {
+	return wrapCompressed("WrapCSR", rows, cols, indptr, ind, data, false)
+}
+
+// Create a CSC matrix using existing storage, without copying it. The values
+// of column j are data[indptr[j]:indptr[j+1]], in the rows given by the same
+// range of ind, which must be increasing. indptr has cols+1 entries, starting
+// at 0, and data may not contain zeros. Panics if the storage is invalid.
+func WrapCSC(rows, cols int, indptr, ind []int, data []float64) Matrix {
+	return wrapCompressed("WrapCSC", rows, cols, indptr, ind, data, true)
+}
+
+// Get the storage of a CSR or CSC matrix without copying, in the layout used
+// by WrapCSR() and WrapCSC(). byCol is set for CSC matrices, whose values are
+// grouped by column. The storage mustn't be modified. Returns false if m is
+// not a compressed sparse matrix.
+func RawCompressed(m Matrix) (indptr, ind []int, data []float64, byCol, ok bool) {
+	array, ok := m.(*sparseCompressedF64Matrix)
+	if !ok {
+		return nil, nil, nil, false, false
+	}
+	s := array.store
+	return s.indptr, s.ind, s.data, array.byCol, true
+}
+
 // Create a sparse coo matrix, randomly populated so that approximately
 // density * rows * cols cells are filled with random values uniformly
 // distributed in [0,1). Note that if density is close to 1, this function may
@@ -151,20 +267,7 @@ func SparseRand(rows, cols int, density float64) Matrix {
 	if density < 0 || density >= 1 {
 		panic(fmt.Sprintf("Can't create a SparseRand matrix: density %f should be in [0, 1)", density))
 	}
-	matrix := SparseCoo(rows, cols)
-	shape := []int{rows, cols}
-	size := rows * cols
-	count := int(float64(size) * density)
-	for i := 0; i < count; i++ {
-		for {
-			coord := flatToNd(shape, rand.Intn(size))
-			if matrix.Item(coord...) == 0 {
-				matrix.ItemSet(rand.Float64(), coord...)
-				break
-			}
-		}
-	}
-	return matrix
+	return NewMatrix(rows, cols, WithStorage(SparseCooMatrix), WithRandom(Uniform, density))
 }
 
 // Create a sparse coo matrix, randomly populated so that approximately
@@ -176,30 +279,120 @@ func SparseRandN(rows, cols int, density float64) Matrix {
 	if density < 0 || density >= 1 {
 		panic(fmt.Sprintf("Can't create a SparseRandN matrix: density %f should be in [0, 1)", density))
 	}
-	matrix := SparseCoo(rows, cols)
-	shape := []int{rows, cols}
-	size := rows * cols
-	count := int(float64(size) * density)
-	for i := 0; i < count; i++ {
-		for {
-			coord := flatToNd(shape, rand.Intn(size))
-			if matrix.Item(coord...) == 0 {
-				matrix.ItemSet(rand.NormFloat64(), coord...)
-				break
-			}
+	return NewMatrix(rows, cols, WithStorage(SparseCooMatrix), WithRandom(Normal, density))
+}
+
+// A view of one of our matrices which implements gonum's mat.Matrix interface.
+// Our Matrix types can't implement mat.Matrix themselves, because their T()
+// method returns our Matrix type rather than mat.Matrix. The matrix is not
+// embedded, since gonum would then find our methods (such as Norm) by type
+// assertion and call them in place of its own algorithms.
+type gonumView struct {
+	m Matrix
+}
+
+// Get the number of rows and columns
+func (v gonumView) Dims() (r, c int) {
+	return v.m.Rows(), v.m.Cols()
+}
+
+// Get the value of a matrix element
+func (v gonumView) At(i, j int) float64 {
+	return v.m.Item(i, j)
+}
+
+// Set the value of a matrix element, as required by mat.Mutable
+func (v gonumView) Set(i, j int, value float64) {
+	v.m.ItemSet(value, i, j)
+}
+
+// Get the transpose of the matrix, as required by mat.Matrix
+func (v gonumView) T() mat.Matrix {
+	return mat.Transpose{Matrix: v}
+}
+
+// A view of a non-transposed dense matrix which exposes its backing slice to
+// gonum through the mat.RawMatrixer interface, so gonum routines can operate
+// on our storage directly.
+type denseGonumView struct {
+	array *denseF64Array
+}
+
+// Get the number of rows and columns
+func (v denseGonumView) Dims() (r, c int) {
+	return v.array.shape[0], v.array.shape[1]
+}
+
+// Get the value of a matrix element
+func (v denseGonumView) At(i, j int) float64 {
+	return v.array.array[i*v.array.shape[1]+j]
+}
+
+// Set the value of a matrix element, as required by mat.Mutable
+func (v denseGonumView) Set(i, j int, value float64) {
+	v.array.array[i*v.array.shape[1]+j] = value
+}
+
+// Get the transpose of the matrix, as required by mat.Matrix
+func (v denseGonumView) T() mat.Matrix {
+	return mat.Transpose{Matrix: v}
+}
+
+// Get the backing storage of the matrix, as required by mat.RawMatrixer
+func (v denseGonumView) RawMatrix() blas64.General {
+	return blas64.General{
+		Rows:   v.array.shape[0],
+		Cols:   v.array.shape[1],
+		Stride: v.array.shape[1],
+		Data:   v.array.array,
+	}
+}
+
+// Get a view of our matrix which implements gonum's mat.Matrix and
+// mat.Mutable interfaces, so it can be passed to gonum functions without
+// copying. The view shares storage with m, so changes made through either one
+// are visible in the other. Views of dense matrices also implement
+// mat.RawMatrixer, so gonum's optimized routines use our storage in place.
+func AsMat(m Matrix) mat.Matrix {
+	if dense, ok := denseStorage(m); ok {
+		view := denseGonumView{&denseF64Array{
+			shape: []int{dense.shape[0], dense.shape[1]},
+			array: dense.array,
+		}}
+		if dense.transpose {
+			view.array.shape[0], view.array.shape[1] = dense.shape[1], dense.shape[0]
+			return mat.Transpose{Matrix: view}
 		}
+		return view
 	}
-	return matrix
+	return gonumView{m}
 }
 
-// Convert our matrix type to mat64's matrix type
-func ToMat64(m Matrix) *mat64.Dense {
-	return mat64.NewDense(m.Rows(), m.Cols(), m.Array())
+// Convert our matrix type to gonum's dense matrix type. For non-transposed
+// dense matrices, the result shares storage with m; other matrices are copied.
+func ToMat(m Matrix) *mat.Dense {
+	return mat.NewDense(m.Rows(), m.Cols(), m.Array())
 }
 
-// Convert mat64's matrix type to our matrix type
-func ToMatrix(m mat64.Matrix) Matrix {
+// Convert gonum's matrix type to our matrix type. If m stores its elements
+// contiguously in row-major order, as a *mat.Dense usually does, the result
+// shares storage with m; otherwise the elements are copied.
+func ToMatrix(m mat.Matrix) Matrix {
 	rows, cols := m.Dims()
+	switch v := m.(type) {
+	case denseGonumView:
+		return v.array
+	case gonumView:
+		return v.m
+	case mat.RawMatrixer:
+		raw := v.RawMatrix()
+		if raw.Stride == cols {
+			return &denseF64Array{
+				shape: []int{rows, cols},
+				array: raw.Data[:rows*cols],
+			}
+		}
+	}
 	array := &denseF64Array{
 		shape: []int{rows, cols},
 		array: make([]float64, rows*cols),
@@ -212,31 +405,310 @@ func ToMatrix(m mat64.Matrix) Matrix {
 	return array
 }
 
-// Get the matrix inverse
+// Get the matrix inverse, using the current backend. The inverse of a square
+// diagonal matrix is the diagonal matrix of reciprocals, and is found without
+// the backend.
 func Inverse(a Matrix) (Matrix, error) {
-	inv, err := mat64.Inverse(ToMat64(a))
+	debugCheck("Inverse", a)
+	if recip, ok := diagReciprocals(a); ok {
+		if recip == nil {
+			return nil, fmt.Errorf("%w: zero on the diagonal", ErrSingular)
+		}
+		return Diag(recip...), nil
+	}
+	return CurrentBackend().Inverse(a)
+}
+
+// Get the Moore-Penrose pseudo-inverse of an m x n matrix: the n x m matrix
+// which inverts a on its range, and which equals the inverse when a is
+// invertible. Singular values no larger than tol are treated as zero, so
+// rank-deficient matrices have a pseudo-inverse; if tol is zero, it defaults
+// to max(m, n) times the largest singular value times machine epsilon. It is
+// found from the SVD, or from the reciprocals of the diagonal for diagonal
+// matrices. Returns ErrNoConvergence if the SVD fails.
+func Pinv(a Matrix, tol float64) (Matrix, error) {
+	debugCheck("Pinv", a)
+	if tol < 0 {
+		panic(fmt.Sprintf("Pinv tolerance %g can't be negative", tol))
+	}
+	rows, cols := a.Rows(), a.Cols()
+	if a.Sparsity() == SparseDiagMatrix {
+		diag := a.Diag().Array()
+		if tol == 0 {
+			for _, v := range diag {
+				tol = max(tol, singularTol(rows, cols, math.Abs(v)))
+			}
+		}
+		for idx, v := range diag {
+			if math.Abs(v) > tol {
+				diag[idx] = 1 / v
+			} else {
+				diag[idx] = 0
+			}
+		}
+		return SparseDiag(cols, rows, diag...), nil
+	}
+
+	U, S, V, err := SVD(a, SVDThin)
 	if err != nil {
 		return nil, err
 	}
-	return ToMatrix(inv), nil
+	sv := S.Diag().Array()
+	if tol == 0 && len(sv) > 0 {
+		tol = singularTol(rows, cols, sv[0])
+	}
+	for idx, v := range sv {
+		if v > tol {
+			sv[idx] = 1 / v
+		} else {
+			sv[idx] = 0
+		}
+	}
+	return V.MProd(SparseDiag(len(sv), len(sv), sv...), U.T()), nil
 }
 
-// Solve for x, where ax = b.
+// Solve for x, where ax = b, using the current backend. If the system can't be
+// solved, the result is filled with NaN. When a is square and diagonal, x is
+// found by scaling the rows of b, and keeps b's storage format. To solve
+// several systems with the same a, use Factor() or LDivideAll().
 func LDivide(a, b Matrix) Matrix {
-	var x mat64.Dense
-	err := x.Solve(ToMat64(a), ToMat64(b))
+	x, err := ldivide(a, b)
 	if err != nil {
 		return WithValue(math.NaN(), a.Shape()[0], b.Shape()[1]).M()
 	}
-	return ToMatrix(&x)
+	return x
+}
+
+// Solve for x, where ax = b, as LDivide() does, returning an error if the
+// system can't be solved
+func ldivide(a, b Matrix) (Matrix, error) {
+	debugCheck("LDivide", a, b)
+	if recip, ok := diagReciprocals(a); ok {
+		if recip == nil {
+			return nil, fmt.Errorf("%w: the diagonal has a zero", ErrSingular)
+		}
+		return MProd(Diag(recip...), b), nil
+	}
+	return CurrentBackend().Solve(a, b)
+}
+
+// Get the reciprocals of the diagonal of a, if a is a square diagonal matrix.
+// The slice is nil if a is singular, and ok is false if a isn't diagonal.
+func diagReciprocals(a Matrix) (recip []float64, ok bool) {
+	sh := a.Shape()
+	if a.Sparsity() != SparseDiagMatrix || sh[0] != sh[1] {
+		return nil, false
+	}
+	recip = a.Diag().Array()
+	for idx, v := range recip {
+		if v == 0 {
+			return nil, true
+		}
+		recip[idx] = 1 / v
+	}
+	return recip, true
 }
 
-// Get the matrix norm of the specified ordinality (1, 2, infinity, ...)
+// Orders for Norm() which aren't induced norms
+const (
+	// The Frobenius norm: the square root of the sum of the squared elements
+	FrobeniusNorm = -100
+
+	// The largest absolute value of any element
+	MaxAbsNorm = -101
+
+	// The nuclear norm: the sum of the singular values
+	NuclearNorm = -102
+)
+
+// Get the matrix norm of the specified ordinality (1, 2, infinity, ...), or
+// FrobeniusNorm, MaxAbsNorm or NuclearNorm. The 2-norm is the induced 2-norm:
+// the largest singular value. The Frobenius and max-abs norms, and the 1- and
+// infinity-norms of sparse matrices, only visit the nonzero elements, and the
+// 2- and nuclear norms of diagonal matrices are read from the diagonal; the
+// others use the current backend. Every norm of an empty matrix is 0.
 func Norm(m Matrix, ord float64) float64 {
-	return ToMat64(m).Norm(ord)
+	debugCheck("Norm", m)
+	switch {
+	case m.Size() == 0:
+		return 0
+
+	case ord == FrobeniusNorm:
+		var sum float64
+		m.VisitNonzero(func(pos []int, value float64) bool {
+			sum += value * value
+			return true
+		})
+		return math.Sqrt(sum)
+
+	case ord == MaxAbsNorm:
+		var max float64
+		m.VisitNonzero(func(pos []int, value float64) bool {
+			max = math.Max(max, math.Abs(value))
+			return true
+		})
+		return max
+
+	case m.Sparsity() == SparseDiagMatrix && (ord == 2 || ord == NuclearNorm):
+		// The singular values are the absolute values of the diagonal
+		var max, sum float64
+		for _, v := range m.Diag().Array() {
+			max = math.Max(max, math.Abs(v))
+			sum += math.Abs(v)
+		}
+		if ord == 2 {
+			return max
+		}
+		return sum
+
+	case m.Sparsity() != DenseArray && (ord == 1 || math.IsInf(ord, 1)):
+		// The largest absolute column sum, or row sum for the infinity-norm
+		axis := 1
+		sums := make([]float64, m.Cols())
+		if ord != 1 {
+			axis = 0
+			sums = make([]float64, m.Rows())
+		}
+		m.VisitNonzero(func(pos []int, value float64) bool {
+			sums[pos[axis]] += math.Abs(value)
+			return true
+		})
+		var max float64
+		for _, sum := range sums {
+			max = math.Max(max, sum)
+		}
+		return max
+	}
+	return CurrentBackend().Norm(m, ord)
+}
+
+// Get the sum of the elements on a diagonal of the matrix. Positive offsets
+// select superdiagonals (above the main diagonal), and negative offsets select
+// subdiagonals. Offsets beyond the matrix bounds give zero.
+func Trace(m Matrix, offset int) float64 {
+	var (
+		sum      float64
+		row, col = 0, offset
+	)
+	if offset < 0 {
+		row, col = -offset, 0
+	}
+	for ; row < m.Rows() && col < m.Cols(); row, col = row+1, col+1 {
+		sum += m.Item(row, col)
+	}
+	return sum
+}
+
+// Get the default tolerance below which the singular values of a rows x cols
+// matrix are treated as zero: max(rows, cols) times the largest singular value
+// times machine epsilon
+func singularTol(rows, cols int, largest float64) float64 {
+	return float64(max(rows, cols)) * largest * (math.Nextafter(1, 2) - 1)
+}
+
+// Get the singular values of m in decreasing order. Those of a diagonal
+// matrix are the absolute values of its diagonal, and are found without a
+// decomposition.
+func singularValues(m Matrix) ([]float64, error) {
+	if m.Size() == 0 {
+		return nil, nil
+	} else if m.Sparsity() == SparseDiagMatrix {
+		sv := m.Diag().Array()
+		for idx, v := range sv {
+			sv[idx] = math.Abs(v)
+		}
+		sort.Sort(sort.Reverse(sort.Float64Slice(sv)))
+		return sv, nil
+	}
+	var svd mat.SVD
+	if !svd.Factorize(ToMat(m), mat.SVDNone) {
+		return nil, ErrNoConvergence
+	}
+	return svd.Values(nil), nil
+}
+
+// Get the determinant of a square matrix. The determinant of a diagonal
+// matrix is the product of its diagonal; other matrices use an LU
+// decomposition. The determinant of a 0x0 matrix is 1.
+func Det(m Matrix) float64 {
+	debugCheck("Det", m)
+	if m.Rows() != m.Cols() {
+		panic(ErrShapeMismatch{Op: "Det", Got: m.Shape(), Want: []int{m.Rows(), m.Rows()}})
+	}
+	if m.Rows() == 0 {
+		return 1
+	} else if m.Sparsity() == SparseDiagMatrix {
+		det := 1.0
+		for _, v := range m.Diag().Array() {
+			det *= v
+		}
+		return det
+	}
+	return LU(m).Det()
+}
+
+// Get the rank of a matrix: the number of its singular values which are
+// larger than tol. If tol is zero, it defaults to max(m, n) times the largest
+// singular value times machine epsilon, as for Pinv(). Panics with
+// ErrNoConvergence if the SVD fails, which can happen if m has infinite or NaN
+// elements. The rank of an empty matrix is 0.
+func Rank(m Matrix, tol float64) int {
+	debugCheck("Rank", m)
+	if tol < 0 {
+		panic(fmt.Sprintf("Rank tolerance %g can't be negative", tol))
+	}
+	sv, err := singularValues(m)
+	if err != nil {
+		panic(err)
+	}
+	if tol == 0 && len(sv) > 0 {
+		tol = singularTol(m.Rows(), m.Cols(), sv[0])
+	}
+	rank := 0
+	for rank < len(sv) && sv[rank] > tol {
+		rank++
+	}
+	return rank
+}
+
+// Get the condition number of a matrix for the matrix norm of the specified
+// ordinality: Norm(m, ord) times Norm(Inverse(m), ord). The 2-norm condition
+// number is the ratio of the largest and smallest singular values, and is
+// also defined for non-square matrices; other norms need a square matrix.
+// Singular matrices have an infinite condition number, and the result is NaN
+// if it can't be computed. Diagonal matrices don't need a decomposition.
+func Cond(m Matrix, ord float64) float64 {
+	debugCheck("Cond", m)
+	if ord == 2 {
+		sv, err := singularValues(m)
+		switch {
+		case err != nil:
+			return math.NaN()
+		case len(sv) == 0:
+			return 0
+		case sv[len(sv)-1] == 0:
+			return math.Inf(1)
+		}
+		return sv[0] / sv[len(sv)-1]
+	}
+	if m.Rows() != m.Cols() {
+		panic(ErrShapeMismatch{Op: "Cond", Got: m.Shape(), Want: []int{m.Rows(), m.Rows()}})
+	}
+	inv, err := Inverse(m)
+	if err != nil {
+		return math.Inf(1)
+	}
+	return Norm(m, ord) * Norm(inv, ord)
 }
 
 // Solve is an alias for LDivide
 func Solve(a, b Matrix) Matrix {
 	return LDivide(a, b)
 }
+
+// Get the name of the BLAS implementation used for linear algebra: "gonum"
+// for the default pure Go implementation, or "netlib" when built with the
+// cblas tag to use a C BLAS library.
+func BLASImplementation() string {
+	return blasImplementation
+}
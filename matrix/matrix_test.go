@@ -2,6 +2,7 @@ package matrix
 
 import (
 	. "github.com/smartystreets/goconvey/convey"
+	"gonum.org/v1/gonum/mat"
 	"math"
 	"testing"
 )
@@ -9,8 +10,8 @@ import (
 func TestConversion(t *testing.T) {
 	Convey("Given a Matrix", t, func() {
 		m := Rand(3, 5).M()
-		Convey("Converting it ToMat64 works", func() {
-			m2 := ToMat64(m)
+		Convey("Converting it ToMat works", func() {
+			m2 := ToMat(m)
 			r, c := m2.Dims()
 			So(r, ShouldEqual, 3)
 			So(c, ShouldEqual, 5)
@@ -33,6 +34,81 @@ func TestConversion(t *testing.T) {
 	})
 }
 
+func TestAsMat(t *testing.T) {
+	Convey("Given a Matrix", t, func() {
+		m := M(2, 3,
+			1, 2, 3,
+			4, 5, 6)
+
+		Convey("AsMat gives a gonum view of it", func() {
+			v := AsMat(m)
+			r, c := v.Dims()
+			So(r, ShouldEqual, 2)
+			So(c, ShouldEqual, 3)
+			So(v.At(1, 2), ShouldEqual, 6)
+			So(v.T().At(2, 1), ShouldEqual, 6)
+			So(mat.Sum(v), ShouldEqual, 21)
+
+			Convey("The view shares storage", func() {
+				m.ItemSet(10, 1, 2)
+				So(v.At(1, 2), ShouldEqual, 10)
+			})
+		})
+
+		Convey("AsMat works for sparse matrices", func() {
+			v := AsMat(Diag(1, 2))
+			So(mat.Trace(v), ShouldEqual, 3)
+			v.(mat.Mutable).Set(1, 1, 5)
+			So(v.At(1, 1), ShouldEqual, 5)
+		})
+
+		Convey("AsMat exposes dense storage without copying", func() {
+			raw := AsMat(m).(mat.RawMatrixer).RawMatrix()
+			So(raw.Rows, ShouldEqual, 2)
+			So(raw.Cols, ShouldEqual, 3)
+			So(raw.Stride, ShouldEqual, 3)
+			raw.Data[0] = -1
+			So(m.Item(0, 0), ShouldEqual, -1)
+
+			AsMat(m).(mat.Mutable).Set(0, 1, -2)
+			So(m.Item(0, 1), ShouldEqual, -2)
+		})
+
+		Convey("gonum routines can write into our storage", func() {
+			var d mat.Dense
+			d.Mul(AsMat(m), AsMat(m.T()))
+			out := Dense(2, 2).M()
+			mat.NewDense(2, 2, out.Array()).Copy(&d)
+			So(out.Array(), ShouldResemble, []float64{14, 32, 32, 77})
+			d2 := ToMat(out)
+			d2.Scale(2, d2)
+			So(out.Array(), ShouldResemble, []float64{28, 64, 64, 154})
+		})
+
+		Convey("AsMat works for transposed dense matrices", func() {
+			v := AsMat(m.T())
+			r, c := v.Dims()
+			So(r, ShouldEqual, 3)
+			So(c, ShouldEqual, 2)
+			So(v.At(2, 0), ShouldEqual, 3)
+			So(ToMatrix(v).Array(), ShouldResemble, m.T().Array())
+		})
+
+		Convey("ToMatrix shares storage with a mat.Dense", func() {
+			d := mat.NewDense(2, 2, []float64{1, 2, 3, 4})
+			m2 := ToMatrix(d)
+			d.Set(1, 1, 10)
+			So(m2.Item(1, 1), ShouldEqual, 10)
+		})
+
+		Convey("ToMatrix unwraps views made by AsMat", func() {
+			So(ToMatrix(AsMat(m)).Array(), ShouldResemble, m.Array())
+			coo := SparseCoo(2, 2)
+			So(ToMatrix(AsMat(coo)).Sparsity(), ShouldEqual, SparseCooMatrix)
+		})
+	})
+}
+
 func TestDiag(t *testing.T) {
 	Convey("Given a diagonal array with 3 elements", t, func() {
 		array := Diag(1, 2, 3)
@@ -332,6 +408,57 @@ func TestInverse(t *testing.T) {
 	})
 }
 
+func TestPinv(t *testing.T) {
+	Convey("Given an invertible matrix", t, func() {
+		a := M(2, 2, 4, 7, 2, 6)
+
+		Convey("The pseudo-inverse is the inverse", func() {
+			inv, err := a.Inverse()
+			So(err, ShouldBeNil)
+			p, err := a.Pinv(0)
+			So(err, ShouldBeNil)
+			So(ApproxEqual(p, inv, 1e-12, 1e-12), ShouldBeTrue)
+		})
+	})
+
+	Convey("Given rank-deficient and non-square matrices", t, func() {
+		singular := M(2, 2, 1, 2, 2, 4)
+		tall := M(3, 2, 1, 0, 0, 1, 1, 1)
+
+		Convey("The Moore-Penrose conditions hold", func() {
+			for _, a := range []Matrix{singular, tall, tall.T(), tall.SparseCSR()} {
+				p, err := Pinv(a, 0)
+				So(err, ShouldBeNil)
+				So(p.Shape(), ShouldResemble, []int{a.Cols(), a.Rows()})
+				So(ApproxEqual(a.MProd(p, a), a, 1e-12, 1e-12), ShouldBeTrue)
+				So(ApproxEqual(p.MProd(a, p), p, 1e-12, 1e-12), ShouldBeTrue)
+				So(ApproxEqual(a.MProd(p), a.MProd(p).T(), 1e-12, 1e-12), ShouldBeTrue)
+			}
+		})
+
+		Convey("The tolerance drops small singular values", func() {
+			p, err := Diag(4, 1e-8).Pinv(1e-6)
+			So(err, ShouldBeNil)
+			So(p.Array(), ShouldResemble, []float64{.25, 0, 0, 0})
+			p, err = M(2, 2, 4, 0, 0, 1e-8).Pinv(1e-6)
+			So(err, ShouldBeNil)
+			So(ApproxEqual(p, M(2, 2, .25, 0, 0, 0), 1e-12, 1e-12), ShouldBeTrue)
+		})
+
+		Convey("Diagonal matrices stay diagonal", func() {
+			p, err := SparseDiag(3, 2, 2, 0).Pinv(0)
+			So(err, ShouldBeNil)
+			So(p.Sparsity(), ShouldEqual, SparseDiagMatrix)
+			So(p.Shape(), ShouldResemble, []int{2, 3})
+			So(p.Array(), ShouldResemble, []float64{.5, 0, 0, 0, 0, 0})
+		})
+
+		Convey("Negative tolerances panic", func() {
+			So(func() { Pinv(tall, -1) }, ShouldPanic)
+		})
+	})
+}
+
 func TestLDivide(t *testing.T) {
 	Convey("Given a simple division problem", t, func() {
 		a := M(3, 3,
@@ -402,6 +529,28 @@ func TestNorm(t *testing.T) {
 		Convey("The inf-norm is correct", func() {
 			So(Norm(m, math.Inf(1)), ShouldEqual, 24)
 		})
+
+		Convey("The Frobenius norm is correct", func() {
+			So(Norm(m, FrobeniusNorm), ShouldAlmostEqual, math.Sqrt(285), 1e-12)
+		})
+
+		Convey("The max-abs norm is correct", func() {
+			So(m.ItemProd(-1).M().Norm(MaxAbsNorm), ShouldEqual, 9)
+		})
+
+		Convey("The nuclear norm is the sum of the singular values", func() {
+			So(Norm(Diag(3, -4, 0), NuclearNorm), ShouldAlmostEqual, 7, 1e-12)
+			So(Norm(m, NuclearNorm), ShouldAlmostEqual, 16.84810335261421+1.068369514554709, 1e-9)
+		})
+
+		Convey("Sparse matrices give the same norms", func() {
+			for _, sp := range []Matrix{m.SparseCoo(), m.SparseCSR(), m.SparseCSC()} {
+				for _, ord := range []float64{1, 2, math.Inf(1), FrobeniusNorm, MaxAbsNorm} {
+					So(Norm(sp, ord), ShouldAlmostEqual, Norm(m, ord), 1e-12)
+				}
+			}
+			So(Norm(SparseCoo(2, 3, 0, -5), 1), ShouldEqual, 5)
+		})
 	})
 }
 
@@ -456,3 +605,129 @@ func TestSolve(t *testing.T) {
 		})
 	})
 }
+
+func TestTrace(t *testing.T) {
+	Convey("Given a non-square dense matrix", t, func() {
+		m := M(3, 4,
+			1, 2, 3, 4,
+			5, 6, 7, 8,
+			9, 10, 11, 12)
+
+		Convey("Trace sums the main diagonal", func() {
+			So(m.Trace(), ShouldEqual, 18)
+			So(Trace(m, 0), ShouldEqual, 18)
+		})
+
+		Convey("TraceOffset sums the other diagonals", func() {
+			So(m.TraceOffset(1), ShouldEqual, 2+7+12)
+			So(m.TraceOffset(3), ShouldEqual, 4)
+			So(m.TraceOffset(-1), ShouldEqual, 5+10)
+			So(m.TraceOffset(-2), ShouldEqual, 9)
+			So(m.TraceOffset(4), ShouldEqual, 0)
+			So(m.TraceOffset(-3), ShouldEqual, 0)
+		})
+
+		Convey("Transposing swaps super- and subdiagonals", func() {
+			So(m.T().TraceOffset(-1), ShouldEqual, 2+7+12)
+			So(m.SparseCoo().T().TraceOffset(1), ShouldEqual, 5+10)
+		})
+	})
+
+	Convey("Given a sparse diagonal matrix", t, func() {
+		m := Diag(1, 2, 3)
+
+		Convey("Trace sums the diagonal", func() {
+			So(m.Trace(), ShouldEqual, 6)
+			So(m.TraceOffset(0), ShouldEqual, 6)
+			So(m.TraceOffset(1), ShouldEqual, 0)
+		})
+	})
+}
+
+func TestDetRankCond(t *testing.T) {
+	Convey("Given a square matrix in several formats", t, func() {
+		values := []float64{
+			2, 0, 1,
+			1, 3, 0,
+			0, 1, 4,
+		}
+		all := []Matrix{M(3, 3, values...), SparseCoo(3, 3, values...), SparseCSR(3, 3, values...)}
+
+		Convey("Det, Rank and Cond agree with the definitions", func() {
+			for _, m := range all {
+				So(m.Det(), ShouldAlmostEqual, 25, 1e-12)
+				So(m.Rank(0), ShouldEqual, 3)
+				inv, _ := m.Inverse()
+				for _, ord := range []float64{1, 2, math.Inf(1), FrobeniusNorm} {
+					So(m.Cond(ord), ShouldAlmostEqual, m.Norm(ord)*inv.Norm(ord), 1e-12)
+				}
+			}
+		})
+	})
+
+	Convey("Given singular and non-square matrices", t, func() {
+		singular := M(3, 3, 1, 2, 3, 2, 4, 6, 1, 0, 1)
+		wide := M(2, 3, 1, 0, 0, 0, 2, 0)
+
+		Convey("Rank counts the independent rows", func() {
+			So(singular.Rank(0), ShouldEqual, 2)
+			So(wide.Rank(0), ShouldEqual, 2)
+			So(M(2, 2, 1, 0, 0, 1e-9).Rank(1e-6), ShouldEqual, 1)
+			So(Dense(2, 2).M().Rank(0), ShouldEqual, 0)
+		})
+
+		Convey("Det is zero and Cond is infinite", func() {
+			So(singular.Det(), ShouldAlmostEqual, 0, 1e-12)
+			So(math.IsInf(singular.Cond(1), 1), ShouldBeTrue)
+			So(singular.Cond(2), ShouldBeGreaterThan, 1e12)
+		})
+
+		Convey("Only the 2-norm condition number needs no square matrix", func() {
+			So(wide.Cond(2), ShouldAlmostEqual, 2, 1e-12)
+			So(func() { wide.Cond(1) }, ShouldPanic)
+			So(func() { wide.Det() }, ShouldPanic)
+		})
+	})
+
+	Convey("Given a diagonal matrix", t, func() {
+		m := Diag(2, -4, 0.5)
+
+		Convey("The scalars are read from the diagonal", func() {
+			So(m.Det(), ShouldEqual, -4)
+			So(m.Rank(0), ShouldEqual, 3)
+			So(m.Rank(1), ShouldEqual, 2)
+			So(m.Cond(2), ShouldEqual, 8)
+			So(m.Cond(1), ShouldEqual, 8)
+			So(m.Norm(2), ShouldEqual, 4)
+			So(m.Norm(NuclearNorm), ShouldEqual, 6.5)
+			So(SparseDiag(3, 2, 1, 0).Rank(0), ShouldEqual, 1)
+			So(math.IsInf(SparseDiag(3, 2, 1, 0).Cond(2), 1), ShouldBeTrue)
+		})
+	})
+
+	Convey("Given empty matrices", t, func() {
+		Convey("Det is 1, Rank is 0 and the norms are 0", func() {
+			for _, m := range []Matrix{Dense(0, 0).M(), SparseCoo(0, 0), SparseCSR(0, 0)} {
+				So(m.Det(), ShouldEqual, 1)
+				So(m.Rank(0), ShouldEqual, 0)
+				So(m.Norm(2), ShouldEqual, 0)
+				So(m.Norm(NuclearNorm), ShouldEqual, 0)
+			}
+			So(Dense(0, 3).M().Rank(0), ShouldEqual, 0)
+			So(GonumBackend{}.Norm(Dense(0, 3).M(), NuclearNorm), ShouldEqual, 0)
+			So(GonumBackend{}.Norm(Dense(3, 0).M(), 1), ShouldEqual, 0)
+		})
+	})
+
+	Convey("Given a permutation", t, func() {
+		Convey("Det is its sign", func() {
+			So(NewPermutation([]int{0, 1, 2}).Det(), ShouldEqual, 1)
+			So(NewPermutation([]int{1, 0, 2}).Det(), ShouldEqual, -1)
+			So(NewPermutation([]int{1, 2, 0}).Det(), ShouldEqual, 1)
+			p := NewPermutation([]int{3, 0, 1, 2})
+			So(p.Det(), ShouldEqual, LU(p.Dense().M()).Det())
+			So(p.Rank(0), ShouldEqual, 4)
+			So(p.Cond(2), ShouldAlmostEqual, 1, 1e-12)
+		})
+	})
+}
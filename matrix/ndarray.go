@@ -75,9 +75,82 @@
 // To create a 3x4 sparse coo with half the items randomly populated:
 //     m5 := SparseRand(3, 4, 0.5)
 //     m6 := SparseRandN(3, 4, 0.5)
+//
+// To create a 3x4 matrix in compressed sparse row (CSR) format, for fast row
+// access and matrix-vector products, or to convert another matrix to CSR or
+// compressed sparse column (CSC) format:
+//     m6a := SparseCSR(3, 4, 1.0, 0.0, 2.0)
+//     m6b := m5.SparseCSC()
+//
+// To create a 2x3 dense matrix stored in column-major (Fortran) order, or to
+// use existing column-major storage without copying:
+//     m7 := MOrder(ColMajor, 2, 3,
+//                  1.0, 4.0,
+//                  2.0, 5.0,
+//                  3.0, 6.0)
+//     m8 := WrapOrder(ColMajor, 2, 3, values)
+//
+// NewMatrix() combines these variants through options. To create a
+// reproducible 100x100 sparse coo matrix with 1% of its values randomly
+// populated:
+//     m9 := NewMatrix(100, 100,
+//                     WithStorage(SparseCooMatrix),
+//                     WithRand(rand.NewSource(42)),
+//                     WithRandom(Uniform, 0.01))
+//
+// Vector
+//
+// The Vector interface describes a one-dimensional array, with Dot(), AXPY()
+// and Norm() and conversions to row and column matrices. Dense and sparse
+// representations are available.
+//
+// To create a dense vector, or a sparse vector of length 1000:
+//     v0 := DenseVector(1.0, 2.0, 3.0)
+//     v1 := SparseVector(1000)
+//
+// Build Modes
+//
+// By default the package is pure Go: linear algebra runs on gonum's native Go
+// BLAS and LAPACK, so the package builds with CGO_ENABLED=0 and for
+// GOOS=js GOARCH=wasm. Besides gonum, it imports only the protobuf wire format
+// package. Conversions for libraries with heavier dependencies are in
+// subpackages, which are only built if imported: featherio for Arrow Feather
+// files, gorgoniaconv for Gorgonia tensors and jbsparseconv for
+// github.com/james-bowman/sparse matrices. To use a cgo-backed BLAS library
+// such as OpenBLAS instead, build with the cblas tag and gonum.org/v1/netlib
+// installed:
+//     go build -tags cblas
+// BLASImplementation() reports which implementation is in use.
+//
+// Concurrency
+//
+// Arrays don't lock. Any number of goroutines may call methods which only
+// read an array, such as Item(), Sum(), MProd() and Copy(), as long as no
+// goroutine modifies it at the same time. The methods which modify an array
+// are ItemSet(), FlatItemSet(), RowSet(), ColSet(), SetSubmatrix(),
+// SwapRows(), SwapCols(), Fill() and Scan(), and they need exclusive access,
+// which includes access to any view of the same storage (see below). Package
+// functions such as Add() and LDivide() only read their arguments.
+//
+// To share a matrix which some goroutines modify, wrap it with Synchronized():
+//     shared := Synchronized(m)
+//
+// Copies and Views
+//
+// Every method either returns a new array, which never shares storage with
+// its receiver, or a view, which always does. Writes through a view are
+// visible in the original array, and vice versa. The views are:
+//     View(), T() and M()
+//     Array(), for row-major dense arrays only
+//     conversions documented as sharing storage, such as WrapOrder(),
+//     RawOrder(), ToMatrix(), AsMat(), RowSubset() and DenseVector()
+// Clone() and Copy() make deep copies, and Row() and Col() always return
+// new slices. All other methods which return arrays, such as Add() or
+// Slice(), return new arrays.
 package matrix
 
 import (
+	"database/sql/driver"
 	"fmt"
 	"math/rand"
 	"time"
@@ -94,8 +167,27 @@ const (
 	DenseArray ArraySparsity = iota
 	SparseCooMatrix
 	SparseDiagMatrix
+	SparseCSRMatrix
+	SparseCSCMatrix
 )
 
+// Get the name of the representation
+func (sp ArraySparsity) String() string {
+	switch sp {
+	case DenseArray:
+		return "dense"
+	case SparseCooMatrix:
+		return "sparse coo"
+	case SparseDiagMatrix:
+		return "sparse diagonal"
+	case SparseCSRMatrix:
+		return "sparse csr"
+	case SparseCSCMatrix:
+		return "sparse csc"
+	}
+	return fmt.Sprintf("ArraySparsity(%d)", int(sp))
+}
+
 // A NDArray is an n-dimensional array of numbers which can be manipulated in
 // various ways. Concrete implementations can differ; for instance, sparse
 // and dense representations are possible.
@@ -113,6 +205,9 @@ type NDArray interface {
 	// Returns true if f is true for all pairs of array elements in the same position
 	AllF2(f func(v1, v2 float64) bool, other NDArray) bool
 
+	// Returns true if and only if no items are NaN or infinite
+	AllFinite() bool
+
 	// Returns true if and only if any item is nonzero
 	Any() bool
 
@@ -122,19 +217,27 @@ type NDArray interface {
 	// Returns true if f is true for any pair of array elements in the same position
 	AnyF2(f func(v1, v2 float64) bool, other NDArray) bool
 
+	// Returns true if and only if any item is NaN
+	AnyNaN() bool
+
 	// Return the result of applying a function to all elements
 	Apply(f func(float64) float64) NDArray
 
-	// Get the matrix data as a flattened 1D array; sparse matrices will make
-	// a copy first.
+	// Get the matrix data as a flattened 1D array in row-major order. For
+	// row-major dense arrays, this is the backing storage, so changes to it
+	// change the array. All other arrays return a copy.
 	Array() []float64
 
+	// Returns a deep copy of this array, in the same storage format. Changes to
+	// the copy never affect this array, or vice versa.
+	Clone() NDArray
+
 	// Create a new array by concatenating this with another array along the
 	// specified axis. The array shapes must be equal along all other axes.
 	// It is legal to add a new axis.
 	Concat(axis int, others ...NDArray) NDArray
 
-	// Returns a duplicate of this array
+	// Returns a duplicate of this array; the same as Clone()
 	Copy() NDArray
 
 	// Counts the number of nonzero elements in the array
@@ -181,7 +284,8 @@ type NDArray interface {
 	ItemSet(value float64, index ...int)
 
 	// Returns the array as a matrix. This is only possible for 1D and 2D arrays;
-	// 1D arrays of length n are converted into n x 1 vectors.
+	// 1D arrays of length n are converted into n x 1 vectors. The result is a
+	// view: it shares storage with this array.
 	M() Matrix
 
 	// Get the value of the largest array element
@@ -202,6 +306,11 @@ type NDArray interface {
 	// Get a 1D copy of the array, in 'C' order: rightmost axes change fastest
 	Ravel() NDArray
 
+	// Replace the array with one read from a database column, implementing
+	// sql.Scanner. Accepts the binary format written by Value() and the JSON
+	// format written by JSON.Value(). The array keeps its storage format.
+	Scan(src interface{}) error
+
 	// A slice giving the size of all array dimensions
 	Shape() []int
 
@@ -225,6 +334,14 @@ type NDArray interface {
 	// Return the sum of all array elements
 	Sum() float64
 
+	// Encode the array in the package's binary format for storage in a
+	// database column, implementing driver.Valuer
+	Value() (driver.Value, error)
+
+	// Returns a view of this array: a new array value which shares storage
+	// with this one, so changes made through either are visible in both.
+	View() NDArray
+
 	// Visit all matrix elements, invoking a method on each. If the method
 	// returns false, iteration is aborted and VisitNonzero() returns false.
 	// Otherwise, it returns true.
@@ -243,7 +360,7 @@ func A(shape []int, values ...float64) NDArray {
 		size *= sz
 	}
 	if len(values) != size {
-		panic(fmt.Sprintf("Expected %d array elements but got %d", size, len(values)))
+		panic(ErrShapeMismatch{Op: "A", Got: []int{len(values)}, Want: []int{size}})
 	}
 	array := &denseF64Array{
 		shape: shape,
@@ -253,6 +370,24 @@ func A(shape []int, values ...float64) NDArray {
 	return array
 }
 
+// Create a dense array which uses values, listed in row-major order, as its
+// storage without copying. Changes to the array are visible in values, and
+// vice versa. This lets packages which convert to and from other libraries
+// share their memory.
+func WrapA(shape []int, values []float64) NDArray {
+	size := 1
+	for _, sz := range shape {
+		size *= sz
+	}
+	if len(values) != size {
+		panic(ErrShapeMismatch{Op: "WrapA", Got: []int{len(values)}, Want: []int{size}})
+	}
+	return &denseF64Array{
+		shape: append([]int(nil), shape...),
+		array: values,
+	}
+}
+
 // Create a 1D array
 func A1(values ...float64) NDArray {
 	return A([]int{len(values)}, values...)
@@ -266,7 +401,7 @@ func A2(rows ...[]float64) NDArray {
 	}
 	for i0 := 0; i0 < array.shape[0]; i0++ {
 		if len(rows[i0]) != array.shape[1] {
-			panic(fmt.Sprintf("A2 got inconsistent array lengths %d and %d", array.shape[1], len(rows[i0])))
+			panic(ErrShapeMismatch{Op: "A2", Got: []int{len(rows[i0])}, Want: []int{array.shape[1]}})
 		}
 		for i1 := 0; i1 < array.shape[1]; i1++ {
 			array.ItemSet(rows[i0][i1], i0, i1)
@@ -287,11 +422,12 @@ func Dense(size ...int) NDArray {
 	}
 }
 
-// Create an NDArray of float64 values, initialized to value
+// Create a dense NDArray of float64 values, initialized to value. Only the
+// value is stored until the array is modified, so large placeholder arrays
+// are free to create; the elements are stored the first time one of them is
+// set.
 func WithValue(value float64, size ...int) NDArray {
-	array := Dense(size...)
-	array.Fill(value)
-	return array
+	return newConst(value, size)
 }
 
 // Create an NDArray of float64 values, initialized to zero
@@ -299,7 +435,8 @@ func Zeros(size ...int) NDArray {
 	return Dense(size...)
 }
 
-// Create an NDArray of float64 values, initialized to one
+// Create an NDArray of float64 values, initialized to one. It is stored as
+// for WithValue().
 func Ones(size ...int) NDArray {
 	return WithValue(1.0, size...)
 }
@@ -180,6 +180,27 @@ func TestWithValue(t *testing.T) {
 	})
 }
 
+func TestWrapA(t *testing.T) {
+	Convey("WrapA() panics when data doesn't match dimensions", t, func() {
+		So(try(func() { WrapA([]int{2, 3}, []float64{1, 2, 3, 4, 5}) }), ShouldResemble,
+			ErrShapeMismatch{Op: "WrapA", Got: []int{5}, Want: []int{6}})
+	})
+
+	Convey("Given an array created with WrapA", t, func() {
+		values := []float64{1, 2, 3, 4, 5, 6}
+		m := WrapA([]int{2, 3}, values)
+		Convey("Shape() is 2, 3", func() {
+			So(m.Shape(), ShouldResemble, []int{2, 3})
+		})
+		Convey("It shares storage with the values", func() {
+			m.ItemSet(10, 1, 2)
+			So(values[5], ShouldEqual, 10)
+			values[0] = -1
+			So(m.Item(0, 0), ShouldEqual, -1)
+		})
+	})
+}
+
 func TestZeros(t *testing.T) {
 	Convey("Given a zeros array with shape 5, 3", t, func() {
 		array := Zeros(5, 3)
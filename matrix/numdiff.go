@@ -0,0 +1,79 @@
+package matrix
+
+import (
+	"fmt"
+	"math"
+)
+
+// Get the step used to perturb an element with value v by central
+// differences. A zero eps is replaced by the cube root of machine epsilon,
+// which balances truncation and rounding error, and the step is scaled by
+// the size of v so that large values are perturbed measurably.
+func diffStep(eps, v float64) float64 {
+	if eps == 0 {
+		eps = math.Cbrt(math.Nextafter(1, 2) - 1)
+	}
+	return eps * math.Max(1, math.Abs(v))
+}
+
+// Get the gradient of f at x by central finite differences, for checking
+// analytic gradients: element (i, j) of the result, which has the same shape
+// as x, is (f(x + h e_ij) - f(x - h e_ij)) / 2h. The step h is eps times the
+// larger of 1 and |x_ij|, and eps defaults to about 6e-6 if it is zero. f is
+// called 2 x.Size() times on a dense copy of x, which it mustn't modify or
+// keep. x isn't changed.
+func NumGrad(f func(Matrix) float64, x Matrix, eps float64) Matrix {
+	debugCheck("NumGrad", x)
+	if eps < 0 {
+		panic(fmt.Sprintf("NumGrad step %g can't be negative", eps))
+	}
+	point := x.Dense().M()
+	grad := Dense(x.Shape()...).M()
+	for idx := 0; idx < point.Size(); idx++ {
+		v := point.FlatItem(idx)
+		h := diffStep(eps, v)
+		point.FlatItemSet(v+h, idx)
+		up := f(point)
+		point.FlatItemSet(v-h, idx)
+		down := f(point)
+		point.FlatItemSet(v, idx)
+		grad.FlatItemSet((up-down)/(2*h), idx)
+	}
+	return grad
+}
+
+// Get the Jacobian of f at x by central finite differences. The outputs and
+// inputs are flattened in row-major order: element (i, j) of the result is
+// the derivative of element i of f(x) with respect to element j of x, so the
+// result is f(x).Size() x x.Size(). Steps are chosen and f is called as for
+// NumGrad(), and f must return a matrix of the same size each time.
+func Jacobian(f func(Matrix) Matrix, x Matrix, eps float64) Matrix {
+	debugCheck("Jacobian", x)
+	if eps < 0 {
+		panic(fmt.Sprintf("Jacobian step %g can't be negative", eps))
+	}
+	point := x.Dense().M()
+	var jac Matrix
+	for idx := 0; idx < point.Size(); idx++ {
+		v := point.FlatItem(idx)
+		h := diffStep(eps, v)
+		point.FlatItemSet(v+h, idx)
+		up := f(point).Array()
+		point.FlatItemSet(v-h, idx)
+		down := f(point).Array()
+		point.FlatItemSet(v, idx)
+		if jac == nil {
+			jac = Dense(len(up), point.Size()).M()
+		}
+		if len(up) != jac.Rows() || len(down) != jac.Rows() {
+			panic(ErrShapeMismatch{Op: "Jacobian", Got: []int{len(down)}, Want: []int{jac.Rows()}})
+		}
+		for row := range up {
+			jac.ItemSet((up[row]-down[row])/(2*h), row, idx)
+		}
+	}
+	if jac == nil {
+		jac = Dense(f(point).Size(), 0).M()
+	}
+	return jac
+}
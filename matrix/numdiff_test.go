@@ -0,0 +1,65 @@
+package matrix
+
+import (
+	. "github.com/smartystreets/goconvey/convey"
+	"math"
+	"testing"
+)
+
+func TestNumGrad(t *testing.T) {
+	Convey("Given a quadratic form", t, func() {
+		a := M(2, 2, 2, 1, 1, 3)
+		f := func(x Matrix) float64 { return x.T().MProd(a, x).Item(0, 0) }
+		x := M(2, 1, 1, -2)
+
+		Convey("NumGrad matches the analytic gradient", func() {
+			grad := NumGrad(f, x, 0)
+			So(grad.Shape(), ShouldResemble, []int{2, 1})
+			So(ApproxEqual(grad, a.Add(a.T()).M().MProd(x), 1e-8, 1e-8), ShouldBeTrue)
+		})
+
+		Convey("x isn't changed", func() {
+			NumGrad(f, x, 1e-4)
+			So(x.Array(), ShouldResemble, []float64{1, -2})
+		})
+
+		Convey("The gradient has the shape of x", func() {
+			g := NumGrad(func(x Matrix) float64 { return x.Prod(x).Sum() }, M(2, 3, 1, 2, 3, 4, 5, 6).T(), 0)
+			So(ApproxEqual(g, M(3, 2, 2, 8, 4, 10, 6, 12), 1e-6, 1e-6), ShouldBeTrue)
+		})
+
+		Convey("Negative steps panic", func() {
+			So(func() { NumGrad(f, x, -1) }, ShouldPanic)
+		})
+	})
+}
+
+func TestJacobian(t *testing.T) {
+	Convey("Given a function from R^2 to R^3", t, func() {
+		f := func(x Matrix) Matrix {
+			x0, x1 := x.FlatItem(0), x.FlatItem(1)
+			return M(3, 1, x0*x1, math.Sin(x0), x1*x1)
+		}
+		x := M(1, 2, 0.5, 2)
+
+		Convey("Jacobian matches the analytic derivatives", func() {
+			jac := Jacobian(f, x, 0)
+			So(jac.Shape(), ShouldResemble, []int{3, 2})
+			want := M(3, 2,
+				2, 0.5,
+				math.Cos(0.5), 0,
+				0, 4)
+			So(ApproxEqual(jac, want, 1e-8, 1e-8), ShouldBeTrue)
+		})
+
+		Convey("Functions whose output size changes panic", func() {
+			calls := 0
+			g := func(x Matrix) Matrix {
+				calls++
+				return Dense(calls, 1).M()
+			}
+			err := try(func() { Jacobian(g, x, 0) })
+			So(err, ShouldHaveSameTypeAs, ErrShapeMismatch{})
+		})
+	})
+}
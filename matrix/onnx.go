@@ -0,0 +1,191 @@
+package matrix
+
+import (
+	"encoding/binary"
+	"fmt"
+	"google.golang.org/protobuf/encoding/protowire"
+	"math"
+	"sort"
+)
+
+// Field numbers and enum values from the ONNX protobuf schema (onnx.proto)
+const (
+	onnxTensorDims         protowire.Number = 1
+	onnxTensorDataType     protowire.Number = 2
+	onnxTensorFloatData    protowire.Number = 4
+	onnxTensorName         protowire.Number = 8
+	onnxTensorRawData      protowire.Number = 9
+	onnxTensorDoubleData   protowire.Number = 10
+	onnxTensorExternalData protowire.Number = 13
+	onnxGraphInitializer   protowire.Number = 5
+	onnxModelGraph         protowire.Number = 7
+
+	onnxFloat  = 1
+	onnxDouble = 11
+)
+
+// Encode an array as a serialized ONNX TensorProto message with the given
+// name. The tensor has the same shape as the array and data type DOUBLE, and
+// its elements are stored in raw_data in row-major order.
+func ToONNX(array NDArray, name string) []byte {
+	var msg []byte
+	var dims []byte
+	for _, dim := range array.Shape() {
+		dims = protowire.AppendVarint(dims, uint64(dim))
+	}
+	msg = protowire.AppendTag(msg, onnxTensorDims, protowire.BytesType)
+	msg = protowire.AppendBytes(msg, dims)
+	msg = protowire.AppendTag(msg, onnxTensorDataType, protowire.VarintType)
+	msg = protowire.AppendVarint(msg, onnxDouble)
+	if name != "" {
+		msg = protowire.AppendTag(msg, onnxTensorName, protowire.BytesType)
+		msg = protowire.AppendString(msg, name)
+	}
+	values := array.Array()
+	raw := make([]byte, 8*len(values))
+	for idx, v := range values {
+		binary.LittleEndian.PutUint64(raw[8*idx:], math.Float64bits(v))
+	}
+	msg = protowire.AppendTag(msg, onnxTensorRawData, protowire.BytesType)
+	msg = protowire.AppendBytes(msg, raw)
+	return msg
+}
+
+// Decode a serialized ONNX TensorProto message, returning a dense array and
+// the tensor's name. Tensors of data type DOUBLE and FLOAT are supported, with
+// elements stored either in raw_data or in the typed data fields. Returns an
+// error for other data types, scalar tensors, and externally stored data.
+func FromONNX(data []byte) (NDArray, string, error) {
+	var (
+		name     string
+		dtype    uint64
+		shape    []int
+		raw      []byte
+		hasRaw   bool
+		values   []float64
+		badField error
+	)
+	err := protoFields(data, func(num protowire.Number, typ protowire.Type, field []byte) error {
+		switch num {
+		case onnxTensorDims:
+			return protoVarints(typ, field, func(v uint64) {
+				shape = append(shape, int(int64(v)))
+			})
+		case onnxTensorDataType:
+			v, n := protowire.ConsumeVarint(field)
+			if n < 0 {
+				return protowire.ParseError(n)
+			}
+			dtype = v
+		case onnxTensorName:
+			name = string(field)
+		case onnxTensorRawData:
+			raw, hasRaw = field, true
+		case onnxTensorDoubleData:
+			return protoFixed(typ, field, protowire.Fixed64Type, func(v uint64) {
+				values = append(values, math.Float64frombits(v))
+			})
+		case onnxTensorFloatData:
+			return protoFixed(typ, field, protowire.Fixed32Type, func(v uint64) {
+				values = append(values, float64(math.Float32frombits(uint32(v))))
+			})
+		case onnxTensorExternalData:
+			badField = fmt.Errorf("Can't decode ONNX tensor %q with external data", name)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, "", err
+	} else if badField != nil {
+		return nil, "", badField
+	} else if len(shape) == 0 {
+		return nil, "", fmt.Errorf("Can't convert scalar ONNX tensor %q to an array", name)
+	} else if dtype != onnxDouble && dtype != onnxFloat {
+		return nil, "", fmt.Errorf("Can't convert ONNX tensor %q of data type %d to an array", name, dtype)
+	}
+
+	if hasRaw {
+		values = nil
+		if dtype == onnxDouble {
+			if len(raw)%8 != 0 {
+				return nil, "", fmt.Errorf("ONNX tensor %q has %d bytes of raw DOUBLE data", name, len(raw))
+			}
+			for idx := 0; idx < len(raw); idx += 8 {
+				values = append(values, math.Float64frombits(binary.LittleEndian.Uint64(raw[idx:])))
+			}
+		} else {
+			if len(raw)%4 != 0 {
+				return nil, "", fmt.Errorf("ONNX tensor %q has %d bytes of raw FLOAT data", name, len(raw))
+			}
+			for idx := 0; idx < len(raw); idx += 4 {
+				values = append(values, float64(math.Float32frombits(binary.LittleEndian.Uint32(raw[idx:]))))
+			}
+		}
+	}
+
+	size := 1
+	for _, dim := range shape {
+		if dim < 0 {
+			return nil, "", fmt.Errorf("ONNX tensor %q has invalid shape %v", name, shape)
+		}
+		size *= dim
+	}
+	if len(values) != size {
+		return nil, "", fmt.Errorf("ONNX tensor %q of shape %v has %d elements", name, shape, len(values))
+	}
+	return &denseF64Array{
+		shape: shape,
+		array: values,
+	}, name, nil
+}
+
+// Encode a set of named arrays as ONNX graph initializers, and append them to
+// a serialized ONNX ModelProto. By protobuf merge semantics the tensors are
+// added to the initializers already in the model's graph, so model may be nil
+// to produce a model holding only the initializers. Initializers are written
+// in order of name, so the output is deterministic.
+func AddONNXInitializers(model []byte, arrays map[string]NDArray) []byte {
+	var graph []byte
+	for _, name := range sortedKeys(arrays) {
+		graph = protowire.AppendTag(graph, onnxGraphInitializer, protowire.BytesType)
+		graph = protowire.AppendBytes(graph, ToONNX(arrays[name], name))
+	}
+	model = protowire.AppendTag(model, onnxModelGraph, protowire.BytesType)
+	return protowire.AppendBytes(model, graph)
+}
+
+// Decode the initializers of the graph in a serialized ONNX ModelProto,
+// returning them as arrays keyed by name.
+func ONNXInitializers(model []byte) (map[string]NDArray, error) {
+	arrays := make(map[string]NDArray)
+	err := protoFields(model, func(num protowire.Number, typ protowire.Type, graph []byte) error {
+		if num != onnxModelGraph || typ != protowire.BytesType {
+			return nil
+		}
+		return protoFields(graph, func(num protowire.Number, typ protowire.Type, tensor []byte) error {
+			if num != onnxGraphInitializer || typ != protowire.BytesType {
+				return nil
+			}
+			array, name, err := FromONNX(tensor)
+			if err != nil {
+				return err
+			}
+			arrays[name] = array
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return arrays, nil
+}
+
+// Get the keys of a map of arrays in sorted order
+func sortedKeys(arrays map[string]NDArray) []string {
+	keys := make([]string, 0, len(arrays))
+	for key := range arrays {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,91 @@
+package matrix
+
+import (
+	. "github.com/smartystreets/goconvey/convey"
+	"google.golang.org/protobuf/encoding/protowire"
+	"math"
+	"testing"
+)
+
+func TestONNX(t *testing.T) {
+	Convey("Given a matrix", t, func() {
+		m := M(2, 3,
+			1, 2, 3,
+			4, 5, 6)
+
+		Convey("ToONNX and FromONNX round trip it", func() {
+			a, name, err := FromONNX(ToONNX(m, "weights"))
+			So(err, ShouldBeNil)
+			So(name, ShouldEqual, "weights")
+			So(a.Shape(), ShouldResemble, []int{2, 3})
+			So(a.Array(), ShouldResemble, m.Array())
+		})
+
+		Convey("Transposed and sparse matrices round trip", func() {
+			a, _, err := FromONNX(ToONNX(m.T(), ""))
+			So(err, ShouldBeNil)
+			So(a.Shape(), ShouldResemble, []int{3, 2})
+			So(a.Array(), ShouldResemble, []float64{1, 4, 2, 5, 3, 6})
+
+			a, _, err = FromONNX(ToONNX(Diag(1, 2), "d"))
+			So(err, ShouldBeNil)
+			So(a.Array(), ShouldResemble, []float64{1, 0, 0, 2})
+		})
+
+		Convey("Initializers are added to a model", func() {
+			model := AddONNXInitializers(nil, map[string]NDArray{"w": m})
+			model = AddONNXInitializers(model, map[string]NDArray{
+				"b": A1(7, 8),
+			})
+			arrays, err := ONNXInitializers(model)
+			So(err, ShouldBeNil)
+			So(len(arrays), ShouldEqual, 2)
+			So(arrays["w"].Array(), ShouldResemble, m.Array())
+			So(arrays["b"].Shape(), ShouldResemble, []int{2})
+			So(arrays["b"].Array(), ShouldResemble, []float64{7, 8})
+		})
+	})
+
+	Convey("Given tensors encoded by other writers", t, func() {
+		tensor := func(dtype uint64, dataField protowire.Number, data []byte) []byte {
+			var msg []byte
+			msg = protowire.AppendTag(msg, 1, protowire.VarintType)
+			msg = protowire.AppendVarint(msg, 2)
+			msg = protowire.AppendTag(msg, 2, protowire.VarintType)
+			msg = protowire.AppendVarint(msg, dtype)
+			msg = protowire.AppendTag(msg, dataField, protowire.BytesType)
+			return protowire.AppendBytes(msg, data)
+		}
+
+		Convey("FLOAT data is converted", func() {
+			var data []byte
+			data = protowire.AppendFixed32(data, math.Float32bits(1.5))
+			data = protowire.AppendFixed32(data, math.Float32bits(-2))
+			a, _, err := FromONNX(tensor(1, 4, data))
+			So(err, ShouldBeNil)
+			So(a.Array(), ShouldResemble, []float64{1.5, -2})
+
+			a, _, err = FromONNX(tensor(1, 9, data))
+			So(err, ShouldBeNil)
+			So(a.Array(), ShouldResemble, []float64{1.5, -2})
+		})
+
+		Convey("DOUBLE data is read from double_data", func() {
+			var data []byte
+			data = protowire.AppendFixed64(data, math.Float64bits(3))
+			data = protowire.AppendFixed64(data, math.Float64bits(4))
+			a, _, err := FromONNX(tensor(11, 10, data))
+			So(err, ShouldBeNil)
+			So(a.Array(), ShouldResemble, []float64{3, 4})
+		})
+
+		Convey("Unsupported or malformed tensors are an error", func() {
+			_, _, err := FromONNX(tensor(7, 9, make([]byte, 16)))
+			So(err, ShouldNotBeNil)
+			_, _, err = FromONNX(tensor(11, 9, make([]byte, 8)))
+			So(err, ShouldNotBeNil)
+			_, _, err = FromONNX([]byte{0xff})
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
@@ -0,0 +1,230 @@
+package matrix
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// The precision of the values in an array
+type DType int
+
+const (
+	// Double precision: the precision arrays store
+	Float64 DType = iota
+
+	// Single precision. Arrays still store float64 values, but values set by
+	// NewMatrix() are rounded to float32 precision, to match computations done
+	// elsewhere in single precision.
+	Float32
+)
+
+// The distribution of random values chosen by WithRandom()
+type RandDist int
+
+const (
+	// Uniformly distributed in [0, 1)
+	Uniform RandDist = iota
+
+	// Distributed on the standard normal distribution
+	Normal
+)
+
+// An Option configures the matrix created by NewMatrix()
+type Option func(*matrixOptions)
+
+// The configuration built up by a list of options
+type matrixOptions struct {
+	storage  ArraySparsity
+	layout   Order
+	capacity int
+	dtype    DType
+	values   []float64
+	rng      *rand.Rand
+	random   bool
+	dist     RandDist
+	density  float64
+}
+
+// Store the matrix in the given format: DenseArray (the default),
+// SparseCooMatrix, SparseDiagMatrix, SparseCSRMatrix or SparseCSCMatrix.
+// Compressed matrices are filled in coo format and then converted.
+func WithStorage(storage ArraySparsity) Option {
+	return func(o *matrixOptions) { o.storage = storage }
+}
+
+// Store a dense matrix in the given order; RowMajor by default. Ignored for
+// sparse matrices.
+func WithLayout(layout Order) Option {
+	return func(o *matrixOptions) { o.layout = layout }
+}
+
+// Reserve space for about nnz nonzero elements in a sparse coo matrix.
+// Ignored for other formats.
+func WithCapacity(nnz int) Option {
+	return func(o *matrixOptions) { o.capacity = nnz }
+}
+
+// Round the initial values to the precision of dtype
+func WithDType(dtype DType) Option {
+	return func(o *matrixOptions) { o.dtype = dtype }
+}
+
+// Initialize the matrix from values, listed in row-major order. Zero values
+// are not stored in sparse matrices, and sparse diagonal matrices can only be
+// given nonzero values on the diagonal.
+func WithValues(values ...float64) Option {
+	return func(o *matrixOptions) { o.values = values }
+}
+
+// Use src as the source of random numbers for WithRandom(), in place of the
+// global source. Seed it to create reproducible random matrices.
+func WithRand(src rand.Source) Option {
+	return func(o *matrixOptions) { o.rng = rand.New(src) }
+}
+
+// Initialize the matrix with random values from the given distribution.
+// With a density of 1, every element is set; with a smaller density,
+// approximately that fraction of elements is set and the rest are zero. A
+// sparse diagonal matrix only sets elements on its diagonal.
+func WithRandom(dist RandDist, density float64) Option {
+	return func(o *matrixOptions) {
+		o.random = true
+		o.dist = dist
+		o.density = density
+	}
+}
+
+// Create a matrix configured by options. With no options, this is a dense
+// zero matrix, the same as Dense(rows, cols).M(). For example, a
+// reproducible random sparse matrix:
+//
+//	m := NewMatrix(1000, 1000,
+//	    WithStorage(SparseCooMatrix),
+//	    WithRand(rand.NewSource(42)),
+//	    WithRandom(Normal, 0.01))
+func NewMatrix(rows, cols int, opts ...Option) Matrix {
+	o := matrixOptions{storage: DenseArray, layout: RowMajor, dtype: Float64}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.values != nil && o.random {
+		panic("Can't use both WithValues() and WithRandom() for a matrix")
+	}
+	if o.values != nil && len(o.values) != rows*cols {
+		panic(ErrShapeMismatch{Op: "NewMatrix", Got: []int{len(o.values)}, Want: []int{rows * cols}})
+	}
+	if o.random && (o.density < 0 || o.density > 1) {
+		panic(fmt.Sprintf("Can't create a random matrix: density %f should be in [0, 1]", o.density))
+	}
+
+	var m Matrix
+	switch o.storage {
+	case DenseArray:
+		m = DenseOrder(o.layout, rows, cols)
+	case SparseCooMatrix, SparseCSRMatrix, SparseCSCMatrix:
+		coo := &sparseCooF64Matrix{
+			shape:  []int{rows, cols},
+			values: make([]map[int]float64, rows),
+		}
+		perRow := 0
+		if rows > 0 {
+			perRow = o.capacity / rows
+		}
+		for i := range coo.values {
+			coo.values[i] = make(map[int]float64, perRow)
+		}
+		m = coo
+	case SparseDiagMatrix:
+		m = SparseDiag(rows, cols)
+	default:
+		panic(fmt.Sprintf("Unknown matrix storage %v", o.storage))
+	}
+
+	if o.values != nil {
+		for idx, v := range o.values {
+			if v != 0 {
+				m.ItemSet(o.round(v), idx/cols, idx%cols)
+			}
+		}
+	} else if o.random {
+		o.fillRandom(m)
+	}
+	switch o.storage {
+	case SparseCSRMatrix:
+		return m.SparseCSR()
+	case SparseCSCMatrix:
+		return m.SparseCSC()
+	}
+	return m
+}
+
+// Round a value to the configured precision
+func (o *matrixOptions) round(v float64) float64 {
+	if o.dtype == Float32 {
+		return float64(float32(v))
+	}
+	return v
+}
+
+// Get a random value from the configured source and distribution
+func (o *matrixOptions) randValue() float64 {
+	var v float64
+	switch {
+	case o.dist == Normal && o.rng != nil:
+		v = o.rng.NormFloat64()
+	case o.dist == Normal:
+		v = rand.NormFloat64()
+	case o.rng != nil:
+		v = o.rng.Float64()
+	default:
+		v = rand.Float64()
+	}
+	return o.round(v)
+}
+
+// Get a random position in [0, n)
+func (o *matrixOptions) randPos(n int) int {
+	if o.rng != nil {
+		return o.rng.Intn(n)
+	}
+	return rand.Intn(n)
+}
+
+// Set random values at approximately density * size positions of m
+func (o *matrixOptions) fillRandom(m Matrix) {
+	rows, cols := m.Rows(), m.Cols()
+	size := rows * cols
+	coord := func(pos int) (int, int) { return pos / cols, pos % cols }
+	if m.Sparsity() == SparseDiagMatrix {
+		size = rows
+		if cols < rows {
+			size = cols
+		}
+		coord = func(pos int) (int, int) { return pos, pos }
+	}
+
+	if o.density == 1 {
+		for pos := 0; pos < size; pos++ {
+			row, col := coord(pos)
+			m.ItemSet(o.randValue(), row, col)
+		}
+		return
+	}
+
+	// Choose empty positions at random. This is slow if density is close to
+	// 1, since most of the positions tried at the end are already full.
+	count := int(float64(size) * o.density)
+	for i := 0; i < count; i++ {
+		for {
+			row, col := coord(o.randPos(size))
+			if m.Item(row, col) == 0 {
+				v := o.randValue()
+				for v == 0 {
+					v = o.randValue()
+				}
+				m.ItemSet(v, row, col)
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package matrix
+
+import (
+	"errors"
+	. "github.com/smartystreets/goconvey/convey"
+	"math/rand"
+	"testing"
+)
+
+func TestNewMatrix(t *testing.T) {
+	Convey("Given no options, NewMatrix creates a dense zero matrix", t, func() {
+		m := NewMatrix(2, 3)
+		So(m.Sparsity(), ShouldEqual, DenseArray)
+		So(m.Equal(Dense(2, 3)), ShouldBeTrue)
+	})
+
+	Convey("Given values, NewMatrix stores them in the chosen format", t, func() {
+		values := []float64{1, 0, 0, 2}
+		for _, sp := range []ArraySparsity{DenseArray, SparseCooMatrix, SparseDiagMatrix} {
+			m := NewMatrix(2, 2, WithStorage(sp), WithValues(values...), WithCapacity(2))
+			So(m.Sparsity(), ShouldEqual, sp)
+			So(m.Equal(M(2, 2, values...)), ShouldBeTrue)
+		}
+		So(func() { NewMatrix(2, 2, WithValues(1, 2)) }, ShouldPanic)
+		var sparseErr ErrNotSparse
+		So(errors.As(try(func() {
+			NewMatrix(2, 2, WithStorage(SparseDiagMatrix), WithValues(1, 2, 0, 1))
+		}), &sparseErr), ShouldBeTrue)
+	})
+
+	Convey("Given a layout, NewMatrix stores dense values in that order", t, func() {
+		m := NewMatrix(2, 3, WithLayout(ColMajor), WithValues(1, 2, 3, 4, 5, 6))
+		values, order, ok := RawOrder(m)
+		So(ok, ShouldBeTrue)
+		So(order, ShouldEqual, ColMajor)
+		So(values, ShouldResemble, []float64{1, 4, 2, 5, 3, 6})
+	})
+
+	Convey("Given Float32, values are rounded to single precision", t, func() {
+		m := NewMatrix(1, 2, WithDType(Float32), WithValues(0.1, 1))
+		So(m.Item(0, 0), ShouldEqual, float64(float32(0.1)))
+		So(m.Item(0, 0), ShouldNotEqual, 0.1)
+		So(m.Item(0, 1), ShouldEqual, 1)
+	})
+
+	Convey("Given a random source, random matrices are reproducible", t, func() {
+		newRand := func() Matrix {
+			return NewMatrix(20, 20, WithStorage(SparseCooMatrix),
+				WithRand(rand.NewSource(7)), WithRandom(Normal, 0.1))
+		}
+		a, b := newRand(), newRand()
+		So(a.Equal(b), ShouldBeTrue)
+		So(a.CountNonzero(), ShouldEqual, 40)
+
+		u := NewMatrix(5, 5, WithRand(rand.NewSource(1)), WithRandom(Uniform, 1))
+		So(u.AllF(func(v float64) bool { return v >= 0 && v < 1 }), ShouldBeTrue)
+		So(u.CountNonzero(), ShouldEqual, 25)
+
+		d := NewMatrix(4, 6, WithStorage(SparseDiagMatrix), WithRandom(Uniform, 1))
+		So(d.Sparsity(), ShouldEqual, SparseDiagMatrix)
+		So(func() { NewMatrix(2, 2, WithRandom(Uniform, 1.5)) }, ShouldPanic)
+		So(func() { NewMatrix(2, 2, WithRandom(Uniform, 1), WithValues(1, 2, 3, 4)) }, ShouldPanic)
+	})
+}
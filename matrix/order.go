@@ -0,0 +1,86 @@
+package matrix
+
+import (
+	"fmt"
+)
+
+// The order in which a dense matrix stores its values in memory
+type Order int
+
+const (
+	// Row-major ('C') order: the values of each row are contiguous. This is
+	// the order used by C and by NumPy by default.
+	RowMajor Order = iota
+
+	// Column-major ('F', or Fortran) order: the values of each column are
+	// contiguous. This is the order used by LAPACK, Julia and R.
+	ColMajor
+)
+
+// Create a dense matrix which stores its values in the given order. The
+// values are copied from array, which lists them in that same order.
+func MOrder(order Order, rows, cols int, array ...float64) Matrix {
+	if len(array) != rows*cols {
+		panic(ErrShapeMismatch{Op: "MOrder", Got: []int{len(array)}, Want: []int{rows * cols}})
+	}
+	values := make([]float64, len(array))
+	copy(values, array)
+	return WrapOrder(order, rows, cols, values)
+}
+
+// Create a dense zero matrix which stores its values in the given order
+func DenseOrder(order Order, rows, cols int) Matrix {
+	return WrapOrder(order, rows, cols, make([]float64, rows*cols))
+}
+
+// Create a dense matrix which uses values, listed in the given order, as its
+// storage without copying. Changes to the matrix are visible in values, and
+// vice versa. Use this to share memory with LAPACK, Julia or R via ColMajor.
+func WrapOrder(order Order, rows, cols int, values []float64) Matrix {
+	if len(values) != rows*cols {
+		panic(ErrShapeMismatch{Op: "WrapOrder", Got: []int{len(values)}, Want: []int{rows * cols}})
+	}
+	switch order {
+	case RowMajor:
+		return &denseF64Array{
+			shape: []int{rows, cols},
+			array: values,
+		}
+	case ColMajor:
+		// Column-major storage is the row-major storage of the transpose
+		return &denseF64Array{
+			shape:     []int{rows, cols},
+			array:     values,
+			transpose: true,
+		}
+	default:
+		panic(fmt.Sprintf("Unknown matrix order %d", order))
+	}
+}
+
+// Get the storage of a dense matrix without copying, along with the order of
+// its values. The values may be modified to change the matrix. Returns false
+// if m is not dense.
+func RawOrder(m Matrix) (values []float64, order Order, ok bool) {
+	dense, ok := denseStorage(m)
+	if !ok {
+		return nil, RowMajor, false
+	} else if dense.transpose {
+		return dense.array, ColMajor, true
+	}
+	return dense.array, RowMajor, true
+}
+
+// Get a dense matrix which stores its values in the given order. Returns m
+// itself if it already does; otherwise, returns a copy.
+func AsOrder(m Matrix, order Order) Matrix {
+	if _, current, ok := RawOrder(m); ok && current == order {
+		return m
+	}
+	result := DenseOrder(order, m.Rows(), m.Cols())
+	m.VisitNonzero(func(pos []int, value float64) bool {
+		result.ItemSet(value, pos[0], pos[1])
+		return true
+	})
+	return result
+}
@@ -0,0 +1,89 @@
+package matrix
+
+import (
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+func TestOrder(t *testing.T) {
+	Convey("Given column-major values", t, func() {
+		values := []float64{
+			1, 4,
+			2, 5,
+			3, 6,
+		}
+		rowMajor := M(2, 3,
+			1, 2, 3,
+			4, 5, 6)
+
+		Convey("MOrder interprets them in column-major order", func() {
+			m := MOrder(ColMajor, 2, 3, values...)
+			So(m.Equal(rowMajor), ShouldBeTrue)
+			So(m.Array(), ShouldResemble, rowMajor.Array())
+			So(m.Row(1), ShouldResemble, []float64{4, 5, 6})
+			So(m.Col(2), ShouldResemble, []float64{3, 6})
+
+			values[0] = 42
+			So(m.Item(0, 0), ShouldEqual, 1)
+			So(MOrder(RowMajor, 2, 3, 1, 2, 3, 4, 5, 6).Equal(rowMajor), ShouldBeTrue)
+		})
+
+		Convey("WrapOrder shares their storage", func() {
+			m := WrapOrder(ColMajor, 2, 3, values)
+			m.ItemSet(42, 1, 0)
+			So(values[1], ShouldEqual, 42)
+			values[5] = -1
+			So(m.Item(1, 2), ShouldEqual, -1)
+
+			raw, order, ok := RawOrder(m)
+			So(ok, ShouldBeTrue)
+			So(order, ShouldEqual, ColMajor)
+			So(&raw[0], ShouldEqual, &values[0])
+		})
+
+		Convey("Column-major matrices work with other operations", func() {
+			m := WrapOrder(ColMajor, 2, 3, values)
+			So(m.MProd(Eye(3)).Equal(rowMajor), ShouldBeTrue)
+			So(m.T().Equal(rowMajor.T()), ShouldBeTrue)
+			So(m.Add(rowMajor).Equal(rowMajor.ItemProd(2)), ShouldBeTrue)
+			So(m.Sum(), ShouldEqual, 21)
+			So(m.Slice([]int{0, 1}, []int{2, 3}).Equal(M(2, 2, 2, 3, 5, 6)), ShouldBeTrue)
+			So(ToMatrix(AsMat(m)).Equal(rowMajor), ShouldBeTrue)
+		})
+
+		Convey("Item doesn't modify its index argument", func() {
+			m := WrapOrder(ColMajor, 2, 3, values)
+			index := []int{0, 2}
+			So(m.Item(index...), ShouldEqual, 3)
+			m.ItemSet(7, index...)
+			So(index, ShouldResemble, []int{0, 2})
+		})
+
+		Convey("AsOrder converts between orders", func() {
+			m := AsOrder(rowMajor, ColMajor)
+			raw, order, ok := RawOrder(m)
+			So(ok, ShouldBeTrue)
+			So(order, ShouldEqual, ColMajor)
+			So(raw, ShouldResemble, values)
+			So(AsOrder(m, ColMajor), ShouldEqual, m)
+
+			raw, order, _ = RawOrder(AsOrder(m, RowMajor))
+			So(order, ShouldEqual, RowMajor)
+			So(raw, ShouldResemble, rowMajor.Array())
+
+			raw, order, _ = RawOrder(AsOrder(Diag(1, 2), ColMajor))
+			So(order, ShouldEqual, ColMajor)
+			So(raw, ShouldResemble, []float64{1, 0, 0, 2})
+			_, _, ok = RawOrder(Diag(1, 2))
+			So(ok, ShouldBeFalse)
+		})
+
+		Convey("Invalid shapes panic", func() {
+			So(try(func() { MOrder(ColMajor, 2, 2, values...) }), ShouldResemble,
+				ErrShapeMismatch{Op: "MOrder", Got: []int{6}, Want: []int{4}})
+			So(try(func() { WrapOrder(RowMajor, 3, 3, values) }), ShouldResemble,
+				ErrShapeMismatch{Op: "WrapOrder", Got: []int{6}, Want: []int{9}})
+			So(func() { WrapOrder(Order(7), 2, 3, values) }, ShouldPanic)
+		})
+	})
+}
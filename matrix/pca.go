@@ -0,0 +1,80 @@
+package matrix
+
+import (
+	"fmt"
+	"gonum.org/v1/gonum/mat"
+	"math"
+)
+
+// Find the k principal components of the rows of m, which are the directions
+// of greatest variance. Each row of m is an observation and each column a
+// variable. Returns:
+//
+//   - components, a k x m.Cols() matrix whose rows are the unit-length
+//     principal axes, in order of decreasing variance. Each axis is signed so
+//     that its largest element is positive.
+//   - explained, the fraction of the total variance along each axis
+//   - transform, which projects the rows of a matrix with m.Cols() columns
+//     onto the axes, after centering them with the column means of m. The
+//     result has k columns.
+//
+// The components are found from the singular value decomposition of the
+// centered data, which is more accurate than using its covariance matrix.
+func PCA(m Matrix, k int) (components Matrix, explained []float64, transform func(Matrix) Matrix) {
+	debugCheck("PCA", m)
+	rows, cols := m.Rows(), m.Cols()
+	if k < 1 || k > min(rows, cols) {
+		panic(fmt.Sprintf("PCA: can't find %d components of a %dx%d matrix", k, rows, cols))
+	}
+
+	acc := axisWelford(m, 0)
+	means := make([]float64, cols)
+	for col := range acc {
+		means[col] = acc[col].mean
+	}
+	centered := Sub(m, RowBroadcast(means, rows)).M()
+
+	var svd mat.SVD
+	if !svd.Factorize(ToMat(centered), mat.SVDThin) {
+		panic("PCA: the singular value decomposition failed to converge")
+	}
+	values := svd.Values(nil)
+	var v mat.Dense
+	svd.VTo(&v)
+
+	var total float64
+	for _, s := range values {
+		total += s * s
+	}
+	components = Dense(k, cols).M()
+	explained = make([]float64, k)
+	for i := 0; i < k; i++ {
+		axis := mat.Col(nil, i, &v)
+		if axis[argMaxVec(absVec(axis))] < 0 {
+			for j := range axis {
+				axis[j] = -axis[j]
+			}
+		}
+		components.RowSet(i, axis)
+		if total > 0 {
+			explained[i] = values[i] * values[i] / total
+		}
+	}
+
+	transform = func(x Matrix) Matrix {
+		if x.Cols() != cols {
+			panic(ErrShapeMismatch{Op: "PCA transform", Got: x.Shape(), Want: []int{-1, cols}})
+		}
+		return Sub(x, RowBroadcast(means, x.Rows())).M().MProd(components.T())
+	}
+	return components, explained, transform
+}
+
+// Get the absolute values of a vector
+func absVec(vec []float64) []float64 {
+	result := make([]float64, len(vec))
+	for i, v := range vec {
+		result[i] = math.Abs(v)
+	}
+	return result
+}
@@ -0,0 +1,45 @@
+package matrix
+
+import (
+	. "github.com/smartystreets/goconvey/convey"
+	"math"
+	"testing"
+)
+
+func TestPCA(t *testing.T) {
+	Convey("Given points spread along a line", t, func() {
+		// Points along (1, 1) with a little noise along (1, -1)
+		m := M(4, 2,
+			0, 0.1,
+			1.1, 1,
+			2, 2.1,
+			3.1, 3)
+
+		Convey("The first component follows the line", func() {
+			components, explained, _ := PCA(m, 2)
+			So(components.Shape(), ShouldResemble, []int{2, 2})
+			So(components.Item(0, 0), ShouldAlmostEqual, math.Sqrt(0.5), 0.02)
+			So(components.Item(0, 1), ShouldAlmostEqual, math.Sqrt(0.5), 0.02)
+			So(explained[0], ShouldBeGreaterThan, 0.99)
+			So(explained[0]+explained[1], ShouldAlmostEqual, 1, 1e-12)
+			So(ApproxEqual(components.MProd(components.T()), Eye(2), 1e-12, 0), ShouldBeTrue)
+		})
+
+		Convey("The transform projects centered rows onto the components", func() {
+			components, _, transform := PCA(m, 1)
+			scores := transform(m)
+			So(scores.Shape(), ShouldResemble, []int{4, 1})
+			So(scores.Sum(), ShouldAlmostEqual, 0, 1e-12)
+			So(scores.Item(3, 0), ShouldBeGreaterThan, scores.Item(0, 0))
+			point := transform(M(1, 2, 1.55, 1.55))
+			So(point.Item(0, 0), ShouldAlmostEqual, 0, 1e-12)
+			So(components.Rows(), ShouldEqual, 1)
+			So(func() { transform(M(1, 3, 1, 2, 3)) }, ShouldPanic)
+		})
+
+		Convey("Invalid numbers of components panic", func() {
+			So(func() { PCA(m, 0) }, ShouldPanic)
+			So(func() { PCA(m, 3) }, ShouldPanic)
+		})
+	})
+}
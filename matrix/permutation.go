@@ -0,0 +1,230 @@
+package matrix
+
+// A Permutation is an n x n permutation matrix, stored as the vector of
+// indices perm such that row i has its one in column perm[i]. Multiplying by
+// a permutation reorders rows or columns in time proportional to the size of
+// the other matrix, and permutations invert and compose without arithmetic:
+//
+//	p := NewPermutation([]int{2, 0, 1})
+//	b := p.MProd(a)           // PermuteRows(a, []int{2, 0, 1})
+//	c := MProd(p, a, p.T())   // P A P'
+//
+// Permutations are read-only: methods which would modify one panic with an
+// error wrapping ErrFrozen, as for Freeze(). Methods without a faster
+// implementation act on a sparse coo copy, which is created when first
+// needed.
+type Permutation struct {
+	lazyMatrix
+	perm, inv []int
+}
+
+// Create the permutation matrix whose row i has a one in column perm[i].
+// Panics unless perm is a permutation of 0, ..., len(perm)-1.
+func NewPermutation(perm []int) *Permutation {
+	inv := invertPermutation("NewPermutation", perm, len(perm))
+	return newPermutation(append([]int(nil), perm...), inv)
+}
+
+// Create a permutation from valid index vectors, without copying them
+func newPermutation(perm, inv []int) *Permutation {
+	p := &Permutation{perm: perm, inv: inv}
+	p.build = func() Matrix {
+		m := SparseCoo(len(perm), len(perm))
+		for i, j := range perm {
+			m.ItemSet(1, i, j)
+		}
+		return m
+	}
+	return p
+}
+
+// Get a copy of the permutation's index vector
+func (p *Permutation) Indices() []int {
+	return append([]int(nil), p.perm...)
+}
+
+// Get the inverse permutation, which is also the transpose
+func (p *Permutation) Invert() *Permutation {
+	return newPermutation(p.inv, p.perm)
+}
+
+// Get the permutation matrix p q, which applies q and then p to the rows of
+// a matrix
+func (p *Permutation) Compose(q *Permutation) *Permutation {
+	if len(p.perm) != len(q.perm) {
+		panic(ErrShapeMismatch{Op: "Compose", Got: q.Shape(), Want: p.Shape()})
+	}
+	perm := make([]int, len(p.perm))
+	inv := make([]int, len(p.perm))
+	for i, pi := range p.perm {
+		perm[i] = q.perm[pi]
+		inv[perm[i]] = i
+	}
+	return newPermutation(perm, inv)
+}
+
+// Get a copy of a column, which has a single one
+func (p *Permutation) Col(col int) []float64 {
+	if col < 0 || col >= len(p.perm) {
+		panic(ErrIndexOutOfRange{Op: "Col", Index: []int{col}, Shape: []int{len(p.perm)}})
+	}
+	result := make([]float64, len(p.perm))
+	result[p.inv[col]] = 1
+	return result
+}
+
+// Get the number of columns
+func (p *Permutation) Cols() int {
+	return len(p.perm)
+}
+
+// Counts the nonzero elements, which is one per row
+func (p *Permutation) CountNonzero() int {
+	return len(p.perm)
+}
+
+// Get the determinant, which is the sign of the permutation: 1 if it is made
+// of an even number of swaps, and -1 otherwise
+func (p *Permutation) Det() float64 {
+	det := 1.0
+	seen := make([]bool, len(p.perm))
+	for i := range p.perm {
+		if seen[i] {
+			continue
+		}
+		seen[i] = true
+		for j := p.perm[i]; j != i; j = p.perm[j] {
+			seen[j] = true
+			det = -det
+		}
+	}
+	return det
+}
+
+// Get an array element in a flattened version of this array
+func (p *Permutation) FlatItem(index int) float64 {
+	n := len(p.perm)
+	if index < 0 || index >= n*n {
+		panic(ErrIndexOutOfRange{Op: "FlatItem", Index: []int{index}, Shape: []int{n * n}})
+	}
+	return p.Item(index/n, index%n)
+}
+
+// Get the inverse permutation, which is also the transpose. Never fails.
+func (p *Permutation) Inverse() (Matrix, error) {
+	return p.Invert(), nil
+}
+
+// Get an array element
+func (p *Permutation) Item(index ...int) float64 {
+	n := len(p.perm)
+	if len(index) != 2 || index[0] < 0 || index[0] >= n || index[1] < 0 || index[1] >= n {
+		panic(ErrIndexOutOfRange{Op: "Item", Index: index, Shape: p.Shape()})
+	}
+	if p.perm[index[0]] == index[1] {
+		return 1
+	}
+	return 0
+}
+
+// Solve for x, where px = b, by applying the inverse permutation to b
+func (p *Permutation) LDivide(b Matrix) Matrix {
+	if b.Rows() != len(p.perm) {
+		panic(ErrShapeMismatch{Op: "LDivide", Got: b.Shape(), Want: []int{len(p.perm), -1}})
+	}
+	return PermuteRows(b, p.inv)
+}
+
+// A permutation is already a matrix
+func (p *Permutation) M() Matrix {
+	return p
+}
+
+// Get the result of multiplying this permutation by other matrices. Products
+// with permutations on either side reorder the rows or columns of the other
+// matrix, and products of permutations are permutations.
+func (p *Permutation) MProd(others ...Matrix) Matrix {
+	return MProd(p, others...)
+}
+
+// Get the number of array dimensions
+func (p *Permutation) NDim() int {
+	return 2
+}
+
+// Get the pseudo-inverse, which is the inverse permutation. Never fails.
+func (p *Permutation) Pinv(tol float64) (Matrix, error) {
+	return p.Invert(), nil
+}
+
+// Get the rank, which is the size of the permutation
+func (p *Permutation) Rank(tol float64) int {
+	return len(p.perm)
+}
+
+// Get a copy of a row, which has a single one
+func (p *Permutation) Row(row int) []float64 {
+	if row < 0 || row >= len(p.perm) {
+		panic(ErrIndexOutOfRange{Op: "Row", Index: []int{row}, Shape: []int{len(p.perm)}})
+	}
+	result := make([]float64, len(p.perm))
+	result[p.perm[row]] = 1
+	return result
+}
+
+// Get the number of rows
+func (p *Permutation) Rows() int {
+	return len(p.perm)
+}
+
+// Get the array dimensions
+func (p *Permutation) Shape() []int {
+	return []int{len(p.perm), len(p.perm)}
+}
+
+// Get the number of elements
+func (p *Permutation) Size() int {
+	return len(p.perm) * len(p.perm)
+}
+
+// Permutations are sparse, and are converted to sparse coo matrices when an
+// operation needs their elements
+func (p *Permutation) Sparsity() ArraySparsity {
+	return SparseCooMatrix
+}
+
+// Get the sum of the elements, which is the number of rows
+func (p *Permutation) Sum() float64 {
+	return float64(len(p.perm))
+}
+
+// Get the transpose, which is the inverse permutation
+func (p *Permutation) T() Matrix {
+	return p.Invert()
+}
+
+// Get the sum of the diagonal, which is the number of fixed points
+func (p *Permutation) Trace() float64 {
+	var trace float64
+	for i, j := range p.perm {
+		if i == j {
+			trace++
+		}
+	}
+	return trace
+}
+
+// Get the permutation itself, since it can't be modified
+func (p *Permutation) View() NDArray {
+	return p
+}
+
+// Visit just the nonzero elements, in row order
+func (p *Permutation) VisitNonzero(f func(pos []int, value float64) bool) bool {
+	for i, j := range p.perm {
+		if !f([]int{i, j}, 1) {
+			return false
+		}
+	}
+	return true
+}
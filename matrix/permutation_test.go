@@ -0,0 +1,74 @@
+package matrix
+
+import (
+	"errors"
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+func TestPermutation(t *testing.T) {
+	Convey("Given a permutation", t, func() {
+		p := NewPermutation([]int{2, 0, 1})
+		dense := M(3, 3,
+			0, 0, 1,
+			1, 0, 0,
+			0, 1, 0)
+		a := M(3, 2, 1, 2, 3, 4, 5, 6)
+
+		Convey("It has the elements of a permutation matrix", func() {
+			So(p.Equal(dense), ShouldBeTrue)
+			So(dense.Equal(p), ShouldBeTrue)
+			So(p.Row(0), ShouldResemble, []float64{0, 0, 1})
+			So(p.Col(0), ShouldResemble, []float64{0, 1, 0})
+			So(p.FlatItem(5), ShouldEqual, 0)
+			So(p.FlatItem(2), ShouldEqual, 1)
+			So(p.CountNonzero(), ShouldEqual, 3)
+			So(p.Trace(), ShouldEqual, 0)
+			So(p.Indices(), ShouldResemble, []int{2, 0, 1})
+			So(CheckInvariants(p), ShouldBeNil)
+		})
+
+		Convey("Products reorder rows and columns", func() {
+			So(p.MProd(a).Equal(dense.MProd(a)), ShouldBeTrue)
+			So(p.MProd(a).Equal(PermuteRows(a, []int{2, 0, 1})), ShouldBeTrue)
+			So(a.T().MProd(p).Equal(a.T().MProd(dense)), ShouldBeTrue)
+			So(MProd(p, M(3, 3, 1, 2, 3, 4, 5, 6, 7, 8, 9), p.T()).Equal(
+				MProd(dense, M(3, 3, 1, 2, 3, 4, 5, 6, 7, 8, 9), dense.T())), ShouldBeTrue)
+			So(SparseCoo(2, 3, 1, 0, 2, 0, 3, 0).MProd(p).Equal(M(2, 3, 0, 2, 1, 3, 0, 0)), ShouldBeTrue)
+		})
+
+		Convey("Permutations invert and compose", func() {
+			inv, err := p.Inverse()
+			So(err, ShouldBeNil)
+			So(inv.Equal(dense.T()), ShouldBeTrue)
+			So(p.T().Equal(inv), ShouldBeTrue)
+
+			q := NewPermutation([]int{1, 0, 2})
+			pq := p.MProd(q)
+			_, ok := pq.(*Permutation)
+			So(ok, ShouldBeTrue)
+			So(pq.Equal(dense.MProd(q.Copy().M())), ShouldBeTrue)
+			So(p.Compose(p.Invert()).Equal(Eye(3)), ShouldBeTrue)
+			So(func() { p.Compose(NewPermutation([]int{0})) }, ShouldPanic)
+		})
+
+		Convey("LDivide applies the inverse", func() {
+			b := p.MProd(a)
+			So(p.LDivide(b).Equal(a), ShouldBeTrue)
+		})
+
+		Convey("Permutations can't be modified", func() {
+			So(errors.Is(TryItemSet(p, 1, 0, 0), ErrFrozen), ShouldBeTrue)
+			So(func() { p.SwapRows(0, 1) }, ShouldPanic)
+			c := p.Copy()
+			c.ItemSet(5, 0, 0)
+			So(p.Item(0, 0), ShouldEqual, 0)
+		})
+
+		Convey("Invalid permutations panic", func() {
+			So(func() { NewPermutation([]int{0, 0}) }, ShouldPanic)
+			So(func() { NewPermutation([]int{1, 2}) }, ShouldPanic)
+			So(func() { p.Item(3, 0) }, ShouldPanic)
+		})
+	})
+}
@@ -0,0 +1,55 @@
+package matrix
+
+import (
+	"fmt"
+)
+
+// Fit a polynomial of the given degree to the points (x[i], y[i]) by least
+// squares. The coefficients are listed from the highest power to the
+// constant term, as for PolyVal() and numpy.polyfit. Panics with ErrSingular
+// if the fit is not unique, such as when there are fewer distinct x values
+// than coefficients.
+func PolyFit(x, y []float64, degree int) []float64 {
+	if len(y) != len(x) {
+		panic(ErrShapeMismatch{Op: "PolyFit", Got: []int{len(y)}, Want: []int{len(x)}})
+	}
+	return PolyFitCols(x, M(len(y), 1, y...), degree).Col(0)
+}
+
+// Fit a polynomial of the given degree to each column of m by least squares,
+// where row i of m is at x[i]. Returns a (degree+1) x m.Cols() matrix whose
+// columns hold the coefficients for each column of m, from the highest power
+// to the constant term. All the columns are fit together, with a single
+// factorization of the Vandermonde matrix of x.
+func PolyFitCols(x []float64, m Matrix, degree int) Matrix {
+	debugCheck("PolyFitCols", m)
+	if degree < 0 {
+		panic(fmt.Sprintf("PolyFit: can't fit a polynomial of degree %d", degree))
+	}
+	if m.Rows() != len(x) {
+		panic(ErrShapeMismatch{Op: "PolyFit", Got: m.Shape(), Want: []int{len(x), -1}})
+	}
+	coeffs, err := CurrentBackend().Solve(Vandermonde(x, degree, false), m)
+	if err != nil {
+		panic(err)
+	}
+	return coeffs
+}
+
+// Evaluate the polynomial with the given coefficients at each element of x,
+// returning a dense array of the same shape. The coefficients are listed
+// from the highest power to the constant term, as returned by PolyFit().
+func PolyVal(coeffs []float64, x NDArray) NDArray {
+	return x.Apply(func(v float64) float64 {
+		return polyVal(coeffs, v)
+	})
+}
+
+// Evaluate a polynomial at v by Horner's method
+func polyVal(coeffs []float64, v float64) float64 {
+	var result float64
+	for _, c := range coeffs {
+		result = result*v + c
+	}
+	return result
+}
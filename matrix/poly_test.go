@@ -0,0 +1,60 @@
+package matrix
+
+import (
+	"errors"
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+func TestPolyFit(t *testing.T) {
+	Convey("Given points on a quadratic", t, func() {
+		x := []float64{-2, -1, 0, 1, 2, 3}
+		y := make([]float64, len(x))
+		for i, v := range x {
+			y[i] = 2*v*v - 3*v + 1
+		}
+
+		Convey("PolyFit recovers the coefficients", func() {
+			coeffs := PolyFit(x, y, 2)
+			So(len(coeffs), ShouldEqual, 3)
+			So(coeffs[0], ShouldAlmostEqual, 2, 1e-10)
+			So(coeffs[1], ShouldAlmostEqual, -3, 1e-10)
+			So(coeffs[2], ShouldAlmostEqual, 1, 1e-10)
+		})
+
+		Convey("A lower degree gives the least squares fit", func() {
+			coeffs := PolyFit([]float64{0, 1, 2}, []float64{0, 2, 1}, 1)
+			So(coeffs[0], ShouldAlmostEqual, 0.5, 1e-10)
+			So(coeffs[1], ShouldAlmostEqual, 0.5, 1e-10)
+		})
+
+		Convey("PolyFitCols fits each column", func() {
+			m := Dense(len(x), 2).M()
+			m.ColSet(0, y)
+			for i, v := range x {
+				m.ItemSet(4*v-1, i, 1)
+			}
+			coeffs := PolyFitCols(x, m, 1)
+			So(coeffs.Shape(), ShouldResemble, []int{2, 2})
+			So(coeffs.Item(0, 1), ShouldAlmostEqual, 4, 1e-10)
+			So(coeffs.Item(1, 1), ShouldAlmostEqual, -1, 1e-10)
+		})
+
+		Convey("PolyVal evaluates elementwise", func() {
+			result := PolyVal([]float64{2, -3, 1}, M(2, 2, 0, 1, 2, 3))
+			So(result.Array(), ShouldResemble, []float64{1, 0, 3, 10})
+			So(PolyVal([]float64{5}, SparseCoo(1, 2)).Array(), ShouldResemble, []float64{5, 5})
+			So(PolyVal(nil, M(1, 1, 4)).Array(), ShouldResemble, []float64{0})
+		})
+
+		Convey("Fits which aren't unique panic with ErrSingular", func() {
+			err := try(func() { PolyFit([]float64{1, 1, 1}, []float64{1, 2, 3}, 2) })
+			So(errors.Is(err, ErrSingular), ShouldBeTrue)
+		})
+
+		Convey("Invalid arguments panic", func() {
+			So(func() { PolyFit(x, y[:3], 1) }, ShouldPanic)
+			So(func() { PolyFit(x, y, -1) }, ShouldPanic)
+		})
+	})
+}
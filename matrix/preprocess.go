@@ -0,0 +1,183 @@
+package matrix
+
+import (
+	"fmt"
+	"math"
+)
+
+// Get the vector norm of the specified ordinality of each vector along an
+// axis: one norm per column for axis 0, and one per row for axis 1. Only
+// nonzero elements are visited. Supported ordinalities are 1 (sum of absolute
+// values), 2 (Euclidean length), and +Inf (largest absolute value).
+func axisNorms(m Matrix, ord float64, axis int) []float64 {
+	var norms []float64
+	switch axis {
+	case 0:
+		norms = make([]float64, m.Cols())
+	case 1:
+		norms = make([]float64, m.Rows())
+	default:
+		panic(fmt.Sprintf("Invalid axis %d for a 2-dim matrix", axis))
+	}
+	if ord != 1 && ord != 2 && !math.IsInf(ord, 1) {
+		panic(fmt.Sprintf("Can't normalize using invalid norm %v", ord))
+	}
+	m.VisitNonzero(func(pos []int, value float64) bool {
+		idx := pos[1-axis]
+		switch {
+		case ord == 1:
+			norms[idx] += math.Abs(value)
+		case ord == 2:
+			norms[idx] += value * value
+		default:
+			norms[idx] = math.Max(norms[idx], math.Abs(value))
+		}
+		return true
+	})
+	if ord == 2 {
+		for idx, v := range norms {
+			norms[idx] = math.Sqrt(v)
+		}
+	}
+	return norms
+}
+
+// Divide each vector along an axis by its norm, modifying the matrix.
+// Vectors with zero norm are left unchanged.
+func normalizeAxisInPlace(m Matrix, ord float64, axis int) {
+	norms := axisNorms(m, ord, axis)
+	type entry struct {
+		row, col int
+		value    float64
+	}
+	var entries []entry
+	m.VisitNonzero(func(pos []int, value float64) bool {
+		entries = append(entries, entry{pos[0], pos[1], value})
+		return true
+	})
+	for _, e := range entries {
+		norm := norms[e.col]
+		if axis == 1 {
+			norm = norms[e.row]
+		}
+		if norm != 0 {
+			m.ItemSet(e.value/norm, e.row, e.col)
+		}
+	}
+}
+
+// Return a copy of the matrix with each row scaled to have unit norm of the
+// specified ordinality: 1 (L1), 2 (L2), or +Inf (max absolute value). Rows
+// which are entirely zero are left as zero. The result has the same
+// representation as the input.
+func NormalizeRows(m Matrix, ord float64) Matrix {
+	result := m.Copy().M()
+	normalizeAxisInPlace(result, ord, 1)
+	return result
+}
+
+// Scale each row of the matrix in place to have unit norm of the specified
+// ordinality. See NormalizeRows() for details.
+func NormalizeRowsInPlace(m Matrix, ord float64) {
+	normalizeAxisInPlace(m, ord, 1)
+}
+
+// Return a copy of the matrix with each column scaled to have unit norm of the
+// specified ordinality: 1 (L1), 2 (L2), or +Inf (max absolute value). Columns
+// which are entirely zero are left as zero. The result has the same
+// representation as the input.
+func NormalizeCols(m Matrix, ord float64) Matrix {
+	result := m.Copy().M()
+	normalizeAxisInPlace(result, ord, 0)
+	return result
+}
+
+// Scale each column of the matrix in place to have unit norm of the specified
+// ordinality. See NormalizeCols() for details.
+func NormalizeColsInPlace(m Matrix, ord float64) {
+	normalizeAxisInPlace(m, ord, 0)
+}
+
+// Standardize each column of a matrix to have mean zero and unit variance,
+// returning a dense result along with the per-column means and (population)
+// standard deviations used. Columns with zero variance are centered but not
+// scaled. Use ApplyStandardization() to apply the same transformation to new
+// data, e.g. a test set.
+func Standardize(m Matrix) (out Matrix, means, stds []float64) {
+	acc := axisWelford(m, 0)
+	means = make([]float64, len(acc))
+	stds = make([]float64, len(acc))
+	for col := range acc {
+		means[col] = acc[col].mean
+		stds[col] = math.Sqrt(acc[col].variance(0))
+	}
+	return ApplyStandardization(m, means, stds), means, stds
+}
+
+// Standardize each column of a matrix using previously computed means and
+// standard deviations, such as those returned by Standardize(). Returns a
+// dense matrix whose column j is (m[:, j] - means[j]) / stds[j]. Columns with a
+// standard deviation of zero are centered but not scaled.
+func ApplyStandardization(m Matrix, means, stds []float64) Matrix {
+	cols := m.Cols()
+	if len(means) != cols || len(stds) != cols {
+		panic(fmt.Sprintf("Can't standardize %d columns with %d means and %d stds", cols, len(means), len(stds)))
+	}
+	result := m.Dense().M()
+	for row := 0; row < result.Rows(); row++ {
+		for col := 0; col < cols; col++ {
+			value := result.Item(row, col) - means[col]
+			if stds[col] != 0 {
+				value /= stds[col]
+			}
+			result.ItemSet(value, row, col)
+		}
+	}
+	return result
+}
+
+// Scale each column of a matrix linearly so that its smallest value maps to lo
+// and its largest to hi, returning a dense result along with the per-column
+// minimums and maximums used. Constant columns map to lo. The minimums and
+// maximums of a matrix with no rows are NaN. Use ApplyMinMaxScale() to apply
+// the same transformation to new data.
+func MinMaxScale(m Matrix, lo, hi float64) (out Matrix, mins, maxs []float64) {
+	if lo >= hi {
+		panic(fmt.Sprintf("Can't MinMaxScale to invalid range [%v, %v]", lo, hi))
+	}
+	vecs := axisVectors(m, 0)
+	mins = make([]float64, len(vecs))
+	maxs = make([]float64, len(vecs))
+	for col, vec := range vecs {
+		if len(vec) == 0 {
+			mins[col], maxs[col] = math.NaN(), math.NaN()
+			continue
+		}
+		mins[col] = vec[argMinVec(vec)]
+		maxs[col] = vec[argMaxVec(vec)]
+	}
+	return ApplyMinMaxScale(m, mins, maxs, lo, hi), mins, maxs
+}
+
+// Scale each column of a matrix linearly using previously computed column
+// minimums and maximums, such as those returned by MinMaxScale(), so that
+// mins[j] maps to lo and maxs[j] maps to hi. Values outside the fitted range
+// map outside [lo, hi]. Columns with mins[j] == maxs[j] are shifted so that
+// mins[j] maps to lo, but are not scaled.
+func ApplyMinMaxScale(m Matrix, mins, maxs []float64, lo, hi float64) Matrix {
+	cols := m.Cols()
+	if len(mins) != cols || len(maxs) != cols {
+		panic(fmt.Sprintf("Can't scale %d columns with %d mins and %d maxs", cols, len(mins), len(maxs)))
+	}
+	result := m.Dense().M()
+	for row := 0; row < result.Rows(); row++ {
+		for col := 0; col < cols; col++ {
+			value := result.Item(row, col) - mins[col]
+			if span := maxs[col] - mins[col]; span != 0 {
+				value = value / span * (hi - lo)
+			}
+			result.ItemSet(lo+value, row, col)
+		}
+	}
+	return result
+}
@@ -0,0 +1,193 @@
+package matrix
+
+import (
+	. "github.com/smartystreets/goconvey/convey"
+	"math"
+	"testing"
+)
+
+func TestNormalizeRowsCols(t *testing.T) {
+	var inf = math.Inf(+1)
+
+	Convey("Given a dense matrix with a zero row", t, func() {
+		m := M(3, 2,
+			3, -4,
+			0, 0,
+			1, 1)
+
+		Convey("NormalizeRows works for L1, L2, and max norms", func() {
+			So(NormalizeRows(m, 1).Array(), ShouldResemble, []float64{
+				3. / 7, -4. / 7,
+				0, 0,
+				.5, .5,
+			})
+			So(NormalizeRows(m, 2).Array(), ShouldResemble, []float64{
+				.6, -.8,
+				0, 0,
+				1 / math.Sqrt(2), 1 / math.Sqrt(2),
+			})
+			So(NormalizeRows(m, inf).Array(), ShouldResemble, []float64{
+				.75, -1,
+				0, 0,
+				1, 1,
+			})
+		})
+
+		Convey("NormalizeCols works", func() {
+			So(NormalizeCols(m, 1).Array(), ShouldResemble, []float64{
+				.75, -.8,
+				0, 0,
+				.25, .2,
+			})
+		})
+
+		Convey("The original matrix is unchanged", func() {
+			NormalizeRows(m, 2)
+			So(m.Array(), ShouldResemble, []float64{3, -4, 0, 0, 1, 1})
+		})
+
+		Convey("The in-place variants modify the matrix", func() {
+			NormalizeRowsInPlace(m, inf)
+			So(m.Array(), ShouldResemble, []float64{.75, -1, 0, 0, 1, 1})
+			NormalizeColsInPlace(m, inf)
+			So(m.Array(), ShouldResemble, []float64{.75, -1, 0, 0, 1, 1})
+		})
+
+		Convey("Invalid norms panic", func() {
+			So(func() { NormalizeRows(m, 3) }, ShouldPanic)
+		})
+	})
+
+	Convey("Given a sparse coo matrix", t, func() {
+		m := SparseCoo(3, 3)
+		m.ItemSet(2, 0, 1)
+		m.ItemSet(-2, 0, 2)
+		m.ItemSet(5, 2, 2)
+
+		Convey("NormalizeRows keeps the sparse representation", func() {
+			n := NormalizeRows(m, 1)
+			So(n.Sparsity(), ShouldEqual, SparseCooMatrix)
+			So(n.Array(), ShouldResemble, []float64{
+				0, .5, -.5,
+				0, 0, 0,
+				0, 0, 1,
+			})
+		})
+
+		Convey("NormalizeCols works on a transpose", func() {
+			n := NormalizeCols(m.T(), inf)
+			So(n.Array(), ShouldResemble, []float64{
+				0, 0, 0,
+				1, 0, 0,
+				-1, 0, 1,
+			})
+		})
+	})
+
+	Convey("Given a sparse diagonal matrix", t, func() {
+		m := Diag(2, -3, 0)
+
+		Convey("NormalizeRows gives the signs", func() {
+			n := NormalizeRows(m, 2)
+			So(n.Sparsity(), ShouldEqual, SparseDiagMatrix)
+			So(n.Diag().Array(), ShouldResemble, []float64{1, -1, 0})
+		})
+	})
+}
+
+func TestStandardize(t *testing.T) {
+	Convey("Given a training matrix", t, func() {
+		m := M(4, 3,
+			1, 10, 5,
+			2, 20, 5,
+			3, 30, 5,
+			4, 40, 5)
+
+		Convey("Standardize gives zero mean and unit variance", func() {
+			out, means, stds := Standardize(m)
+			So(means, ShouldResemble, []float64{2.5, 25, 5})
+			So(stds[0], ShouldBeBetween, math.Sqrt(1.25)-Eps, math.Sqrt(1.25)+Eps)
+			So(stds[2], ShouldEqual, 0)
+			v := Var(out, 0, 0)
+			for col := 0; col < 2; col++ {
+				So(v[col], ShouldBeBetween, 1-Eps, 1+Eps)
+				So(A1(out.Col(col)...).Sum(), ShouldBeBetween, -Eps, Eps)
+			}
+
+			Convey("Zero-variance columns are only centered", func() {
+				So(out.Col(2), ShouldResemble, []float64{0, 0, 0, 0})
+			})
+
+			Convey("ApplyStandardization reuses the fitted statistics", func() {
+				test := ApplyStandardization(M(1, 3, 2.5, 25+stds[1], 6), means, stds)
+				So(test.Item(0, 0), ShouldEqual, 0)
+				So(test.Item(0, 1), ShouldBeBetween, 1-Eps, 1+Eps)
+				So(test.Item(0, 2), ShouldEqual, 1)
+			})
+
+			Convey("ApplyStandardization panics on mismatched columns", func() {
+				So(func() { ApplyStandardization(M(1, 2, 1, 2), means, stds) }, ShouldPanic)
+			})
+		})
+	})
+
+	Convey("Given a sparse matrix", t, func() {
+		m := Diag(1, 2)
+
+		Convey("Standardize returns a dense matrix", func() {
+			out, _, _ := Standardize(m)
+			So(out.Sparsity(), ShouldEqual, DenseArray)
+			So(out.Array(), ShouldResemble, []float64{1, -1, -1, 1})
+		})
+	})
+}
+
+func TestMinMaxScale(t *testing.T) {
+	Convey("Given a training matrix", t, func() {
+		m := M(3, 3,
+			1, -10, 7,
+			3, 0, 7,
+			5, 10, 7)
+
+		Convey("MinMaxScale maps each column onto [lo, hi]", func() {
+			out, mins, maxs := MinMaxScale(m, 0, 1)
+			So(mins, ShouldResemble, []float64{1, -10, 7})
+			So(maxs, ShouldResemble, []float64{5, 10, 7})
+			So(out.Array(), ShouldResemble, []float64{
+				0, 0, 0,
+				.5, .5, 0,
+				1, 1, 0,
+			})
+
+			Convey("ApplyMinMaxScale reuses the fitted range", func() {
+				test := ApplyMinMaxScale(M(1, 3, 7, 0, 8), mins, maxs, -1, 1)
+				So(test.Array(), ShouldResemble, []float64{2, 0, 0})
+			})
+
+			Convey("Constant columns are shifted but not scaled", func() {
+				So(out.Col(2), ShouldResemble, []float64{0, 0, 0})
+				test := ApplyMinMaxScale(M(2, 3, 1, -10, 9, 1, -10, 6), mins, maxs, 5, 10)
+				So(test.Col(2), ShouldResemble, []float64{7, 4})
+			})
+
+			Convey("ApplyMinMaxScale panics on mismatched columns", func() {
+				So(func() { ApplyMinMaxScale(M(1, 1, 0), mins, maxs, 0, 1) }, ShouldPanic)
+			})
+		})
+
+		Convey("MinMaxScale works with other ranges", func() {
+			out, _, _ := MinMaxScale(m, -1, 1)
+			So(out.Col(0), ShouldResemble, []float64{-1, 0, 1})
+		})
+
+		Convey("MinMaxScale handles a matrix with no rows", func() {
+			out, mins, maxs := MinMaxScale(Dense(0, 2).M(), 0, 1)
+			So(out.Shape(), ShouldResemble, []int{0, 2})
+			So(math.IsNaN(mins[0]) && math.IsNaN(maxs[1]), ShouldBeTrue)
+		})
+
+		Convey("MinMaxScale panics on an invalid range", func() {
+			So(func() { MinMaxScale(m, 1, 1) }, ShouldPanic)
+		})
+	})
+}
@@ -0,0 +1,73 @@
+package matrix
+
+import (
+	"fmt"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// Call f for each field of a serialized protobuf message. The field bytes
+// are the payload of length-delimited fields and the raw encoding of others.
+func protoFields(msg []byte, f func(num protowire.Number, typ protowire.Type, field []byte) error) error {
+	for len(msg) > 0 {
+		num, typ, n := protowire.ConsumeTag(msg)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		msg = msg[n:]
+		n = protowire.ConsumeFieldValue(num, typ, msg)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		field := msg[:n]
+		if typ == protowire.BytesType {
+			field, _ = protowire.ConsumeBytes(field)
+		}
+		if err := f(num, typ, field); err != nil {
+			return err
+		}
+		msg = msg[n:]
+	}
+	return nil
+}
+
+// Call f for each value of a repeated varint field, packed or not
+func protoVarints(typ protowire.Type, field []byte, f func(uint64)) error {
+	if typ != protowire.BytesType && typ != protowire.VarintType {
+		return fmt.Errorf("Unexpected protobuf wire type %d for varint field", typ)
+	}
+	for len(field) > 0 {
+		v, n := protowire.ConsumeVarint(field)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		f(v)
+		field = field[n:]
+	}
+	return nil
+}
+
+// Call f for each value of a repeated fixed-width field, packed or not
+func protoFixed(typ protowire.Type, field []byte, want protowire.Type, f func(uint64)) error {
+	if typ != protowire.BytesType && typ != want {
+		return fmt.Errorf("Unexpected protobuf wire type %d for fixed-width field", typ)
+	}
+	for len(field) > 0 {
+		var (
+			v uint64
+			n int
+		)
+		if want == protowire.Fixed64Type {
+			v, n = protowire.ConsumeFixed64(field)
+		} else {
+			var v32 uint32
+			v32, n = protowire.ConsumeFixed32(field)
+			v = uint64(v32)
+		}
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		f(v)
+		field = field[n:]
+	}
+	return nil
+}
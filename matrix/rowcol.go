@@ -0,0 +1,190 @@
+package matrix
+
+import (
+	"fmt"
+)
+
+// Get a copy of m with values inserted as a new row before row. Passing
+// m.Rows() as row appends the new row. Dense matrices give a dense result and
+// sparse matrices give a sparse coo result, which only moves the nonzero
+// values.
+func InsertRow(m Matrix, row int, values []float64) Matrix {
+	debugCheck("InsertRow", m)
+	if row < 0 || row > m.Rows() {
+		panic(ErrIndexOutOfRange{Op: "InsertRow", Index: []int{row}, Shape: []int{m.Rows() + 1}})
+	} else if len(values) != m.Cols() {
+		panic(ErrShapeMismatch{Op: "InsertRow", Got: []int{len(values)}, Want: []int{m.Cols()}})
+	}
+	result := remap(m, m.Rows()+1, m.Cols(), func(r, c int) (int, int, bool) {
+		if r >= row {
+			r++
+		}
+		return r, c, true
+	})
+	for col, v := range values {
+		if v != 0 {
+			result.ItemSet(v, row, col)
+		}
+	}
+	return result
+}
+
+// Get a copy of m with values inserted as a new column before col. Passing
+// m.Cols() as col appends the new column. The result is stored as for
+// InsertRow().
+func InsertCol(m Matrix, col int, values []float64) Matrix {
+	debugCheck("InsertCol", m)
+	if col < 0 || col > m.Cols() {
+		panic(ErrIndexOutOfRange{Op: "InsertCol", Index: []int{col}, Shape: []int{m.Cols() + 1}})
+	} else if len(values) != m.Rows() {
+		panic(ErrShapeMismatch{Op: "InsertCol", Got: []int{len(values)}, Want: []int{m.Rows()}})
+	}
+	result := remap(m, m.Rows(), m.Cols()+1, func(r, c int) (int, int, bool) {
+		if c >= col {
+			c++
+		}
+		return r, c, true
+	})
+	for row, v := range values {
+		if v != 0 {
+			result.ItemSet(v, row, col)
+		}
+	}
+	return result
+}
+
+// Get a copy of m without the given row. The result is stored as for
+// InsertRow().
+func DeleteRow(m Matrix, row int) Matrix {
+	debugCheck("DeleteRow", m)
+	if row < 0 || row >= m.Rows() {
+		panic(ErrIndexOutOfRange{Op: "DeleteRow", Index: []int{row}, Shape: []int{m.Rows()}})
+	}
+	return remap(m, m.Rows()-1, m.Cols(), func(r, c int) (int, int, bool) {
+		switch {
+		case r == row:
+			return 0, 0, false
+		case r > row:
+			r--
+		}
+		return r, c, true
+	})
+}
+
+// Get a copy of m without the given column. The result is stored as for
+// InsertRow().
+func DeleteCol(m Matrix, col int) Matrix {
+	debugCheck("DeleteCol", m)
+	if col < 0 || col >= m.Cols() {
+		panic(ErrIndexOutOfRange{Op: "DeleteCol", Index: []int{col}, Shape: []int{m.Cols()}})
+	}
+	return remap(m, m.Rows(), m.Cols()-1, func(r, c int) (int, int, bool) {
+		switch {
+		case c == col:
+			return 0, 0, false
+		case c > col:
+			c--
+		}
+		return r, c, true
+	})
+}
+
+// Get a copy of m with its rows reordered, so that row i of the result is
+// row perm[i] of m. perm must be a permutation of 0, ..., m.Rows()-1. Labels
+// of a LabeledMatrix are reordered with the rows. The result is stored as for
+// InsertRow().
+func PermuteRows(m Matrix, perm []int) Matrix {
+	debugCheck("PermuteRows", m)
+	inv := invertPermutation("PermuteRows", perm, m.Rows())
+	result := remap(m, m.Rows(), m.Cols(), func(r, c int) (int, int, bool) {
+		return inv[r], c, true
+	})
+	if l, ok := m.(*LabeledMatrix); ok {
+		return WithLabels(result, permuteLabels(l.rowLabels, perm), l.colLabels)
+	}
+	return result
+}
+
+// Get a copy of m with its columns reordered, so that column j of the result
+// is column perm[j] of m. perm must be a permutation of 0, ...,
+// m.Cols()-1. Labels of a LabeledMatrix are reordered with the columns. The
+// result is stored as for InsertRow().
+func PermuteCols(m Matrix, perm []int) Matrix {
+	debugCheck("PermuteCols", m)
+	inv := invertPermutation("PermuteCols", perm, m.Cols())
+	result := remap(m, m.Rows(), m.Cols(), func(r, c int) (int, int, bool) {
+		return r, inv[c], true
+	})
+	if l, ok := m.(*LabeledMatrix); ok {
+		return WithLabels(result, l.rowLabels, permuteLabels(l.colLabels, perm))
+	}
+	return result
+}
+
+// Get the inverse of a permutation of 0, ..., len(perm)-1, such that
+// inv[perm[i]] == i. Permuting by perm and then by inv restores the original
+// order. Panics if perm isn't a permutation.
+func InversePermutation(perm []int) []int {
+	return invertPermutation("InversePermutation", perm, len(perm))
+}
+
+// Get the inverse of perm, or panic if it isn't a permutation of 0, ...,
+// size-1
+func invertPermutation(op string, perm []int, size int) []int {
+	if len(perm) != size {
+		panic(ErrShapeMismatch{Op: op, Got: []int{len(perm)}, Want: []int{size}})
+	}
+	inv := make([]int, size)
+	for i := range inv {
+		inv[i] = -1
+	}
+	for i, p := range perm {
+		if p < 0 || p >= size {
+			panic(ErrIndexOutOfRange{Op: op, Index: []int{p}, Shape: []int{size}})
+		} else if inv[p] >= 0 {
+			panic(fmt.Sprintf("%s: invalid permutation: %d appears at positions %d and %d", op, p, inv[p], i))
+		}
+		inv[p] = i
+	}
+	return inv
+}
+
+// Get labels reordered by perm, or nil for an unlabeled axis
+func permuteLabels(labels []string, perm []int) []string {
+	if labels == nil {
+		return nil
+	}
+	result := make([]string, len(perm))
+	for i, p := range perm {
+		result[i] = labels[p]
+	}
+	return result
+}
+
+// Panic unless i and j are valid indices for an axis of the given size
+func checkSwap(op string, i, j, size int) {
+	for _, idx := range []int{i, j} {
+		if idx < 0 || idx >= size {
+			panic(ErrIndexOutOfRange{Op: op, Index: []int{idx}, Shape: []int{size}})
+		}
+	}
+}
+
+// Create a rows x cols matrix holding the nonzero values of m, moved to the
+// positions given by f. Values for which f returns false are dropped. The
+// result is dense if m is dense, and sparse coo otherwise.
+func remap(m Matrix, rows, cols int, f func(row, col int) (int, int, bool)) Matrix {
+	var result Matrix
+	if m.Sparsity() == DenseArray {
+		result = Dense(rows, cols).M()
+	} else {
+		result = SparseCoo(rows, cols)
+	}
+	m.VisitNonzero(func(pos []int, value float64) bool {
+		if row, col, ok := f(pos[0], pos[1]); ok {
+			result.ItemSet(value, row, col)
+		}
+		return true
+	})
+	return result
+}
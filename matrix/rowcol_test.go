@@ -0,0 +1,172 @@
+package matrix
+
+import (
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+func TestInsertDelete(t *testing.T) {
+	Convey("Given dense and sparse matrices", t, func() {
+		values := []float64{
+			1, 0, 2,
+			0, 3, 0,
+		}
+		matrices := []Matrix{M(2, 3, values...), SparseCoo(2, 3, values...), M(2, 3, values...).T().T()}
+
+		Convey("InsertRow shifts the following rows down", func() {
+			for _, m := range matrices {
+				So(InsertRow(m, 1, []float64{4, 0, 5}).Equal(M(3, 3, 1, 0, 2, 4, 0, 5, 0, 3, 0)), ShouldBeTrue)
+				So(InsertRow(m, 2, []float64{4, 0, 5}).Equal(M(3, 3, 1, 0, 2, 0, 3, 0, 4, 0, 5)), ShouldBeTrue)
+				So(InsertRow(m, 0, []float64{4, 0, 5}).Sparsity(), ShouldEqual, m.Sparsity())
+			}
+		})
+
+		Convey("InsertCol shifts the following columns right", func() {
+			for _, m := range matrices {
+				So(InsertCol(m, 0, []float64{7, 8}).Equal(M(2, 4, 7, 1, 0, 2, 8, 0, 3, 0)), ShouldBeTrue)
+				So(InsertCol(m, 3, []float64{7, 8}).Equal(M(2, 4, 1, 0, 2, 7, 0, 3, 0, 8)), ShouldBeTrue)
+			}
+		})
+
+		Convey("DeleteRow and DeleteCol remove a row or column", func() {
+			for _, m := range matrices {
+				So(DeleteRow(m, 0).Equal(M(1, 3, 0, 3, 0)), ShouldBeTrue)
+				So(DeleteRow(m, 1).Equal(M(1, 3, 1, 0, 2)), ShouldBeTrue)
+				So(DeleteCol(m, 1).Equal(M(2, 2, 1, 2, 0, 0)), ShouldBeTrue)
+				So(DeleteCol(m, 0).Equal(M(2, 2, 0, 2, 3, 0)), ShouldBeTrue)
+			}
+		})
+
+		Convey("Sparse diagonal matrices give sparse coo results", func() {
+			m := DeleteRow(Eye(3), 1)
+			So(m.Sparsity(), ShouldEqual, SparseCooMatrix)
+			So(m.Equal(M(2, 3, 1, 0, 0, 0, 0, 1)), ShouldBeTrue)
+		})
+
+		Convey("The original matrix is unchanged", func() {
+			m := matrices[1]
+			InsertRow(m, 0, []float64{1, 1, 1})
+			DeleteCol(m, 0)
+			So(m.Equal(M(2, 3, values...)), ShouldBeTrue)
+		})
+
+		Convey("Invalid indices and lengths panic", func() {
+			m := matrices[0]
+			So(try(func() { InsertRow(m, 3, []float64{1, 2, 3}) }), ShouldResemble,
+				ErrIndexOutOfRange{Op: "InsertRow", Index: []int{3}, Shape: []int{3}})
+			So(try(func() { InsertCol(m, 0, []float64{1, 2, 3}) }), ShouldResemble,
+				ErrShapeMismatch{Op: "InsertCol", Got: []int{3}, Want: []int{2}})
+			So(func() { DeleteRow(m, 2) }, ShouldPanic)
+			So(func() { DeleteCol(m, -1) }, ShouldPanic)
+		})
+	})
+}
+
+func TestSwap(t *testing.T) {
+	Convey("Given dense and sparse matrices", t, func() {
+		values := []float64{
+			1, 0, 2,
+			0, 3, 0,
+			4, 0, 5,
+		}
+		matrices := []Matrix{
+			M(3, 3, values...),
+			M(3, 3, values...).T().Copy().M().T(),
+			SparseCoo(3, 3, values...),
+			SparseCoo(3, 3, values...).T().Copy().M().T(),
+		}
+
+		Convey("SwapRows exchanges two rows in place", func() {
+			for _, m := range matrices {
+				m.SwapRows(0, 1)
+				So(m.Equal(M(3, 3, 0, 3, 0, 1, 0, 2, 4, 0, 5)), ShouldBeTrue)
+				m.SwapRows(2, 2)
+				So(m.Equal(M(3, 3, 0, 3, 0, 1, 0, 2, 4, 0, 5)), ShouldBeTrue)
+			}
+		})
+
+		Convey("SwapCols exchanges two columns in place", func() {
+			for _, m := range matrices {
+				m.SwapCols(2, 1)
+				So(m.Equal(M(3, 3, 1, 2, 0, 0, 0, 3, 4, 5, 0)), ShouldBeTrue)
+			}
+		})
+
+		Convey("Invalid indices panic", func() {
+			for _, m := range matrices {
+				So(try(func() { m.SwapRows(0, 3) }), ShouldResemble,
+					ErrIndexOutOfRange{Op: "SwapRows", Index: []int{3}, Shape: []int{3}})
+				So(func() { m.SwapCols(-1, 0) }, ShouldPanic)
+			}
+		})
+
+		Convey("Sparse diagonal matrices can only swap zeros on the diagonal", func() {
+			m := SparseDiag(3, 4, 1, 0, 0)
+			m.SwapRows(1, 2)
+			m.SwapCols(1, 3)
+			m.SwapCols(0, 0)
+			So(m.Equal(Diag(1, 0, 0).Concat(1, Dense(3, 1))), ShouldBeTrue)
+			So(try(func() { m.SwapRows(0, 2) }), ShouldResemble,
+				ErrNotSparse{Op: "SwapRows", Index: []int{2, 0}, Sparsity: SparseDiagMatrix})
+			So(try(func() { m.SwapCols(3, 0) }), ShouldResemble,
+				ErrNotSparse{Op: "SwapCols", Index: []int{0, 3}, Sparsity: SparseDiagMatrix})
+		})
+
+		Convey("Labeled matrices swap their labels too", func() {
+			m := WithLabels(M(2, 2, 1, 2, 3, 4), []string{"a", "b"}, []string{"x", "y"})
+			m.SwapRows(0, 1)
+			m.SwapCols(0, 1)
+			So(m.Equal(M(2, 2, 4, 3, 2, 1)), ShouldBeTrue)
+			So(m.RowLabels(), ShouldResemble, []string{"b", "a"})
+			So(m.ColByName("x"), ShouldResemble, []float64{3, 1})
+		})
+
+		Convey("Frozen matrices can't be swapped", func() {
+			So(func() { Freeze(matrices[0]).SwapRows(0, 1) }, ShouldPanic)
+			So(func() { Freeze(matrices[0]).SwapCols(0, 1) }, ShouldPanic)
+		})
+	})
+}
+
+func TestPermute(t *testing.T) {
+	Convey("Given dense and sparse matrices", t, func() {
+		values := []float64{
+			1, 0, 2,
+			0, 3, 0,
+		}
+		matrices := []Matrix{M(2, 3, values...), SparseCoo(2, 3, values...)}
+
+		Convey("PermuteRows and PermuteCols reorder a copy", func() {
+			for _, m := range matrices {
+				So(PermuteRows(m, []int{1, 0}).Equal(M(2, 3, 0, 3, 0, 1, 0, 2)), ShouldBeTrue)
+				So(PermuteCols(m, []int{2, 0, 1}).Equal(M(2, 3, 2, 1, 0, 0, 0, 3)), ShouldBeTrue)
+				So(PermuteCols(m, []int{0, 1, 2}).Equal(m), ShouldBeTrue)
+				So(m.Equal(M(2, 3, values...)), ShouldBeTrue)
+			}
+		})
+
+		Convey("InversePermutation undoes a permutation", func() {
+			perm := []int{2, 0, 3, 1}
+			inv := InversePermutation(perm)
+			So(inv, ShouldResemble, []int{1, 3, 0, 2})
+			m := M(4, 1, 1, 2, 3, 4)
+			So(PermuteRows(PermuteRows(m, perm), inv).Equal(m), ShouldBeTrue)
+		})
+
+		Convey("Labels follow the rows and columns", func() {
+			m := WithLabels(matrices[0], []string{"a", "b"}, []string{"x", "y", "z"})
+			So(RowLabels(PermuteRows(m, []int{1, 0})), ShouldResemble, []string{"b", "a"})
+			So(ColLabels(PermuteCols(m, []int{2, 0, 1})), ShouldResemble, []string{"z", "x", "y"})
+		})
+
+		Convey("Invalid permutations panic", func() {
+			m := matrices[0]
+			So(try(func() { PermuteRows(m, []int{0}) }), ShouldResemble,
+				ErrShapeMismatch{Op: "PermuteRows", Got: []int{1}, Want: []int{2}})
+			So(try(func() { PermuteCols(m, []int{0, 1, 3}) }), ShouldResemble,
+				ErrIndexOutOfRange{Op: "PermuteCols", Index: []int{3}, Shape: []int{3}})
+			So(func() { PermuteCols(m, []int{0, 1, 1}) }, ShouldPanic)
+			So(func() { InversePermutation([]int{1, 1}) }, ShouldPanic)
+		})
+	})
+}
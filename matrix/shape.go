@@ -0,0 +1,58 @@
+package matrix
+
+// Panic with ErrShapeMismatch unless the array has the given shape. A
+// dimension of -1 matches any size, so AssertShape(m, -1, 3) checks that m is
+// a matrix with three columns.
+func AssertShape(array NDArray, shape ...int) {
+	if !shapeMatches(array.Shape(), shape) {
+		panic(ErrShapeMismatch{Op: "AssertShape", Got: array.Shape(), Want: shape})
+	}
+}
+
+// Get an ErrShapeMismatch if the arrays have different shapes, or nil if
+// they can be combined element-wise.
+func SameShape(a, b NDArray) error {
+	if !shapeMatches(b.Shape(), a.Shape()) {
+		return ErrShapeMismatch{Op: "SameShape", Got: b.Shape(), Want: a.Shape()}
+	}
+	return nil
+}
+
+// Get an ErrShapeMismatch if a.MProd(b) is undefined because the number of
+// columns of a differs from the number of rows of b, or nil otherwise.
+func CanMultiply(a, b Matrix) error {
+	if a.Cols() != b.Rows() {
+		return ErrShapeMismatch{Op: "CanMultiply", Got: b.Shape(), Want: []int{a.Cols(), -1}}
+	}
+	return nil
+}
+
+// Returns true if got has the same dimensions as want, where -1 dimensions in
+// want match any size
+func shapeMatches(got, want []int) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	for i := range got {
+		if want[i] >= 0 && got[i] != want[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Panic unless src fits inside dst with its top-left element at (row, col),
+// as required by op. Reports the first out-of-range corner of the block.
+func checkSubmatrix(op string, dst Matrix, row, col int, src Matrix) {
+	debugCheck(op, dst, src)
+	if row < 0 || col < 0 {
+		panic(ErrIndexOutOfRange{Op: op, Index: []int{row, col}, Shape: dst.Shape()})
+	}
+	if row+src.Rows() > dst.Rows() || col+src.Cols() > dst.Cols() {
+		panic(ErrIndexOutOfRange{
+			Op:    op,
+			Index: []int{row + src.Rows() - 1, col + src.Cols() - 1},
+			Shape: dst.Shape(),
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package matrix
+
+import (
+	"errors"
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+func TestShapeHelpers(t *testing.T) {
+	Convey("Given a 2x3 matrix", t, func() {
+		m := Dense(2, 3).M()
+
+		Convey("AssertShape accepts matching shapes and wildcards", func() {
+			So(func() { AssertShape(m, 2, 3) }, ShouldNotPanic)
+			So(func() { AssertShape(m, -1, 3) }, ShouldNotPanic)
+			So(func() { AssertShape(m, -1, -1) }, ShouldNotPanic)
+		})
+
+		Convey("AssertShape panics with ErrShapeMismatch otherwise", func() {
+			So(func() { AssertShape(m, 3, 2) }, ShouldPanic)
+			So(func() { AssertShape(m, 2, 3, 1) }, ShouldPanic)
+			err := try(func() { AssertShape(m, -1, 4) })
+			So(err, ShouldResemble, ErrShapeMismatch{Op: "AssertShape", Got: []int{2, 3}, Want: []int{-1, 4}})
+			So(err.Error(), ShouldEqual, "AssertShape: got shape [2 3] but want [* 4]")
+		})
+
+		Convey("SameShape compares shapes of any storage", func() {
+			So(SameShape(m, SparseCoo(2, 3)), ShouldBeNil)
+			So(SameShape(m, SparseDiag(2, 3)), ShouldBeNil)
+			err := SameShape(m, m.T())
+			var shapeErr ErrShapeMismatch
+			So(errors.As(err, &shapeErr), ShouldBeTrue)
+			So(shapeErr.Got, ShouldResemble, []int{3, 2})
+			So(SameShape(m, Dense(6)), ShouldNotBeNil)
+		})
+
+		Convey("CanMultiply checks the inner dimensions", func() {
+			So(CanMultiply(m, m.T()), ShouldBeNil)
+			So(CanMultiply(m, Eye(3)), ShouldBeNil)
+			err := CanMultiply(m, m)
+			So(err, ShouldResemble, ErrShapeMismatch{Op: "CanMultiply", Got: []int{2, 3}, Want: []int{3, -1}})
+		})
+	})
+}
@@ -0,0 +1,135 @@
+package matrix
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"unsafe"
+)
+
+// The layout of a shared-memory matrix segment: a 64-byte header holding
+// the magic string "NUMGOSHM", a uint32 format version, a uint32 header
+// size, and uint64 row and column counts, all little-endian. The header is
+// followed by the matrix values as float64s in row-major order and native
+// byte order.
+const (
+	shmMagic      = "NUMGOSHM"
+	shmVersion    = 1
+	shmHeaderSize = 64
+)
+
+// A dense matrix whose values are stored in a named shared-memory segment, so
+// other processes on the same machine can map them without copying. From
+// Python, the matrix can be mapped as a NumPy array with:
+//
+//	from multiprocessing import shared_memory
+//	import numpy as np
+//	shm = shared_memory.SharedMemory(name)
+//	rows, cols = np.frombuffer(shm.buf, dtype="<u8", count=2, offset=16)
+//	m = np.ndarray((rows, cols), dtype=np.float64, buffer=shm.buf, offset=64)
+//
+// Shared buffers are only supported on Linux, where segments live in /dev/shm.
+type SharedBuffer struct {
+	// The name of the shared-memory segment
+	Name string
+
+	// The matrix, whose storage is the shared segment. Changes to it are
+	// visible to other processes mapping the segment, and vice versa. It must
+	// not be used after Close().
+	Matrix Matrix
+
+	data []byte
+}
+
+// Create a named shared-memory segment holding a copy of m. The returned
+// buffer's Matrix uses the segment for storage. Fails if a segment with the
+// name already exists.
+func ToSharedBuffer(m Matrix, name string) (*SharedBuffer, error) {
+	if err := checkShmName(name); err != nil {
+		return nil, err
+	}
+	rows, cols := m.Rows(), m.Cols()
+	data, err := mapShm(name, shmHeaderSize+8*rows*cols, true)
+	if err != nil {
+		return nil, err
+	}
+	copy(data, shmMagic)
+	binary.LittleEndian.PutUint32(data[8:], shmVersion)
+	binary.LittleEndian.PutUint32(data[12:], shmHeaderSize)
+	binary.LittleEndian.PutUint64(data[16:], uint64(rows))
+	binary.LittleEndian.PutUint64(data[24:], uint64(cols))
+	buf := newSharedBuffer(name, data, rows, cols)
+	copy(buf.Matrix.(*denseF64Array).array, m.Array())
+	return buf, nil
+}
+
+// Map an existing named shared-memory segment, such as one created by
+// ToSharedBuffer in another process. The returned buffer's Matrix uses the
+// segment for storage.
+func FromSharedBuffer(name string) (*SharedBuffer, error) {
+	if err := checkShmName(name); err != nil {
+		return nil, err
+	}
+	data, err := mapShm(name, -1, false)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < shmHeaderSize || string(data[:8]) != shmMagic {
+		unmapShm(data)
+		return nil, fmt.Errorf("Shared memory segment %q doesn't hold a matrix", name)
+	} else if version := binary.LittleEndian.Uint32(data[8:]); version != shmVersion {
+		unmapShm(data)
+		return nil, fmt.Errorf("Shared memory segment %q has unsupported version %d", name, version)
+	}
+	headerSize := binary.LittleEndian.Uint32(data[12:])
+	rows := binary.LittleEndian.Uint64(data[16:])
+	cols := binary.LittleEndian.Uint64(data[24:])
+	if headerSize != shmHeaderSize || cols != 0 && rows > uint64(len(data))/8/cols ||
+		uint64(len(data)) < shmHeaderSize+8*rows*cols {
+
+		unmapShm(data)
+		return nil, fmt.Errorf("Shared memory segment %q is too small for a %dx%d matrix", name, rows, cols)
+	}
+	return newSharedBuffer(name, data, int(rows), int(cols)), nil
+}
+
+// Unmap the segment from this process. The segment itself remains until it
+// is unlinked and every process has unmapped it.
+func (buf *SharedBuffer) Close() error {
+	if buf.data == nil {
+		return nil
+	}
+	err := unmapShm(buf.data)
+	buf.data, buf.Matrix = nil, nil
+	return err
+}
+
+// Remove the segment's name, so no new processes can map it. Processes which
+// have already mapped it can keep using it.
+func (buf *SharedBuffer) Unlink() error {
+	return unlinkShm(buf.Name)
+}
+
+// Create a shared buffer whose matrix is backed by a mapped segment
+func newSharedBuffer(name string, data []byte, rows, cols int) *SharedBuffer {
+	var values []float64
+	if rows*cols > 0 {
+		values = unsafe.Slice((*float64)(unsafe.Pointer(&data[shmHeaderSize])), rows*cols)
+	}
+	return &SharedBuffer{
+		Name: name,
+		Matrix: &denseF64Array{
+			shape: []int{rows, cols},
+			array: values,
+		},
+		data: data,
+	}
+}
+
+// Check that a segment name is valid
+func checkShmName(name string) error {
+	if name == "" || strings.ContainsAny(name, "/\x00") {
+		return fmt.Errorf("Invalid shared memory segment name %q", name)
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package matrix
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+)
+
+// The directory holding POSIX shared-memory segments
+const shmDir = "/dev/shm"
+
+// Map a shared-memory segment into memory. If create is set, a new segment of
+// the given size is created; otherwise an existing segment is mapped in full.
+func mapShm(name string, size int, create bool) ([]byte, error) {
+	flags := os.O_RDWR
+	if create {
+		flags |= os.O_CREATE | os.O_EXCL
+	}
+	f, err := os.OpenFile(filepath.Join(shmDir, name), flags, 0600)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	if create {
+		if err := f.Truncate(int64(size)); err != nil {
+			os.Remove(f.Name())
+			return nil, err
+		}
+	} else {
+		info, err := f.Stat()
+		if err != nil {
+			return nil, err
+		}
+		size = int(info.Size())
+	}
+	data, err := syscall.Mmap(int(f.Fd()), 0, size, syscall.PROT_READ|syscall.PROT_WRITE, syscall.MAP_SHARED)
+	if err != nil && create {
+		os.Remove(f.Name())
+	}
+	return data, err
+}
+
+// Unmap a shared-memory segment
+func unmapShm(data []byte) error {
+	return syscall.Munmap(data)
+}
+
+// Remove the name of a shared-memory segment
+func unlinkShm(name string) error {
+	return os.Remove(filepath.Join(shmDir, name))
+}
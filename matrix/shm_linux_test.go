@@ -0,0 +1,57 @@
+package matrix
+
+import (
+	"fmt"
+	. "github.com/smartystreets/goconvey/convey"
+	"os"
+	"testing"
+)
+
+func TestSharedBuffer(t *testing.T) {
+	Convey("Given a matrix in a shared buffer", t, func() {
+		name := fmt.Sprintf("numgo-test-%d", os.Getpid())
+		m := M(2, 3,
+			1, 2, 3,
+			4, 5, 6)
+		buf, err := ToSharedBuffer(m.T(), name)
+		So(err, ShouldBeNil)
+		defer buf.Unlink()
+		defer buf.Close()
+		So(buf.Matrix.Equal(m.T()), ShouldBeTrue)
+
+		Convey("Another mapping shares its storage", func() {
+			other, err := FromSharedBuffer(name)
+			So(err, ShouldBeNil)
+			defer other.Close()
+			So(other.Matrix.Shape(), ShouldResemble, []int{3, 2})
+			So(other.Matrix.Equal(m.T()), ShouldBeTrue)
+
+			buf.Matrix.ItemSet(42, 2, 1)
+			So(other.Matrix.Item(2, 1), ShouldEqual, 42)
+		})
+
+		Convey("Names can't be reused until unlinked", func() {
+			_, err := ToSharedBuffer(m, name)
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("Close releases the matrix", func() {
+			So(buf.Close(), ShouldBeNil)
+			So(buf.Matrix, ShouldBeNil)
+			So(buf.Close(), ShouldBeNil)
+		})
+	})
+
+	Convey("Invalid segments are an error", t, func() {
+		_, err := FromSharedBuffer("numgo-test-missing")
+		So(err, ShouldNotBeNil)
+		_, err = ToSharedBuffer(Eye(2), "bad/name")
+		So(err, ShouldNotBeNil)
+
+		name := fmt.Sprintf("numgo-test-bad-%d", os.Getpid())
+		So(os.WriteFile(shmDir+"/"+name, []byte("not a matrix"), 0600), ShouldBeNil)
+		defer unlinkShm(name)
+		_, err = FromSharedBuffer(name)
+		So(err, ShouldNotBeNil)
+	})
+}
@@ -0,0 +1,20 @@
+//go:build !linux
+
+package matrix
+
+import "fmt"
+
+// Shared-memory segments are only supported on Linux
+func mapShm(name string, size int, create bool) ([]byte, error) {
+	return nil, fmt.Errorf("Shared memory buffers are not supported on this platform")
+}
+
+// Shared-memory segments are only supported on Linux
+func unmapShm(data []byte) error {
+	return nil
+}
+
+// Shared-memory segments are only supported on Linux
+func unlinkShm(name string) error {
+	return fmt.Errorf("Shared memory buffers are not supported on this platform")
+}
@@ -0,0 +1,757 @@
+package matrix
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"sort"
+)
+
+// The storage of a compressed sparse matrix, shared with its views. Values
+// are grouped by their major index, which is the row for CSR and the column
+// for CSC: the values with major index i are data[indptr[i]:indptr[i+1]],
+// at the minor indices in the same range of ind, which are increasing. Only
+// nonzero values are stored. Inserting or removing a value replaces the
+// slices rather than changing them in place, so visiting the values isn't
+// disturbed by setting them as they are visited.
+type compressedStore struct {
+	indptr []int
+	ind    []int
+	data   []float64
+}
+
+// A sparse 2D Matrix with compressed sparse row (CSR) or compressed sparse
+// column (CSC) representation. The transpose of a CSR matrix is a CSC matrix
+// with the same storage, and vice versa.
+type sparseCompressedF64Matrix struct {
+	shape []int
+	store *compressedStore
+	byCol bool
+}
+
+// Create a compressed sparse matrix holding the nonzero values of m
+func newCompressed(m Matrix, byCol bool) *sparseCompressedF64Matrix {
+	indptr, ind, data := compressNonzero(m, byCol)
+	return &sparseCompressedF64Matrix{
+		shape: []int{m.Rows(), m.Cols()},
+		store: &compressedStore{indptr: indptr, ind: ind, data: data},
+		byCol: byCol,
+	}
+}
+
+// Get the compressed representation of the nonzero values of a matrix. Values
+// are grouped by row, or by column if byCol is set. Returns the offsets of
+// each group in ind and data, the minor index of each value, and the values.
+func compressNonzero(m Matrix, byCol bool) (indptr, ind []int, data []float64) {
+	type entry struct {
+		minor int
+		value float64
+	}
+	major := m.Rows()
+	if byCol {
+		major = m.Cols()
+	}
+	groups := make([][]entry, major)
+	m.VisitNonzero(func(pos []int, value float64) bool {
+		if value != 0 {
+			i, j := pos[0], pos[1]
+			if byCol {
+				i, j = j, i
+			}
+			groups[i] = append(groups[i], entry{j, value})
+		}
+		return true
+	})
+
+	indptr = make([]int, major+1)
+	for i, group := range groups {
+		sort.Slice(group, func(a, b int) bool { return group[a].minor < group[b].minor })
+		for _, e := range group {
+			ind = append(ind, e.minor)
+			data = append(data, e.value)
+		}
+		indptr[i+1] = len(ind)
+	}
+	return indptr, ind, data
+}
+
+// Create a compressed sparse matrix using existing storage, which must be
+// valid
+func wrapCompressed(op string, rows, cols int, indptr, ind []int, data []float64, byCol bool) Matrix {
+	array := &sparseCompressedF64Matrix{
+		shape: []int{rows, cols},
+		store: &compressedStore{indptr: indptr, ind: ind, data: data},
+		byCol: byCol,
+	}
+	if problem := compressedProblem(array); problem != "" {
+		panic(ErrInvariant{Op: op, Problem: problem})
+	}
+	return array
+}
+
+// Get the major and minor storage indices of a position
+func (array sparseCompressedF64Matrix) storageIndex(row, col int) (major, minor int) {
+	if array.byCol {
+		return col, row
+	}
+	return row, col
+}
+
+// Find where the value at (major, minor) is or would be stored, and whether
+// it is stored
+func (s *compressedStore) find(major, minor int) (int, bool) {
+	lo, hi := s.indptr[major], s.indptr[major+1]
+	pos := lo + sort.SearchInts(s.ind[lo:hi], minor)
+	return pos, pos < hi && s.ind[pos] == minor
+}
+
+// Set the value at (major, minor), inserting or removing it as needed
+func (s *compressedStore) set(major, minor int, value float64) {
+	pos, found := s.find(major, minor)
+	switch {
+	case found && value != 0:
+		s.data[pos] = value
+	case found:
+		s.ind = append(s.ind[:pos:pos], s.ind[pos+1:]...)
+		s.data = append(s.data[:pos:pos], s.data[pos+1:]...)
+		s.shift(major, -1)
+	case value != 0:
+		ind := make([]int, len(s.ind)+1)
+		copy(ind, s.ind[:pos])
+		ind[pos] = minor
+		copy(ind[pos+1:], s.ind[pos:])
+		data := make([]float64, len(s.data)+1)
+		copy(data, s.data[:pos])
+		data[pos] = value
+		copy(data[pos+1:], s.data[pos:])
+		s.ind, s.data = ind, data
+		s.shift(major, 1)
+	}
+}
+
+// Get the n values with major index i
+func (s *compressedStore) major(i, n int) []float64 {
+	result := make([]float64, n)
+	for pos := s.indptr[i]; pos < s.indptr[i+1]; pos++ {
+		result[s.ind[pos]] = s.data[pos]
+	}
+	return result
+}
+
+// Get the n values with minor index j, searching each major index for it
+func (s *compressedStore) minor(j, n int) []float64 {
+	result := make([]float64, n)
+	for i := range result {
+		if pos, found := s.find(i, j); found {
+			result[i] = s.data[pos]
+		}
+	}
+	return result
+}
+
+// Move the end of the values with major index major, and the values after
+// them, by delta
+func (s *compressedStore) shift(major, delta int) {
+	indptr := append([]int(nil), s.indptr...)
+	for i := major + 1; i < len(indptr); i++ {
+		indptr[i] += delta
+	}
+	s.indptr = indptr
+}
+
+// Return the element-wise sum of this array and one or more others
+func (array sparseCompressedF64Matrix) Add(other ...NDArray) NDArray {
+	return Add(&array, other...)
+}
+
+// Returns true if and only if all items are nonzero
+func (array sparseCompressedF64Matrix) All() bool {
+	return All(&array)
+}
+
+// Returns true if f is true for all array elements
+func (array sparseCompressedF64Matrix) AllF(f func(v float64) bool) bool {
+	return AllF(&array, f)
+}
+
+// Returns true if f is true for all pairs of array elements in the same position
+func (array sparseCompressedF64Matrix) AllF2(f func(v1, v2 float64) bool, other NDArray) bool {
+	return AllF2(&array, f, other)
+}
+
+// Returns true if and only if no items are NaN or infinite
+func (array sparseCompressedF64Matrix) AllFinite() bool {
+	return AllFinite(&array)
+}
+
+// Returns true if and only if any item is nonzero
+func (array sparseCompressedF64Matrix) Any() bool {
+	return len(array.store.data) > 0
+}
+
+// Returns true if f is true for any array element
+func (array sparseCompressedF64Matrix) AnyF(f func(v float64) bool) bool {
+	return AnyF(&array, f)
+}
+
+// Returns true if f is true for any pair of array elements in the same position
+func (array sparseCompressedF64Matrix) AnyF2(f func(v1, v2 float64) bool, other NDArray) bool {
+	return AnyF2(&array, f, other)
+}
+
+// Returns true if and only if any item is NaN
+func (array sparseCompressedF64Matrix) AnyNaN() bool {
+	return AnyNaN(&array)
+}
+
+// Return the result of applying a function to all elements
+func (array sparseCompressedF64Matrix) Apply(f func(float64) float64) NDArray {
+	return Apply(&array, f)
+}
+
+// Get the matrix data as a flattened 1D array; sparse matrices will make
+// a copy first.
+func (array sparseCompressedF64Matrix) Array() []float64 {
+	return array.Dense().Array()
+}
+
+// Set the values of the items on a given column. Setting elements which were
+// zero is slow, since the storage must be rebuilt.
+func (array *sparseCompressedF64Matrix) ColSet(col int, values []float64) {
+	if col < 0 || col >= array.shape[1] {
+		panic(ErrIndexOutOfRange{Op: "ColSet", Index: []int{col}, Shape: array.shape[1:]})
+	} else if len(values) != array.shape[0] {
+		panic(ErrShapeMismatch{Op: "ColSet", Got: []int{len(values)}, Want: array.shape[:1]})
+	}
+	for row := 0; row < array.shape[0]; row++ {
+		array.ItemSet(values[row], row, col)
+	}
+}
+
+// Get a copy of a particular column. This is fast for CSC matrices.
+func (array sparseCompressedF64Matrix) Col(col int) []float64 {
+	if col < 0 || col >= array.shape[1] {
+		panic(ErrIndexOutOfRange{Op: "Col", Index: []int{col}, Shape: array.shape[1:]})
+	}
+	if array.byCol {
+		return array.store.major(col, array.shape[0])
+	}
+	return array.store.minor(col, array.shape[0])
+}
+
+// Get the number of columns
+func (array sparseCompressedF64Matrix) Cols() int {
+	return array.shape[1]
+}
+
+// Create a new array by concatenating this with another array along the
+// specified axis. The array shapes must be equal along all other axes.
+// It is legal to add a new axis.
+func (array sparseCompressedF64Matrix) Concat(axis int, others ...NDArray) NDArray {
+	return Concat(axis, &array, others...)
+}
+
+// Returns a deep copy of this array
+func (array sparseCompressedF64Matrix) Clone() NDArray {
+	return array.copy()
+}
+
+// Get the condition number for the matrix norm of the specified ordinality
+func (array sparseCompressedF64Matrix) Cond(ord float64) float64 {
+	return Cond(&array, ord)
+}
+
+// Returns a duplicate of this array
+func (array sparseCompressedF64Matrix) Copy() NDArray {
+	return array.copy()
+}
+
+// Returns a duplicate of this array, preserving type
+func (array sparseCompressedF64Matrix) copy() *sparseCompressedF64Matrix {
+	s := array.store
+	return &sparseCompressedF64Matrix{
+		shape: append([]int(nil), array.shape...),
+		store: &compressedStore{
+			indptr: append([]int(nil), s.indptr...),
+			ind:    append([]int(nil), s.ind...),
+			data:   append([]float64(nil), s.data...),
+		},
+		byCol: array.byCol,
+	}
+}
+
+// Counts the number of nonzero elements in the array
+func (array sparseCompressedF64Matrix) CountNonzero() int {
+	return len(array.store.data)
+}
+
+// Returns a dense copy of the array
+func (array sparseCompressedF64Matrix) Dense() NDArray {
+	result := Dense(array.shape...)
+	array.VisitNonzero(func(pos []int, value float64) bool {
+		result.ItemSet(value, pos...)
+		return true
+	})
+	return result
+}
+
+// Get the determinant of a square matrix
+func (array sparseCompressedF64Matrix) Det() float64 {
+	return Det(&array)
+}
+
+// Get a column vector containing the main diagonal elements of the matrix
+func (array sparseCompressedF64Matrix) Diag() Matrix {
+	size := min(array.shape[0], array.shape[1])
+	result := Dense(size, 1).M()
+	for i := 0; i < size; i++ {
+		result.ItemSet(array.Item(i, i), i, 0)
+	}
+	return result
+}
+
+// Treat the rows as points, and get the pairwise distance between them.
+// Returns a distance matrix D such that D_i,j is the distance between
+// rows i and j.
+func (array sparseCompressedF64Matrix) Dist(t DistType) Matrix {
+	return Dist(&array, t)
+}
+
+// Treat the rows of this and other as points, and get the distance between
+// each pair. Returns an m x n distance matrix D such that D_i,j is the
+// distance between row i of this and row j of other.
+func (array sparseCompressedF64Matrix) DistTo(other Matrix, t DistType) Matrix {
+	return DistTo(&array, other, t)
+}
+
+// Return the element-wise quotient of this array and one or more others.
+// This function defines 0 / 0 = 0, so it's useful for sparse arrays.
+func (array sparseCompressedF64Matrix) Div(other ...NDArray) NDArray {
+	return Div(&array, other...)
+}
+
+// Returns true if and only if all elements in the two arrays are equal
+func (array sparseCompressedF64Matrix) Equal(other NDArray) bool {
+	return Equal(&array, other)
+}
+
+// Set all array elements to the given value
+func (array sparseCompressedF64Matrix) Fill(value float64) {
+	panic(fmt.Sprintf("Can't Fill() a %v matrix", array.Sparsity()))
+}
+
+// Get the coordinates for the item at the specified flat position
+func (array sparseCompressedF64Matrix) FlatCoord(index int) []int {
+	return flatToNd(array.shape, index)
+}
+
+// Get an array element in a flattened verison of this array
+func (array sparseCompressedF64Matrix) FlatItem(index int) float64 {
+	nd := flatToNd(array.shape, index)
+	return array.Item(nd[0], nd[1])
+}
+
+// Set an array element in a flattened version of this array
+func (array *sparseCompressedF64Matrix) FlatItemSet(value float64, index int) {
+	nd := flatToNd(array.shape, index)
+	array.ItemSet(value, nd[0], nd[1])
+}
+
+// Get the matrix inverse
+func (array sparseCompressedF64Matrix) Inverse() (Matrix, error) {
+	return Inverse(&array)
+}
+
+// Get an array element
+func (array sparseCompressedF64Matrix) Item(index ...int) float64 {
+	if len(index) != 2 || index[0] < 0 || index[0] >= array.shape[0] || index[1] < 0 || index[1] >= array.shape[1] {
+		panic(ErrIndexOutOfRange{Op: "Item", Index: index, Shape: array.shape})
+	}
+	major, minor := array.storageIndex(index[0], index[1])
+	if pos, found := array.store.find(major, minor); found {
+		return array.store.data[pos]
+	}
+	return 0
+}
+
+// Add a scalar value to each array element
+func (array *sparseCompressedF64Matrix) ItemAdd(value float64) NDArray {
+	return ItemAdd(array, value)
+}
+
+// Divide each array element by a scalar value
+func (array *sparseCompressedF64Matrix) ItemDiv(value float64) NDArray {
+	return ItemDiv(array, value)
+}
+
+// Multiply each array element by a scalar value
+func (array *sparseCompressedF64Matrix) ItemProd(value float64) NDArray {
+	return ItemProd(array, value)
+}
+
+// Subtract a scalar value from each array element
+func (array *sparseCompressedF64Matrix) ItemSub(value float64) NDArray {
+	return ItemSub(array, value)
+}
+
+// Set an array element. Changing an element which is stored takes
+// logarithmic time, but setting an element which was zero, or setting one to
+// zero, rebuilds the storage. To build a large matrix, build it in coo format
+// and convert it with SparseCSR() or SparseCSC().
+func (array *sparseCompressedF64Matrix) ItemSet(value float64, index ...int) {
+	if len(index) != 2 || index[0] < 0 || index[0] >= array.shape[0] || index[1] < 0 || index[1] >= array.shape[1] {
+		panic(ErrIndexOutOfRange{Op: "ItemSet", Index: index, Shape: array.shape})
+	}
+	major, minor := array.storageIndex(index[0], index[1])
+	array.store.set(major, minor, value)
+}
+
+// Solve for x, where ax = b.
+func (array sparseCompressedF64Matrix) LDivide(b Matrix) Matrix {
+	return LDivide(&array, b)
+}
+
+// Get the result of matrix multiplication between this and some other
+// array(s). All arrays must have two dimensions, and the dimensions must
+// be aligned correctly for multiplication.
+// If A is m x p and B is p x n, then C = A.MProd(B) is the m x n matrix
+// with C[i, j] = \sum_{k=1}^p A[i,k] * B[k,j].
+func (array sparseCompressedF64Matrix) MProd(others ...Matrix) Matrix {
+	return MProd(&array, others...)
+}
+
+// Get the value of the largest array element
+func (array sparseCompressedF64Matrix) Max() float64 {
+	return Max(&array)
+}
+
+// Get the value of the smallest array element
+func (array sparseCompressedF64Matrix) Min() float64 {
+	return Min(&array)
+}
+
+// The number of dimensions in the matrix
+func (array sparseCompressedF64Matrix) NDim() int {
+	return 2
+}
+
+// Get the matrix norm of the specified ordinality (1, 2, infinity, ...)
+func (array sparseCompressedF64Matrix) Norm(ord float64) float64 {
+	return Norm(&array, ord)
+}
+
+// Return a copy of the array, normalized to sum to 1
+func (array *sparseCompressedF64Matrix) Normalize() NDArray {
+	return Normalize(array)
+}
+
+// Get the Moore-Penrose pseudo-inverse, treating singular values no larger
+// than tol as zero
+func (array sparseCompressedF64Matrix) Pinv(tol float64) (Matrix, error) {
+	return Pinv(&array, tol)
+}
+
+// Return the element-wise product of this array and one or more others
+func (array sparseCompressedF64Matrix) Prod(other ...NDArray) NDArray {
+	return Prod(&array, other...)
+}
+
+// Get the number of singular values larger than tol
+func (array sparseCompressedF64Matrix) Rank(tol float64) int {
+	return Rank(&array, tol)
+}
+
+// Get a 1D copy of the array, in 'C' order: rightmost axes change fastest
+func (array sparseCompressedF64Matrix) Ravel() NDArray {
+	return Ravel(&array)
+}
+
+// Set the values of the items on a given row. Setting elements which were
+// zero is slow, since the storage must be rebuilt.
+func (array *sparseCompressedF64Matrix) RowSet(row int, values []float64) {
+	if row < 0 || row >= array.shape[0] {
+		panic(ErrIndexOutOfRange{Op: "RowSet", Index: []int{row}, Shape: array.shape[:1]})
+	} else if len(values) != array.shape[1] {
+		panic(ErrShapeMismatch{Op: "RowSet", Got: []int{len(values)}, Want: array.shape[1:]})
+	}
+	for col := 0; col < array.shape[1]; col++ {
+		array.ItemSet(values[col], row, col)
+	}
+}
+
+// Get a copy of a particular row. This is fast for CSR matrices.
+func (array sparseCompressedF64Matrix) Row(row int) []float64 {
+	if row < 0 || row >= array.shape[0] {
+		panic(ErrIndexOutOfRange{Op: "Row", Index: []int{row}, Shape: array.shape[:1]})
+	}
+	if array.byCol {
+		return array.store.minor(row, array.shape[1])
+	}
+	return array.store.major(row, array.shape[1])
+}
+
+// Get the number of rows
+func (array sparseCompressedF64Matrix) Rows() int {
+	return array.shape[0]
+}
+
+// Replace the array with one read from a database column, implementing
+// sql.Scanner. The matrix keeps its format.
+func (array *sparseCompressedF64Matrix) Scan(src interface{}) error {
+	return scanSparseCompressed(array, src)
+}
+
+// Copy src into this matrix, with the top-left element of src at (row, col).
+// Only the nonzero values of src are stored.
+func (array *sparseCompressedF64Matrix) SetSubmatrix(row, col int, src Matrix) {
+	checkSubmatrix("SetSubmatrix", array, row, col, src)
+	result := remap(array, array.shape[0], array.shape[1], func(r, c int) (int, int, bool) {
+		inside := r >= row && r < row+src.Rows() && c >= col && c < col+src.Cols()
+		return r, c, !inside
+	})
+	src.VisitNonzero(func(pos []int, value float64) bool {
+		result.ItemSet(value, row+pos[0], col+pos[1])
+		return true
+	})
+	array.replace(result)
+}
+
+// Replace the stored values with the nonzero values of m, which has the same
+// shape, keeping the format. Views see the new values.
+func (array *sparseCompressedF64Matrix) replace(m Matrix) {
+	*array.store = *newCompressed(m, array.byCol).store
+}
+
+// A slice giving the size of all array dimensions
+func (array sparseCompressedF64Matrix) Shape() []int {
+	return array.shape
+}
+
+// The total number of elements in the matrix
+func (array sparseCompressedF64Matrix) Size() int {
+	return array.shape[0] * array.shape[1]
+}
+
+// Get an array containing a rectangular slice of this array.
+// `from` and `to` should both have one index per axis. The indices
+// in `from` and `to` define the first and just-past-last indices you wish
+// to select along each axis.
+func (array sparseCompressedF64Matrix) Slice(from []int, to []int) NDArray {
+	return Slice(&array, from, to)
+}
+
+// Return a sparse coo copy of the matrix
+func (array sparseCompressedF64Matrix) SparseCoo() Matrix {
+	m := SparseCoo(array.shape[0], array.shape[1])
+	array.VisitNonzero(func(pos []int, value float64) bool {
+		m.ItemSet(value, pos[0], pos[1])
+		return true
+	})
+	return m
+}
+
+// Return a compressed sparse column copy of the matrix
+func (array sparseCompressedF64Matrix) SparseCSC() Matrix {
+	if array.byCol {
+		return array.copy()
+	}
+	return newCompressed(&array, true)
+}
+
+// Return a compressed sparse row copy of the matrix
+func (array sparseCompressedF64Matrix) SparseCSR() Matrix {
+	if !array.byCol {
+		return array.copy()
+	}
+	return newCompressed(&array, false)
+}
+
+// Return a sparse diag copy of the matrix. The method will panic
+// if any off-diagonal elements are nonzero.
+func (array sparseCompressedF64Matrix) SparseDiag() Matrix {
+	m := SparseDiag(array.shape[0], array.shape[1])
+	array.VisitNonzero(func(pos []int, value float64) bool {
+		m.ItemSet(value, pos[0], pos[1])
+		return true
+	})
+	return m
+}
+
+// Ask whether the matrix has a sparse representation (useful for optimization)
+func (array sparseCompressedF64Matrix) Sparsity() ArraySparsity {
+	if array.byCol {
+		return SparseCSCMatrix
+	}
+	return SparseCSRMatrix
+}
+
+// Return the element-wise difference of this array and one or more others
+func (array sparseCompressedF64Matrix) Sub(other ...NDArray) NDArray {
+	return Sub(&array, other...)
+}
+
+// Return the sum of all array elements
+func (array sparseCompressedF64Matrix) Sum() float64 {
+	var sum float64
+	for _, v := range array.store.data {
+		sum += v
+	}
+	return sum
+}
+
+// Returns the array as a matrix. This is only possible for 1D and 2D arrays;
+// 1D arrays of length n are converted into n x 1 vectors.
+func (array sparseCompressedF64Matrix) M() Matrix {
+	return &array
+}
+
+// Exchange the values of two columns in place. This rebuilds the storage.
+func (array *sparseCompressedF64Matrix) SwapCols(i, j int) {
+	checkSwap("SwapCols", i, j, array.shape[1])
+	array.replace(remap(array, array.shape[0], array.shape[1], func(r, c int) (int, int, bool) {
+		return r, swapIndex(c, i, j), true
+	}))
+}
+
+// Exchange the values of two rows in place. This rebuilds the storage.
+func (array *sparseCompressedF64Matrix) SwapRows(i, j int) {
+	checkSwap("SwapRows", i, j, array.shape[0])
+	array.replace(remap(array, array.shape[0], array.shape[1], func(r, c int) (int, int, bool) {
+		return swapIndex(r, i, j), c, true
+	}))
+}
+
+// Get the index which idx moves to when i and j are exchanged
+func swapIndex(idx, i, j int) int {
+	switch idx {
+	case i:
+		return j
+	case j:
+		return i
+	}
+	return idx
+}
+
+// Return the same matrix, but with axes transposed. The same data is used,
+// for speed and memory efficiency: the transpose of a CSR matrix is a CSC
+// matrix, and vice versa. Use Copy() to create a new array.
+func (array sparseCompressedF64Matrix) T() Matrix {
+	return &sparseCompressedF64Matrix{
+		shape: []int{array.shape[1], array.shape[0]},
+		store: array.store,
+		byCol: !array.byCol,
+	}
+}
+
+// Get the sum of the elements on the main diagonal
+func (array sparseCompressedF64Matrix) Trace() float64 {
+	return Trace(&array, 0)
+}
+
+// Get the sum of the elements on a diagonal offset from the main diagonal.
+// Positive offsets select superdiagonals and negative offsets select
+// subdiagonals.
+func (array sparseCompressedF64Matrix) TraceOffset(offset int) float64 {
+	return Trace(&array, offset)
+}
+
+// Encode the array in the package's binary format for storage in a
+// database column, implementing driver.Valuer
+func (array sparseCompressedF64Matrix) Value() (driver.Value, error) {
+	return Value(&array)
+}
+
+// Returns a view of this array, which shares its storage
+func (array sparseCompressedF64Matrix) View() NDArray {
+	return &sparseCompressedF64Matrix{
+		shape: append([]int(nil), array.shape...),
+		store: array.store,
+		byCol: array.byCol,
+	}
+}
+
+// Visit all matrix elements, invoking a method on each. If the method
+// returns false, iteration is aborted and VisitNonzero() returns false.
+// Otherwise, it returns true.
+func (array sparseCompressedF64Matrix) Visit(f func(pos []int, value float64) bool) bool {
+	for row := 0; row < array.shape[0]; row++ {
+		for col, value := range array.Row(row) {
+			if !f([]int{row, col}, value) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Visit just nonzero elements, in storage order: row by row for CSR
+// matrices, and column by column for CSC matrices. If the method returns
+// false, iteration is aborted and VisitNonzero() returns false. Otherwise, it
+// returns true. The method may set elements of the matrix; the values it
+// visits are those stored when the visit started.
+func (array sparseCompressedF64Matrix) VisitNonzero(f func(pos []int, value float64) bool) bool {
+	s := *array.store
+	for major := 0; major+1 < len(s.indptr); major++ {
+		for pos := s.indptr[major]; pos < s.indptr[major+1]; pos++ {
+			row, col := major, s.ind[pos]
+			if array.byCol {
+				row, col = col, row
+			}
+			if !f([]int{row, col}, s.data[pos]) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Get m in CSR format, without copying it if it already is
+func asCSR(m Matrix) *sparseCompressedF64Matrix {
+	if c, ok := m.(*sparseCompressedF64Matrix); ok && !c.byCol {
+		return c
+	}
+	return newCompressed(m, false)
+}
+
+// Multiply two CSR matrices using Gustavson's algorithm: each row of the
+// product is accumulated from the rows of b selected by the nonzero values in
+// the same row of a. This takes memory proportional to the size of the
+// product's storage plus one row.
+func csrMProd(a, b *sparseCompressedF64Matrix) *sparseCompressedF64Matrix {
+	rows, cols := a.shape[0], b.shape[1]
+	as, bs := a.store, b.store
+	var (
+		indptr = make([]int, rows+1)
+		ind    []int
+		data   []float64
+		acc    = make([]float64, cols)
+		seen   = make([]bool, cols)
+		used   []int
+	)
+	for i := 0; i < rows; i++ {
+		used = used[:0]
+		for apos := as.indptr[i]; apos < as.indptr[i+1]; apos++ {
+			k, aValue := as.ind[apos], as.data[apos]
+			for bpos := bs.indptr[k]; bpos < bs.indptr[k+1]; bpos++ {
+				j := bs.ind[bpos]
+				if !seen[j] {
+					seen[j] = true
+					used = append(used, j)
+				}
+				acc[j] += aValue * bs.data[bpos]
+			}
+		}
+		sort.Ints(used)
+		for _, j := range used {
+			if acc[j] != 0 {
+				ind = append(ind, j)
+				data = append(data, acc[j])
+			}
+			acc[j], seen[j] = 0, false
+		}
+		indptr[i+1] = len(ind)
+	}
+	return &sparseCompressedF64Matrix{
+		shape: []int{rows, cols},
+		store: &compressedStore{indptr: indptr, ind: ind, data: data},
+	}
+}
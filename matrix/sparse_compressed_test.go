@@ -0,0 +1,264 @@
+package matrix
+
+import (
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+func TestSparseCompressedConversions(t *testing.T) {
+	Convey("Given a matrix in every format", t, func() {
+		values := []float64{
+			1, 0, 2, 0,
+			0, 0, 0, 0,
+			0, 3, 0, 4,
+		}
+		dense := M(3, 4, values...)
+		all := []Matrix{
+			dense,
+			SparseCoo(3, 4, values...),
+			SparseCSR(3, 4, values...),
+			SparseCSC(3, 4, values...),
+		}
+
+		Convey("The compressed formats report their sparsity", func() {
+			So(all[2].Sparsity(), ShouldEqual, SparseCSRMatrix)
+			So(all[3].Sparsity(), ShouldEqual, SparseCSCMatrix)
+			So(SparseCSRMatrix.String(), ShouldEqual, "sparse csr")
+			So(SparseCSCMatrix.String(), ShouldEqual, "sparse csc")
+		})
+
+		Convey("Every conversion preserves the values", func() {
+			for _, m := range all {
+				So(m.SparseCoo().Array(), ShouldResemble, values)
+				So(m.SparseCSR().Array(), ShouldResemble, values)
+				So(m.SparseCSC().Array(), ShouldResemble, values)
+				So(m.SparseCSR().Sparsity(), ShouldEqual, SparseCSRMatrix)
+				So(m.SparseCSC().Sparsity(), ShouldEqual, SparseCSCMatrix)
+				So(CheckInvariants(m.SparseCSR()), ShouldBeNil)
+				So(CheckInvariants(m.SparseCSC()), ShouldBeNil)
+			}
+		})
+
+		Convey("A diagonal matrix converts", func() {
+			m := Diag(1, 2, 3).SparseCSC()
+			So(m.Array(), ShouldResemble, Diag(1, 2, 3).Array())
+			So(m.SparseDiag().Sparsity(), ShouldEqual, SparseDiagMatrix)
+		})
+
+		Convey("Rows, columns and counts are read from storage", func() {
+			for _, m := range all[2:] {
+				So(m.Row(2), ShouldResemble, []float64{0, 3, 0, 4})
+				So(m.Col(2), ShouldResemble, []float64{2, 0, 0})
+				So(m.Item(2, 3), ShouldEqual, 4)
+				So(m.Item(1, 1), ShouldEqual, 0)
+				So(m.CountNonzero(), ShouldEqual, 4)
+				So(m.Sum(), ShouldEqual, 10)
+			}
+		})
+
+		Convey("NewMatrix can create compressed matrices", func() {
+			m := NewMatrix(3, 4, WithStorage(SparseCSRMatrix), WithValues(values...))
+			So(m.Sparsity(), ShouldEqual, SparseCSRMatrix)
+			So(m.Array(), ShouldResemble, values)
+		})
+	})
+}
+
+func TestSparseCompressedItemSet(t *testing.T) {
+	Convey("Given a CSR matrix", t, func() {
+		m := SparseCSR(2, 3, 1, 0, 2, 0, 3, 0)
+
+		Convey("ItemSet changes, inserts and removes values", func() {
+			m.ItemSet(5, 0, 0)
+			m.ItemSet(4, 1, 2)
+			m.ItemSet(0, 0, 2)
+			So(m.Array(), ShouldResemble, []float64{5, 0, 0, 0, 3, 4})
+			So(m.CountNonzero(), ShouldEqual, 3)
+			So(CheckInvariants(m), ShouldBeNil)
+		})
+
+		Convey("The transpose is a CSC matrix sharing storage", func() {
+			tr := m.T()
+			So(tr.Sparsity(), ShouldEqual, SparseCSCMatrix)
+			So(tr.Array(), ShouldResemble, []float64{1, 0, 0, 3, 2, 0})
+			m.ItemSet(7, 1, 0)
+			So(tr.Item(0, 1), ShouldEqual, 7)
+			So(CheckInvariants(tr), ShouldBeNil)
+		})
+
+		Convey("Copies don't share storage", func() {
+			c := m.Copy()
+			m.ItemSet(9, 0, 1)
+			So(c.Item(0, 1), ShouldEqual, 0)
+		})
+
+		Convey("Prod can remove values while visiting them", func() {
+			p := m.Prod(M(2, 3, 0, 1, 1, 1, 1, 1))
+			So(p.Array(), ShouldResemble, []float64{0, 0, 2, 0, 3, 0})
+			So(CheckInvariants(p), ShouldBeNil)
+		})
+
+		Convey("Swapping rows and columns works", func() {
+			m.SwapRows(0, 1)
+			So(m.Array(), ShouldResemble, []float64{0, 3, 0, 1, 0, 2})
+			m.SwapCols(0, 1)
+			So(m.Array(), ShouldResemble, []float64{3, 0, 0, 0, 1, 2})
+			So(CheckInvariants(m), ShouldBeNil)
+		})
+
+		Convey("Fill panics", func() {
+			So(func() { m.Fill(1) }, ShouldPanic)
+		})
+	})
+}
+
+func TestSparseCompressedArithmetic(t *testing.T) {
+	Convey("Given compressed and dense matrices", t, func() {
+		values := []float64{
+			1, 0, 2,
+			0, 0, 3,
+			4, 5, 0,
+		}
+		dense := M(3, 3, values...)
+		csr := SparseCSR(3, 3, values...)
+		csc := SparseCSC(3, 3, values...)
+		x := M(3, 2, 1, 2, 3, 4, 5, 6)
+
+		Convey("Matrix-vector products match the dense product", func() {
+			want := dense.MProd(x).Array()
+			So(csr.MProd(x).Array(), ShouldResemble, want)
+			So(csc.MProd(x).Array(), ShouldResemble, want)
+			So(x.T().MProd(csr).Array(), ShouldResemble, x.T().MProd(dense).Array())
+			So(x.T().MProd(csc).Array(), ShouldResemble, x.T().MProd(dense).Array())
+		})
+
+		Convey("Adding matrices of one format keeps the format", func() {
+			sum := csr.Add(csr)
+			So(sum.M().Sparsity(), ShouldEqual, SparseCSRMatrix)
+			So(sum.Array(), ShouldResemble, dense.Add(dense).Array())
+		})
+
+		Convey("Mixing formats gives coo or dense results", func() {
+			So(csr.Add(csc).M().Sparsity(), ShouldEqual, SparseCooMatrix)
+			So(csr.Sub(Eye(3)).M().Sparsity(), ShouldEqual, SparseCooMatrix)
+			So(csr.Add(dense).M().Sparsity(), ShouldEqual, DenseArray)
+			So(csr.Sub(csc).CountNonzero(), ShouldEqual, 0)
+		})
+	})
+}
+
+func TestWrapCompressed(t *testing.T) {
+	Convey("Given CSR storage", t, func() {
+		indptr := []int{0, 2, 2, 3}
+		ind := []int{0, 2, 1}
+		data := []float64{1, 2, 3}
+
+		Convey("WrapCSR shares it", func() {
+			m := WrapCSR(3, 3, indptr, ind, data)
+			So(m.Array(), ShouldResemble, []float64{1, 0, 2, 0, 0, 0, 0, 3, 0})
+			data[1] = 5
+			So(m.Item(0, 2), ShouldEqual, 5)
+		})
+
+		Convey("WrapCSC reads it by column", func() {
+			m := WrapCSC(3, 3, indptr, ind, data)
+			So(m.Array(), ShouldResemble, []float64{1, 0, 0, 0, 0, 3, 2, 0, 0})
+		})
+
+		Convey("RawCompressed returns it", func() {
+			gotIndptr, gotInd, gotData, byCol, ok := RawCompressed(WrapCSC(3, 3, indptr, ind, data))
+			So(ok, ShouldBeTrue)
+			So(byCol, ShouldBeTrue)
+			So(gotIndptr, ShouldResemble, indptr)
+			So(gotInd, ShouldResemble, ind)
+			So(gotData, ShouldResemble, data)
+			_, _, _, _, ok = RawCompressed(Diag(1, 2))
+			So(ok, ShouldBeFalse)
+		})
+
+		Convey("Invalid storage panics", func() {
+			err := try(func() { WrapCSR(3, 3, indptr, []int{2, 0, 1}, data) })
+			So(err, ShouldHaveSameTypeAs, ErrInvariant{})
+			err = try(func() { WrapCSR(2, 3, indptr, ind, data) })
+			So(err, ShouldHaveSameTypeAs, ErrInvariant{})
+		})
+	})
+}
+
+func TestSparseCompressedCodec(t *testing.T) {
+	Convey("Given a CSC matrix", t, func() {
+		m := SparseCSC(2, 3, 0, 1, 0, 2, 0, 3)
+
+		Convey("It round-trips through the binary format", func() {
+			v, err := m.Value()
+			So(err, ShouldBeNil)
+			got, err := scanArray(v)
+			So(err, ShouldBeNil)
+			So(got.M().Sparsity(), ShouldEqual, SparseCSCMatrix)
+			So(got.Array(), ShouldResemble, m.Array())
+		})
+
+		Convey("A CSR matrix can scan it", func() {
+			v, _ := m.Value()
+			csr := SparseCSR(1, 1).(*sparseCompressedF64Matrix)
+			So(csr.Scan(v), ShouldBeNil)
+			So(csr.Sparsity(), ShouldEqual, SparseCSRMatrix)
+			So(csr.Array(), ShouldResemble, m.Array())
+		})
+	})
+}
+
+func TestSparseMProd(t *testing.T) {
+	Convey("Given sparse matrices in several formats", t, func() {
+		a := M(3, 4,
+			1, 0, 2, 0,
+			0, 0, 0, 3,
+			4, 0, 0, 0)
+		b := M(4, 2,
+			0, 1,
+			5, 0,
+			0, -2,
+			6, 0)
+		want := a.MProd(b).Array()
+
+		Convey("Every pair of formats gives the dense product", func() {
+			formats := []func(Matrix) Matrix{
+				Matrix.SparseCoo, Matrix.SparseCSR, Matrix.SparseCSC,
+				func(m Matrix) Matrix { return m },
+			}
+			for _, fa := range formats {
+				for _, fb := range formats {
+					c := fa(a).MProd(fb(b))
+					So(c.Array(), ShouldResemble, want)
+					So(CheckInvariants(c), ShouldBeNil)
+				}
+			}
+		})
+
+		Convey("Products of compressed matrices are compressed", func() {
+			So(a.SparseCSR().MProd(b.SparseCSR()).Sparsity(), ShouldEqual, SparseCSRMatrix)
+			So(a.SparseCoo().MProd(b.SparseCSC()).Sparsity(), ShouldEqual, SparseCSRMatrix)
+			So(a.SparseCSC().MProd(b.SparseCSC()).Sparsity(), ShouldEqual, SparseCSCMatrix)
+			So(Diag(1, 2, 3).MProd(a.SparseCSR()).Sparsity(), ShouldEqual, SparseCSRMatrix)
+			So(a.SparseCSC().MProd(Diag(1, 2, 3, 4)).Sparsity(), ShouldEqual, SparseCSCMatrix)
+			So(a.SparseCoo().MProd(b).Sparsity(), ShouldEqual, DenseArray)
+		})
+
+		Convey("Values which cancel aren't stored", func() {
+			c := SparseCSR(2, 2, 1, 1, 0, 0).MProd(SparseCSR(2, 1, 1, -1))
+			So(c.CountNonzero(), ShouldEqual, 0)
+			So(CheckInvariants(c), ShouldBeNil)
+		})
+
+		Convey("Large sparse products stay small", func() {
+			n := 1000000
+			x := SparseCSR(n, n)
+			x.ItemSet(2, 0, n-1)
+			x.ItemSet(3, n-1, 0)
+			y := x.MProd(x)
+			So(y.CountNonzero(), ShouldEqual, 2)
+			So(y.Item(0, 0), ShouldEqual, 6)
+			So(y.Item(n-1, n-1), ShouldEqual, 6)
+		})
+	})
+}
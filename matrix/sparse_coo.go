@@ -1,7 +1,7 @@
 package matrix
 
 import (
-	"fmt"
+	"database/sql/driver"
 )
 
 // A sparse 2D Matrix with coordinate representation
@@ -31,6 +31,11 @@ func (array sparseCooF64Matrix) AllF2(f func(v1, v2 float64) bool, other NDArray
 	return AllF2(&array, f, other)
 }
 
+// Returns true if and only if no items are NaN or infinite
+func (array sparseCooF64Matrix) AllFinite() bool {
+	return AllFinite(&array)
+}
+
 // Returns true if and only if any item is nonzero
 func (array sparseCooF64Matrix) Any() bool {
 	return Any(&array)
@@ -46,6 +51,11 @@ func (array sparseCooF64Matrix) AnyF2(f func(v1, v2 float64) bool, other NDArray
 	return AnyF2(&array, f, other)
 }
 
+// Returns true if and only if any item is NaN
+func (array sparseCooF64Matrix) AnyNaN() bool {
+	return AnyNaN(&array)
+}
+
 // Return the result of applying a function to all elements
 func (array sparseCooF64Matrix) Apply(f func(float64) float64) NDArray {
 	return Apply(&array, f)
@@ -60,21 +70,24 @@ func (array sparseCooF64Matrix) Array() []float64 {
 // Set the values of the items on a given column
 func (array *sparseCooF64Matrix) ColSet(col int, values []float64) {
 	if col < 0 || col >= array.shape[1] {
-		panic(fmt.Sprintf("ColSet can't set col %d of a %d-col array", col, array.shape[1]))
+		panic(ErrIndexOutOfRange{Op: "ColSet", Index: []int{col}, Shape: array.shape[1:]})
 	} else if len(values) != array.shape[0] {
-		panic(fmt.Sprintf("ColSet has %d rows but got %d values", array.shape[0], len(values)))
+		panic(ErrShapeMismatch{Op: "ColSet", Got: []int{len(values)}, Want: array.shape[:1]})
 	}
 	for row := 0; row < array.shape[0]; row++ {
 		array.ItemSet(values[row], row, col)
 	}
 }
 
-// Get a particular column for read-only access. May or may not be a copy.
+// Get a copy of a particular column
 func (array sparseCooF64Matrix) Col(col int) []float64 {
 	if col < 0 || col >= array.shape[1] {
-		panic(fmt.Sprintf("Can't get column %d from a %dx%d array", col, array.shape[0], array.shape[1]))
+		panic(ErrIndexOutOfRange{Op: "Col", Index: []int{col}, Shape: array.shape[1:]})
 	}
-	result := make([]float64, array.shape[1])
+	if array.transpose {
+		return array.T().Row(col)
+	}
+	result := make([]float64, array.shape[0])
 	for row, val := range array.values {
 		result[row] = val[col]
 	}
@@ -93,6 +106,16 @@ func (array sparseCooF64Matrix) Concat(axis int, others ...NDArray) NDArray {
 	return Concat(axis, &array, others...)
 }
 
+// Returns a deep copy of this array
+func (array sparseCooF64Matrix) Clone() NDArray {
+	return array.copy()
+}
+
+// Get the condition number for the matrix norm of the specified ordinality
+func (array sparseCooF64Matrix) Cond(ord float64) float64 {
+	return Cond(&array, ord)
+}
+
 // Returns a duplicate of this array
 func (array sparseCooF64Matrix) Copy() NDArray {
 	return array.copy()
@@ -142,6 +165,11 @@ func (array sparseCooF64Matrix) Dense() NDArray {
 	return result
 }
 
+// Get the determinant of a square matrix
+func (array sparseCooF64Matrix) Det() float64 {
+	return Det(&array)
+}
+
 // Get a column vector containing the main diagonal elements of the matrix
 func (array sparseCooF64Matrix) Diag() Matrix {
 	size := array.shape[0]
@@ -162,6 +190,13 @@ func (array sparseCooF64Matrix) Dist(t DistType) Matrix {
 	return Dist(&array, t)
 }
 
+// Treat the rows of this and other as points, and get the distance between
+// each pair. Returns an m x n distance matrix D such that D_i,j is the
+// distance between row i of this and row j of other.
+func (array sparseCooF64Matrix) DistTo(other Matrix, t DistType) Matrix {
+	return DistTo(&array, other, t)
+}
+
 // Return the element-wise quotient of this array and one or more others.
 // This function defines 0 / 0 = 0, so it's useful for sparse arrays.
 func (array sparseCooF64Matrix) Div(other ...NDArray) NDArray {
@@ -207,10 +242,10 @@ func (array sparseCooF64Matrix) Inverse() (Matrix, error) {
 // Get an array element
 func (array sparseCooF64Matrix) Item(index ...int) float64 {
 	if len(index) != 2 || index[0] >= array.shape[0] || index[1] >= array.shape[1] {
-		panic(fmt.Sprintf("Item indices %v invalid for array shape %v", index, array.shape))
+		panic(ErrIndexOutOfRange{Op: "Item", Index: index, Shape: array.shape})
 	}
 	if array.transpose {
-		index[0], index[1] = index[1], index[0]
+		index = []int{index[1], index[0]}
 	}
 	return array.values[index[0]][index[1]]
 }
@@ -278,10 +313,10 @@ func (array *sparseCooF64Matrix) ItemSub(value float64) NDArray {
 // Set an array element
 func (array *sparseCooF64Matrix) ItemSet(value float64, index ...int) {
 	if len(index) != 2 || index[0] >= array.shape[0] || index[1] >= array.shape[1] {
-		panic(fmt.Sprintf("Item indices %v invalid for array shape %v", index, array.shape))
+		panic(ErrIndexOutOfRange{Op: "ItemSet", Index: index, Shape: array.shape})
 	}
 	if array.transpose {
-		index[0], index[1] = index[1], index[0]
+		index = []int{index[1], index[0]}
 	}
 	if value == 0 {
 		delete(array.values[index[0]], index[1])
@@ -329,11 +364,22 @@ func (array *sparseCooF64Matrix) Normalize() NDArray {
 	return Normalize(array)
 }
 
+// Get the Moore-Penrose pseudo-inverse, treating singular values no larger
+// than tol as zero
+func (array sparseCooF64Matrix) Pinv(tol float64) (Matrix, error) {
+	return Pinv(&array, tol)
+}
+
 // Return the element-wise product of this array and one or more others
 func (array sparseCooF64Matrix) Prod(other ...NDArray) NDArray {
 	return Prod(&array, other...)
 }
 
+// Get the number of singular values larger than tol
+func (array sparseCooF64Matrix) Rank(tol float64) int {
+	return Rank(&array, tol)
+}
+
 // Get a 1D copy of the array, in 'C' order: rightmost axes change fastest
 func (array sparseCooF64Matrix) Ravel() NDArray {
 	return Ravel(&array)
@@ -342,21 +388,24 @@ func (array sparseCooF64Matrix) Ravel() NDArray {
 // Set the values of the items on a given row
 func (array *sparseCooF64Matrix) RowSet(row int, values []float64) {
 	if row < 0 || row >= array.shape[0] {
-		panic(fmt.Sprintf("RowSet can't set row %d of a %d-row array", row, array.shape[0]))
+		panic(ErrIndexOutOfRange{Op: "RowSet", Index: []int{row}, Shape: array.shape[:1]})
 	} else if len(values) != array.shape[1] {
-		panic(fmt.Sprintf("RowSet has %d columns but got %d values", array.shape[1], len(values)))
+		panic(ErrShapeMismatch{Op: "RowSet", Got: []int{len(values)}, Want: array.shape[1:]})
 	}
 	for col := 0; col < array.shape[1]; col++ {
 		array.ItemSet(values[col], row, col)
 	}
 }
 
-// Get a particular row for read-only access. May or may not be a copy.
+// Get a copy of a particular row
 func (array sparseCooF64Matrix) Row(row int) []float64 {
 	if row < 0 || row >= array.shape[0] {
-		panic(fmt.Sprintf("Can't get row %d from a %dx%d array", row, array.shape[0], array.shape[1]))
+		panic(ErrIndexOutOfRange{Op: "Row", Index: []int{row}, Shape: array.shape[:1]})
 	}
-	result := make([]float64, array.shape[0])
+	if array.transpose {
+		return array.T().Col(row)
+	}
+	result := make([]float64, array.shape[1])
 	for col, val := range array.values[row] {
 		result[col] = val
 	}
@@ -368,6 +417,41 @@ func (array sparseCooF64Matrix) Rows() int {
 	return array.shape[0]
 }
 
+// Replace the array with one read from a database column, implementing
+// sql.Scanner
+func (array *sparseCooF64Matrix) Scan(src interface{}) error {
+	return scanSparseCoo(array, src)
+}
+
+// Copy src into this matrix, with the top-left element of src at (row, col).
+// Only the nonzero values of src are stored.
+func (array *sparseCooF64Matrix) SetSubmatrix(row, col int, src Matrix) {
+	checkSubmatrix("SetSubmatrix", array, row, col, src)
+	if array.transpose {
+		array.T().SetSubmatrix(col, row, src.T())
+		return
+	}
+	to := col + src.Cols()
+	for i := row; i < row+src.Rows(); i++ {
+		values := array.values[i]
+		if len(values) < src.Cols() {
+			for j := range values {
+				if j >= col && j < to {
+					delete(values, j)
+				}
+			}
+		} else {
+			for j := col; j < to; j++ {
+				delete(values, j)
+			}
+		}
+	}
+	src.VisitNonzero(func(pos []int, value float64) bool {
+		array.values[row+pos[0]][col+pos[1]] = value
+		return true
+	})
+}
+
 // A slice giving the size of all array dimensions
 func (array sparseCooF64Matrix) Shape() []int {
 	return array.shape
@@ -403,6 +487,16 @@ func (array sparseCooF64Matrix) SparseDiag() Matrix {
 	return m
 }
 
+// Return a compressed sparse column copy of the matrix
+func (array sparseCooF64Matrix) SparseCSC() Matrix {
+	return newCompressed(&array, true)
+}
+
+// Return a compressed sparse row copy of the matrix
+func (array sparseCooF64Matrix) SparseCSR() Matrix {
+	return newCompressed(&array, false)
+}
+
 // Ask whether the matrix has a sparse representation (useful for optimization)
 func (array sparseCooF64Matrix) Sparsity() ArraySparsity {
 	return SparseCooMatrix
@@ -424,6 +518,43 @@ func (array sparseCooF64Matrix) M() Matrix {
 	return &array
 }
 
+// Exchange the values of two columns in place
+func (array *sparseCooF64Matrix) SwapCols(i, j int) {
+	checkSwap("SwapCols", i, j, array.shape[1])
+	if array.transpose {
+		array.values[i], array.values[j] = array.values[j], array.values[i]
+	} else {
+		swapKeys(array.values, i, j)
+	}
+}
+
+// Exchange the values of two rows in place. Rows are stored separately, so
+// this takes constant time unless the matrix is transposed.
+func (array *sparseCooF64Matrix) SwapRows(i, j int) {
+	checkSwap("SwapRows", i, j, array.shape[0])
+	if array.transpose {
+		swapKeys(array.values, i, j)
+	} else {
+		array.values[i], array.values[j] = array.values[j], array.values[i]
+	}
+}
+
+// Exchange the values stored under keys i and j in each map
+func swapKeys(values []map[int]float64, i, j int) {
+	for _, m := range values {
+		vi, iok := m[i]
+		vj, jok := m[j]
+		delete(m, i)
+		delete(m, j)
+		if iok {
+			m[j] = vi
+		}
+		if jok {
+			m[i] = vj
+		}
+	}
+}
+
 // Return the same matrix, but with axes transposed. The same data is used,
 // for speed and memory efficiency. Use Copy() to create a new array.
 func (array sparseCooF64Matrix) T() Matrix {
@@ -434,6 +565,33 @@ func (array sparseCooF64Matrix) T() Matrix {
 	}
 }
 
+// Get the sum of the elements on the main diagonal
+func (array sparseCooF64Matrix) Trace() float64 {
+	return Trace(&array, 0)
+}
+
+// Get the sum of the elements on a diagonal offset from the main diagonal.
+// Positive offsets select superdiagonals and negative offsets select
+// subdiagonals.
+func (array sparseCooF64Matrix) TraceOffset(offset int) float64 {
+	return Trace(&array, offset)
+}
+
+// Encode the array in the package's binary format for storage in a
+// database column, implementing driver.Valuer
+func (array sparseCooF64Matrix) Value() (driver.Value, error) {
+	return Value(&array)
+}
+
+// Returns a view of this array, which shares its storage
+func (array sparseCooF64Matrix) View() NDArray {
+	return &sparseCooF64Matrix{
+		shape:     append([]int(nil), array.shape...),
+		values:    array.values,
+		transpose: array.transpose,
+	}
+}
+
 // Visit all matrix elements, invoking a method on each. If the method
 // returns false, iteration is aborted and VisitNonzero() returns false.
 // Otherwise, it returns true.
@@ -1127,5 +1127,13 @@ func TestSparseCooMatrix(t *testing.T) {
 				}
 			}
 		})
+
+		Convey("MProd() works with transposed and wrapped sparse matrices", func() {
+			a := SparseCoo(2, 3, 1, 2, 0, 0, 5, -6)
+			want := M(2, 3, 1, 2, 0, 0, 5, -6).MProd(M(3, 2, 1, 0, 2, 5, 0, -6))
+			So(a.MProd(a.T()).Equal(want), ShouldBeTrue)
+			So(a.MProd(Freeze(a.T())).Equal(want), ShouldBeTrue)
+			So(CheckInvariants(a.MProd(SparseCoo(3, 1, 2, -1, 0))), ShouldBeNil)
+		})
 	})
 }
@@ -1,7 +1,7 @@
 package matrix
 
 import (
-	"fmt"
+	"database/sql/driver"
 )
 
 // A sparse 2D Matrix with diagonal representation: only the main diagonal is
@@ -31,6 +31,11 @@ func (array sparseDiagF64Matrix) AllF2(f func(v1, v2 float64) bool, other NDArra
 	return AllF2(&array, f, other)
 }
 
+// Returns true if and only if no items are NaN or infinite
+func (array sparseDiagF64Matrix) AllFinite() bool {
+	return AllFinite(&array)
+}
+
 // Returns true if and only if any item is nonzero
 func (array sparseDiagF64Matrix) Any() bool {
 	for _, v := range array.diag {
@@ -51,6 +56,11 @@ func (array sparseDiagF64Matrix) AnyF2(f func(v1, v2 float64) bool, other NDArra
 	return AnyF2(&array, f, other)
 }
 
+// Returns true if and only if any item is NaN
+func (array sparseDiagF64Matrix) AnyNaN() bool {
+	return AnyNaN(&array)
+}
+
 // Return the result of applying a function to all elements
 func (array sparseDiagF64Matrix) Apply(f func(float64) float64) NDArray {
 	return Apply(&array, f)
@@ -65,14 +75,14 @@ func (array sparseDiagF64Matrix) Array() []float64 {
 // Set the values of the items on a given column
 func (array sparseDiagF64Matrix) ColSet(col int, values []float64) {
 	if col < 0 || col >= array.shape[1] {
-		panic(fmt.Sprintf("ColSet can't set col %d of a %d-col array", col, array.shape[1]))
+		panic(ErrIndexOutOfRange{Op: "ColSet", Index: []int{col}, Shape: array.shape[1:]})
 	} else if len(values) != array.shape[0] {
-		panic(fmt.Sprintf("ColSet has %d rows but got %d values", array.shape[0], len(values)))
+		panic(ErrShapeMismatch{Op: "ColSet", Got: []int{len(values)}, Want: array.shape[:1]})
 	}
 	for row := 0; row < array.shape[0]; row++ {
 		if row != col {
 			if values[row] != 0 {
-				panic(fmt.Sprintf("ColSet can't set cell (%d, %d) of a %dx%d sparse diagonal matrix", row, col, array.shape[0], array.shape[1]))
+				panic(ErrNotSparse{Op: "ColSet", Index: []int{row, col}, Sparsity: SparseDiagMatrix})
 			}
 		} else {
 			array.diag[row] = values[row]
@@ -80,13 +90,15 @@ func (array sparseDiagF64Matrix) ColSet(col int, values []float64) {
 	}
 }
 
-// Get a particular column for read-only access. May or may not be a copy.
+// Get a copy of a particular column
 func (array sparseDiagF64Matrix) Col(col int) []float64 {
 	if col < 0 || col >= array.shape[1] {
-		panic(fmt.Sprintf("Can't get column %d from a %dx%d array", col, array.shape[0], array.shape[1]))
+		panic(ErrIndexOutOfRange{Op: "Col", Index: []int{col}, Shape: array.shape[1:]})
+	}
+	result := make([]float64, array.shape[0])
+	if col < len(array.diag) {
+		result[col] = array.diag[col]
 	}
-	result := make([]float64, array.shape[1])
-	result[col] = array.diag[col]
 	return result
 }
 
@@ -102,6 +114,16 @@ func (array sparseDiagF64Matrix) Concat(axis int, others ...NDArray) NDArray {
 	return Concat(axis, &array, others...)
 }
 
+// Returns a deep copy of this array
+func (array sparseDiagF64Matrix) Clone() NDArray {
+	return array.copy()
+}
+
+// Get the condition number for the matrix norm of the specified ordinality
+func (array sparseDiagF64Matrix) Cond(ord float64) float64 {
+	return Cond(&array, ord)
+}
+
 // Returns a duplicate of this array
 func (array sparseDiagF64Matrix) Copy() NDArray {
 	return array.copy()
@@ -138,6 +160,11 @@ func (array sparseDiagF64Matrix) Dense() NDArray {
 	return result
 }
 
+// Get the determinant of a square matrix
+func (array sparseDiagF64Matrix) Det() float64 {
+	return Det(&array)
+}
+
 // Get a column vector containing the main diagonal elements of the matrix
 func (array sparseDiagF64Matrix) Diag() Matrix {
 	return A([]int{len(array.diag), 1}, array.diag...).M()
@@ -150,6 +177,13 @@ func (array sparseDiagF64Matrix) Dist(t DistType) Matrix {
 	return Dist(&array, t)
 }
 
+// Treat the rows of this and other as points, and get the distance between
+// each pair. Returns an m x n distance matrix D such that D_i,j is the
+// distance between row i of this and row j of other.
+func (array sparseDiagF64Matrix) DistTo(other Matrix, t DistType) Matrix {
+	return DistTo(&array, other, t)
+}
+
 // Return the element-wise quotient of this array and one or more others.
 // This function defines 0 / 0 = 0, so it's useful for sparse arrays.
 func (array sparseDiagF64Matrix) Div(other ...NDArray) NDArray {
@@ -184,7 +218,7 @@ func (array sparseDiagF64Matrix) FlatItem(index int) float64 {
 func (array sparseDiagF64Matrix) FlatItemSet(value float64, index int) {
 	coord := flatToNd(array.shape, index)
 	if coord[0] != coord[1] || coord[0] >= len(array.diag) {
-		panic(fmt.Sprintf("FlatItemSet index %v invalid for sparse diagonal array shape %v", index, array.shape))
+		panic(ErrNotSparse{Op: "FlatItemSet", Index: coord, Sparsity: SparseDiagMatrix})
 	}
 	array.diag[coord[0]] = value
 }
@@ -197,7 +231,7 @@ func (array sparseDiagF64Matrix) Inverse() (Matrix, error) {
 // Get an array element
 func (array sparseDiagF64Matrix) Item(index ...int) float64 {
 	if len(index) != 2 || index[0] >= array.shape[0] || index[1] >= array.shape[1] {
-		panic(fmt.Sprintf("Item indices %v invalid for array shape %v", index, array.shape))
+		panic(ErrIndexOutOfRange{Op: "Item", Index: index, Shape: array.shape})
 	} else if index[0] != index[1] || index[0] >= len(array.diag) {
 		return 0
 	}
@@ -267,9 +301,9 @@ func (array *sparseDiagF64Matrix) ItemSub(value float64) NDArray {
 // Set an array element
 func (array sparseDiagF64Matrix) ItemSet(value float64, index ...int) {
 	if len(index) != 2 || index[0] >= array.shape[0] || index[1] >= array.shape[1] {
-		panic(fmt.Sprintf("ItemSet indices %v invalid for array shape %v", index, array.shape))
+		panic(ErrIndexOutOfRange{Op: "ItemSet", Index: index, Shape: array.shape})
 	} else if index[0] != index[1] {
-		panic(fmt.Sprintf("ItemSet indices %v invalid for sparse diagonal array", index))
+		panic(ErrNotSparse{Op: "ItemSet", Index: index, Sparsity: SparseDiagMatrix})
 	}
 	array.diag[index[0]] = value
 }
@@ -313,11 +347,22 @@ func (array *sparseDiagF64Matrix) Normalize() NDArray {
 	return Normalize(array)
 }
 
+// Get the Moore-Penrose pseudo-inverse, treating singular values no larger
+// than tol as zero
+func (array sparseDiagF64Matrix) Pinv(tol float64) (Matrix, error) {
+	return Pinv(&array, tol)
+}
+
 // Return the element-wise product of this array and one or more others
 func (array sparseDiagF64Matrix) Prod(other ...NDArray) NDArray {
 	return Prod(&array, other...)
 }
 
+// Get the number of singular values larger than tol
+func (array sparseDiagF64Matrix) Rank(tol float64) int {
+	return Rank(&array, tol)
+}
+
 // Get a 1D copy of the array, in 'C' order: rightmost axes change fastest
 func (array sparseDiagF64Matrix) Ravel() NDArray {
 	return Ravel(&array)
@@ -326,14 +371,14 @@ func (array sparseDiagF64Matrix) Ravel() NDArray {
 // Set the values of the items on a given row
 func (array sparseDiagF64Matrix) RowSet(row int, values []float64) {
 	if row < 0 || row >= array.shape[0] {
-		panic(fmt.Sprintf("RowSet can't set row %d of a %d-row array", row, array.shape[0]))
+		panic(ErrIndexOutOfRange{Op: "RowSet", Index: []int{row}, Shape: array.shape[:1]})
 	} else if len(values) != array.shape[1] {
-		panic(fmt.Sprintf("RowSet has %d columns but got %d values", array.shape[1], len(values)))
+		panic(ErrShapeMismatch{Op: "RowSet", Got: []int{len(values)}, Want: array.shape[1:]})
 	}
 	for col := 0; col < array.shape[1]; col++ {
 		if row != col {
 			if values[col] != 0 {
-				panic(fmt.Sprintf("RowSet can't set cell (%d, %d) of a %dx%d sparse diagonal matrix", row, col, array.shape[0], array.shape[1]))
+				panic(ErrNotSparse{Op: "RowSet", Index: []int{row, col}, Sparsity: SparseDiagMatrix})
 			}
 		} else {
 			array.diag[col] = values[col]
@@ -341,13 +386,15 @@ func (array sparseDiagF64Matrix) RowSet(row int, values []float64) {
 	}
 }
 
-// Get a particular row for read-only access. May or may not be a copy.
+// Get a copy of a particular row
 func (array sparseDiagF64Matrix) Row(row int) []float64 {
 	if row < 0 || row >= array.shape[0] {
-		panic(fmt.Sprintf("Can't get row %d from a %dx%d array", row, array.shape[0], array.shape[1]))
+		panic(ErrIndexOutOfRange{Op: "Row", Index: []int{row}, Shape: array.shape[:1]})
+	}
+	result := make([]float64, array.shape[1])
+	if row < len(array.diag) {
+		result[row] = array.diag[row]
 	}
-	result := make([]float64, array.shape[0])
-	result[row] = array.diag[row]
 	return result
 }
 
@@ -356,6 +403,34 @@ func (array sparseDiagF64Matrix) Rows() int {
 	return array.shape[0]
 }
 
+// Replace the array with one read from a database column, implementing
+// sql.Scanner
+func (array *sparseDiagF64Matrix) Scan(src interface{}) error {
+	return scanSparseDiag(array, src)
+}
+
+// Copy src into this matrix, with the top-left element of src at (row, col).
+// Panics without changing the matrix if src has a nonzero value which would
+// fall off the diagonal.
+func (array sparseDiagF64Matrix) SetSubmatrix(row, col int, src Matrix) {
+	checkSubmatrix("SetSubmatrix", &array, row, col, src)
+	src.VisitNonzero(func(pos []int, value float64) bool {
+		if row+pos[0] != col+pos[1] {
+			panic(ErrNotSparse{
+				Op:       "SetSubmatrix",
+				Index:    []int{row + pos[0], col + pos[1]},
+				Sparsity: SparseDiagMatrix,
+			})
+		}
+		return true
+	})
+	for i := 0; i < src.Rows(); i++ {
+		if j := i + row - col; j >= 0 && j < src.Cols() {
+			array.diag[row+i] = src.Item(i, j)
+		}
+	}
+}
+
 // A slice giving the size of all array dimensions
 func (array sparseDiagF64Matrix) Shape() []int {
 	return array.shape
@@ -391,6 +466,16 @@ func (array sparseDiagF64Matrix) SparseDiag() Matrix {
 	return array.copy()
 }
 
+// Return a compressed sparse column copy of the matrix
+func (array sparseDiagF64Matrix) SparseCSC() Matrix {
+	return newCompressed(&array, true)
+}
+
+// Return a compressed sparse row copy of the matrix
+func (array sparseDiagF64Matrix) SparseCSR() Matrix {
+	return newCompressed(&array, false)
+}
+
 // Ask whether the matrix has a sparse representation (useful for optimization)
 func (array sparseDiagF64Matrix) Sparsity() ArraySparsity {
 	return SparseDiagMatrix
@@ -411,6 +496,39 @@ func (array sparseDiagF64Matrix) M() Matrix {
 	return &array
 }
 
+// Exchange the values of two columns in place. This is only possible if it
+// leaves the diagonal in place: if the columns are the same, or both have
+// zeros on the diagonal.
+func (array sparseDiagF64Matrix) SwapCols(i, j int) {
+	checkSwap("SwapCols", i, j, array.shape[1])
+	array.checkDiagSwap("SwapCols", i, j, false)
+}
+
+// Exchange the values of two rows in place. This is only possible if it
+// leaves the diagonal in place: if the rows are the same, or both have zeros
+// on the diagonal.
+func (array sparseDiagF64Matrix) SwapRows(i, j int) {
+	checkSwap("SwapRows", i, j, array.shape[0])
+	array.checkDiagSwap("SwapRows", i, j, true)
+}
+
+// Panic with ErrNotSparse if swapping rows or columns i and j would move a
+// nonzero value off the diagonal
+func (array sparseDiagF64Matrix) checkDiagSwap(op string, i, j int, rows bool) {
+	if i == j {
+		return
+	}
+	for _, pair := range [][2]int{{i, j}, {j, i}} {
+		if from := pair[0]; from < len(array.diag) && array.diag[from] != 0 {
+			index := []int{pair[1], from}
+			if !rows {
+				index = []int{from, pair[1]}
+			}
+			panic(ErrNotSparse{Op: op, Index: index, Sparsity: SparseDiagMatrix})
+		}
+	}
+}
+
 // Return the same matrix, but with axes transposed. The same data is used,
 // for speed and memory efficiency. Use Copy() to create a new array.
 func (array sparseDiagF64Matrix) T() Matrix {
@@ -420,6 +538,39 @@ func (array sparseDiagF64Matrix) T() Matrix {
 	}
 }
 
+// Get the sum of the elements on the main diagonal
+func (array sparseDiagF64Matrix) Trace() float64 {
+	var sum float64
+	for _, v := range array.diag {
+		sum += v
+	}
+	return sum
+}
+
+// Get the sum of the elements on a diagonal offset from the main diagonal.
+// Positive offsets select superdiagonals and negative offsets select
+// subdiagonals.
+func (array sparseDiagF64Matrix) TraceOffset(offset int) float64 {
+	if offset != 0 {
+		return 0
+	}
+	return array.Trace()
+}
+
+// Encode the array in the package's binary format for storage in a
+// database column, implementing driver.Valuer
+func (array sparseDiagF64Matrix) Value() (driver.Value, error) {
+	return Value(&array)
+}
+
+// Returns a view of this array, which shares its storage
+func (array sparseDiagF64Matrix) View() NDArray {
+	return &sparseDiagF64Matrix{
+		shape: append([]int(nil), array.shape...),
+		diag:  array.diag,
+	}
+}
+
 // Visit all matrix elements, invoking a method on each. If the method
 // returns false, iteration is aborted and VisitNonzero() returns false.
 // Otherwise, it returns true.
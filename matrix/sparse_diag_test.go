@@ -1,6 +1,7 @@
 package matrix
 
 import (
+	"errors"
 	. "github.com/smartystreets/goconvey/convey"
 	"math"
 	"testing"
@@ -326,6 +327,61 @@ func TestSparseDiagInverseNormLDivide(t *testing.T) {
 	})
 }
 
+func TestSparseDiagFastPaths(t *testing.T) {
+	Convey("Given diagonal matrices", t, func() {
+		a := Diag(2, 4, 8)
+		b := M(3, 2, 2, 4, 8, 16, 32, 64)
+
+		Convey("Inverse and LDivide keep the result diagonal", func() {
+			inv, err := Inverse(a)
+			So(err, ShouldBeNil)
+			So(inv.Sparsity(), ShouldEqual, SparseDiagMatrix)
+			x := LDivide(a, Diag(2, 2, 2))
+			So(x.Sparsity(), ShouldEqual, SparseDiagMatrix)
+			So(x.Diag().Array(), ShouldResemble, []float64{1, .5, .25})
+		})
+
+		Convey("LDivide scales the rows of b and keeps its format", func() {
+			So(LDivide(a, b).Array(), ShouldResemble, []float64{1, 2, 2, 4, 4, 8})
+			x := LDivide(a, b.SparseCSR())
+			So(x.Sparsity(), ShouldEqual, SparseCSRMatrix)
+			So(x.Array(), ShouldResemble, []float64{1, 2, 2, 4, 4, 8})
+		})
+
+		Convey("Singular matrices are detected", func() {
+			s := Diag(1, 0, 2)
+			_, err := Inverse(s)
+			So(errors.Is(err, ErrSingular), ShouldBeTrue)
+			So(math.IsNaN(LDivide(s, b).Item(0, 0)), ShouldBeTrue)
+		})
+
+		Convey("Wrapped diagonal matrices use the fast path", func() {
+			inv, err := Inverse(Freeze(a))
+			So(err, ShouldBeNil)
+			So(inv.Sparsity(), ShouldEqual, SparseDiagMatrix)
+		})
+
+		Convey("Sums of diagonal matrices stay diagonal", func() {
+			sum := a.Add(Diag(1, 1, 1), Eye(3))
+			So(sum.M().Sparsity(), ShouldEqual, SparseDiagMatrix)
+			So(sum.M().Diag().Array(), ShouldResemble, []float64{4, 6, 10})
+			diff := a.Sub(Diag(1, 1, 1))
+			So(diff.M().Diag().Array(), ShouldResemble, []float64{1, 3, 7})
+			So(a.Diag().Array(), ShouldResemble, []float64{2, 4, 8})
+		})
+
+		Convey("Products with rectangular diagonal matrices are correct", func() {
+			r := SparseDiag(2, 3, 2, 3)
+			So(r.MProd(b).Array(), ShouldResemble, r.Dense().M().MProd(b).Array())
+			So(b.T().MProd(r.T()).Array(), ShouldResemble, b.T().MProd(r.T().Dense().M()).Array())
+			p := r.MProd(SparseDiag(3, 4, 1, 1, 1))
+			So(p.Shape(), ShouldResemble, []int{2, 4})
+			So(p.Sparsity(), ShouldEqual, SparseDiagMatrix)
+			So(p.Array(), ShouldResemble, []float64{2, 0, 0, 0, 0, 3, 0, 0})
+		})
+	})
+}
+
 func TestSparseDiagItemMath(t *testing.T) {
 	Convey("Given a diag array", t, func() {
 		a := Diag(1, 2, 3)
@@ -0,0 +1,138 @@
+package matrix
+
+import (
+	"fmt"
+)
+
+// Create a dense matrix whose elements are f(row, col), such as a kernel
+// matrix
+func FromFunc(rows, cols int, f func(row, col int) float64) Matrix {
+	result := Dense(rows, cols).M()
+	for row := 0; row < rows; row++ {
+		for col := 0; col < cols; col++ {
+			if v := f(row, col); v != 0 {
+				result.ItemSet(v, row, col)
+			}
+		}
+	}
+	return result
+}
+
+// Create a sparse coo matrix whose elements are f(row, col) where
+// nonzero(row, col) is true, and zero elsewhere. f is only called for the
+// elements selected by nonzero.
+func FromFuncSparse(rows, cols int, nonzero func(row, col int) bool, f func(row, col int) float64) Matrix {
+	result := SparseCoo(rows, cols)
+	for row := 0; row < rows; row++ {
+		for col := 0; col < cols; col++ {
+			if nonzero(row, col) {
+				if v := f(row, col); v != 0 {
+					result.ItemSet(v, row, col)
+				}
+			}
+		}
+	}
+	return result
+}
+
+// Create a Toeplitz matrix, which is constant along each diagonal. The matrix
+// has firstCol as its first column and firstRow as its first row, so it is
+// len(firstCol) x len(firstRow). The first element of firstRow is ignored in
+// favor of firstCol[0]. If firstRow is nil, the result is the symmetric
+// Toeplitz matrix with firstCol as its first row and column.
+//
+// The result stores only its rows+cols-1 distinct values, and is read-only, as
+// for Freeze(). Element access, Row() and Col() take no extra memory; methods
+// which need the full matrix, such as Array() and MProd(), create a dense copy
+// once.
+func Toeplitz(firstCol, firstRow []float64) Matrix {
+	if firstRow == nil {
+		firstRow = firstCol
+	}
+	rows, cols := len(firstCol), len(firstRow)
+	values := make([]float64, max(rows+cols-1, 0))
+	if rows > 0 && cols > 0 {
+		for col := 1; col < cols; col++ {
+			values[cols-1-col] = firstRow[col]
+		}
+		copy(values[cols-1:], firstCol)
+	}
+	return newToeplitz(values, rows, cols, false)
+}
+
+// Create a circulant matrix: the square Toeplitz matrix whose first column is
+// c, and in which each column is the previous column rotated down by one. It
+// is stored as for Toeplitz().
+func Circulant(c []float64) Matrix {
+	n := len(c)
+	firstRow := make([]float64, n)
+	for col := range firstRow {
+		firstRow[col] = c[(n-col)%n]
+	}
+	return Toeplitz(c, firstRow)
+}
+
+// Create a Hankel matrix, which is constant along each anti-diagonal. The
+// matrix has firstCol as its first column and lastRow as its last row, so it
+// is len(firstCol) x len(lastRow). The first element of lastRow is ignored in
+// favor of the last element of firstCol. If lastRow is nil, the result is
+// square and zero below the anti-diagonal. It is stored as for Toeplitz().
+func Hankel(firstCol, lastRow []float64) Matrix {
+	rows, cols := len(firstCol), len(lastRow)
+	if lastRow == nil {
+		cols = rows
+	}
+	values := make([]float64, max(rows+cols-1, 0))
+	if rows > 0 && cols > 0 {
+		copy(values, firstCol)
+		if lastRow != nil {
+			copy(values[rows:], lastRow[1:])
+		}
+	}
+	return newToeplitz(values, rows, cols, true)
+}
+
+// Create a Vandermonde matrix, whose rows hold the powers of x[i] from 0 to
+// degree. The result is len(x) x (degree+1), with x^0 in the first column if
+// increasing is true and in the last column otherwise. This is the design
+// matrix for fitting a polynomial of the given degree by least squares.
+func Vandermonde(x []float64, degree int, increasing bool) Matrix {
+	if degree < 0 {
+		panic(fmt.Sprintf("Can't create a Vandermonde matrix of degree %d", degree))
+	}
+	result := Dense(len(x), degree+1).M()
+	for row, v := range x {
+		power := 1.0
+		for p := 0; p <= degree; p++ {
+			col := p
+			if !increasing {
+				col = degree - p
+			}
+			result.ItemSet(power, row, col)
+			power *= v
+		}
+	}
+	return result
+}
+
+// Create the companion matrix of the polynomial with the given coefficients,
+// listed from the highest power down to the constant term. Its eigenvalues
+// are the roots of the polynomial. For a polynomial of degree n, the result
+// is n x n, with -coeffs[1:]/coeffs[0] as its first row and ones on its
+// subdiagonal, as in SciPy.
+func Companion(coeffs []float64) Matrix {
+	if len(coeffs) < 2 {
+		panic(fmt.Sprintf("Companion: need at least 2 coefficients, got %d", len(coeffs)))
+	} else if coeffs[0] == 0 {
+		panic("Companion: the leading coefficient can't be zero")
+	}
+	n := len(coeffs) - 1
+	result := Dense(n, n).M()
+	for col := 0; col < n; col++ {
+		result.ItemSet(-coeffs[col+1]/coeffs[0], 0, col)
+	}
+	for row := 1; row < n; row++ {
+		result.ItemSet(1, row, row-1)
+	}
+	return result
+}
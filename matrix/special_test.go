@@ -0,0 +1,164 @@
+package matrix
+
+import (
+	"errors"
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+func TestToeplitz(t *testing.T) {
+	Convey("Toeplitz is constant along its diagonals", t, func() {
+		m := Toeplitz([]float64{1, 2, 3}, []float64{9, 4, 5, 6})
+		So(m.Equal(M(3, 4,
+			1, 4, 5, 6,
+			2, 1, 4, 5,
+			3, 2, 1, 4)), ShouldBeTrue)
+	})
+
+	Convey("Toeplitz without a first row is symmetric", t, func() {
+		m := Toeplitz([]float64{1, 2, 3}, nil)
+		So(m.Equal(M(3, 3,
+			1, 2, 3,
+			2, 1, 2,
+			3, 2, 1)), ShouldBeTrue)
+		So(m.Equal(m.T()), ShouldBeTrue)
+	})
+
+	Convey("Circulant rotates its first column", t, func() {
+		m := Circulant([]float64{1, 2, 3})
+		So(m.Equal(M(3, 3,
+			1, 3, 2,
+			2, 1, 3,
+			3, 2, 1)), ShouldBeTrue)
+		So(Circulant(nil).Size(), ShouldEqual, 0)
+	})
+
+	Convey("Toeplitz matrices store only their diagonals", t, func() {
+		firstCol := []float64{1, 2, 0}
+		m := Toeplitz(firstCol, []float64{9, 0, 5, 6})
+		want := M(3, 4,
+			1, 0, 5, 6,
+			2, 1, 0, 5,
+			0, 2, 1, 0)
+		So(m.(*toeplitzMatrix).values, ShouldResemble, []float64{6, 5, 0, 1, 2, 0})
+		firstCol[0] = 7
+		So(m.Item(1, 1), ShouldEqual, 1)
+		So(m.FlatItem(7), ShouldEqual, 5)
+		So(m.Row(1), ShouldResemble, want.Row(1))
+		So(m.Col(2), ShouldResemble, want.Col(2))
+		So(m.Sum(), ShouldEqual, want.Sum())
+		So(m.CountNonzero(), ShouldEqual, want.CountNonzero())
+		So(m.T().Equal(want.T()), ShouldBeTrue)
+		So(m.T().Col(1), ShouldResemble, want.Row(1))
+		So(m.MProd(Ones(4, 1).M()).Equal(want.MProd(Ones(4, 1).M())), ShouldBeTrue)
+		So(try(func() { m.Item(3, 0) }), ShouldResemble,
+			ErrIndexOutOfRange{Op: "Item", Index: []int{3, 0}, Shape: []int{3, 4}})
+		So(errors.Is(TryItemSet(m, 1, 0, 0), ErrFrozen), ShouldBeTrue)
+	})
+
+	Convey("Empty Toeplitz matrices keep their shape", t, func() {
+		m := Toeplitz(nil, []float64{1, 2, 3})
+		So(m.Shape(), ShouldResemble, []int{0, 3})
+		So(m.Col(0), ShouldBeEmpty)
+		So(m.Sum(), ShouldEqual, 0)
+		So(m.T().Shape(), ShouldResemble, []int{3, 0})
+	})
+}
+
+func TestHankel(t *testing.T) {
+	Convey("Hankel is constant along its anti-diagonals", t, func() {
+		m := Hankel([]float64{1, 2, 3}, []float64{9, 4, 5, 6})
+		So(m.Equal(M(3, 4,
+			1, 2, 3, 4,
+			2, 3, 4, 5,
+			3, 4, 5, 6)), ShouldBeTrue)
+	})
+
+	Convey("Hankel without a last row is zero below the anti-diagonal", t, func() {
+		m := Hankel([]float64{1, 2, 3}, nil)
+		So(m.Equal(M(3, 3,
+			1, 2, 3,
+			2, 3, 0,
+			3, 0, 0)), ShouldBeTrue)
+	})
+
+	Convey("Hankel matrices store only their anti-diagonals", t, func() {
+		m := Hankel([]float64{1, 0, 3}, []float64{9, 4, 0, 6})
+		want := M(3, 4,
+			1, 0, 3, 4,
+			0, 3, 4, 0,
+			3, 4, 0, 6)
+		So(m.(*toeplitzMatrix).values, ShouldResemble, []float64{1, 0, 3, 4, 0, 6})
+		So(m.Item(2, 1), ShouldEqual, 4)
+		So(m.FlatItem(11), ShouldEqual, 6)
+		So(m.Row(1), ShouldResemble, want.Row(1))
+		So(m.Col(3), ShouldResemble, want.Col(3))
+		So(m.Sum(), ShouldEqual, want.Sum())
+		So(m.CountNonzero(), ShouldEqual, want.CountNonzero())
+		So(m.T().Equal(want.T()), ShouldBeTrue)
+		So(m.MProd(Ones(4, 1).M()).Equal(want.MProd(Ones(4, 1).M())), ShouldBeTrue)
+		So(errors.Is(TryItemSet(m, 1, 0, 0), ErrFrozen), ShouldBeTrue)
+		So(Hankel(nil, []float64{1, 2}).Shape(), ShouldResemble, []int{0, 2})
+	})
+}
+
+func TestVandermonde(t *testing.T) {
+	Convey("Vandermonde holds the powers of each value", t, func() {
+		x := []float64{2, 3, -1}
+		So(Vandermonde(x, 2, true).Equal(M(3, 3,
+			1, 2, 4,
+			1, 3, 9,
+			1, -1, 1)), ShouldBeTrue)
+		So(Vandermonde(x, 3, false).Equal(M(3, 4,
+			8, 4, 2, 1,
+			27, 9, 3, 1,
+			-1, 1, -1, 1)), ShouldBeTrue)
+		So(Vandermonde(x, 0, false).Equal(M(3, 1, 1, 1, 1)), ShouldBeTrue)
+		So(func() { Vandermonde(x, -1, true) }, ShouldPanic)
+	})
+}
+
+func TestCompanion(t *testing.T) {
+	Convey("Companion puts the scaled coefficients in the first row", t, func() {
+		// 2x^3 - 4x^2 + 6x - 8
+		So(Companion([]float64{2, -4, 6, -8}).Equal(M(3, 3,
+			2, -3, 4,
+			1, 0, 0,
+			0, 1, 0)), ShouldBeTrue)
+		So(Companion([]float64{1, 3}).Equal(M(1, 1, -3)), ShouldBeTrue)
+		So(func() { Companion([]float64{1}) }, ShouldPanic)
+		So(func() { Companion([]float64{0, 1, 2}) }, ShouldPanic)
+	})
+
+	Convey("The roots of the polynomial solve the characteristic equation", t, func() {
+		// (x - 1)(x - 2) = x^2 - 3x + 2, so C - 2I is singular
+		c := Companion([]float64{1, -3, 2})
+		_, err := Inverse(Sub(c, Diag(2, 2)).M())
+		So(err, ShouldNotBeNil)
+	})
+}
+
+func TestFromFunc(t *testing.T) {
+	Convey("FromFunc fills a dense matrix", t, func() {
+		m := FromFunc(2, 3, func(row, col int) float64 { return float64(10*row + col) })
+		So(m.Sparsity(), ShouldEqual, DenseArray)
+		So(m.Equal(M(2, 3, 0, 1, 2, 10, 11, 12)), ShouldBeTrue)
+	})
+
+	Convey("FromFuncSparse only evaluates the selected elements", t, func() {
+		calls := 0
+		m := FromFuncSparse(3, 3,
+			func(row, col int) bool { return row == col || row == col+1 },
+			func(row, col int) float64 {
+				calls++
+				return float64(row + col)
+			})
+		So(calls, ShouldEqual, 5)
+		So(m.Sparsity(), ShouldEqual, SparseCooMatrix)
+		So(m.Equal(M(3, 3,
+			0, 0, 0,
+			1, 2, 0,
+			0, 3, 4)), ShouldBeTrue)
+		So(CheckInvariants(m), ShouldBeNil)
+	})
+}
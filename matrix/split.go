@@ -0,0 +1,79 @@
+package matrix
+
+import (
+	"fmt"
+	"iter"
+	"math"
+	"math/rand"
+)
+
+// Split the rows of X, and the matching rows of y, at random into a training
+// set and a test set holding about frac of the rows. The results are
+// read-only views created by RowSubset(), so no data is copied. y may be nil,
+// in which case yTrain and yTest are nil. src is the source of random numbers
+// for choosing the rows; seed it to get a reproducible split, or pass nil to
+// use the global source.
+func TrainTestSplit(X, y Matrix, frac float64, src rand.Source) (XTrain, XTest, yTrain, yTest Matrix) {
+	debugCheck("TrainTestSplit", X)
+	if frac < 0 || frac > 1 || math.IsNaN(frac) {
+		panic(fmt.Sprintf("TrainTestSplit: test fraction %v should be in [0, 1]", frac))
+	}
+	if y != nil && y.Rows() != X.Rows() {
+		panic(ErrShapeMismatch{Op: "TrainTestSplit", Got: y.Shape(), Want: []int{X.Rows(), -1}})
+	}
+	perm := rand.Perm
+	if src != nil {
+		perm = rand.New(src).Perm
+	}
+	rows := perm(X.Rows())
+	nTest := int(math.Round(frac * float64(len(rows))))
+	train, test := rows[nTest:], rows[:nTest]
+
+	XTrain, XTest = RowSubset(X, train), RowSubset(X, test)
+	if y != nil {
+		yTrain, yTest = RowSubset(y, train), RowSubset(y, test)
+	}
+	return XTrain, XTest, yTrain, yTest
+}
+
+// Iterate over the k folds of k-fold cross-validation on n rows, yielding the
+// indices of the training rows and the test rows for each fold. The test
+// rows of the folds are consecutive, disjoint, and together cover all the
+// rows; the first n % k folds have one more row than the rest. Shuffle the
+// rows first, such as with PermuteRows(), if they are ordered. Use
+// RowSubset() to get views of the rows:
+//
+//	for train, test := range KFold(X.Rows(), 5) {
+//	    coef, predict := FitLinear(RowSubset(X, train), RowSubset(y, train))
+//	    ...
+//	}
+//
+// The index slices are reused between folds, so copy them to keep them.
+func KFold(n, k int) iter.Seq2[[]int, []int] {
+	if k < 2 || k > n {
+		panic(fmt.Sprintf("KFold: can't split %d rows into %d folds", n, k))
+	}
+	return func(yield func(train, test []int) bool) {
+		train := make([]int, 0, n)
+		test := make([]int, 0, n/k+1)
+		start := 0
+		for fold := 0; fold < k; fold++ {
+			size := n / k
+			if fold < n%k {
+				size++
+			}
+			train, test = train[:0], test[:0]
+			for row := 0; row < n; row++ {
+				if row >= start && row < start+size {
+					test = append(test, row)
+				} else {
+					train = append(train, row)
+				}
+			}
+			if !yield(train, test) {
+				return
+			}
+			start += size
+		}
+	}
+}
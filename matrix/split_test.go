@@ -0,0 +1,68 @@
+package matrix
+
+import (
+	. "github.com/smartystreets/goconvey/convey"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func TestTrainTestSplit(t *testing.T) {
+	Convey("Given data with matching labels", t, func() {
+		X := FromFunc(10, 2, func(row, col int) float64 { return float64(10*row + col) })
+		y := FromFunc(10, 1, func(row, col int) float64 { return float64(row) })
+
+		Convey("The rows are split without losing any", func() {
+			XTrain, XTest, yTrain, yTest := TrainTestSplit(X, y, 0.3, rand.NewSource(1))
+			So(XTrain.Rows(), ShouldEqual, 7)
+			So(XTest.Rows(), ShouldEqual, 3)
+			var seen []int
+			for _, part := range [][]Matrix{{XTrain, yTrain}, {XTest, yTest}} {
+				for i := 0; i < part[0].Rows(); i++ {
+					row := int(part[1].Item(i, 0))
+					So(part[0].Item(i, 0), ShouldEqual, 10*row)
+					seen = append(seen, row)
+				}
+			}
+			sort.Ints(seen)
+			So(seen, ShouldResemble, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9})
+		})
+
+		Convey("Seeded splits are reproducible", func() {
+			_, a, _, _ := TrainTestSplit(X, nil, 0.5, rand.NewSource(3))
+			_, b, yTrain, _ := TrainTestSplit(X, nil, 0.5, rand.NewSource(3))
+			So(a.Equal(b), ShouldBeTrue)
+			So(yTrain, ShouldBeNil)
+		})
+
+		Convey("Invalid arguments panic", func() {
+			So(func() { TrainTestSplit(X, y, 1.5, nil) }, ShouldPanic)
+			So(func() { TrainTestSplit(X, M(1, 1, 0), 0.5, nil) }, ShouldPanic)
+		})
+	})
+}
+
+func TestKFold(t *testing.T) {
+	Convey("KFold covers every row once", t, func() {
+		var tests [][]int
+		for train, test := range KFold(7, 3) {
+			So(len(train)+len(test), ShouldEqual, 7)
+			tests = append(tests, append([]int(nil), test...))
+		}
+		So(tests, ShouldResemble, [][]int{{0, 1, 2}, {3, 4}, {5, 6}})
+	})
+
+	Convey("KFold stops early", t, func() {
+		folds := 0
+		for range KFold(10, 5) {
+			folds++
+			break
+		}
+		So(folds, ShouldEqual, 1)
+	})
+
+	Convey("Invalid folds panic", t, func() {
+		So(func() { KFold(3, 1) }, ShouldPanic)
+		So(func() { KFold(3, 4) }, ShouldPanic)
+	})
+}
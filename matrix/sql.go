@@ -0,0 +1,151 @@
+package matrix
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// Wraps an array to store it in a JSON database column, such as a PostgreSQL
+// JSONB column. Arrays are stored as {"shape": [...], "values": [...]}, with
+// the values in row-major order. JSON can't represent NaN or infinite values,
+// so arrays containing them can't be stored.
+//
+// To store and load an array:
+//
+//	db.Exec("INSERT INTO weights (w) VALUES ($1)", matrix.JSON{Array: w})
+//	var j matrix.JSON
+//	db.QueryRow("SELECT w FROM weights").Scan(&j)
+type JSON struct {
+	Array NDArray
+}
+
+// The JSON representation of an array
+type jsonArray struct {
+	Shape  []int     `json:"shape"`
+	Values []float64 `json:"values"`
+}
+
+// Encode the wrapped array as JSON, implementing driver.Valuer. A nil array
+// is stored as NULL.
+func (j JSON) Value() (driver.Value, error) {
+	if j.Array == nil {
+		return nil, nil
+	}
+	return json.Marshal(jsonArray{
+		Shape:  j.Array.Shape(),
+		Values: j.Array.Array(),
+	})
+}
+
+// Read an array from a database column, implementing sql.Scanner. Accepts
+// both JSON and the package's binary format. NULL values give a nil array.
+func (j *JSON) Scan(src interface{}) error {
+	if src == nil {
+		j.Array = nil
+		return nil
+	}
+	array, err := scanArray(src)
+	if err != nil {
+		return err
+	}
+	j.Array = array
+	return nil
+}
+
+// Encode an array in the package's binary format for storage in a database
+// column. This is the format read and written by Encoder and Decoder.
+func Value(array NDArray) (driver.Value, error) {
+	return appendBinary(nil, array), nil
+}
+
+// Decode an array from a database value holding either JSON or the package's
+// binary format
+func scanArray(src interface{}) (NDArray, error) {
+	var data []byte
+	switch src := src.(type) {
+	case []byte:
+		data = src
+	case string:
+		data = []byte(src)
+	case nil:
+		return nil, fmt.Errorf("Can't scan NULL into an array")
+	default:
+		return nil, fmt.Errorf("Can't scan a %T into an array", src)
+	}
+
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		var j jsonArray
+		if err := json.Unmarshal(trimmed, &j); err != nil {
+			return nil, err
+		}
+		size := 1
+		for _, dim := range j.Shape {
+			if dim < 0 {
+				return nil, fmt.Errorf("Invalid JSON array shape %v", j.Shape)
+			}
+			size *= dim
+		}
+		if len(j.Shape) == 0 || len(j.Values) != size {
+			return nil, fmt.Errorf("JSON array of shape %v has %d values", j.Shape, len(j.Values))
+		}
+		return &denseF64Array{shape: j.Shape, array: j.Values}, nil
+	}
+	return parseBinary(data)
+}
+
+// Scan a database value into a dense array
+func scanDense(array *denseF64Array, src interface{}) error {
+	result, err := scanArray(src)
+	if err != nil {
+		return err
+	}
+	*array = denseF64Array{
+		shape: result.Shape(),
+		array: result.Array(),
+	}
+	return nil
+}
+
+// Scan a database value into a sparse coo matrix
+func scanSparseCoo(array *sparseCooF64Matrix, src interface{}) error {
+	result, err := scanArray(src)
+	if err != nil {
+		return err
+	} else if result.NDim() != 2 {
+		return fmt.Errorf("Can't scan an array of shape %v into a sparse coo matrix", result.Shape())
+	}
+	*array = *result.M().SparseCoo().(*sparseCooF64Matrix)
+	return nil
+}
+
+// Scan a database value into a compressed sparse matrix, keeping its format
+func scanSparseCompressed(array *sparseCompressedF64Matrix, src interface{}) error {
+	result, err := scanArray(src)
+	if err != nil {
+		return err
+	} else if result.NDim() != 2 {
+		return fmt.Errorf("Can't scan an array of shape %v into a %v matrix", result.Shape(), array.Sparsity())
+	}
+	*array = *newCompressed(result.M(), array.byCol)
+	return nil
+}
+
+// Scan a database value into a sparse diag matrix
+func scanSparseDiag(array *sparseDiagF64Matrix, src interface{}) error {
+	result, err := scanArray(src)
+	if err != nil {
+		return err
+	} else if result.NDim() != 2 {
+		return fmt.Errorf("Can't scan an array of shape %v into a sparse diag matrix", result.Shape())
+	}
+	offDiag := !result.VisitNonzero(func(pos []int, value float64) bool {
+		return pos[0] == pos[1]
+	})
+	if offDiag {
+		return fmt.Errorf("Can't scan a matrix with off-diagonal values into a sparse diag matrix")
+	}
+	*array = *result.M().SparseDiag().(*sparseDiagF64Matrix)
+	return nil
+}
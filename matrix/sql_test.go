@@ -0,0 +1,91 @@
+package matrix
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	. "github.com/smartystreets/goconvey/convey"
+	"math"
+	"testing"
+)
+
+func TestSQL(t *testing.T) {
+	Convey("Given arrays of each storage format", t, func() {
+		dense := M(2, 3,
+			1, 2, 3,
+			4, 5, 6)
+		coo := SparseCoo(2, 3,
+			0, 2, 0,
+			3, 0, 0)
+		diag := Diag(7, 8)
+
+		Convey("They implement sql.Scanner and driver.Valuer", func() {
+			for _, array := range []NDArray{dense, coo, diag} {
+				So(array, ShouldImplement, (*sql.Scanner)(nil))
+				So(array, ShouldImplement, (*driver.Valuer)(nil))
+			}
+		})
+
+		Convey("Value and Scan round trip them", func() {
+			for _, array := range []NDArray{dense, dense.T(), coo, coo.T(), diag} {
+				v, err := array.Value()
+				So(err, ShouldBeNil)
+				So(driver.IsValue(v), ShouldBeTrue)
+
+				result := Dense(1, 1)
+				So(result.Scan(v), ShouldBeNil)
+				So(result.Shape(), ShouldResemble, array.Shape())
+				So(result.Equal(array), ShouldBeTrue)
+				So(result.Sparsity(), ShouldEqual, DenseArray)
+			}
+		})
+
+		Convey("Scan keeps the receiver's storage format", func() {
+			v, _ := dense.Value()
+			result := SparseCoo(1, 1)
+			So(result.Scan(v), ShouldBeNil)
+			So(result.Sparsity(), ShouldEqual, SparseCooMatrix)
+			So(result.Equal(dense), ShouldBeTrue)
+
+			v, _ = diag.Dense().Value()
+			result = SparseDiag(1, 1)
+			So(result.Scan(v), ShouldBeNil)
+			So(result.Sparsity(), ShouldEqual, SparseDiagMatrix)
+			So(result.Equal(diag), ShouldBeTrue)
+
+			v, _ = dense.Value()
+			So(SparseDiag(1, 1).Scan(v), ShouldNotBeNil)
+			v, _ = Rand(2, 3, 4).Value()
+			So(SparseCoo(1, 1).Scan(v), ShouldNotBeNil)
+		})
+
+		Convey("JSON stores them as JSON", func() {
+			v, err := JSON{Array: coo}.Value()
+			So(err, ShouldBeNil)
+			So(string(v.([]byte)), ShouldEqual, `{"shape":[2,3],"values":[0,2,0,3,0,0]}`)
+
+			var j JSON
+			So(j.Scan(v), ShouldBeNil)
+			So(j.Array.Equal(coo), ShouldBeTrue)
+
+			result := SparseCoo(1, 1)
+			So(result.Scan(string(v.([]byte))), ShouldBeNil)
+			So(result.Equal(coo), ShouldBeTrue)
+
+			So(j.Scan(nil), ShouldBeNil)
+			So(j.Array, ShouldBeNil)
+			v, err = j.Value()
+			So(err, ShouldBeNil)
+			So(v, ShouldBeNil)
+
+			_, err = JSON{Array: A1(math.NaN())}.Value()
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("Invalid values are an error", func() {
+			So(Dense(1, 1).Scan(nil), ShouldNotBeNil)
+			So(Dense(1, 1).Scan(42), ShouldNotBeNil)
+			So(Dense(1, 1).Scan(`{"shape":[2,2],"values":[1,2,3]}`), ShouldNotBeNil)
+			So(Dense(1, 1).Scan([]byte{1, 0}), ShouldNotBeNil)
+		})
+	})
+}
@@ -0,0 +1,950 @@
+package matrix
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Get the vectors along the specified axis of a matrix. For axis 0, this
+// returns one vector per column (running down the rows); for axis 1, it
+// returns one vector per row. This mirrors NumPy's axis convention: reducing
+// over axis 0 gives one result per column.
+func axisVectors(m Matrix, axis int) [][]float64 {
+	rows, cols := m.Rows(), m.Cols()
+	switch axis {
+	case 0:
+		result := make([][]float64, cols)
+		for col := 0; col < cols; col++ {
+			result[col] = make([]float64, rows)
+			for row := 0; row < rows; row++ {
+				result[col][row] = m.Item(row, col)
+			}
+		}
+		return result
+	case 1:
+		result := make([][]float64, rows)
+		for row := 0; row < rows; row++ {
+			result[row] = make([]float64, cols)
+			for col := 0; col < cols; col++ {
+				result[row][col] = m.Item(row, col)
+			}
+		}
+		return result
+	default:
+		panic(fmt.Sprintf("Invalid axis %d for a 2-dim matrix", axis))
+	}
+}
+
+// Get the position of the first occurrence of the largest element of a
+// vector, or of the first NaN if there is one. Returns -1 for an empty vector.
+func argMaxVec(vec []float64) int {
+	best := -1
+	for idx, v := range vec {
+		if math.IsNaN(v) {
+			return idx
+		} else if best < 0 || v > vec[best] {
+			best = idx
+		}
+	}
+	return best
+}
+
+// Get the position of the first occurrence of the smallest element of a
+// vector, or of the first NaN if there is one. Returns -1 for an empty vector.
+func argMinVec(vec []float64) int {
+	best := -1
+	for idx, v := range vec {
+		if math.IsNaN(v) {
+			return idx
+		} else if best < 0 || v < vec[best] {
+			best = idx
+		}
+	}
+	return best
+}
+
+// Get the row and column of the largest matrix element. Ties are broken in
+// favor of the first element in 'C' order. As in NumPy, if the matrix
+// contains NaN, the position of the first NaN is returned.
+func ArgMax(m Matrix) (i, j int) {
+	if m.Size() == 0 {
+		panic("Can't get the ArgMax of an empty matrix")
+	}
+	for row := 0; row < m.Rows(); row++ {
+		for col := 0; col < m.Cols(); col++ {
+			if v := m.Item(row, col); math.IsNaN(v) {
+				return row, col
+			} else if v > m.Item(i, j) {
+				i, j = row, col
+			}
+		}
+	}
+	return i, j
+}
+
+// Get the index of the largest element along each vector of the specified
+// axis. For axis 0, the result holds the row of the largest element in each
+// column; for axis 1, it holds the column of the largest element in each row.
+// NaNs are handled as for ArgMax().
+func ArgMaxAxis(m Matrix, axis int) []int {
+	vecs := axisVectors(m, axis)
+	result := make([]int, len(vecs))
+	for idx, vec := range vecs {
+		result[idx] = argMaxVec(vec)
+	}
+	return result
+}
+
+// Get the row and column of the smallest matrix element. Ties are broken in
+// favor of the first element in 'C' order. As in NumPy, if the matrix
+// contains NaN, the position of the first NaN is returned.
+func ArgMin(m Matrix) (i, j int) {
+	if m.Size() == 0 {
+		panic("Can't get the ArgMin of an empty matrix")
+	}
+	for row := 0; row < m.Rows(); row++ {
+		for col := 0; col < m.Cols(); col++ {
+			if v := m.Item(row, col); math.IsNaN(v) {
+				return row, col
+			} else if v < m.Item(i, j) {
+				i, j = row, col
+			}
+		}
+	}
+	return i, j
+}
+
+// Get the index of the smallest element along each vector of the specified
+// axis. For axis 0, the result holds the row of the smallest element in each
+// column; for axis 1, it holds the column of the smallest element in each row.
+// NaNs are handled as for ArgMin().
+func ArgMinAxis(m Matrix, axis int) []int {
+	vecs := axisVectors(m, axis)
+	result := make([]int, len(vecs))
+	for idx, vec := range vecs {
+		result[idx] = argMinVec(vec)
+	}
+	return result
+}
+
+// A running accumulator for the mean and variance of a stream of values,
+// using Welford's single-pass algorithm for numerical stability.
+type welford struct {
+	count int
+	mean  float64
+	m2    float64
+}
+
+// Add a value to the accumulator
+func (w *welford) add(value float64) {
+	w.count++
+	delta := value - w.mean
+	w.mean += delta / float64(w.count)
+	w.m2 += delta * (value - w.mean)
+}
+
+// Remove a value which was previously added to the accumulator
+func (w *welford) remove(value float64) {
+	w.count--
+	if w.count == 0 {
+		w.mean, w.m2 = 0, 0
+		return
+	}
+	delta := value - w.mean
+	w.mean -= delta / float64(w.count)
+	w.m2 -= delta * (value - w.mean)
+	if w.m2 < 0 {
+		// Guard against rounding error
+		w.m2 = 0
+	}
+}
+
+// Get the variance of the values seen so far, with ddof delta degrees of
+// freedom. Returns NaN if there are not more than ddof values.
+func (w *welford) variance(ddof int) float64 {
+	if w.count <= ddof {
+		return math.NaN()
+	}
+	return w.m2 / float64(w.count-ddof)
+}
+
+// Run a Welford accumulator over each vector along the specified axis, in a
+// single pass over the matrix.
+func axisWelford(m Matrix, axis int) []welford {
+	rows, cols := m.Rows(), m.Cols()
+	var acc []welford
+	switch axis {
+	case 0:
+		acc = make([]welford, cols)
+	case 1:
+		acc = make([]welford, rows)
+	default:
+		panic(fmt.Sprintf("Invalid axis %d for a 2-dim matrix", axis))
+	}
+	for row := 0; row < rows; row++ {
+		for col := 0; col < cols; col++ {
+			if axis == 0 {
+				acc[col].add(m.Item(row, col))
+			} else {
+				acc[row].add(m.Item(row, col))
+			}
+		}
+	}
+	return acc
+}
+
+// Get the variance along the specified axis, with ddof delta degrees of
+// freedom: the sum of squared deviations is divided by N - ddof. Use ddof=0
+// for the population variance and ddof=1 for the unbiased sample variance.
+// Vectors with no more than ddof elements have variance NaN.
+func Var(m Matrix, axis int, ddof int) []float64 {
+	acc := axisWelford(m, axis)
+	result := make([]float64, len(acc))
+	for idx := range acc {
+		result[idx] = acc[idx].variance(ddof)
+	}
+	return result
+}
+
+// Get the standard deviation along the specified axis, with ddof delta
+// degrees of freedom. See Var() for details.
+func Std(m Matrix, axis int, ddof int) []float64 {
+	result := Var(m, axis, ddof)
+	for idx, v := range result {
+		result[idx] = math.Sqrt(v)
+	}
+	return result
+}
+
+// Interpolation methods for quantiles which fall between two data points
+type QuantileInterp int
+
+const (
+	// Interpolate linearly between the two nearest data points
+	LinearInterp QuantileInterp = iota
+
+	// Use the lower of the two nearest data points
+	LowerInterp
+
+	// Use the higher of the two nearest data points
+	HigherInterp
+
+	// Use the nearest data point, rounding half to even
+	NearestInterp
+
+	// Use the average of the two nearest data points
+	MidpointInterp
+)
+
+// Ask whether a is ordered before b when sorting, which places NaNs after
+// all other values as NumPy does
+func lessNaNLast(a, b float64) bool {
+	return a < b || (!math.IsNaN(a) && math.IsNaN(b))
+}
+
+// Rearrange vec so that vec[k] holds the value it would hold if vec were
+// sorted, with smaller values before it and larger values after it. NaNs
+// sort after all other values. Runs in expected linear time.
+func selectKth(vec []float64, k int) float64 {
+	lo, hi := 0, len(vec)-1
+	for lo < hi {
+		pivot := vec[lo+(hi-lo)/2]
+
+		// Three-way partition into [< pivot | == pivot | > pivot]
+		lt, i, gt := lo, lo, hi
+		for i <= gt {
+			switch {
+			case lessNaNLast(vec[i], pivot):
+				vec[lt], vec[i] = vec[i], vec[lt]
+				lt++
+				i++
+			case lessNaNLast(pivot, vec[i]):
+				vec[gt], vec[i] = vec[i], vec[gt]
+				gt--
+			default:
+				i++
+			}
+		}
+
+		switch {
+		case k < lt:
+			hi = lt - 1
+		case k > gt:
+			lo = gt + 1
+		default:
+			return vec[k]
+		}
+	}
+	return vec[k]
+}
+
+// Get the q-th quantile of a vector, reordering it in the process. The
+// quantile of a vector containing NaN is NaN.
+func quantileVec(vec []float64, q float64, interp QuantileInterp) float64 {
+	if len(vec) == 0 {
+		return math.NaN()
+	}
+	for _, v := range vec {
+		if math.IsNaN(v) {
+			return math.NaN()
+		}
+	}
+	h := float64(len(vec)-1) * q
+	lo := int(math.Floor(h))
+	loVal := selectKth(vec, lo)
+	hiVal := loVal
+	if float64(lo) < h {
+		// After selection, the next order statistic is the smallest of the rest
+		hiVal = vec[lo+1]
+		for _, v := range vec[lo+2:] {
+			if v < hiVal {
+				hiVal = v
+			}
+		}
+	}
+
+	switch interp {
+	case LinearInterp:
+		return loVal + (h-float64(lo))*(hiVal-loVal)
+	case LowerInterp:
+		return loVal
+	case HigherInterp:
+		return hiVal
+	case NearestInterp:
+		if math.RoundToEven(h) > float64(lo) {
+			return hiVal
+		}
+		return loVal
+	case MidpointInterp:
+		return (loVal + hiVal) / 2
+	default:
+		panic(fmt.Sprintf("Can't calculate quantile with invalid interpolation %v", interp))
+	}
+}
+
+// Get the q-th quantile along the specified axis, for q in [0, 1]. When the
+// quantile falls between two data points, interp determines the result.
+// Quantiles are found by selection rather than by sorting each vector. As in
+// NumPy, the quantile of a vector containing NaN is NaN.
+func Quantile(m Matrix, q float64, axis int, interp QuantileInterp) []float64 {
+	if q < 0 || q > 1 || math.IsNaN(q) {
+		panic(fmt.Sprintf("Can't calculate quantile %f: q should be in [0, 1]", q))
+	}
+	vecs := axisVectors(m, axis)
+	result := make([]float64, len(vecs))
+	for idx, vec := range vecs {
+		result[idx] = quantileVec(vec, q, interp)
+	}
+	return result
+}
+
+// Get the median along the specified axis. For vectors of even length, this
+// is the average of the two middle elements.
+func Median(m Matrix, axis int) []float64 {
+	return Quantile(m, 0.5, axis, LinearInterp)
+}
+
+// Get a copy of a vector, centered to have mean zero and scaled to have unit
+// L2 norm. If the vector is constant, all values in the result are NaN.
+func centerNormalize(vec []float64) []float64 {
+	var mean float64
+	for _, v := range vec {
+		mean += v
+	}
+	mean /= float64(len(vec))
+
+	result := make([]float64, len(vec))
+	var norm float64
+	for idx, v := range vec {
+		result[idx] = v - mean
+		norm += result[idx] * result[idx]
+	}
+	norm = math.Sqrt(norm)
+	for idx := range result {
+		result[idx] /= norm
+	}
+	return result
+}
+
+// Get the Pearson correlation between two vectors which have already been
+// passed through centerNormalize().
+func normedCorr(a, b []float64) float64 {
+	var r float64
+	for idx, v := range a {
+		r += v * b[idx]
+	}
+
+	// Guard against rounding error pushing us outside [-1, 1]
+	return math.Max(-1, math.Min(1, r))
+}
+
+// Get the matrix of correlations between the rows of a matrix, given a
+// function to transform each row before taking the Pearson correlation.
+func corrCoef(m Matrix, transform func([]float64) []float64) Matrix {
+	rows := axisVectors(m, 1)
+	for idx, row := range rows {
+		rows[idx] = centerNormalize(transform(row))
+	}
+	result := Dense(len(rows), len(rows)).M()
+	for i := range rows {
+		for j := 0; j <= i; j++ {
+			r := normedCorr(rows[i], rows[j])
+			result.ItemSet(r, i, j)
+			result.ItemSet(r, j, i)
+		}
+	}
+	return result
+}
+
+// Get the matrix of Pearson correlation coefficients between the rows of a
+// matrix. As in NumPy, each row is treated as a variable and each column as an
+// observation; the result R has R_i,j equal to the correlation between rows i
+// and j. Rows with zero variance have NaN correlation.
+func CorrCoef(m Matrix) Matrix {
+	return corrCoef(m, func(row []float64) []float64 { return row })
+}
+
+// Get the matrix of Spearman rank correlation coefficients between the rows of
+// a matrix. This is the Pearson correlation of the rows after replacing each
+// value by its rank within the row; tied values receive their average rank.
+// Rows containing NaN have NaN correlations.
+func SpearmanCorrCoef(m Matrix) Matrix {
+	return corrCoef(m, func(row []float64) []float64 {
+		return rankVec(row, AverageRank)
+	})
+}
+
+// Methods for assigning ranks to tied values
+type RankTies int
+
+const (
+	// Tied values get the average of the ranks they span
+	AverageRank RankTies = iota
+
+	// Tied values get the smallest of the ranks they span
+	MinRank
+
+	// Tied values get the largest of the ranks they span
+	MaxRank
+
+	// Tied values get the same rank, and the next larger value gets the next
+	// rank, so there are no gaps between ranks
+	DenseRank
+
+	// Tied values get distinct ranks in the order they appear
+	OrdinalRank
+)
+
+// Get the ranks of the elements of a vector, starting from 1. Ties are
+// assigned ranks using the specified method. NaNs are sorted after the other
+// values and ranked NaN, so they don't affect the ranks of the others.
+func rankVec(vec []float64, ties RankTies) []float64 {
+	order := make([]int, len(vec))
+	for idx := range order {
+		order[idx] = idx
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return lessNaNLast(vec[order[a]], vec[order[b]])
+	})
+
+	ranks := make([]float64, len(vec))
+	dense := 0
+	for start := 0; start < len(order); {
+		if math.IsNaN(vec[order[start]]) {
+			for _, idx := range order[start:] {
+				ranks[idx] = math.NaN()
+			}
+			break
+		}
+		end := start + 1
+		for end < len(order) && vec[order[end]] == vec[order[start]] {
+			end++
+		}
+		dense++
+		for pos, idx := range order[start:end] {
+			switch ties {
+			case AverageRank:
+				ranks[idx] = float64(start+end+1) / 2
+			case MinRank:
+				ranks[idx] = float64(start + 1)
+			case MaxRank:
+				ranks[idx] = float64(end)
+			case DenseRank:
+				ranks[idx] = float64(dense)
+			case OrdinalRank:
+				ranks[idx] = float64(start + pos + 1)
+			default:
+				panic(fmt.Sprintf("Can't rank with invalid tie method %v", ties))
+			}
+		}
+		start = end
+	}
+	return ranks
+}
+
+// Replace each element with its rank within its row, starting from 1. Ties
+// are assigned ranks using the specified method. As with scipy's default
+// nan_policy, NaNs are ranked NaN and the other elements are ranked as if
+// they weren't there. Returns a dense matrix.
+func RankRows(m Matrix, ties RankTies) Matrix {
+	result := Dense(m.Rows(), m.Cols()).M()
+	for row, vec := range axisVectors(m, 1) {
+		result.RowSet(row, rankVec(vec, ties))
+	}
+	return result
+}
+
+// Replace each element with its rank within its column, starting from 1. Ties
+// are assigned ranks using the specified method. NaNs are handled as for
+// RankRows(). Returns a dense matrix.
+func RankCols(m Matrix, ties RankTies) Matrix {
+	result := Dense(m.Rows(), m.Cols()).M()
+	for col, vec := range axisVectors(m, 0) {
+		result.ColSet(col, rankVec(vec, ties))
+	}
+	return result
+}
+
+// A running accumulator for the weighted mean and variance of a stream of
+// values, using West's weighted extension of Welford's algorithm.
+type weightedWelford struct {
+	weight float64
+	mean   float64
+	m2     float64
+}
+
+// Add a value with the specified weight to the accumulator
+func (w *weightedWelford) add(value, weight float64) {
+	if weight == 0 {
+		return
+	}
+	w.weight += weight
+	delta := value - w.mean
+	w.mean += delta * weight / w.weight
+	w.m2 += weight * delta * (value - w.mean)
+}
+
+// Run a weighted Welford accumulator over each vector along the specified
+// axis. The weights are indexed by position along the axis, so there should be
+// one weight per row for axis 0 and one per column for axis 1.
+func axisWeightedWelford(m Matrix, weights []float64, axis int) []weightedWelford {
+	rows, cols := m.Rows(), m.Cols()
+	var acc []weightedWelford
+	switch axis {
+	case 0:
+		if len(weights) != rows {
+			panic(fmt.Sprintf("Can't weight %d rows with %d weights", rows, len(weights)))
+		}
+		acc = make([]weightedWelford, cols)
+	case 1:
+		if len(weights) != cols {
+			panic(fmt.Sprintf("Can't weight %d columns with %d weights", cols, len(weights)))
+		}
+		acc = make([]weightedWelford, rows)
+	default:
+		panic(fmt.Sprintf("Invalid axis %d for a 2-dim matrix", axis))
+	}
+	for _, w := range weights {
+		if w < 0 || math.IsNaN(w) {
+			panic(fmt.Sprintf("Invalid weight %v: weights should be non-negative", w))
+		}
+	}
+	for row := 0; row < rows; row++ {
+		for col := 0; col < cols; col++ {
+			if axis == 0 {
+				acc[col].add(m.Item(row, col), weights[row])
+			} else {
+				acc[row].add(m.Item(row, col), weights[col])
+			}
+		}
+	}
+	return acc
+}
+
+// Get the weighted mean along the specified axis. There should be one
+// non-negative weight per row for axis 0, and one per column for axis 1. If
+// all weights are zero, the result is NaN.
+func WeightedMean(m Matrix, weights []float64, axis int) []float64 {
+	acc := axisWeightedWelford(m, weights, axis)
+	result := make([]float64, len(acc))
+	for idx := range acc {
+		if acc[idx].weight == 0 {
+			result[idx] = math.NaN()
+		} else {
+			result[idx] = acc[idx].mean
+		}
+	}
+	return result
+}
+
+// Get the weighted variance along the specified axis: the weighted mean of
+// the squared deviations from the weighted mean. Weights are given as in
+// WeightedMean(). If all weights are zero, the result is NaN.
+func WeightedVar(m Matrix, weights []float64, axis int) []float64 {
+	acc := axisWeightedWelford(m, weights, axis)
+	result := make([]float64, len(acc))
+	for idx := range acc {
+		if acc[idx].weight == 0 {
+			result[idx] = math.NaN()
+		} else {
+			result[idx] = acc[idx].m2 / acc[idx].weight
+		}
+	}
+	return result
+}
+
+// Get the sum and the number of non-NaN elements of a vector, skipping NaNs
+func nanSumVec(vec []float64) (sum float64, count int) {
+	for _, v := range vec {
+		if !math.IsNaN(v) {
+			sum += v
+			count++
+		}
+	}
+	return sum, count
+}
+
+// Get the largest non-NaN element of a vector and the number of non-NaN
+// elements. If all elements are NaN, the maximum is NaN.
+func nanMaxVec(vec []float64) (max float64, count int) {
+	max = math.NaN()
+	for _, v := range vec {
+		if !math.IsNaN(v) {
+			if count == 0 || v > max {
+				max = v
+			}
+			count++
+		}
+	}
+	return max, count
+}
+
+// Get the smallest non-NaN element of a vector and the number of non-NaN
+// elements. If all elements are NaN, the minimum is NaN.
+func nanMinVec(vec []float64) (min float64, count int) {
+	min = math.NaN()
+	for _, v := range vec {
+		if !math.IsNaN(v) {
+			if count == 0 || v < min {
+				min = v
+			}
+			count++
+		}
+	}
+	return min, count
+}
+
+// Apply a NaN-skipping reduction to each vector along the specified axis,
+// returning the results and the number of non-NaN elements in each vector.
+func nanReduceAxis(m Matrix, axis int, f func([]float64) (float64, int)) ([]float64, []int) {
+	vecs := axisVectors(m, axis)
+	result := make([]float64, len(vecs))
+	counts := make([]int, len(vecs))
+	for idx, vec := range vecs {
+		result[idx], counts[idx] = f(vec)
+	}
+	return result, counts
+}
+
+// Apply a NaN-skipping reduction to the nonzero elements of an array, plus a
+// single zero if the array has any implicit or explicit zeros. Returns the
+// result and the number of non-NaN elements.
+func nanReduce(array NDArray, f func([]float64) (float64, int)) (float64, int) {
+	var values []float64
+	array.VisitNonzero(func(pos []int, value float64) bool {
+		values = append(values, value)
+		return true
+	})
+	zeros := array.Size() - len(values)
+	if zeros > 0 {
+		values = append(values, 0)
+	}
+	result, count := f(values)
+	if zeros > 0 {
+		count += zeros - 1
+	}
+	return result, count
+}
+
+// Get the sum of all array elements, treating NaNs as missing. Also returns the
+// number of non-NaN elements.
+func NaNSum(array NDArray) (sum float64, count int) {
+	return nanReduce(array, nanSumVec)
+}
+
+// Get the sum along the specified axis, treating NaNs as missing. Also returns
+// the number of non-NaN elements in each vector along the axis.
+func NaNSumAxis(m Matrix, axis int) (sums []float64, counts []int) {
+	return nanReduceAxis(m, axis, nanSumVec)
+}
+
+// Get the mean of all array elements, treating NaNs as missing. Also returns
+// the number of non-NaN elements. The mean is NaN if all elements are NaN.
+func NaNMean(array NDArray) (mean float64, count int) {
+	sum, count := NaNSum(array)
+	return sum / float64(count), count
+}
+
+// Get the mean along the specified axis, treating NaNs as missing. Also
+// returns the number of non-NaN elements in each vector along the axis. The
+// mean of a vector which is entirely NaN is NaN.
+func NaNMeanAxis(m Matrix, axis int) (means []float64, counts []int) {
+	means, counts = NaNSumAxis(m, axis)
+	for idx := range means {
+		means[idx] /= float64(counts[idx])
+	}
+	return means, counts
+}
+
+// Get the largest array element, treating NaNs as missing. Also returns the
+// number of non-NaN elements. The maximum is NaN if all elements are NaN.
+func NaNMax(array NDArray) (max float64, count int) {
+	return nanReduce(array, nanMaxVec)
+}
+
+// Get the largest element along the specified axis, treating NaNs as missing.
+// Also returns the number of non-NaN elements in each vector along the axis.
+func NaNMaxAxis(m Matrix, axis int) (maxs []float64, counts []int) {
+	return nanReduceAxis(m, axis, nanMaxVec)
+}
+
+// Get the smallest array element, treating NaNs as missing. Also returns the
+// number of non-NaN elements. The minimum is NaN if all elements are NaN.
+func NaNMin(array NDArray) (min float64, count int) {
+	return nanReduce(array, nanMinVec)
+}
+
+// Get the smallest element along the specified axis, treating NaNs as missing.
+// Also returns the number of non-NaN elements in each vector along the axis.
+func NaNMinAxis(m Matrix, axis int) (mins []float64, counts []int) {
+	return nanReduceAxis(m, axis, nanMinVec)
+}
+
+// Slide a window of the specified size along each vector of an axis, calling
+// stat for each window position after the window's accumulator has been
+// updated. The result has one element for each full window, so its length
+// along the axis is shortened by window-1. Only finite values are added to and
+// removed from the running accumulator, since NaN and Inf can't be removed
+// again; windows which contain any other values are computed from scratch.
+func rollingAxis(m Matrix, window, axis int, stat func(w *welford, sum float64) float64) Matrix {
+	vecs := axisVectors(m, axis)
+	if window < 1 || (len(vecs) > 0 && window > len(vecs[0])) {
+		panic(fmt.Sprintf("Invalid rolling window size %d for a %dx%d matrix along axis %d", window, m.Rows(), m.Cols(), axis))
+	}
+	var result Matrix
+	if axis == 0 {
+		result = Dense(m.Rows()-window+1, m.Cols()).M()
+	} else {
+		result = Dense(m.Rows(), m.Cols()-window+1).M()
+	}
+	for idx, vec := range vecs {
+		var (
+			acc       welford
+			sum       float64
+			nonFinite int
+		)
+		for pos, v := range vec {
+			if math.IsNaN(v) || math.IsInf(v, 0) {
+				nonFinite++
+			} else {
+				acc.add(v)
+				sum += v
+			}
+			if pos >= window {
+				if old := vec[pos-window]; math.IsNaN(old) || math.IsInf(old, 0) {
+					nonFinite--
+				} else {
+					acc.remove(old)
+					sum -= old
+				}
+			}
+			if pos >= window-1 {
+				var value float64
+				if nonFinite > 0 {
+					var (
+						winAcc welford
+						winSum float64
+					)
+					for _, x := range vec[pos-window+1 : pos+1] {
+						winAcc.add(x)
+						winSum += x
+					}
+					value = stat(&winAcc, winSum)
+				} else {
+					value = stat(&acc, sum)
+				}
+				if axis == 0 {
+					result.ItemSet(value, pos-window+1, idx)
+				} else {
+					result.ItemSet(value, idx, pos-window+1)
+				}
+			}
+		}
+	}
+	return result
+}
+
+// Get the sum of each window of the specified size sliding along an axis. For
+// axis 1, each row is treated as a series and the result has shape
+// rows x (cols-window+1); for axis 0, each column is treated as a series. Each
+// step updates the previous window's sum rather than recomputing it.
+func RollingSum(m Matrix, window, axis int) Matrix {
+	return rollingAxis(m, window, axis, func(w *welford, sum float64) float64 {
+		return sum
+	})
+}
+
+// Get the mean of each window of the specified size sliding along an axis.
+// See RollingSum() for the shape of the result.
+func RollingMean(m Matrix, window, axis int) Matrix {
+	return rollingAxis(m, window, axis, func(w *welford, sum float64) float64 {
+		return sum / float64(window)
+	})
+}
+
+// Get the standard deviation, with ddof delta degrees of freedom, of each
+// window of the specified size sliding along an axis. See RollingSum() for
+// the shape of the result and Var() for the meaning of ddof.
+func RollingStd(m Matrix, window, axis, ddof int) Matrix {
+	return rollingAxis(m, window, axis, func(w *welford, sum float64) float64 {
+		return math.Sqrt(w.variance(ddof))
+	})
+}
+
+// Get log(sum(exp(vec))), shifting by the largest element so that exp()
+// cannot overflow.
+func logSumExpVec(vec []float64) float64 {
+	max := math.Inf(-1)
+	for _, v := range vec {
+		max = math.Max(max, v)
+	}
+	if math.IsInf(max, 0) {
+		// All -Inf gives -Inf; any +Inf gives +Inf
+		return max
+	}
+	var sum float64
+	for _, v := range vec {
+		sum += math.Exp(v - max)
+	}
+	return max + math.Log(sum)
+}
+
+// Get log(sum(exp(x))) for each vector x along the specified axis, computed
+// so that large inputs do not overflow and very negative inputs do not
+// underflow to log(0).
+func LogSumExp(m Matrix, axis int) []float64 {
+	vecs := axisVectors(m, axis)
+	result := make([]float64, len(vecs))
+	for idx, vec := range vecs {
+		result[idx] = logSumExpVec(vec)
+	}
+	return result
+}
+
+// Apply the softmax function to each vector along the specified axis, so that
+// each vector in the result is non-negative and sums to one. Each vector is
+// shifted by its largest element before exponentiating, which is stable for
+// large logits. If the largest element is infinite, the elements equal to it
+// share the result equally, as in the limit of large logits: a vector of all
+// -Inf gives a uniform result, and the +Inf elements of any other vector get
+// equal shares. Vectors containing NaN give NaN. Returns a dense matrix with
+// the same shape as m, which is empty if m is.
+func Softmax(m Matrix, axis int) Matrix {
+	vecs := axisVectors(m, axis)
+	result := Dense(m.Rows(), m.Cols()).M()
+	for idx, vec := range vecs {
+		if len(vec) == 0 {
+			continue
+		}
+		max := vec[argMaxVec(vec)]
+		var sum float64
+		for pos, v := range vec {
+			switch {
+			case !math.IsInf(max, 0):
+				vec[pos] = math.Exp(v - max)
+			case v == max:
+				vec[pos] = 1
+			default:
+				vec[pos] = 0
+			}
+			sum += vec[pos]
+		}
+		for pos, v := range vec {
+			if axis == 0 {
+				result.ItemSet(v/sum, pos, idx)
+			} else {
+				result.ItemSet(v/sum, idx, pos)
+			}
+		}
+	}
+	return result
+}
+
+// Get the second, third, and fourth central moments of a vector
+func centralMoments(vec []float64) (m2, m3, m4 float64) {
+	var mean float64
+	for _, v := range vec {
+		mean += v
+	}
+	n := float64(len(vec))
+	mean /= n
+	for _, v := range vec {
+		d := v - mean
+		d2 := d * d
+		m2 += d2
+		m3 += d2 * d
+		m4 += d2 * d2
+	}
+	return m2 / n, m3 / n, m4 / n
+}
+
+// Get the skewness along the specified axis: the third central moment divided
+// by the 1.5 power of the second. If bias is false, the result is corrected for
+// statistical bias using the adjusted Fisher-Pearson coefficient, which
+// requires at least 3 elements per vector. Vectors with zero variance have
+// NaN skewness.
+func Skew(m Matrix, axis int, bias bool) []float64 {
+	vecs := axisVectors(m, axis)
+	result := make([]float64, len(vecs))
+	for idx, vec := range vecs {
+		m2, m3, _ := centralMoments(vec)
+		n := float64(len(vec))
+		if m2 == 0 || (!bias && n < 3) {
+			result[idx] = math.NaN()
+			continue
+		}
+		result[idx] = m3 / math.Pow(m2, 1.5)
+		if !bias {
+			result[idx] *= math.Sqrt(n*(n-1)) / (n - 2)
+		}
+	}
+	return result
+}
+
+// Get the kurtosis along the specified axis: the fourth central moment divided
+// by the square of the second. If fisher is true, 3 is subtracted so that the
+// normal distribution has kurtosis zero (excess kurtosis). If bias is false,
+// the result is corrected for statistical bias, which requires at least 4
+// elements per vector. Vectors with zero variance have NaN kurtosis.
+func Kurtosis(m Matrix, axis int, fisher, bias bool) []float64 {
+	vecs := axisVectors(m, axis)
+	result := make([]float64, len(vecs))
+	for idx, vec := range vecs {
+		m2, _, m4 := centralMoments(vec)
+		n := float64(len(vec))
+		if m2 == 0 || (!bias && n < 4) {
+			result[idx] = math.NaN()
+			continue
+		}
+		k := m4 / (m2 * m2)
+		if !bias {
+			k = ((n+1)*k-3*(n-1))*(n-1)/((n-2)*(n-3)) + 3
+		}
+		if fisher {
+			k -= 3
+		}
+		result[idx] = k
+	}
+	return result
+}
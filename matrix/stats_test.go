@@ -0,0 +1,670 @@
+package matrix
+
+import (
+	. "github.com/smartystreets/goconvey/convey"
+	"math"
+	"testing"
+)
+
+func TestArgMaxArgMin(t *testing.T) {
+	Convey("Given a dense matrix", t, func() {
+		m := M(3, 4,
+			1, 9, 3, 4,
+			5, 6, -7, 8,
+			9, 0, 11, 2)
+
+		Convey("ArgMax finds the largest element", func() {
+			i, j := ArgMax(m)
+			So(i, ShouldEqual, 2)
+			So(j, ShouldEqual, 2)
+		})
+
+		Convey("ArgMin finds the smallest element", func() {
+			i, j := ArgMin(m)
+			So(i, ShouldEqual, 1)
+			So(j, ShouldEqual, 2)
+		})
+
+		Convey("ArgMaxAxis works along both axes", func() {
+			So(ArgMaxAxis(m, 0), ShouldResemble, []int{2, 0, 2, 1})
+			So(ArgMaxAxis(m, 1), ShouldResemble, []int{1, 3, 2})
+		})
+
+		Convey("ArgMinAxis works along both axes", func() {
+			So(ArgMinAxis(m, 0), ShouldResemble, []int{0, 2, 1, 2})
+			So(ArgMinAxis(m, 1), ShouldResemble, []int{0, 2, 1})
+		})
+
+		Convey("Ties go to the first element", func() {
+			So(ArgMaxAxis(m, 0)[0], ShouldEqual, 2)
+			i, j := ArgMax(M(2, 2, 1, 1, 1, 1))
+			So(i, ShouldEqual, 0)
+			So(j, ShouldEqual, 0)
+		})
+
+		Convey("Invalid axes panic", func() {
+			So(func() { ArgMaxAxis(m, 2) }, ShouldPanic)
+			So(func() { ArgMinAxis(m, -1) }, ShouldPanic)
+		})
+
+		Convey("Transposed matrices work", func() {
+			i, j := ArgMin(m.T())
+			So(i, ShouldEqual, 2)
+			So(j, ShouldEqual, 1)
+			So(ArgMaxAxis(m.T(), 1), ShouldResemble, []int{2, 0, 2, 1})
+		})
+	})
+
+	Convey("Given a sparse coo matrix with negative values", t, func() {
+		m := SparseCoo(3, 3)
+		m.ItemSet(-2, 1, 1)
+		m.ItemSet(-1, 2, 0)
+
+		Convey("ArgMax finds the first implicit zero", func() {
+			i, j := ArgMax(m)
+			So(i, ShouldEqual, 0)
+			So(j, ShouldEqual, 0)
+		})
+
+		Convey("ArgMin finds the stored minimum", func() {
+			i, j := ArgMin(m)
+			So(i, ShouldEqual, 1)
+			So(j, ShouldEqual, 1)
+		})
+	})
+
+	Convey("Given a sparse diagonal matrix", t, func() {
+		m := Diag(1, 3, 2)
+
+		Convey("ArgMax finds the largest diagonal element", func() {
+			i, j := ArgMax(m)
+			So(i, ShouldEqual, 1)
+			So(j, ShouldEqual, 1)
+			So(ArgMaxAxis(m, 1), ShouldResemble, []int{0, 1, 2})
+		})
+	})
+
+	Convey("Given a matrix containing NaN", t, func() {
+		nan := math.NaN()
+		m := M(2, 3,
+			1, nan, 5,
+			nan, 0, nan)
+
+		Convey("ArgMax and ArgMin find the first NaN wherever it is", func() {
+			i, j := ArgMax(m)
+			So([]int{i, j}, ShouldResemble, []int{0, 1})
+			i, j = ArgMin(m)
+			So([]int{i, j}, ShouldResemble, []int{0, 1})
+			i, j = ArgMax(M(1, 2, nan, 1))
+			So([]int{i, j}, ShouldResemble, []int{0, 0})
+		})
+
+		Convey("ArgMaxAxis and ArgMinAxis find the first NaN of each vector", func() {
+			So(ArgMaxAxis(m, 0), ShouldResemble, []int{1, 0, 1})
+			So(ArgMinAxis(m, 0), ShouldResemble, []int{1, 0, 1})
+			So(ArgMaxAxis(m, 1), ShouldResemble, []int{1, 0})
+			So(ArgMinAxis(m, 1), ShouldResemble, []int{1, 0})
+		})
+	})
+}
+
+func TestVarStd(t *testing.T) {
+	Convey("Given a dense matrix", t, func() {
+		m := M(4, 2,
+			2, 1,
+			4, 1,
+			4, 1,
+			6, 1)
+
+		Convey("Var works along axis 0", func() {
+			So(Var(m, 0, 0), ShouldResemble, []float64{2, 0})
+			So(Var(m, 0, 1), ShouldResemble, []float64{8. / 3, 0})
+		})
+
+		Convey("Var works along axis 1", func() {
+			So(Var(m, 1, 0), ShouldResemble, []float64{0.25, 2.25, 2.25, 6.25})
+			So(Var(m, 1, 1), ShouldResemble, []float64{0.5, 4.5, 4.5, 12.5})
+		})
+
+		Convey("Std is the square root of Var", func() {
+			So(Std(m, 0, 0), ShouldResemble, []float64{math.Sqrt(2), 0})
+			So(Std(m, 1, 1)[3], ShouldEqual, math.Sqrt(12.5))
+		})
+
+		Convey("Too few degrees of freedom gives NaN", func() {
+			v := Var(m, 1, 2)
+			So(len(v), ShouldEqual, 4)
+			for _, x := range v {
+				So(math.IsNaN(x), ShouldBeTrue)
+			}
+		})
+
+		Convey("Invalid axes panic", func() {
+			So(func() { Var(m, 2, 0) }, ShouldPanic)
+		})
+	})
+
+	Convey("Given values with a large offset", t, func() {
+		m := M(4, 1, 1e9+4, 1e9+7, 1e9+13, 1e9+16)
+
+		Convey("Var remains numerically stable", func() {
+			So(Var(m, 0, 1)[0], ShouldBeBetween, 30-Eps, 30+Eps)
+		})
+	})
+
+	Convey("Given a sparse diagonal matrix", t, func() {
+		m := Diag(3, 3, 3)
+
+		Convey("Var accounts for implicit zeros", func() {
+			So(Var(m, 0, 0), ShouldResemble, []float64{2, 2, 2})
+			So(Var(m.SparseCoo(), 1, 0), ShouldResemble, []float64{2, 2, 2})
+		})
+	})
+}
+
+func TestMedianQuantile(t *testing.T) {
+	Convey("Given a dense matrix", t, func() {
+		m := M(4, 3,
+			7, 1, 2,
+			1, 1, 8,
+			3, 1, 4,
+			5, 1, 6)
+
+		Convey("Median works along axis 0", func() {
+			So(Median(m, 0), ShouldResemble, []float64{4, 1, 5})
+		})
+
+		Convey("Median works along axis 1", func() {
+			So(Median(m, 1), ShouldResemble, []float64{2, 1, 3, 5})
+		})
+
+		Convey("The extreme quantiles are the min and max", func() {
+			So(Quantile(m, 0, 0, LinearInterp), ShouldResemble, []float64{1, 1, 2})
+			So(Quantile(m, 1, 0, LinearInterp), ShouldResemble, []float64{7, 1, 8})
+		})
+
+		Convey("The interpolation modes work", func() {
+			// Column 0 sorted is 1, 3, 5, 7; q=0.4 falls at position 1.2
+			So(Quantile(m, 0.4, 0, LinearInterp)[0], ShouldBeBetween, 3.4-Eps, 3.4+Eps)
+			So(Quantile(m, 0.4, 0, LowerInterp)[0], ShouldEqual, 3)
+			So(Quantile(m, 0.4, 0, HigherInterp)[0], ShouldEqual, 5)
+			So(Quantile(m, 0.4, 0, NearestInterp)[0], ShouldEqual, 3)
+			So(Quantile(m, 0.4, 0, MidpointInterp)[0], ShouldEqual, 4)
+			So(Quantile(m, 0.5, 0, NearestInterp)[0], ShouldEqual, 5)
+		})
+
+		Convey("Invalid arguments panic", func() {
+			So(func() { Quantile(m, 1.5, 0, LinearInterp) }, ShouldPanic)
+			So(func() { Quantile(m, 0.5, 0, QuantileInterp(-1)) }, ShouldPanic)
+			So(func() { Median(m, 3) }, ShouldPanic)
+		})
+
+		Convey("The matrix is not modified", func() {
+			Median(m, 0)
+			So(m.Col(0), ShouldResemble, []float64{7, 1, 3, 5})
+		})
+	})
+
+	Convey("Given a large vector with many duplicates", t, func() {
+		values := make([]float64, 1001)
+		for i := range values {
+			values[i] = float64((i * 7919) % 10)
+		}
+		m := A([]int{1001, 1}, values...).M()
+
+		Convey("Median finds the middle value", func() {
+			So(Median(m, 0), ShouldResemble, []float64{4})
+		})
+	})
+
+	Convey("Given a sparse coo matrix", t, func() {
+		m := SparseCoo(3, 3)
+		m.ItemSet(5, 0, 0)
+		m.ItemSet(-1, 2, 0)
+
+		Convey("Median accounts for implicit zeros", func() {
+			So(Median(m, 0), ShouldResemble, []float64{0, 0, 0})
+			So(Median(m, 1), ShouldResemble, []float64{0, 0, 0})
+		})
+	})
+
+	Convey("Given vectors containing NaN", t, func() {
+		nan := math.NaN()
+		m := M(4, 2,
+			nan, 1,
+			1, 2,
+			2, 3,
+			3, nan)
+
+		Convey("Their quantiles are NaN wherever the NaN is", func() {
+			for _, q := range []float64{0, 0.25, 0.5, 1} {
+				quantiles := Quantile(m, q, 0, LinearInterp)
+				So(math.IsNaN(quantiles[0]) && math.IsNaN(quantiles[1]), ShouldBeTrue)
+			}
+			So(Median(m, 1)[1:3], ShouldResemble, []float64{1.5, 2.5})
+		})
+
+		Convey("selectKth sorts NaNs last", func() {
+			for k, want := range []float64{1, 2, 3} {
+				So(selectKth([]float64{3, nan, 1, nan, 2}, k), ShouldEqual, want)
+			}
+			So(math.IsNaN(selectKth([]float64{3, nan, 1, nan, 2}, 3)), ShouldBeTrue)
+			So(math.IsNaN(selectKth([]float64{nan, 1}, 1)), ShouldBeTrue)
+		})
+	})
+}
+
+func TestCorrCoef(t *testing.T) {
+	Convey("Given a matrix of variables in rows", t, func() {
+		m := M(4, 4,
+			1, 2, 3, 4,
+			2, 4, 6, 8,
+			4, 3, 2, 1,
+			1, 2, 4, 8)
+
+		Convey("CorrCoef is correct", func() {
+			r := CorrCoef(m)
+			So(r.Shape(), ShouldResemble, []int{4, 4})
+			for i := 0; i < 4; i++ {
+				So(r.Item(i, i), ShouldBeBetween, 1-Eps, 1+Eps)
+			}
+			So(r.Item(0, 1), ShouldBeBetween, 1-Eps, 1+Eps)
+			So(r.Item(0, 2), ShouldBeBetween, -1-Eps, -1+Eps)
+			So(r.Item(0, 3), ShouldBeBetween, 0.9592-1e-4, 0.9592+1e-4)
+			So(r.Item(3, 0), ShouldEqual, r.Item(0, 3))
+		})
+
+		Convey("SpearmanCorrCoef is correct", func() {
+			r := SpearmanCorrCoef(m)
+			So(r.Item(0, 3), ShouldBeBetween, 1-Eps, 1+Eps)
+			So(r.Item(2, 3), ShouldBeBetween, -1-Eps, -1+Eps)
+		})
+
+		Convey("SpearmanCorrCoef propagates NaN", func() {
+			r := SpearmanCorrCoef(M(3, 4,
+				1, 2, 3, 4,
+				4, math.NaN(), 2, 1,
+				1, 3, 5, 7))
+			So(math.IsNaN(r.Item(0, 1)), ShouldBeTrue)
+			So(r.Item(0, 2), ShouldBeBetween, 1-Eps, 1+Eps)
+		})
+
+		Convey("Constant rows have NaN correlation", func() {
+			r := CorrCoef(M(2, 3, 1, 2, 3, 5, 5, 5))
+			So(math.IsNaN(r.Item(0, 1)), ShouldBeTrue)
+			So(math.IsNaN(r.Item(1, 1)), ShouldBeTrue)
+		})
+	})
+
+}
+
+func TestWeightedMeanVar(t *testing.T) {
+	Convey("Given a dense matrix", t, func() {
+		m := M(3, 2,
+			1, 10,
+			2, 20,
+			4, 40)
+
+		Convey("WeightedMean works along axis 0", func() {
+			So(WeightedMean(m, []float64{1, 1, 2}, 0), ShouldResemble, []float64{2.75, 27.5})
+		})
+
+		Convey("WeightedMean works along axis 1", func() {
+			So(WeightedMean(m, []float64{3, 1}, 1), ShouldResemble, []float64{3.25, 6.5, 13})
+		})
+
+		Convey("Equal weights match the unweighted statistics", func() {
+			v := WeightedVar(m, []float64{2, 2, 2}, 0)
+			v0 := Var(m, 0, 0)
+			So(v[0], ShouldBeBetween, v0[0]-Eps, v0[0]+Eps)
+			So(v[1], ShouldBeBetween, v0[1]-Eps, v0[1]+Eps)
+		})
+
+		Convey("Integer weights match repeated observations", func() {
+			v := WeightedVar(m, []float64{1, 0, 3}, 0)
+			v0 := Var(M(4, 1, 1, 4, 4, 4), 0, 0)
+			So(v[0], ShouldBeBetween, v0[0]-Eps, v0[0]+Eps)
+		})
+
+		Convey("All-zero weights give NaN", func() {
+			So(math.IsNaN(WeightedMean(m, []float64{0, 0}, 1)[0]), ShouldBeTrue)
+			So(math.IsNaN(WeightedVar(m, []float64{0, 0}, 1)[0]), ShouldBeTrue)
+		})
+
+		Convey("Invalid weights panic", func() {
+			So(func() { WeightedMean(m, []float64{1, 1}, 0) }, ShouldPanic)
+			So(func() { WeightedMean(m, []float64{1, -1}, 1) }, ShouldPanic)
+			So(func() { WeightedVar(m, []float64{1, 1}, 2) }, ShouldPanic)
+		})
+	})
+}
+
+func TestNaNReductions(t *testing.T) {
+	var nan = math.NaN()
+
+	Convey("Given a dense matrix with missing values", t, func() {
+		m := M(3, 3,
+			1, nan, 3,
+			nan, nan, -6,
+			4, nan, 0)
+
+		Convey("NaNSum skips NaNs", func() {
+			sum, count := NaNSum(m)
+			So(sum, ShouldEqual, 2)
+			So(count, ShouldEqual, 5)
+		})
+
+		Convey("NaNSumAxis skips NaNs", func() {
+			sums, counts := NaNSumAxis(m, 0)
+			So(sums, ShouldResemble, []float64{5, 0, -3})
+			So(counts, ShouldResemble, []int{2, 0, 3})
+			sums, counts = NaNSumAxis(m, 1)
+			So(sums, ShouldResemble, []float64{4, -6, 4})
+			So(counts, ShouldResemble, []int{2, 1, 2})
+		})
+
+		Convey("NaNMean skips NaNs", func() {
+			mean, count := NaNMean(m)
+			So(mean, ShouldEqual, 0.4)
+			So(count, ShouldEqual, 5)
+			means, counts := NaNMeanAxis(m, 0)
+			So(means[0], ShouldEqual, 2.5)
+			So(math.IsNaN(means[1]), ShouldBeTrue)
+			So(means[2], ShouldEqual, -1)
+			So(counts, ShouldResemble, []int{2, 0, 3})
+		})
+
+		Convey("NaNMax and NaNMin skip NaNs", func() {
+			max, _ := NaNMax(m)
+			So(max, ShouldEqual, 4)
+			min, _ := NaNMin(m)
+			So(min, ShouldEqual, -6)
+			maxs, _ := NaNMaxAxis(m, 1)
+			So(maxs, ShouldResemble, []float64{3, -6, 4})
+			mins, counts := NaNMinAxis(m, 0)
+			So(mins[0], ShouldEqual, 1)
+			So(math.IsNaN(mins[1]), ShouldBeTrue)
+			So(mins[2], ShouldEqual, -6)
+			So(counts, ShouldResemble, []int{2, 0, 3})
+		})
+	})
+
+	Convey("Given an array which is entirely NaN", t, func() {
+		a := WithValue(nan, 2, 2)
+
+		Convey("The reductions give NaN and a count of zero", func() {
+			mean, count := NaNMean(a)
+			So(math.IsNaN(mean), ShouldBeTrue)
+			So(count, ShouldEqual, 0)
+			max, count := NaNMax(a)
+			So(math.IsNaN(max), ShouldBeTrue)
+			So(count, ShouldEqual, 0)
+		})
+	})
+
+	Convey("Given a sparse coo matrix with missing values", t, func() {
+		m := SparseCoo(2, 3)
+		m.ItemSet(nan, 0, 0)
+		m.ItemSet(-2, 1, 2)
+
+		Convey("The implicit zeros are counted", func() {
+			sum, count := NaNSum(m)
+			So(sum, ShouldEqual, -2)
+			So(count, ShouldEqual, 5)
+			max, _ := NaNMax(m)
+			So(max, ShouldEqual, 0)
+			mins, counts := NaNMinAxis(m, 1)
+			So(mins, ShouldResemble, []float64{0, -2})
+			So(counts, ShouldResemble, []int{2, 3})
+		})
+	})
+}
+
+func TestRolling(t *testing.T) {
+	Convey("Given a matrix of time series in rows", t, func() {
+		m := M(2, 5,
+			1, 2, 3, 4, 5,
+			2, 4, 4, 4, 6)
+
+		Convey("RollingSum works along axis 1", func() {
+			s := RollingSum(m, 3, 1)
+			So(s.Shape(), ShouldResemble, []int{2, 3})
+			So(s.Array(), ShouldResemble, []float64{
+				6, 9, 12,
+				10, 12, 14,
+			})
+		})
+
+		Convey("RollingMean works along axis 1", func() {
+			So(RollingMean(m, 2, 1).Array(), ShouldResemble, []float64{
+				1.5, 2.5, 3.5, 4.5,
+				3, 4, 4, 5,
+			})
+		})
+
+		Convey("RollingStd works along axis 1", func() {
+			s := RollingStd(m, 3, 1, 0)
+			for i := 0; i < 2; i++ {
+				for j := 0; j < 3; j++ {
+					want := Std(m.Slice([]int{i, j}, []int{i + 1, j + 3}).M(), 1, 0)[0]
+					So(s.Item(i, j), ShouldBeBetween, want-Eps, want+Eps)
+				}
+			}
+		})
+
+		Convey("Windows along axis 0 work", func() {
+			s := RollingSum(m, 2, 0)
+			So(s.Shape(), ShouldResemble, []int{1, 5})
+			So(s.Array(), ShouldResemble, []float64{3, 6, 7, 8, 11})
+			So(RollingSum(m.T(), 2, 0).Array(), ShouldResemble, RollingSum(m, 2, 1).T().Array())
+		})
+
+		Convey("A window of one returns the data", func() {
+			So(RollingMean(m, 1, 1).Array(), ShouldResemble, m.Array())
+			So(RollingStd(m, 1, 1, 0).Sum(), ShouldEqual, 0)
+		})
+
+		Convey("Invalid windows panic", func() {
+			So(func() { RollingSum(m, 0, 1) }, ShouldPanic)
+			So(func() { RollingSum(m, 6, 1) }, ShouldPanic)
+			So(func() { RollingSum(m, 3, 0) }, ShouldPanic)
+			So(func() { RollingSum(m, 2, 2) }, ShouldPanic)
+		})
+	})
+
+	Convey("Given series containing NaN and Inf", t, func() {
+		nan, inf := math.NaN(), math.Inf(1)
+		m := M(2, 6,
+			1, nan, 2, 3, 4, 5,
+			1, inf, 2, 3, 4, 5)
+
+		Convey("Only the windows containing them are affected", func() {
+			s := RollingSum(m, 2, 1)
+			So(math.IsNaN(s.Item(0, 0)) && math.IsNaN(s.Item(0, 1)), ShouldBeTrue)
+			So(s.Row(0)[2:], ShouldResemble, []float64{5, 7, 9})
+			So(s.Row(1), ShouldResemble, []float64{inf, inf, 5, 7, 9})
+
+			mean := RollingMean(m, 2, 1)
+			So(mean.Row(1), ShouldResemble, []float64{inf, inf, 2.5, 3.5, 4.5})
+			std := RollingStd(m, 2, 1, 0)
+			So(math.IsNaN(std.Item(1, 0)), ShouldBeTrue)
+			So(std.Row(0)[2:], ShouldResemble, []float64{.5, .5, .5})
+			So(std.Row(1)[2:], ShouldResemble, []float64{.5, .5, .5})
+		})
+	})
+}
+
+func TestSoftmaxLogSumExp(t *testing.T) {
+	Convey("Given a matrix of small logits", t, func() {
+		m := M(2, 3,
+			1, 2, 3,
+			0, 0, 0)
+
+		Convey("LogSumExp matches the naive computation", func() {
+			lse := LogSumExp(m, 1)
+			want := math.Log(math.E + math.Exp(2) + math.Exp(3))
+			So(lse[0], ShouldBeBetween, want-Eps, want+Eps)
+			So(lse[1], ShouldBeBetween, math.Log(3)-Eps, math.Log(3)+Eps)
+			So(len(LogSumExp(m, 0)), ShouldEqual, 3)
+		})
+
+		Convey("Softmax rows sum to one", func() {
+			s := Softmax(m, 1)
+			So(s.Shape(), ShouldResemble, []int{2, 3})
+			So(A1(s.Row(0)...).Sum(), ShouldBeBetween, 1-Eps, 1+Eps)
+			So(s.Item(1, 0), ShouldBeBetween, 1./3-Eps, 1./3+Eps)
+			So(s.Item(0, 2), ShouldBeGreaterThan, s.Item(0, 1))
+		})
+
+		Convey("Softmax columns sum to one", func() {
+			s := Softmax(m, 0)
+			for col := 0; col < 3; col++ {
+				So(A1(s.Col(col)...).Sum(), ShouldBeBetween, 1-Eps, 1+Eps)
+			}
+		})
+	})
+
+	Convey("Given a matrix of huge logits", t, func() {
+		m := M(2, 2,
+			1000, 1000,
+			-1000, -1001)
+
+		Convey("LogSumExp does not overflow", func() {
+			lse := LogSumExp(m, 1)
+			So(lse[0], ShouldBeBetween, 1000+math.Ln2-Eps, 1000+math.Ln2+Eps)
+			So(math.IsInf(lse[1], 0), ShouldBeFalse)
+		})
+
+		Convey("Softmax does not overflow", func() {
+			s := Softmax(m, 1)
+			So(s.Row(0), ShouldResemble, []float64{.5, .5})
+			So(s.AnyNaN(), ShouldBeFalse)
+		})
+	})
+
+	Convey("Given infinite logits", t, func() {
+		Convey("LogSumExp handles them", func() {
+			inf := math.Inf(1)
+			lse := LogSumExp(M(2, 2, -inf, -inf, inf, 1), 1)
+			So(math.IsInf(lse[0], -1), ShouldBeTrue)
+			So(math.IsInf(lse[1], 1), ShouldBeTrue)
+		})
+
+		Convey("Softmax shares the result among the largest logits", func() {
+			inf := math.Inf(1)
+			s := Softmax(M(3, 3,
+				-inf, -inf, -inf,
+				inf, 1, inf,
+				-inf, 0, -inf), 1)
+			So(s.Row(0), ShouldResemble, []float64{1. / 3, 1. / 3, 1. / 3})
+			So(s.Row(1), ShouldResemble, []float64{.5, 0, .5})
+			So(s.Row(2), ShouldResemble, []float64{0, 1, 0})
+		})
+
+		Convey("Softmax propagates NaN", func() {
+			s := Softmax(M(1, 3, 1, math.NaN(), 2), 1)
+			So(s.AllF(math.IsNaN), ShouldBeTrue)
+		})
+	})
+
+	Convey("Given matrices with empty axes", t, func() {
+		Convey("Softmax returns an empty result", func() {
+			So(Softmax(Dense(0, 3).M(), 0).Shape(), ShouldResemble, []int{0, 3})
+			So(Softmax(Dense(0, 3).M(), 1).Shape(), ShouldResemble, []int{0, 3})
+			So(Softmax(Dense(2, 0).M(), 1).Shape(), ShouldResemble, []int{2, 0})
+		})
+	})
+}
+
+func TestRank(t *testing.T) {
+	Convey("Given a matrix with ties", t, func() {
+		m := M(2, 6,
+			10, 20, 10, 30, 20, 10,
+			6, 5, 4, 3, 2, 1)
+
+		Convey("RankRows supports each tie method", func() {
+			So(RankRows(m, AverageRank).Row(0), ShouldResemble, []float64{2, 4.5, 2, 6, 4.5, 2})
+			So(RankRows(m, MinRank).Row(0), ShouldResemble, []float64{1, 4, 1, 6, 4, 1})
+			So(RankRows(m, MaxRank).Row(0), ShouldResemble, []float64{3, 5, 3, 6, 5, 3})
+			So(RankRows(m, DenseRank).Row(0), ShouldResemble, []float64{1, 2, 1, 3, 2, 1})
+			So(RankRows(m, OrdinalRank).Row(0), ShouldResemble, []float64{1, 4, 2, 6, 5, 3})
+		})
+
+		Convey("Rows without ties are ranked the same way by all methods", func() {
+			for _, ties := range []RankTies{AverageRank, MinRank, MaxRank, DenseRank, OrdinalRank} {
+				So(RankRows(m, ties).Row(1), ShouldResemble, []float64{6, 5, 4, 3, 2, 1})
+			}
+		})
+
+		Convey("RankCols ranks within each column", func() {
+			So(RankCols(m, AverageRank).Array(), ShouldResemble, []float64{
+				2, 2, 2, 2, 2, 2,
+				1, 1, 1, 1, 1, 1,
+			})
+			So(RankCols(m.T(), MinRank).Array(), ShouldResemble, RankRows(m, MinRank).T().Array())
+		})
+
+		Convey("Invalid tie methods panic", func() {
+			So(func() { RankRows(m, RankTies(-1)) }, ShouldPanic)
+		})
+	})
+
+	Convey("Given a row containing NaN", t, func() {
+		nan := math.NaN()
+		m := M(1, 5, 3, nan, 1, nan, 2)
+
+		Convey("NaNs are ranked NaN and the others as if they were absent", func() {
+			for _, ties := range []RankTies{AverageRank, MinRank, MaxRank, DenseRank, OrdinalRank} {
+				ranks := RankRows(m, ties).Row(0)
+				So([]float64{ranks[0], ranks[2], ranks[4]}, ShouldResemble, []float64{3, 1, 2})
+				So(math.IsNaN(ranks[1]) && math.IsNaN(ranks[3]), ShouldBeTrue)
+			}
+		})
+	})
+}
+
+func TestSkewKurtosis(t *testing.T) {
+	Convey("Given a matrix with skewed columns", t, func() {
+		m := M(5, 3,
+			1, 1, 2,
+			2, 1, 2,
+			3, 1, 3,
+			4, 1, 4,
+			10, 1, 10)
+
+		Convey("Symmetric data has zero skewness", func() {
+			s := Skew(M(1, 4, 1, 2, 3, 4), 1, true)
+			So(s[0], ShouldBeBetween, -Eps, Eps)
+		})
+
+		Convey("Skew matches reference values", func() {
+			s := Skew(m, 0, true)
+			So(s[0], ShouldBeBetween, 1.1384199576606167-Eps, 1.1384199576606167+Eps)
+			So(math.IsNaN(s[1]), ShouldBeTrue)
+			u := Skew(m, 0, false)
+			So(u[0], ShouldBeBetween, 1.6970562748477143-Eps, 1.6970562748477143+Eps)
+		})
+
+		Convey("Kurtosis matches reference values", func() {
+			k := Kurtosis(m, 0, true, true)
+			So(k[0], ShouldBeBetween, -0.212-Eps, -0.212+Eps)
+			So(math.IsNaN(k[1]), ShouldBeTrue)
+			k = Kurtosis(m, 0, false, true)
+			So(k[0], ShouldBeBetween, 2.788-Eps, 2.788+Eps)
+			u := Kurtosis(m, 0, true, false)
+			So(u[0], ShouldBeBetween, 3.152-Eps, 3.152+Eps)
+		})
+
+		Convey("Bias correction needs enough elements", func() {
+			So(math.IsNaN(Skew(M(1, 2, 1, 2), 1, false)[0]), ShouldBeTrue)
+			So(math.IsNaN(Kurtosis(M(1, 3, 1, 2, 4), 1, true, false)[0]), ShouldBeTrue)
+		})
+
+		Convey("Axis 1 works", func() {
+			s0 := Skew(m, 0, true)
+			s1 := Skew(m.T(), 1, true)
+			So(s1[0], ShouldEqual, s0[0])
+			So(s1[2], ShouldEqual, s0[2])
+		})
+	})
+}
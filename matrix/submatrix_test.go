@@ -0,0 +1,70 @@
+package matrix
+
+import (
+	"errors"
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+func TestSetSubmatrix(t *testing.T) {
+	Convey("Given matrices of each storage type", t, func() {
+		block := M(2, 2, 1, 2, 3, 4)
+		want := M(3, 4,
+			0, 0, 0, 0,
+			0, 0, 1, 2,
+			0, 0, 3, 4)
+
+		Convey("SetSubmatrix writes a dense block into place", func() {
+			for _, dst := range []Matrix{Dense(3, 4).M(), DenseOrder(ColMajor, 3, 4), SparseCoo(3, 4)} {
+				dst.ItemSet(9, 1, 2)
+				dst.SetSubmatrix(1, 2, block)
+				So(dst.Equal(want), ShouldBeTrue)
+			}
+		})
+
+		Convey("SetSubmatrix overwrites the block with a sparse source", func() {
+			sparse := SparseCoo(2, 2, 0, 5, 0, 0)
+			for _, dst := range []Matrix{M(2, 3, 1, 1, 1, 1, 1, 1), SparseCoo(2, 3, 1, 1, 1, 1, 1, 1)} {
+				dst.SetSubmatrix(0, 1, sparse)
+				So(dst.Equal(M(2, 3, 1, 0, 5, 1, 0, 0)), ShouldBeTrue)
+			}
+		})
+
+		Convey("SetSubmatrix writes through transposed views", func() {
+			dst := SparseCoo(4, 3)
+			dst.T().SetSubmatrix(1, 2, block)
+			So(dst.T().Equal(want), ShouldBeTrue)
+			dense := Dense(4, 3).M()
+			dense.T().SetSubmatrix(1, 2, block)
+			So(dense.Equal(want.T()), ShouldBeTrue)
+		})
+
+		Convey("SetSubmatrix keeps sparse diagonal matrices diagonal", func() {
+			dst := SparseDiag(4, 4, 1, 1, 1, 1)
+			dst.SetSubmatrix(1, 1, Diag(5, 6))
+			So(dst.Equal(Diag(1, 5, 6, 1)), ShouldBeTrue)
+			dst.SetSubmatrix(0, 1, M(2, 2, 0, 0, 7, 0))
+			So(dst.Equal(Diag(1, 7, 6, 1)), ShouldBeTrue)
+
+			var notSparse ErrNotSparse
+			err := TrySetSubmatrix(dst, 2, 1, M(2, 2, 1, 1, 0, 1))
+			So(errors.As(err, &notSparse), ShouldBeTrue)
+			So(notSparse.Index, ShouldResemble, []int{2, 1})
+			So(dst.Equal(Diag(1, 7, 6, 1)), ShouldBeTrue)
+		})
+
+		Convey("SetSubmatrix panics if the block doesn't fit", func() {
+			So(TrySetSubmatrix(Dense(3, 4).M(), 2, 3, block), ShouldResemble,
+				ErrIndexOutOfRange{Op: "SetSubmatrix", Index: []int{3, 4}, Shape: []int{3, 4}})
+			So(TrySetSubmatrix(SparseCoo(3, 4), -1, 0, block), ShouldResemble,
+				ErrIndexOutOfRange{Op: "SetSubmatrix", Index: []int{-1, 0}, Shape: []int{3, 4}})
+		})
+
+		Convey("SetSubmatrix respects frozen and synchronized wrappers", func() {
+			So(errors.Is(TrySetSubmatrix(Freeze(Dense(3, 4).M()), 0, 0, block), ErrFrozen), ShouldBeTrue)
+			shared := Synchronized(Dense(3, 4).M())
+			shared.SetSubmatrix(1, 2, block)
+			So(shared.Equal(want), ShouldBeTrue)
+		})
+	})
+}
@@ -0,0 +1,223 @@
+package matrix
+
+// A read-only view of selected rows of another matrix, created by
+// RowSubset()
+type rowSubset struct {
+	lazyMatrix
+	m    Matrix
+	rows []int
+}
+
+// Get a read-only view of the given rows of m, in the given order, without
+// copying them. Row i of the view is row rows[i] of m, and rows may repeat.
+// Elements are read from m when accessed, so later changes to m are visible
+// through the view. Element access, Row(), Col() and VisitNonzero() take no
+// extra memory; other methods, such as MProd(), act on a copy of the
+// selected rows, which is stored densely if m is dense and as a sparse coo
+// matrix otherwise. Methods which would modify the view panic with an error
+// wrapping ErrFrozen. rows is copied.
+func RowSubset(m Matrix, rows []int) Matrix {
+	debugCheck("RowSubset", m)
+	for _, row := range rows {
+		if row < 0 || row >= m.Rows() {
+			panic(ErrIndexOutOfRange{Op: "RowSubset", Index: []int{row}, Shape: []int{m.Rows()}})
+		}
+	}
+	r := &rowSubset{m: m, rows: append([]int(nil), rows...)}
+	r.volatile = true
+	r.build = func() Matrix {
+		var result Matrix
+		if m.Sparsity() == DenseArray {
+			result = Dense(len(r.rows), m.Cols()).M()
+		} else {
+			result = SparseCoo(len(r.rows), m.Cols())
+		}
+		r.VisitNonzero(func(pos []int, value float64) bool {
+			result.ItemSet(value, pos...)
+			return true
+		})
+		return result
+	}
+	return r
+}
+
+// Get a copy of a column
+func (r *rowSubset) Col(col int) []float64 {
+	if col < 0 || col >= r.m.Cols() {
+		panic(ErrIndexOutOfRange{Op: "Col", Index: []int{col}, Shape: []int{r.m.Cols()}})
+	}
+	result := make([]float64, len(r.rows))
+	for i, row := range r.rows {
+		result[i] = r.m.Item(row, col)
+	}
+	return result
+}
+
+// Get the number of columns
+func (r *rowSubset) Cols() int {
+	return r.m.Cols()
+}
+
+// Get an array element in a flattened version of this array
+func (r *rowSubset) FlatItem(index int) float64 {
+	cols := r.m.Cols()
+	if index < 0 || index >= len(r.rows)*cols {
+		panic(ErrIndexOutOfRange{Op: "FlatItem", Index: []int{index}, Shape: []int{len(r.rows) * cols}})
+	}
+	return r.m.Item(r.rows[index/cols], index%cols)
+}
+
+// Get an array element
+func (r *rowSubset) Item(index ...int) float64 {
+	if len(index) != 2 || index[0] < 0 || index[0] >= len(r.rows) || index[1] < 0 || index[1] >= r.m.Cols() {
+		panic(ErrIndexOutOfRange{Op: "Item", Index: index, Shape: r.Shape()})
+	}
+	return r.m.Item(r.rows[index[0]], index[1])
+}
+
+// A row subset is already a matrix
+func (r *rowSubset) M() Matrix {
+	return r
+}
+
+// Get the number of array dimensions
+func (r *rowSubset) NDim() int {
+	return 2
+}
+
+// Get a copy of a row
+func (r *rowSubset) Row(row int) []float64 {
+	if row < 0 || row >= len(r.rows) {
+		panic(ErrIndexOutOfRange{Op: "Row", Index: []int{row}, Shape: []int{len(r.rows)}})
+	}
+	return r.m.Row(r.rows[row])
+}
+
+// Get the number of rows
+func (r *rowSubset) Rows() int {
+	return len(r.rows)
+}
+
+// Get the array dimensions
+func (r *rowSubset) Shape() []int {
+	return []int{len(r.rows), r.m.Cols()}
+}
+
+// Get the number of elements
+func (r *rowSubset) Size() int {
+	return len(r.rows) * r.m.Cols()
+}
+
+// Get the transpose, which is a copy of the selected rows
+func (r *rowSubset) T() Matrix {
+	return r.matrix().T()
+}
+
+// Get the view itself, since it can't be modified
+func (r *rowSubset) View() NDArray {
+	return r
+}
+
+// Visit just the nonzero elements, in row-major order
+func (r *rowSubset) VisitNonzero(f func(pos []int, value float64) bool) bool {
+	for i, row := range r.rows {
+		for col, value := range r.m.Row(row) {
+			if value != 0 && !f([]int{i, col}, value) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Get a copy of the rows of m for which keep is true, in their original
+// order. keep must have one element per row. Dense and compressed sparse
+// matrices keep their storage format, and other matrices give a sparse coo
+// matrix if they are sparse. Sparse matrices are copied in one pass over
+// their nonzero values, remapping their indices.
+func CompressRows(m Matrix, keep []bool) Matrix {
+	debugCheck("CompressRows", m)
+	return compressAxis("CompressRows", m, keep, 0)
+}
+
+// Get a copy of the columns of m for which keep is true, in their original
+// order. keep must have one element per column. The result's storage format
+// is chosen as for CompressRows().
+func CompressCols(m Matrix, keep []bool) Matrix {
+	debugCheck("CompressCols", m)
+	return compressAxis("CompressCols", m, keep, 1)
+}
+
+// Keep the indices of m along an axis for which keep is true
+func compressAxis(op string, m Matrix, keep []bool, axis int) Matrix {
+	sh := m.Shape()
+	if len(keep) != sh[axis] {
+		panic(ErrShapeMismatch{Op: op, Got: []int{len(keep)}, Want: []int{sh[axis]}})
+	}
+
+	// The new index of each kept index, or -1 for dropped indices
+	index := make([]int, len(keep))
+	kept := 0
+	for i, k := range keep {
+		index[i] = -1
+		if k {
+			index[i] = kept
+			kept++
+		}
+	}
+	shape := []int{sh[0], sh[1]}
+	shape[axis] = kept
+
+	if array, ok := m.(*sparseCompressedF64Matrix); ok {
+		return compressStore(array, index, kept, axis)
+	}
+	var result Matrix
+	if m.Sparsity() == DenseArray {
+		result = Dense(shape...).M()
+	} else {
+		result = SparseCoo(shape[0], shape[1])
+	}
+	m.VisitNonzero(func(pos []int, value float64) bool {
+		if i := index[pos[axis]]; i >= 0 {
+			newPos := []int{pos[0], pos[1]}
+			newPos[axis] = i
+			result.ItemSet(value, newPos...)
+		}
+		return true
+	})
+	return result
+}
+
+// Keep the indices of a compressed matrix along an axis which have a new
+// index, copying the kept values' storage directly
+func compressStore(array *sparseCompressedF64Matrix, index []int, kept, axis int) Matrix {
+	s := array.store
+	indptr := []int{0}
+	var ind []int
+	var data []float64
+	byMajor := (axis == 1) == array.byCol
+	for major := 0; major+1 < len(s.indptr); major++ {
+		if byMajor && index[major] < 0 {
+			continue
+		}
+		for pos := s.indptr[major]; pos < s.indptr[major+1]; pos++ {
+			switch {
+			case byMajor:
+				ind = append(ind, s.ind[pos])
+			case index[s.ind[pos]] >= 0:
+				ind = append(ind, index[s.ind[pos]])
+			default:
+				continue
+			}
+			data = append(data, s.data[pos])
+		}
+		indptr = append(indptr, len(ind))
+	}
+	shape := []int{array.shape[0], array.shape[1]}
+	shape[axis] = kept
+	return &sparseCompressedF64Matrix{
+		shape: shape,
+		store: &compressedStore{indptr: indptr, ind: ind, data: data},
+		byCol: array.byCol,
+	}
+}
@@ -0,0 +1,109 @@
+package matrix
+
+import (
+	"errors"
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+func TestRowSubset(t *testing.T) {
+	Convey("Given a view of some rows of a matrix", t, func() {
+		m := M(4, 2, 1, 2, 3, 4, 5, 6, 7, 8)
+		v := RowSubset(m, []int{2, 0, 2})
+
+		Convey("It reads the selected rows", func() {
+			So(v.Shape(), ShouldResemble, []int{3, 2})
+			So(v.Row(0), ShouldResemble, []float64{5, 6})
+			So(v.Col(1), ShouldResemble, []float64{6, 2, 6})
+			So(v.Item(1, 0), ShouldEqual, 1)
+			So(v.FlatItem(5), ShouldEqual, 6)
+			So(v.Equal(M(3, 2, 5, 6, 1, 2, 5, 6)), ShouldBeTrue)
+			So(v.T().Equal(M(2, 3, 5, 1, 5, 6, 2, 6)), ShouldBeTrue)
+			So(v.MProd(M(2, 1, 1, 1)).Col(0), ShouldResemble, []float64{11, 3, 11})
+		})
+
+		Convey("Changes to the matrix are visible", func() {
+			So(Sum(v), ShouldEqual, 25)
+			m.ItemSet(10, 2, 0)
+			So(v.Item(0, 0), ShouldEqual, 10)
+			So(Sum(v), ShouldEqual, 35)
+		})
+
+		Convey("It is read-only", func() {
+			err := try(func() { v.ItemSet(1, 0, 0) })
+			So(errors.Is(err, ErrFrozen), ShouldBeTrue)
+		})
+
+		Convey("Sparse matrices give sparse copies", func() {
+			s := RowSubset(m.SparseCoo(), []int{3})
+			So(s.Sparsity(), ShouldEqual, SparseCooMatrix)
+			So(s.Row(0), ShouldResemble, []float64{7, 8})
+		})
+
+		Convey("Invalid rows panic", func() {
+			So(func() { RowSubset(m, []int{4}) }, ShouldPanic)
+			So(func() { v.Row(3) }, ShouldPanic)
+		})
+	})
+}
+
+func TestCompressRowsCols(t *testing.T) {
+	Convey("Given a matrix in every format", t, func() {
+		values := []float64{
+			1, 0, 2, 0,
+			0, 3, 0, 0,
+			4, 0, 0, 5,
+		}
+		dense := M(3, 4, values...)
+		all := []Matrix{
+			dense,
+			dense.T().Dense().M().T(),
+			SparseCoo(3, 4, values...),
+			SparseCSR(3, 4, values...),
+			SparseCSC(3, 4, values...),
+		}
+
+		Convey("CompressRows keeps the selected rows", func() {
+			for _, m := range all {
+				c := CompressRows(m, []bool{true, false, true})
+				So(c.Shape(), ShouldResemble, []int{2, 4})
+				So(c.Array(), ShouldResemble, []float64{1, 0, 2, 0, 4, 0, 0, 5})
+				So(c.Sparsity(), ShouldEqual, m.Sparsity())
+				So(CheckInvariants(c), ShouldBeNil)
+			}
+		})
+
+		Convey("CompressCols keeps the selected columns", func() {
+			for _, m := range all {
+				c := CompressCols(m, []bool{false, true, false, true})
+				So(c.Shape(), ShouldResemble, []int{3, 2})
+				So(c.Array(), ShouldResemble, []float64{0, 0, 3, 0, 0, 5})
+				So(c.Sparsity(), ShouldEqual, m.Sparsity())
+				So(CheckInvariants(c), ShouldBeNil)
+			}
+		})
+
+		Convey("The result is a copy", func() {
+			c := CompressRows(all[3], []bool{true, true, true})
+			c.ItemSet(9, 1, 0)
+			So(all[3].Item(1, 0), ShouldEqual, 0)
+		})
+
+		Convey("Diagonal matrices give coo matrices", func() {
+			c := CompressCols(Diag(1, 2, 3), []bool{true, false, true})
+			So(c.Sparsity(), ShouldEqual, SparseCooMatrix)
+			So(c.Array(), ShouldResemble, []float64{1, 0, 0, 0, 0, 3})
+		})
+
+		Convey("Dropping everything gives an empty matrix", func() {
+			c := CompressRows(all[4], []bool{false, false, false})
+			So(c.Shape(), ShouldResemble, []int{0, 4})
+			So(c.CountNonzero(), ShouldEqual, 0)
+		})
+
+		Convey("The mask must match the axis", func() {
+			err := try(func() { CompressCols(dense, []bool{true}) })
+			So(err, ShouldHaveSameTypeAs, ErrShapeMismatch{})
+		})
+	})
+}
@@ -0,0 +1,175 @@
+package matrix
+
+import (
+	"fmt"
+	"gonum.org/v1/gonum/lapack"
+	lapackgonum "gonum.org/v1/gonum/lapack/gonum"
+)
+
+// Solve the Sylvester equation a x + x b = c for x, where a is m x m, b is
+// n x n and c is m x n, by the Bartels-Stewart algorithm: a and b are reduced
+// to real Schur form, the equivalent quasi-triangular equation is solved by
+// substitution, and x is recovered from its solution. The equation has a
+// unique solution unless a and -b have an eigenvalue in common, in which case
+// an error wrapping ErrSingular is returned. Returns ErrNoConvergence if a
+// Schur decomposition fails.
+func SolveSylvester(a, b, c Matrix) (Matrix, error) {
+	debugCheck("SolveSylvester", a, b, c)
+	m, n := a.Rows(), b.Rows()
+	switch {
+	case a.Cols() != m:
+		panic(ErrShapeMismatch{Op: "SolveSylvester", Got: a.Shape(), Want: []int{m, m}})
+	case b.Cols() != n:
+		panic(ErrShapeMismatch{Op: "SolveSylvester", Got: b.Shape(), Want: []int{n, n}})
+	case c.Rows() != m || c.Cols() != n:
+		panic(ErrShapeMismatch{Op: "SolveSylvester", Got: c.Shape(), Want: []int{m, n}})
+	}
+	if m == 0 || n == 0 {
+		return Dense(m, n).M(), nil
+	}
+
+	u, s, err := schur(a)
+	if err != nil {
+		return nil, err
+	}
+	v, t, err := schur(b)
+	if err != nil {
+		return nil, err
+	}
+
+	// With a = U S U^T and b = V T V^T, the equation becomes S y + y T = f
+	// for y = U^T x V and f = U^T c V
+	f := u.T().MProd(c, v)
+	y, err := solveQuasiTriangular(s, t, f)
+	if err != nil {
+		return nil, err
+	}
+	return u.MProd(y, v.T()), nil
+}
+
+// Solve the continuous Lyapunov equation a x + x a^T = q for x, where a and q
+// are n x n, using SolveSylvester(). If q is symmetric, so is x. Returns an
+// error wrapping ErrSingular unless the sum of every pair of eigenvalues of a
+// is nonzero, which holds if a is stable.
+func SolveLyapunov(a, q Matrix) (Matrix, error) {
+	debugCheck("SolveLyapunov", a, q)
+	return SolveSylvester(a, a.T(), q)
+}
+
+// Get the real Schur decomposition of a square matrix, a = U S U^T for
+// orthogonal U and upper quasi-triangular S, which has 1 x 1 and 2 x 2 blocks
+// on its diagonal for the real and complex eigenvalues of a
+func schur(a Matrix) (U, S Matrix, err error) {
+	impl := lapackgonum.Implementation{}
+	n := a.Rows()
+	h := append([]float64(nil), a.Array()...)
+	tau := make([]float64, max(n-1, 1))
+	work := make([]float64, 1)
+
+	// Reduce a to upper Hessenberg form, h = Q^T a Q
+	impl.Dgehrd(n, 0, n-1, h, n, tau, work, -1)
+	work = make([]float64, int(work[0]))
+	impl.Dgehrd(n, 0, n-1, h, n, tau, work, len(work))
+	q := append([]float64(nil), h...)
+	impl.Dorghr(n, 0, n-1, q, n, tau, work, -1)
+	work = make([]float64, max(int(work[0]), len(work)))
+	impl.Dorghr(n, 0, n-1, q, n, tau, work, len(work))
+	for i := 2; i < n; i++ {
+		for j := 0; j < i-1; j++ {
+			h[i*n+j] = 0
+		}
+	}
+
+	// Reduce h to Schur form, accumulating the transformations into Q
+	wr, wi := make([]float64, n), make([]float64, n)
+	impl.Dhseqr(lapack.EigenvaluesAndSchur, lapack.SchurOrig, n, 0, n-1, h, n, wr, wi, q, n, work, -1)
+	work = make([]float64, max(int(work[0]), n))
+	if impl.Dhseqr(lapack.EigenvaluesAndSchur, lapack.SchurOrig, n, 0, n-1, h, n, wr, wi, q, n, work, len(work)) > 0 {
+		return nil, nil, ErrNoConvergence
+	}
+	return M(n, n, q...), M(n, n, h...), nil
+}
+
+// Get the sizes of the diagonal blocks of an upper quasi-triangular matrix
+func schurBlocks(s Matrix) []int {
+	var blocks []int
+	for i := 0; i < s.Rows(); {
+		size := 1
+		if i+1 < s.Rows() && s.Item(i+1, i) != 0 {
+			size = 2
+		}
+		blocks = append(blocks, size)
+		i += size
+	}
+	return blocks
+}
+
+// Solve s y + y t = f for y, where s and t are upper quasi-triangular, one
+// pair of diagonal blocks at a time. Block row k of y depends on the rows
+// below it, and block column l on the columns before it, so rows are solved
+// from the bottom up and columns from left to right.
+func solveQuasiTriangular(s, t, f Matrix) (Matrix, error) {
+	impl := lapackgonum.Implementation{}
+	m, n := s.Rows(), t.Rows()
+	y := Dense(m, n).M()
+	sBlocks, tBlocks := schurBlocks(s), schurBlocks(t)
+	scale := 1.0
+
+	rowEnd := m
+	for bk := len(sBlocks) - 1; bk >= 0; bk-- {
+		k, n1 := rowEnd-sBlocks[bk], sBlocks[bk]
+		rowEnd = k
+		for l, bl := 0, 0; bl < len(tBlocks); l, bl = l+tBlocks[bl], bl+1 {
+			n2 := tBlocks[bl]
+
+			// rhs = f_kl - s_k,below y_below,l - y_k,before t_before,l
+			rhs := make([]float64, 4)
+			for i := 0; i < n1; i++ {
+				for j := 0; j < n2; j++ {
+					v := scale * f.Item(k+i, l+j)
+					for p := k + n1; p < m; p++ {
+						v -= s.Item(k+i, p) * y.Item(p, l+j)
+					}
+					for p := 0; p < l; p++ {
+						v -= y.Item(k+i, p) * t.Item(p, l+j)
+					}
+					rhs[i*2+j] = v
+				}
+			}
+			tl := subBlock(s, k, n1)
+			tr := subBlock(t, l, n2)
+			x := make([]float64, 4)
+			blockScale, _, ok := impl.Dlasy2(false, false, 1, n1, n2, tl, 2, tr, 2, rhs, 2, x, 2)
+			if !ok {
+				return nil, fmt.Errorf("%w: the coefficients share an eigenvalue with opposite signs", ErrSingular)
+			}
+			if blockScale != 1 {
+				// Scale the solution so far to match, and solve for
+				// blockScale * scale * f from now on
+				y = ItemProd(y, blockScale).M()
+				scale *= blockScale
+			}
+			for i := 0; i < n1; i++ {
+				for j := 0; j < n2; j++ {
+					y.ItemSet(x[i*2+j], k+i, l+j)
+				}
+			}
+		}
+	}
+	if scale != 1 {
+		y = ItemDiv(y, scale).M()
+	}
+	return y, nil
+}
+
+// Copy the size x size diagonal block of m starting at (start, start) into a
+// 2 x 2 row-major array
+func subBlock(m Matrix, start, size int) []float64 {
+	block := make([]float64, 4)
+	for i := 0; i < size; i++ {
+		for j := 0; j < size; j++ {
+			block[i*2+j] = m.Item(start+i, start+j)
+		}
+	}
+	return block
+}
@@ -0,0 +1,75 @@
+package matrix
+
+import (
+	"errors"
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+func TestSolveSylvester(t *testing.T) {
+	Convey("Given coefficients with real and complex eigenvalues", t, func() {
+		a := M(3, 3,
+			1, 2, 0,
+			-2, 1, 1,
+			0, 0, 3)
+		b := M(2, 2,
+			4, 1,
+			0, 2)
+		c := M(3, 2,
+			1, 2,
+			3, 4,
+			5, 6)
+
+		Convey("SolveSylvester satisfies a x + x b = c", func() {
+			x, err := SolveSylvester(a, b, c)
+			So(err, ShouldBeNil)
+			So(x.Shape(), ShouldResemble, []int{3, 2})
+			So(ApproxEqual(a.MProd(x).Add(x.MProd(b)).M(), c, 1e-10, 1e-10), ShouldBeTrue)
+		})
+
+		Convey("Larger problems with several 2 x 2 blocks are solved", func() {
+			a := M(4, 4,
+				0, 1, 0, 0,
+				-5, -2, 0, 0,
+				1, 0, 0, 3,
+				0, 1, -3, 0)
+			b := M(3, 3,
+				1, -1, 0,
+				1, 1, 0,
+				2, 0, 4)
+			c := M(4, 3, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12)
+			x, err := SolveSylvester(a, b, c)
+			So(err, ShouldBeNil)
+			So(ApproxEqual(a.MProd(x).Add(x.MProd(b)).M(), c, 1e-10, 1e-10), ShouldBeTrue)
+		})
+
+		Convey("Shared eigenvalues give ErrSingular", func() {
+			_, err := SolveSylvester(Diag(1, 2), Diag(-2, 5), M(2, 2, 1, 1, 1, 1))
+			So(errors.Is(err, ErrSingular), ShouldBeTrue)
+		})
+
+		Convey("Mismatched shapes panic", func() {
+			err := try(func() { SolveSylvester(a, b, M(2, 2, 1, 2, 3, 4)) })
+			So(err, ShouldHaveSameTypeAs, ErrShapeMismatch{})
+		})
+	})
+}
+
+func TestSolveLyapunov(t *testing.T) {
+	Convey("Given a stable system", t, func() {
+		a := M(2, 2,
+			-1, 2,
+			-3, -4)
+		q := M(2, 2,
+			-2, 1,
+			1, -3)
+
+		Convey("SolveLyapunov satisfies a x + x a^T = q with symmetric x", func() {
+			x, err := SolveLyapunov(a, q)
+			So(err, ShouldBeNil)
+			So(ApproxEqual(a.MProd(x).Add(x.MProd(a.T())).M(), q, 1e-10, 1e-10), ShouldBeTrue)
+			So(ApproxEqual(x, x.T(), 1e-10, 1e-10), ShouldBeTrue)
+			So(a.Array(), ShouldResemble, []float64{-1, 2, -3, -4})
+		})
+	})
+}
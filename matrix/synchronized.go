@@ -0,0 +1,549 @@
+package matrix
+
+import (
+	"database/sql/driver"
+	"sync"
+)
+
+// A matrix guarded by a read-write mutex, created by Synchronized()
+type syncMatrix struct {
+	mu *sync.RWMutex
+	m  Matrix
+}
+
+// Get a matrix which can safely be shared between goroutines, some of which
+// modify it. Methods which modify the matrix take an exclusive lock, and all
+// other methods take a shared lock, so reads run concurrently with each other
+// but not with writes. Methods which would return a view of the storage, such
+// as Row(), Col() and Array(), return copies instead, and T() returns a
+// transposed matrix guarded by the same lock.
+//
+// Callbacks passed to Visit() and VisitNonzero() run while the shared lock is
+// held, so they must not modify the matrix. The lock only guards access made
+// through the synchronized matrix; m itself must no longer be used directly.
+func Synchronized(m Matrix) Matrix {
+	if s, ok := m.(*syncMatrix); ok {
+		return s
+	}
+	return &syncMatrix{mu: new(sync.RWMutex), m: m}
+}
+
+// Replace arrays guarded by our own lock with their underlying arrays, since
+// taking a shared lock twice in one goroutine can deadlock
+func (s *syncMatrix) unwrap(others []NDArray) []NDArray {
+	result := make([]NDArray, len(others))
+	for i, o := range others {
+		if other, ok := o.(*syncMatrix); ok && other.mu == s.mu {
+			result[i] = other.m
+		} else {
+			result[i] = o
+		}
+	}
+	return result
+}
+
+// Replace a matrix guarded by our own lock with its underlying matrix
+func (s *syncMatrix) unwrapM(other Matrix) Matrix {
+	if o, ok := other.(*syncMatrix); ok && o.mu == s.mu {
+		return o.m
+	}
+	return other
+}
+
+// Return the element-wise sum of this array and one or more others
+func (s *syncMatrix) Add(others ...NDArray) NDArray {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.m.Add(s.unwrap(others)...)
+}
+
+// Returns true if and only if all items are nonzero
+func (s *syncMatrix) All() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.m.All()
+}
+
+// Returns true if f is true for all array elements
+func (s *syncMatrix) AllF(f func(v float64) bool) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.m.AllF(f)
+}
+
+// Returns true if f is true for all pairs of array elements in the same position
+func (s *syncMatrix) AllF2(f func(v1, v2 float64) bool, other NDArray) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.m.AllF2(f, s.unwrap([]NDArray{other})[0])
+}
+
+// Returns true if all elements are finite
+func (s *syncMatrix) AllFinite() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.m.AllFinite()
+}
+
+// Returns true if and only if any item is nonzero
+func (s *syncMatrix) Any() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.m.Any()
+}
+
+// Returns true if f is true for any array element
+func (s *syncMatrix) AnyF(f func(v float64) bool) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.m.AnyF(f)
+}
+
+// Returns true if f is true for any pair of array elements in the same position
+func (s *syncMatrix) AnyF2(f func(v1, v2 float64) bool, other NDArray) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.m.AnyF2(f, s.unwrap([]NDArray{other})[0])
+}
+
+// Returns true if any element is NaN
+func (s *syncMatrix) AnyNaN() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.m.AnyNaN()
+}
+
+// Return the result of applying a function to all elements
+func (s *syncMatrix) Apply(f func(float64) float64) NDArray {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.m.Apply(f)
+}
+
+// Get a copy of the array's values, flattened in row-major order
+func (s *syncMatrix) Array() []float64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return append([]float64(nil), s.m.Array()...)
+}
+
+// Get a copy of a column
+func (s *syncMatrix) Col(col int) []float64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return append([]float64(nil), s.m.Col(col)...)
+}
+
+// Set the values of the items on a given column
+func (s *syncMatrix) ColSet(col int, values []float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.m.ColSet(col, values)
+}
+
+// Get the number of columns
+func (s *syncMatrix) Cols() int {
+	return s.m.Cols()
+}
+
+// Returns an unsynchronized deep copy of this array
+func (s *syncMatrix) Clone() NDArray {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.m.Clone()
+}
+
+// Create a new array by concatenating this with another array along the
+// specified axis
+func (s *syncMatrix) Concat(axis int, others ...NDArray) NDArray {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.m.Concat(axis, s.unwrap(others)...)
+}
+
+// Get the condition number for the matrix norm of the specified ordinality
+func (s *syncMatrix) Cond(ord float64) float64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.m.Cond(ord)
+}
+
+// Returns an unsynchronized duplicate of this array
+func (s *syncMatrix) Copy() NDArray {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.m.Copy()
+}
+
+// Counts the number of nonzero elements in the array
+func (s *syncMatrix) CountNonzero() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.m.CountNonzero()
+}
+
+// Returns a dense copy of the array
+func (s *syncMatrix) Dense() NDArray {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.m.Dense()
+}
+
+// Get the determinant of a square matrix
+func (s *syncMatrix) Det() float64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.m.Det()
+}
+
+// Get a column vector containing the main diagonal elements of the matrix
+func (s *syncMatrix) Diag() Matrix {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.m.Diag()
+}
+
+// Get the pairwise distance between the rows
+func (s *syncMatrix) Dist(t DistType) Matrix {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.m.Dist(t)
+}
+
+// Get the distance between the rows of this and other
+func (s *syncMatrix) DistTo(other Matrix, t DistType) Matrix {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.m.DistTo(other, t)
+}
+
+// Return the element-wise quotient of this array and one or more others
+func (s *syncMatrix) Div(others ...NDArray) NDArray {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.m.Div(s.unwrap(others)...)
+}
+
+// Returns true if and only if all elements in the two arrays are equal
+func (s *syncMatrix) Equal(other NDArray) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.m.Equal(s.unwrap([]NDArray{other})[0])
+}
+
+// Set all array elements to the given value
+func (s *syncMatrix) Fill(value float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.m.Fill(value)
+}
+
+// Get the coordinates for the item at the specified flat position
+func (s *syncMatrix) FlatCoord(index int) []int {
+	return s.m.FlatCoord(index)
+}
+
+// Get an array element in a flattened version of this array
+func (s *syncMatrix) FlatItem(index int) float64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.m.FlatItem(index)
+}
+
+// Set an array element in a flattened version of this array
+func (s *syncMatrix) FlatItemSet(value float64, index int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.m.FlatItemSet(value, index)
+}
+
+// Get the matrix inverse
+func (s *syncMatrix) Inverse() (Matrix, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.m.Inverse()
+}
+
+// Get an array element
+func (s *syncMatrix) Item(index ...int) float64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.m.Item(index...)
+}
+
+// Add a scalar value to each array element
+func (s *syncMatrix) ItemAdd(value float64) NDArray {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.m.ItemAdd(value)
+}
+
+// Divide each array element by a scalar value
+func (s *syncMatrix) ItemDiv(value float64) NDArray {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.m.ItemDiv(value)
+}
+
+// Multiply each array element by a scalar value
+func (s *syncMatrix) ItemProd(value float64) NDArray {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.m.ItemProd(value)
+}
+
+// Subtract a scalar value from each array element
+func (s *syncMatrix) ItemSub(value float64) NDArray {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.m.ItemSub(value)
+}
+
+// Set an array element
+func (s *syncMatrix) ItemSet(value float64, index ...int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.m.ItemSet(value, index...)
+}
+
+// Solve for x, where ax = b and a is `this`
+func (s *syncMatrix) LDivide(b Matrix) Matrix {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.m.LDivide(s.unwrapM(b))
+}
+
+// A synchronized matrix is already a matrix
+func (s *syncMatrix) M() Matrix {
+	return s
+}
+
+// Get the value of the largest array element
+func (s *syncMatrix) Max() float64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.m.Max()
+}
+
+// Get the value of the smallest array element
+func (s *syncMatrix) Min() float64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.m.Min()
+}
+
+// Get the result of matrix multiplication between this and some other
+// matrices
+func (s *syncMatrix) MProd(others ...Matrix) Matrix {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	unwrapped := make([]Matrix, len(others))
+	for i, o := range others {
+		unwrapped[i] = s.unwrapM(o)
+	}
+	return s.m.MProd(unwrapped...)
+}
+
+// Get the number of dimensions
+func (s *syncMatrix) NDim() int {
+	return s.m.NDim()
+}
+
+// Get the matrix norm of the specified ordinality
+func (s *syncMatrix) Norm(ord float64) float64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.m.Norm(ord)
+}
+
+// Return a copy of the array, normalized to sum to 1
+func (s *syncMatrix) Normalize() NDArray {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.m.Normalize()
+}
+
+// Get the Moore-Penrose pseudo-inverse, treating singular values no larger
+// than tol as zero
+func (s *syncMatrix) Pinv(tol float64) (Matrix, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.m.Pinv(tol)
+}
+
+// Return the element-wise product of this array and one or more others
+func (s *syncMatrix) Prod(others ...NDArray) NDArray {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.m.Prod(s.unwrap(others)...)
+}
+
+// Get the number of singular values larger than tol
+func (s *syncMatrix) Rank(tol float64) int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.m.Rank(tol)
+}
+
+// Return a 1D copy of the array
+func (s *syncMatrix) Ravel() NDArray {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.m.Ravel()
+}
+
+// Get a copy of a row
+func (s *syncMatrix) Row(row int) []float64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return append([]float64(nil), s.m.Row(row)...)
+}
+
+// Set the values of the items on a given row
+func (s *syncMatrix) RowSet(row int, values []float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.m.RowSet(row, values)
+}
+
+// Get the number of rows
+func (s *syncMatrix) Rows() int {
+	return s.m.Rows()
+}
+
+// Replace the contents of the array from a database value
+func (s *syncMatrix) Scan(src interface{}) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.m.Scan(src)
+}
+
+// Copy src into the matrix, with the top-left element of src at (row, col)
+func (s *syncMatrix) SetSubmatrix(row, col int, src Matrix) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.m.SetSubmatrix(row, col, s.unwrapM(src))
+}
+
+// Get the array dimensions
+func (s *syncMatrix) Shape() []int {
+	return s.m.Shape()
+}
+
+// Get the number of elements
+func (s *syncMatrix) Size() int {
+	return s.m.Size()
+}
+
+// Return a slice of the array
+func (s *syncMatrix) Slice(from []int, to []int) NDArray {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.m.Slice(from, to)
+}
+
+// Return a sparse coo copy of the matrix
+func (s *syncMatrix) SparseCoo() Matrix {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.m.SparseCoo()
+}
+
+// Return a compressed sparse column copy of the matrix
+func (s *syncMatrix) SparseCSC() Matrix {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.m.SparseCSC()
+}
+
+// Return a compressed sparse row copy of the matrix
+func (s *syncMatrix) SparseCSR() Matrix {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.m.SparseCSR()
+}
+
+// Return a sparse diag copy of the matrix
+func (s *syncMatrix) SparseDiag() Matrix {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.m.SparseDiag()
+}
+
+// Get the storage format of the underlying matrix
+func (s *syncMatrix) Sparsity() ArraySparsity {
+	return s.m.Sparsity()
+}
+
+// Return the element-wise difference of this array and one or more others
+func (s *syncMatrix) Sub(others ...NDArray) NDArray {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.m.Sub(s.unwrap(others)...)
+}
+
+// Return the sum of all array elements
+func (s *syncMatrix) Sum() float64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.m.Sum()
+}
+
+// Exchange the values of two columns in place
+func (s *syncMatrix) SwapCols(i, j int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.m.SwapCols(i, j)
+}
+
+// Exchange the values of two rows in place
+func (s *syncMatrix) SwapRows(i, j int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.m.SwapRows(i, j)
+}
+
+// Get the transpose, which shares storage and the lock with this matrix
+func (s *syncMatrix) T() Matrix {
+	return &syncMatrix{mu: s.mu, m: s.m.T()}
+}
+
+// Get the sum of the elements on the main diagonal
+func (s *syncMatrix) Trace() float64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.m.Trace()
+}
+
+// Get the sum of the elements on a diagonal offset from the main diagonal
+func (s *syncMatrix) TraceOffset(offset int) float64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.m.TraceOffset(offset)
+}
+
+// Get the database value of the array
+func (s *syncMatrix) Value() (driver.Value, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.m.Value()
+}
+
+// Get a view of the matrix, which shares storage and the lock with this one
+func (s *syncMatrix) View() NDArray {
+	return &syncMatrix{mu: s.mu, m: s.m.View().M()}
+}
+
+// Visit all matrix elements while holding the shared lock
+func (s *syncMatrix) Visit(f func(pos []int, value float64) bool) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.m.Visit(f)
+}
+
+// Visit just nonzero elements while holding the shared lock
+func (s *syncMatrix) VisitNonzero(f func(pos []int, value float64) bool) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.m.VisitNonzero(f)
+}
@@ -0,0 +1,63 @@
+package matrix
+
+import (
+	. "github.com/smartystreets/goconvey/convey"
+	"sync"
+	"testing"
+)
+
+func TestSynchronized(t *testing.T) {
+	Convey("Given a synchronized matrix", t, func() {
+		m := Synchronized(M(2, 3,
+			1, 2, 3,
+			4, 5, 6))
+
+		Convey("It behaves as the underlying matrix", func() {
+			So(m.Equal(M(2, 3, 1, 2, 3, 4, 5, 6)), ShouldBeTrue)
+			So(m.Sum(), ShouldEqual, 21)
+			So(m.MProd(m.T()).Equal(M(2, 2, 14, 32, 32, 77)), ShouldBeTrue)
+			So(m.Add(m).Equal(m.ItemProd(2)), ShouldBeTrue)
+			So(Synchronized(m), ShouldEqual, m)
+			So(m.M(), ShouldEqual, m)
+		})
+
+		Convey("Rows, columns and arrays are copies", func() {
+			m.Row(0)[0] = 10
+			m.Col(1)[0] = 10
+			m.Array()[2] = 10
+			So(m.Equal(M(2, 3, 1, 2, 3, 4, 5, 6)), ShouldBeTrue)
+		})
+
+		Convey("The transpose shares storage and the lock", func() {
+			tr := m.T()
+			tr.ItemSet(7, 2, 1)
+			So(m.Item(1, 2), ShouldEqual, 7)
+			So(tr.MProd(m).Shape(), ShouldResemble, []int{3, 3})
+			So(m.Equal(tr.T()), ShouldBeTrue)
+		})
+
+		Convey("Concurrent reads and writes are safe", func() {
+			var wg sync.WaitGroup
+			for g := 0; g < 8; g++ {
+				wg.Add(2)
+				go func(g int) {
+					defer wg.Done()
+					for i := 0; i < 100; i++ {
+						m.ItemSet(float64(g), i%2, i%3)
+						m.RowSet(i%2, []float64{1, 2, 3})
+					}
+				}(g)
+				go func() {
+					defer wg.Done()
+					for i := 0; i < 100; i++ {
+						m.Sum()
+						m.Row(i % 2)
+						m.MProd(m.T())
+					}
+				}()
+			}
+			wg.Wait()
+			So(m.Shape(), ShouldResemble, []int{2, 3})
+		})
+	})
+}
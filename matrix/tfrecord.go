@@ -0,0 +1,155 @@
+package matrix
+
+import (
+	"encoding/binary"
+	"fmt"
+	"google.golang.org/protobuf/encoding/protowire"
+	"hash/crc32"
+	"io"
+	"math"
+)
+
+// A feature to write to tf.train.Example records. Each record holds row i of
+// every feature's matrix.
+//
+// Dense features are written as a FloatList named Name holding every value
+// in the row. Sparse features are written as two features suitable for
+// tf.io.SparseFeature: an Int64List named Name + "_indices" holding the
+// column indices of the row's nonzero values, and a FloatList named
+// Name + "_values" holding the values themselves.
+//
+// TensorFlow stores float features in single precision, so values are
+// rounded to float32.
+type TFFeature struct {
+	Name   string
+	Matrix Matrix
+	Sparse bool
+}
+
+// Writes tf.train.Example records in the TFRecord file format, which can be
+// read by tf.data.TFRecordDataset.
+type TFRecordWriter struct {
+	w io.Writer
+}
+
+// Create a writer which writes TFRecord data to w
+func NewTFRecordWriter(w io.Writer) *TFRecordWriter {
+	return &TFRecordWriter{w: w}
+}
+
+// Write one tf.train.Example record for each row of the features' matrices.
+// All matrices must have the same number of rows.
+func (w *TFRecordWriter) WriteRows(features ...TFFeature) error {
+	if len(features) == 0 {
+		return nil
+	}
+	rows := features[0].Matrix.Rows()
+	for _, feature := range features[1:] {
+		if feature.Matrix.Rows() != rows {
+			panic(fmt.Sprintf("Can't write TFRecords for features with %d and %d rows",
+				rows, feature.Matrix.Rows()))
+		}
+	}
+	for row := 0; row < rows; row++ {
+		var encoded []byte
+		for _, feature := range features {
+			encoded = appendTFFeature(encoded, feature, row)
+		}
+		var example []byte
+		example = protowire.AppendTag(example, 1, protowire.BytesType)
+		example = protowire.AppendBytes(example, encoded)
+		if err := w.WriteRecord(example); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Write a single serialized record with TFRecord framing: the record length,
+// the data, and masked CRC-32C checksums of each.
+func (w *TFRecordWriter) WriteRecord(data []byte) error {
+	var header [12]byte
+	binary.LittleEndian.PutUint64(header[:8], uint64(len(data)))
+	binary.LittleEndian.PutUint32(header[8:], tfRecordCRC(header[:8]))
+	var footer [4]byte
+	binary.LittleEndian.PutUint32(footer[:], tfRecordCRC(data))
+	for _, buf := range [][]byte{header[:], data, footer[:]} {
+		if _, err := w.w.Write(buf); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Write the rows of m to w as tf.train.Example records in the TFRecord file
+// format, with each row stored in a single feature of the given name.
+func WriteTFRecords(w io.Writer, m Matrix, name string, sparse bool) error {
+	return NewTFRecordWriter(w).WriteRows(TFFeature{
+		Name:   name,
+		Matrix: m,
+		Sparse: sparse,
+	})
+}
+
+// The CRC table for the Castagnoli polynomial, used by TFRecord
+var tfRecordTable = crc32.MakeTable(crc32.Castagnoli)
+
+// Get the masked CRC-32C checksum TFRecord stores for some data
+func tfRecordCRC(data []byte) uint32 {
+	crc := crc32.Checksum(data, tfRecordTable)
+	return ((crc >> 15) | (crc << 17)) + 0xa282ead8
+}
+
+// Append the Features.feature map entries for a row of a feature
+func appendTFFeature(msg []byte, feature TFFeature, row int) []byte {
+	m := feature.Matrix
+	if !feature.Sparse {
+		values := make([]float64, m.Cols())
+		for col := range values {
+			values[col] = m.Item(row, col)
+		}
+		return appendTFMapEntry(msg, feature.Name, 2, appendTFFloats(values))
+	}
+
+	var (
+		indices []byte
+		values  []float64
+	)
+	for col := 0; col < m.Cols(); col++ {
+		if v := m.Item(row, col); v != 0 {
+			indices = protowire.AppendVarint(indices, uint64(col))
+			values = append(values, v)
+		}
+	}
+	var list []byte
+	list = protowire.AppendTag(list, 1, protowire.BytesType)
+	list = protowire.AppendBytes(list, indices)
+	msg = appendTFMapEntry(msg, feature.Name+"_indices", 3, list)
+	return appendTFMapEntry(msg, feature.Name+"_values", 2, appendTFFloats(values))
+}
+
+// Encode a FloatList message holding values as packed floats
+func appendTFFloats(values []float64) []byte {
+	var packed []byte
+	for _, v := range values {
+		packed = protowire.AppendFixed32(packed, math.Float32bits(float32(v)))
+	}
+	var list []byte
+	list = protowire.AppendTag(list, 1, protowire.BytesType)
+	return protowire.AppendBytes(list, packed)
+}
+
+// Append a Features.feature map entry whose Feature holds list in the given
+// oneof field (2 for FloatList, 3 for Int64List)
+func appendTFMapEntry(msg []byte, name string, kind protowire.Number, list []byte) []byte {
+	var feature []byte
+	feature = protowire.AppendTag(feature, kind, protowire.BytesType)
+	feature = protowire.AppendBytes(feature, list)
+	var entry []byte
+	entry = protowire.AppendTag(entry, 1, protowire.BytesType)
+	entry = protowire.AppendString(entry, name)
+	entry = protowire.AppendTag(entry, 2, protowire.BytesType)
+	entry = protowire.AppendBytes(entry, feature)
+	msg = protowire.AppendTag(msg, 1, protowire.BytesType)
+	return protowire.AppendBytes(msg, entry)
+}
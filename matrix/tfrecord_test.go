@@ -0,0 +1,96 @@
+package matrix
+
+import (
+	"bytes"
+	"encoding/binary"
+	. "github.com/smartystreets/goconvey/convey"
+	"google.golang.org/protobuf/encoding/protowire"
+	"math"
+	"testing"
+)
+
+// Read TFRecord data, verifying its checksums, and decode each record's
+// tf.train.Example features
+func readTFExamples(data []byte) []map[string][]float64 {
+	var examples []map[string][]float64
+	for len(data) > 0 {
+		length := binary.LittleEndian.Uint64(data)
+		So(binary.LittleEndian.Uint32(data[8:]), ShouldEqual, tfRecordCRC(data[:8]))
+		record := data[12 : 12+length]
+		So(binary.LittleEndian.Uint32(data[12+length:]), ShouldEqual, tfRecordCRC(record))
+		data = data[16+length:]
+
+		example := make(map[string][]float64)
+		So(protoFields(record, func(_ protowire.Number, _ protowire.Type, features []byte) error {
+			return protoFields(features, func(_ protowire.Number, _ protowire.Type, entry []byte) error {
+				var name string
+				return protoFields(entry, func(num protowire.Number, _ protowire.Type, field []byte) error {
+					if num == 1 {
+						name = string(field)
+						return nil
+					}
+					example[name] = []float64{}
+					return protoFields(field, func(kind protowire.Number, _ protowire.Type, list []byte) error {
+						return protoFields(list, func(_ protowire.Number, typ protowire.Type, packed []byte) error {
+							if kind == 3 {
+								return protoVarints(typ, packed, func(v uint64) {
+									example[name] = append(example[name], float64(v))
+								})
+							}
+							return protoFixed(typ, packed, protowire.Fixed32Type, func(v uint64) {
+								example[name] = append(example[name], float64(math.Float32frombits(uint32(v))))
+							})
+						})
+					})
+				})
+			})
+		}), ShouldBeNil)
+		examples = append(examples, example)
+	}
+	return examples
+}
+
+func TestTFRecord(t *testing.T) {
+	Convey("Given a matrix", t, func() {
+		m := M(2, 3,
+			1, 0, 2.5,
+			0, 0, 4)
+
+		Convey("WriteTFRecords writes dense rows", func() {
+			var buf bytes.Buffer
+			So(WriteTFRecords(&buf, m, "x", false), ShouldBeNil)
+			So(readTFExamples(buf.Bytes()), ShouldResemble, []map[string][]float64{
+				{"x": {1, 0, 2.5}},
+				{"x": {0, 0, 4}},
+			})
+		})
+
+		Convey("WriteTFRecords writes sparse rows", func() {
+			var buf bytes.Buffer
+			So(WriteTFRecords(&buf, m.SparseCoo(), "x", true), ShouldBeNil)
+			So(readTFExamples(buf.Bytes()), ShouldResemble, []map[string][]float64{
+				{"x_indices": {0, 2}, "x_values": {1, 2.5}},
+				{"x_indices": {2}, "x_values": {4}},
+			})
+		})
+
+		Convey("WriteRows combines features in each record", func() {
+			var buf bytes.Buffer
+			w := NewTFRecordWriter(&buf)
+			So(w.WriteRows(
+				TFFeature{Name: "x", Matrix: m},
+				TFFeature{Name: "y", Matrix: M(2, 1, 1, 0)},
+			), ShouldBeNil)
+			So(readTFExamples(buf.Bytes()), ShouldResemble, []map[string][]float64{
+				{"x": {1, 0, 2.5}, "y": {1}},
+				{"x": {0, 0, 4}, "y": {0}},
+			})
+			So(func() { w.WriteRows(TFFeature{Name: "x", Matrix: m}, TFFeature{Name: "y", Matrix: Eye(3)}) }, ShouldPanic)
+		})
+	})
+
+	Convey("Checksums are masked CRC-32C", t, func() {
+		So(tfRecordCRC(nil), ShouldEqual, uint32(0xa282ead8))
+		So(tfRecordCRC([]byte("123456789")), ShouldEqual, uint32(0xc78ab0e5))
+	})
+}
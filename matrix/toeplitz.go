@@ -0,0 +1,172 @@
+package matrix
+
+// A read-only matrix which is constant along each diagonal, created by
+// Toeplitz() or Circulant(), or along each anti-diagonal, created by Hankel().
+// It stores the rows+cols-1 values of its diagonals, from the top-right corner
+// to the bottom-left one, so the element at (row, col) is
+// values[row+cols-1-col]. For a Hankel matrix, the values run from the
+// top-left corner to the bottom-right one, and the element is
+// values[row+col].
+type toeplitzMatrix struct {
+	lazyMatrix
+	values     []float64
+	rows, cols int
+	hankel     bool
+}
+
+// Create a Toeplitz or Hankel matrix from the values of its diagonals, without
+// copying them
+func newToeplitz(values []float64, rows, cols int, hankel bool) *toeplitzMatrix {
+	t := &toeplitzMatrix{values: values, rows: rows, cols: cols, hankel: hankel}
+	t.build = func() Matrix {
+		return FromFunc(rows, cols, t.item)
+	}
+	return t
+}
+
+// Get the index into values of a valid position
+func (t *toeplitzMatrix) diag(row, col int) int {
+	if t.hankel {
+		return row + col
+	}
+	return row + t.cols - 1 - col
+}
+
+// Get the number of elements on the diagonal stored at values[k]
+func (t *toeplitzMatrix) diagLen(k int) int {
+	return max(min(k, t.rows-1)-max(k-t.cols+1, 0)+1, 0)
+}
+
+// Get the element at a valid position
+func (t *toeplitzMatrix) item(row, col int) float64 {
+	return t.values[t.diag(row, col)]
+}
+
+// Get a copy of a column
+func (t *toeplitzMatrix) Col(col int) []float64 {
+	if col < 0 || col >= t.cols {
+		panic(ErrIndexOutOfRange{Op: "Col", Index: []int{col}, Shape: []int{t.cols}})
+	}
+	start := t.diag(0, col)
+	return append([]float64(nil), t.values[start:start+t.rows]...)
+}
+
+// Get the number of columns
+func (t *toeplitzMatrix) Cols() int {
+	return t.cols
+}
+
+// Counts the nonzero elements
+func (t *toeplitzMatrix) CountNonzero() int {
+	count := 0
+	for k, v := range t.values {
+		if v != 0 {
+			count += t.diagLen(k)
+		}
+	}
+	return count
+}
+
+// Get an array element in a flattened version of this array
+func (t *toeplitzMatrix) FlatItem(index int) float64 {
+	if index < 0 || index >= t.rows*t.cols {
+		panic(ErrIndexOutOfRange{Op: "FlatItem", Index: []int{index}, Shape: []int{t.rows * t.cols}})
+	}
+	return t.item(index/t.cols, index%t.cols)
+}
+
+// Get an array element
+func (t *toeplitzMatrix) Item(index ...int) float64 {
+	if len(index) != 2 || index[0] < 0 || index[0] >= t.rows || index[1] < 0 || index[1] >= t.cols {
+		panic(ErrIndexOutOfRange{Op: "Item", Index: index, Shape: t.Shape()})
+	}
+	return t.item(index[0], index[1])
+}
+
+// A Toeplitz matrix is already a matrix
+func (t *toeplitzMatrix) M() Matrix {
+	return t
+}
+
+// Get the number of array dimensions
+func (t *toeplitzMatrix) NDim() int {
+	return 2
+}
+
+// Get a copy of a row
+func (t *toeplitzMatrix) Row(row int) []float64 {
+	if row < 0 || row >= t.rows {
+		panic(ErrIndexOutOfRange{Op: "Row", Index: []int{row}, Shape: []int{t.rows}})
+	}
+	result := make([]float64, t.cols)
+	for col := range result {
+		result[col] = t.item(row, col)
+	}
+	return result
+}
+
+// Get the number of rows
+func (t *toeplitzMatrix) Rows() int {
+	return t.rows
+}
+
+// Get the array dimensions
+func (t *toeplitzMatrix) Shape() []int {
+	return []int{t.rows, t.cols}
+}
+
+// Get the number of elements
+func (t *toeplitzMatrix) Size() int {
+	return t.rows * t.cols
+}
+
+// Toeplitz matrices behave as dense matrices
+func (t *toeplitzMatrix) Sparsity() ArraySparsity {
+	return DenseArray
+}
+
+// Get the sum of the elements
+func (t *toeplitzMatrix) Sum() float64 {
+	var sum float64
+	for k, v := range t.values {
+		sum += v * float64(t.diagLen(k))
+	}
+	return sum
+}
+
+// Get the transpose. A Hankel matrix keeps its values, while a Toeplitz matrix
+// reverses the order of its diagonals.
+func (t *toeplitzMatrix) T() Matrix {
+	if t.hankel {
+		return newToeplitz(t.values, t.cols, t.rows, true)
+	}
+	values := make([]float64, len(t.values))
+	for k, v := range t.values {
+		values[len(values)-1-k] = v
+	}
+	return newToeplitz(values, t.cols, t.rows, false)
+}
+
+// Get the matrix itself, since it can't be modified
+func (t *toeplitzMatrix) View() NDArray {
+	return t
+}
+
+// Visit all matrix elements, in row-major order
+func (t *toeplitzMatrix) Visit(f func(pos []int, value float64) bool) bool {
+	for row := 0; row < t.rows; row++ {
+		for col := 0; col < t.cols; col++ {
+			if !f([]int{row, col}, t.item(row, col)) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Visit just the nonzero elements, in row-major order
+func (t *toeplitzMatrix) VisitNonzero(f func(pos []int, value float64) bool) bool {
+	return t.Visit(func(pos []int, value float64) bool {
+		return value == 0 || f(pos, value)
+	})
+}
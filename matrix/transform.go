@@ -0,0 +1,166 @@
+package matrix
+
+import (
+	"fmt"
+	"math"
+)
+
+// Givens rotations and Householder reflections are the orthogonal transforms
+// used to build factorizations such as QR one element or one column at a
+// time. Each can be created as a matrix, but is usually applied in place
+// with RotateRows() or ReflectRows() and their column variants, which only
+// touch the affected rows or columns.
+
+// Get the cosine c and sine s of the Givens rotation which zeros b, so that
+// c*a - s*b = r and s*a + c*b = 0, where |r| is the length of (a, b). This
+// follows Golub and Van Loan's conventions, as do the functions which use it.
+func Givens(a, b float64) (c, s float64) {
+	switch {
+	case b == 0:
+		return 1, 0
+	case math.Abs(b) > math.Abs(a):
+		tau := -a / b
+		s = 1 / math.Sqrt(1+tau*tau)
+		return s * tau, s
+	default:
+		tau := -b / a
+		c = 1 / math.Sqrt(1+tau*tau)
+		return c, c * tau
+	}
+}
+
+// Create the n x n Givens rotation G(i, j), which is the identity except for
+// G[i, i] = G[j, j] = c, G[i, j] = s and G[j, i] = -s. The result is a sparse
+// coo matrix. Use RotateRows() or RotateCols() to apply it without a matrix
+// multiplication.
+func GivensRotation(n, i, j int, c, s float64) Matrix {
+	checkSwap("GivensRotation", i, j, n)
+	if i == j {
+		panic(fmt.Sprintf("GivensRotation: the rows %d and %d must differ", i, j))
+	}
+	result := SparseCoo(n, n)
+	for k := 0; k < n; k++ {
+		result.ItemSet(1, k, k)
+	}
+	result.ItemSet(c, i, i)
+	result.ItemSet(c, j, j)
+	result.ItemSet(s, i, j)
+	result.ItemSet(-s, j, i)
+	return result
+}
+
+// Replace m with G(i, j)' m in place, where G(i, j) is the Givens rotation
+// with cosine c and sine s. Only rows i and j change, so this takes time
+// proportional to the number of columns.
+func RotateRows(m Matrix, i, j int, c, s float64) {
+	checkSwap("RotateRows", i, j, m.Rows())
+	for k := 0; k < m.Cols(); k++ {
+		x, y := m.Item(i, k), m.Item(j, k)
+		m.ItemSet(c*x-s*y, i, k)
+		m.ItemSet(s*x+c*y, j, k)
+	}
+}
+
+// Replace m with m G(i, j) in place, where G(i, j) is the Givens rotation
+// with cosine c and sine s. Only columns i and j change, so this takes time
+// proportional to the number of rows.
+func RotateCols(m Matrix, i, j int, c, s float64) {
+	checkSwap("RotateCols", i, j, m.Cols())
+	for k := 0; k < m.Rows(); k++ {
+		x, y := m.Item(k, i), m.Item(k, j)
+		m.ItemSet(c*x-s*y, k, i)
+		m.ItemSet(s*x+c*y, k, j)
+	}
+}
+
+// Get the Householder vector v and scale beta of the reflection P = I - beta
+// v v' which maps x to a multiple of the first unit vector: Px = (|x|, 0,
+// ..., 0). The first element of v is 1. x isn't modified.
+func Householder(x []float64) (v []float64, beta float64) {
+	if len(x) == 0 {
+		panic("Householder: x can't be empty")
+	}
+	v = append([]float64(nil), x...)
+	v[0] = 1
+	var sigma float64
+	for _, xi := range x[1:] {
+		sigma += xi * xi
+	}
+	switch {
+	case sigma == 0 && x[0] >= 0:
+		return v, 0
+	case sigma == 0:
+		return v, 2
+	}
+	mu := math.Sqrt(x[0]*x[0] + sigma)
+	var v0 float64
+	if x[0] <= 0 {
+		v0 = x[0] - mu
+	} else {
+		v0 = -sigma / (x[0] + mu)
+	}
+	beta = 2 * v0 * v0 / (sigma + v0*v0)
+	for i := 1; i < len(v); i++ {
+		v[i] /= v0
+	}
+	return v, beta
+}
+
+// Create the dense reflection matrix I - beta v v'. Use ReflectRows() or
+// ReflectCols() to apply it without a matrix multiplication.
+func HouseholderMatrix(v []float64, beta float64) Matrix {
+	n := len(v)
+	result := Dense(n, n).M()
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			value := -beta * v[i] * v[j]
+			if i == j {
+				value++
+			}
+			result.ItemSet(value, i, j)
+		}
+	}
+	return result
+}
+
+// Apply the reflection I - beta v v' in place to the len(v) rows of m
+// starting at row, so that m = Pm for the matching block of P. This takes
+// time proportional to len(v) times the number of columns.
+func ReflectRows(m Matrix, row int, v []float64, beta float64) {
+	if row < 0 || row+len(v) > m.Rows() {
+		panic(ErrIndexOutOfRange{Op: "ReflectRows", Index: []int{row + len(v) - 1}, Shape: []int{m.Rows()}})
+	}
+	for k := 0; k < m.Cols(); k++ {
+		var dot float64
+		for i, vi := range v {
+			dot += vi * m.Item(row+i, k)
+		}
+		if dot == 0 {
+			continue
+		}
+		for i, vi := range v {
+			m.ItemSet(m.Item(row+i, k)-beta*vi*dot, row+i, k)
+		}
+	}
+}
+
+// Apply the reflection I - beta v v' in place to the len(v) columns of m
+// starting at col, so that m = mP for the matching block of P. This takes
+// time proportional to len(v) times the number of rows.
+func ReflectCols(m Matrix, col int, v []float64, beta float64) {
+	if col < 0 || col+len(v) > m.Cols() {
+		panic(ErrIndexOutOfRange{Op: "ReflectCols", Index: []int{col + len(v) - 1}, Shape: []int{m.Cols()}})
+	}
+	for k := 0; k < m.Rows(); k++ {
+		var dot float64
+		for j, vj := range v {
+			dot += vj * m.Item(k, col+j)
+		}
+		if dot == 0 {
+			continue
+		}
+		for j, vj := range v {
+			m.ItemSet(m.Item(k, col+j)-beta*vj*dot, k, col+j)
+		}
+	}
+}
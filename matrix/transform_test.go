@@ -0,0 +1,72 @@
+package matrix
+
+import (
+	. "github.com/smartystreets/goconvey/convey"
+	"math"
+	"testing"
+)
+
+func TestGivens(t *testing.T) {
+	Convey("Givens zeros the second element", t, func() {
+		for _, ab := range [][2]float64{{3, 4}, {4, -3}, {-1, 0}, {0, 2}, {1e-8, 5}} {
+			a, b := ab[0], ab[1]
+			c, s := Givens(a, b)
+			So(c*c+s*s, ShouldAlmostEqual, 1, 1e-12)
+			So(s*a+c*b, ShouldAlmostEqual, 0, 1e-12)
+			So(math.Abs(c*a-s*b), ShouldAlmostEqual, math.Hypot(a, b), 1e-12)
+		}
+	})
+
+	Convey("Rotating in place matches multiplying by the rotation", t, func() {
+		m := M(3, 2, 1, 2, 3, 4, 5, 6)
+		c, s := Givens(m.Item(0, 0), m.Item(2, 0))
+		g := GivensRotation(3, 0, 2, c, s)
+		want := g.T().MProd(m)
+		RotateRows(m, 0, 2, c, s)
+		So(ApproxEqual(m, want, 1e-12, 0), ShouldBeTrue)
+		So(m.Item(2, 0), ShouldAlmostEqual, 0, 1e-12)
+
+		n := M(2, 3, 1, 2, 3, 4, 5, 6)
+		want = n.MProd(GivensRotation(3, 1, 2, c, s))
+		RotateCols(n, 1, 2, c, s)
+		So(ApproxEqual(n, want, 1e-12, 0), ShouldBeTrue)
+
+		So(func() { GivensRotation(3, 1, 1, c, s) }, ShouldPanic)
+		So(func() { RotateRows(m, 0, 3, c, s) }, ShouldPanic)
+	})
+}
+
+func TestHouseholder(t *testing.T) {
+	Convey("Householder maps x onto the first axis", t, func() {
+		for _, x := range [][]float64{{3, 4, 0}, {-2, 1, 2}, {5}, {-5, 0}, {0, 0, 1}} {
+			v, beta := Householder(x)
+			So(v[0], ShouldEqual, 1)
+			p := HouseholderMatrix(v, beta)
+			So(ApproxEqual(p.MProd(p.T()), Eye(len(x)), 1e-12, 0), ShouldBeTrue)
+			px := p.MProd(M(len(x), 1, x...))
+			So(px.Item(0, 0), ShouldAlmostEqual, Norm(M(len(x), 1, x...), 2), 1e-12)
+			for i := 1; i < len(x); i++ {
+				So(px.Item(i, 0), ShouldAlmostEqual, 0, 1e-12)
+			}
+		}
+		So(func() { Householder(nil) }, ShouldPanic)
+	})
+
+	Convey("Reflecting in place matches multiplying by the reflection", t, func() {
+		m := M(3, 2, 1, 2, 3, 4, 5, 6)
+		v, beta := Householder([]float64{3, 5})
+		p := BlockDiag(Eye(1), HouseholderMatrix(v, beta))
+		want := p.MProd(m)
+		ReflectRows(m, 1, v, beta)
+		So(ApproxEqual(m, want, 1e-12, 0), ShouldBeTrue)
+		So(m.Item(2, 0), ShouldAlmostEqual, 0, 1e-12)
+
+		n := M(2, 3, 1, 2, 3, 4, 5, 6)
+		want = n.MProd(p)
+		ReflectCols(n, 1, v, beta)
+		So(ApproxEqual(n, want, 1e-12, 0), ShouldBeTrue)
+
+		So(func() { ReflectRows(m, 2, v, beta) }, ShouldPanic)
+		So(func() { ReflectCols(n, -1, v, beta) }, ShouldPanic)
+	})
+}
@@ -0,0 +1,144 @@
+package matrix
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Most functions in this package panic when given invalid arguments, such as
+// arrays whose shapes don't match. The Try functions below are variants which
+// return those failures as errors instead, for code such as servers which
+// must handle bad input gracefully.
+
+// Create an array from literal data, as A() does
+func TryA(shape []int, values ...float64) (result NDArray, err error) {
+	err = try(func() { result = A(shape, values...) })
+	return
+}
+
+// Create a matrix from literal data, as M() does
+func TryM(rows, cols int, values ...float64) (result Matrix, err error) {
+	err = try(func() { result = M(rows, cols, values...) })
+	return
+}
+
+// Create a sparse coo matrix with random values, as SparseRand() does
+func TrySparseRand(rows, cols int, density float64) (result Matrix, err error) {
+	err = try(func() { result = SparseRand(rows, cols, density) })
+	return
+}
+
+// Convert an array to a matrix, as array.M() does
+func TryMatrix(array NDArray) (result Matrix, err error) {
+	err = try(func() { result = array.M() })
+	return
+}
+
+// Get an array element, as array.Item() does
+func TryItem(array NDArray, index ...int) (result float64, err error) {
+	err = try(func() { result = array.Item(index...) })
+	return
+}
+
+// Set an array element, as array.ItemSet() does
+func TryItemSet(array NDArray, value float64, index ...int) error {
+	return try(func() { array.ItemSet(value, index...) })
+}
+
+// Set the values of a matrix row, as m.RowSet() does
+func TryRowSet(m Matrix, row int, values []float64) error {
+	return try(func() { m.RowSet(row, values) })
+}
+
+// Set the values of a matrix column, as m.ColSet() does
+func TryColSet(m Matrix, col int, values []float64) error {
+	return try(func() { m.ColSet(col, values) })
+}
+
+// Copy a block into a matrix, as m.SetSubmatrix() does
+func TrySetSubmatrix(m Matrix, row, col int, src Matrix) error {
+	return try(func() { m.SetSubmatrix(row, col, src) })
+}
+
+// Get the element-wise sum of arrays, as Add() does
+func TryAdd(array NDArray, others ...NDArray) (result NDArray, err error) {
+	err = try(func() { result = Add(array, others...) })
+	return
+}
+
+// Get the element-wise difference of arrays, as Sub() does
+func TrySub(array NDArray, others ...NDArray) (result NDArray, err error) {
+	err = try(func() { result = Sub(array, others...) })
+	return
+}
+
+// Get the element-wise product of arrays, as Prod() does
+func TryProd(array NDArray, others ...NDArray) (result NDArray, err error) {
+	err = try(func() { result = Prod(array, others...) })
+	return
+}
+
+// Get the element-wise quotient of arrays, as Div() does
+func TryDiv(array NDArray, others ...NDArray) (result NDArray, err error) {
+	err = try(func() { result = Div(array, others...) })
+	return
+}
+
+// Get the matrix product of matrices, as MProd() does
+func TryMProd(array Matrix, others ...Matrix) (result Matrix, err error) {
+	err = try(func() { result = MProd(array, others...) })
+	return
+}
+
+// Solve for x, where ax = b, as LDivide() does. Returns an error wrapping
+// ErrSingular, rather than a result filled with NaN, if the system can't be
+// solved.
+func TryLDivide(a, b Matrix) (result Matrix, err error) {
+	if panicErr := try(func() { result, err = ldivide(a, b) }); panicErr != nil {
+		return nil, panicErr
+	}
+	return result, err
+}
+
+// Concatenate arrays along an axis, as Concat() does
+func TryConcat(axis int, array NDArray, others ...NDArray) (result NDArray, err error) {
+	err = try(func() { result = Concat(axis, array, others...) })
+	return
+}
+
+// Get a rectangular slice of an array, as Slice() does
+func TrySlice(array NDArray, from []int, to []int) (result NDArray, err error) {
+	err = try(func() { result = Slice(array, from, to) })
+	return
+}
+
+// Convert a matrix to sparse coo format, as m.SparseCoo() does
+func TrySparseCoo(m Matrix) (result Matrix, err error) {
+	err = try(func() { result = m.SparseCoo() })
+	return
+}
+
+// Convert a matrix to sparse diag format, as m.SparseDiag() does. Fails if
+// any off-diagonal elements are nonzero.
+func TrySparseDiag(m Matrix) (result Matrix, err error) {
+	err = try(func() { result = m.SparseDiag() })
+	return
+}
+
+// Run f, returning any panic it raises as an error
+func try(f func()) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			switch r := r.(type) {
+			case error:
+				err = r
+			case string:
+				err = errors.New(r)
+			default:
+				err = fmt.Errorf("%v", r)
+			}
+		}
+	}()
+	f()
+	return nil
+}
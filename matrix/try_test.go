@@ -0,0 +1,77 @@
+package matrix
+
+import (
+	"errors"
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+func TestTry(t *testing.T) {
+	Convey("Given valid arguments, Try functions match their counterparts", t, func() {
+		m, err := TryM(2, 2, 1, 2, 3, 4)
+		So(err, ShouldBeNil)
+		So(m.Equal(M(2, 2, 1, 2, 3, 4)), ShouldBeTrue)
+
+		sum, err := TryAdd(m, m)
+		So(err, ShouldBeNil)
+		So(sum.Equal(m.ItemProd(2)), ShouldBeTrue)
+
+		prod, err := TryMProd(m, Eye(2))
+		So(err, ShouldBeNil)
+		So(prod.Equal(m), ShouldBeTrue)
+
+		So(TryItemSet(m, 7, 1, 1), ShouldBeNil)
+		v, err := TryItem(m, 1, 1)
+		So(err, ShouldBeNil)
+		So(v, ShouldEqual, 7)
+
+		x, err := TryLDivide(Diag(2, 4), M(2, 1, 2, 4))
+		So(err, ShouldBeNil)
+		So(x.Equal(LDivide(Diag(2, 4), M(2, 1, 2, 4))), ShouldBeTrue)
+		d, err := TrySparseDiag(M(2, 2, 1, 0, 0, 2))
+		So(err, ShouldBeNil)
+		So(d.Sparsity(), ShouldEqual, SparseDiagMatrix)
+	})
+
+	Convey("Given invalid arguments, Try functions return errors", t, func() {
+		m := M(2, 3,
+			1, 2, 3,
+			4, 5, 6)
+
+		_, err := TryM(2, 2, 1, 2, 3)
+		So(err, ShouldNotBeNil)
+		_, err = TryA([]int{3}, 1, 2)
+		So(err, ShouldNotBeNil)
+		_, err = TrySparseRand(2, 2, 1.5)
+		So(err, ShouldNotBeNil)
+		_, err = TryMatrix(Dense(2, 2, 2))
+		So(err, ShouldNotBeNil)
+
+		_, err = TryItem(Diag(1, 2), 2, 2)
+		So(err, ShouldNotBeNil)
+		So(TryItemSet(Diag(1, 2), 1, 0, 1), ShouldNotBeNil)
+		So(TryRowSet(m, 0, []float64{1}), ShouldNotBeNil)
+		So(TryColSet(m, 5, []float64{1, 2}), ShouldNotBeNil)
+
+		_, err = TryAdd(m, m.T())
+		So(err, ShouldNotBeNil)
+		_, err = TrySub(m, Eye(2))
+		So(err, ShouldNotBeNil)
+		_, err = TryProd(m, Eye(2))
+		So(err, ShouldNotBeNil)
+		_, err = TryDiv(m, Eye(2))
+		So(err, ShouldNotBeNil)
+		_, err = TryLDivide(m, Eye(3))
+		So(err, ShouldNotBeNil)
+		_, err = TryLDivide(M(2, 2, 1, 2, 2, 4), Eye(2))
+		So(errors.Is(err, ErrSingular), ShouldBeTrue)
+		_, err = TryMProd(m, m)
+		So(err, ShouldNotBeNil)
+		_, err = TryConcat(0, m, Eye(2))
+		So(err, ShouldNotBeNil)
+		_, err = TrySlice(m, []int{0}, []int{1})
+		So(err, ShouldNotBeNil)
+		_, err = TrySparseDiag(m)
+		So(err, ShouldNotBeNil)
+	})
+}
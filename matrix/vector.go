@@ -0,0 +1,360 @@
+package matrix
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// A one dimensional array of float64 values, stored densely or sparsely.
+// Vectors are indexed by a single position, so 1-D data doesn't need to be
+// modeled as an n x 1 matrix. Operations between vectors of different
+// storage formats are supported, and visit only the nonzero elements of
+// sparse vectors.
+type Vector interface {
+	// Add alpha * x to this vector, in place
+	AXPY(alpha float64, x Vector)
+
+	// Get the elements as a dense slice. For dense vectors, this is the
+	// backing storage, so changes to it change the vector.
+	Array() []float64
+
+	// Get a copy of the vector, with the same storage format
+	Copy() Vector
+
+	// Get the number of nonzero elements
+	CountNonzero() int
+
+	// Get the dot product with another vector of the same length
+	Dot(other Vector) float64
+
+	// Get an element
+	Item(i int) float64
+
+	// Set an element
+	ItemSet(value float64, i int)
+
+	// Get the length of the vector
+	Len() int
+
+	// Get the vector norm of the given ordinality: 1 (sum of absolute
+	// values), 2 (Euclidean length), or +Inf (largest absolute value)
+	Norm(ord float64) float64
+
+	// Multiply every element by alpha, in place
+	Scale(alpha float64)
+
+	// Get the storage format: DenseArray or SparseCooMatrix
+	Sparsity() ArraySparsity
+
+	// Get a 1 x Len() matrix holding the vector, with the same storage
+	// format. Changes to the matrix don't affect the vector.
+	RowMatrix() Matrix
+
+	// Get a Len() x 1 matrix holding the vector, with the same storage
+	// format. Changes to the matrix don't affect the vector.
+	ColMatrix() Matrix
+
+	// Visit the nonzero elements in increasing order of position, until f
+	// returns false. Returns false if f did.
+	VisitNonzero(f func(i int, value float64) bool) bool
+}
+
+// Create a dense vector holding the given values. The vector uses the slice
+// as its storage, so changes to one are visible in the other.
+func DenseVector(values ...float64) Vector {
+	if values == nil {
+		values = []float64{}
+	}
+	return &denseVector{values: values}
+}
+
+// Create a sparse vector of length n, in which all elements are zero. Only
+// nonzero elements are stored.
+func SparseVector(n int) Vector {
+	if n < 0 {
+		panic(ErrIndexOutOfRange{Op: "SparseVector", Index: []int{n}, Shape: []int{n}})
+	}
+	return &sparseVector{n: n, values: make(map[int]float64)}
+}
+
+// Get a copy of a row of m as a vector, which is sparse if m is
+func RowVector(m Matrix, row int) Vector {
+	return matrixVector(m.Row(row), m.Sparsity() != DenseArray)
+}
+
+// Get a copy of a column of m as a vector, which is sparse if m is
+func ColVector(m Matrix, col int) Vector {
+	return matrixVector(m.Col(col), m.Sparsity() != DenseArray)
+}
+
+// Create a vector from the values of a matrix row or column
+func matrixVector(values []float64, sparse bool) Vector {
+	if !sparse {
+		return DenseVector(values...)
+	}
+	v := SparseVector(len(values))
+	for i, value := range values {
+		v.ItemSet(value, i)
+	}
+	return v
+}
+
+// A vector which stores all its elements
+type denseVector struct {
+	values []float64
+}
+
+// Add alpha * x to this vector, in place
+func (v *denseVector) AXPY(alpha float64, x Vector) {
+	checkVectorLen("AXPY", v, x)
+	x.VisitNonzero(func(i int, value float64) bool {
+		v.values[i] += alpha * value
+		return true
+	})
+}
+
+// Get the backing storage of the vector
+func (v *denseVector) Array() []float64 {
+	return v.values
+}
+
+// Get a copy of the vector
+func (v *denseVector) Copy() Vector {
+	return &denseVector{values: append([]float64{}, v.values...)}
+}
+
+// Get the number of nonzero elements
+func (v *denseVector) CountNonzero() int {
+	count := 0
+	for _, value := range v.values {
+		if value != 0 {
+			count++
+		}
+	}
+	return count
+}
+
+// Get the dot product with another vector
+func (v *denseVector) Dot(other Vector) float64 {
+	checkVectorLen("Dot", v, other)
+	if o, ok := other.(*denseVector); ok {
+		return dotVec(v.values, o.values)
+	}
+	return other.Dot(v)
+}
+
+// Get an element
+func (v *denseVector) Item(i int) float64 {
+	checkVectorIndex("Item", i, len(v.values))
+	return v.values[i]
+}
+
+// Set an element
+func (v *denseVector) ItemSet(value float64, i int) {
+	checkVectorIndex("ItemSet", i, len(v.values))
+	v.values[i] = value
+}
+
+// Get the length of the vector
+func (v *denseVector) Len() int {
+	return len(v.values)
+}
+
+// Get the vector norm of the given ordinality
+func (v *denseVector) Norm(ord float64) float64 {
+	return vectorNorm(v, ord)
+}
+
+// Multiply every element by alpha, in place
+func (v *denseVector) Scale(alpha float64) {
+	for i := range v.values {
+		v.values[i] *= alpha
+	}
+}
+
+// Dense vectors store all their elements
+func (v *denseVector) Sparsity() ArraySparsity {
+	return DenseArray
+}
+
+// Get a 1 x Len() dense matrix holding the vector
+func (v *denseVector) RowMatrix() Matrix {
+	return M(1, len(v.values), append([]float64{}, v.values...)...)
+}
+
+// Get a Len() x 1 dense matrix holding the vector
+func (v *denseVector) ColMatrix() Matrix {
+	return M(len(v.values), 1, append([]float64{}, v.values...)...)
+}
+
+// Visit the nonzero elements in order
+func (v *denseVector) VisitNonzero(f func(i int, value float64) bool) bool {
+	for i, value := range v.values {
+		if value != 0 && !f(i, value) {
+			return false
+		}
+	}
+	return true
+}
+
+// A vector which stores only its nonzero elements, keyed by position
+type sparseVector struct {
+	n      int
+	values map[int]float64
+}
+
+// Add alpha * x to this vector, in place
+func (v *sparseVector) AXPY(alpha float64, x Vector) {
+	checkVectorLen("AXPY", v, x)
+	x.VisitNonzero(func(i int, value float64) bool {
+		v.ItemSet(v.values[i]+alpha*value, i)
+		return true
+	})
+}
+
+// Get a dense copy of the elements
+func (v *sparseVector) Array() []float64 {
+	result := make([]float64, v.n)
+	for i, value := range v.values {
+		result[i] = value
+	}
+	return result
+}
+
+// Get a copy of the vector
+func (v *sparseVector) Copy() Vector {
+	c := &sparseVector{n: v.n, values: make(map[int]float64, len(v.values))}
+	for i, value := range v.values {
+		c.values[i] = value
+	}
+	return c
+}
+
+// Get the number of nonzero elements
+func (v *sparseVector) CountNonzero() int {
+	return len(v.values)
+}
+
+// Get the dot product with another vector
+func (v *sparseVector) Dot(other Vector) float64 {
+	checkVectorLen("Dot", v, other)
+	if o, ok := other.(*sparseVector); ok && len(o.values) < len(v.values) {
+		return o.Dot(v)
+	}
+	var sum float64
+	for i, value := range v.values {
+		sum += value * other.Item(i)
+	}
+	return sum
+}
+
+// Get an element
+func (v *sparseVector) Item(i int) float64 {
+	checkVectorIndex("Item", i, v.n)
+	return v.values[i]
+}
+
+// Set an element. Setting an element to zero removes it from storage.
+func (v *sparseVector) ItemSet(value float64, i int) {
+	checkVectorIndex("ItemSet", i, v.n)
+	if value == 0 {
+		delete(v.values, i)
+	} else {
+		v.values[i] = value
+	}
+}
+
+// Get the length of the vector
+func (v *sparseVector) Len() int {
+	return v.n
+}
+
+// Get the vector norm of the given ordinality
+func (v *sparseVector) Norm(ord float64) float64 {
+	return vectorNorm(v, ord)
+}
+
+// Multiply every element by alpha, in place
+func (v *sparseVector) Scale(alpha float64) {
+	for i, value := range v.values {
+		v.ItemSet(alpha*value, i)
+	}
+}
+
+// Sparse vectors are stored as sparse coo matrices are
+func (v *sparseVector) Sparsity() ArraySparsity {
+	return SparseCooMatrix
+}
+
+// Get a 1 x Len() sparse coo matrix holding the vector
+func (v *sparseVector) RowMatrix() Matrix {
+	result := SparseCoo(1, v.n)
+	for i, value := range v.values {
+		result.ItemSet(value, 0, i)
+	}
+	return result
+}
+
+// Get a Len() x 1 sparse coo matrix holding the vector
+func (v *sparseVector) ColMatrix() Matrix {
+	result := SparseCoo(v.n, 1)
+	for i, value := range v.values {
+		result.ItemSet(value, i, 0)
+	}
+	return result
+}
+
+// Visit the nonzero elements in order
+func (v *sparseVector) VisitNonzero(f func(i int, value float64) bool) bool {
+	indices := make([]int, 0, len(v.values))
+	for i := range v.values {
+		indices = append(indices, i)
+	}
+	sort.Ints(indices)
+	for _, i := range indices {
+		if !f(i, v.values[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// Get the norm of a vector, visiting only its nonzero elements
+func vectorNorm(v Vector, ord float64) float64 {
+	var norm float64
+	switch {
+	case ord == 1:
+		v.VisitNonzero(func(i int, value float64) bool {
+			norm += math.Abs(value)
+			return true
+		})
+	case ord == 2:
+		v.VisitNonzero(func(i int, value float64) bool {
+			norm = math.Hypot(norm, value)
+			return true
+		})
+	case math.IsInf(ord, 1):
+		v.VisitNonzero(func(i int, value float64) bool {
+			norm = math.Max(norm, math.Abs(value))
+			return true
+		})
+	default:
+		panic(fmt.Sprintf("Can't calculate vector norm of invalid ordinality %v", ord))
+	}
+	return norm
+}
+
+// Panic unless two vectors have the same length
+func checkVectorLen(op string, v, other Vector) {
+	if other.Len() != v.Len() {
+		panic(ErrShapeMismatch{Op: op, Got: []int{other.Len()}, Want: []int{v.Len()}})
+	}
+}
+
+// Panic unless i is a valid position in a vector of length n
+func checkVectorIndex(op string, i, n int) {
+	if i < 0 || i >= n {
+		panic(ErrIndexOutOfRange{Op: op, Index: []int{i}, Shape: []int{n}})
+	}
+}
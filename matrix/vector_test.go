@@ -0,0 +1,75 @@
+package matrix
+
+import (
+	. "github.com/smartystreets/goconvey/convey"
+	"math"
+	"testing"
+)
+
+func TestVector(t *testing.T) {
+	Convey("Given dense and sparse vectors", t, func() {
+		d := DenseVector(1, 0, -2, 3)
+		s := SparseVector(4)
+		s.ItemSet(2, 0)
+		s.ItemSet(5, 2)
+
+		Convey("Elements can be read and written", func() {
+			So(d.Len(), ShouldEqual, 4)
+			So(s.Len(), ShouldEqual, 4)
+			So(d.Item(2), ShouldEqual, -2)
+			So(s.Item(1), ShouldEqual, 0)
+			So(s.CountNonzero(), ShouldEqual, 2)
+			s.ItemSet(0, 2)
+			So(s.CountNonzero(), ShouldEqual, 1)
+			So(func() { d.Item(4) }, ShouldPanic)
+			So(func() { s.ItemSet(1, -1) }, ShouldPanic)
+		})
+
+		Convey("Dot works across storage formats", func() {
+			So(d.Dot(d), ShouldEqual, 14)
+			So(d.Dot(s), ShouldEqual, -8)
+			So(s.Dot(d), ShouldEqual, -8)
+			So(s.Dot(s), ShouldEqual, 29)
+			So(func() { d.Dot(DenseVector(1)) }, ShouldPanic)
+		})
+
+		Convey("AXPY adds a multiple in place", func() {
+			d.AXPY(2, s)
+			So(d.Array(), ShouldResemble, []float64{5, 0, 8, 3})
+			s.AXPY(-1, DenseVector(2, 0, 0, 1))
+			So(s.Array(), ShouldResemble, []float64{0, 0, 5, -1})
+			So(s.CountNonzero(), ShouldEqual, 2)
+		})
+
+		Convey("Norms visit the nonzero elements", func() {
+			So(d.Norm(1), ShouldEqual, 6)
+			So(d.Norm(2), ShouldAlmostEqual, math.Sqrt(14), 1e-12)
+			So(s.Norm(math.Inf(1)), ShouldEqual, 5)
+			So(func() { d.Norm(3) }, ShouldPanic)
+		})
+
+		Convey("Vectors convert to row and column matrices", func() {
+			So(d.RowMatrix().Equal(M(1, 4, 1, 0, -2, 3)), ShouldBeTrue)
+			So(d.ColMatrix().Shape(), ShouldResemble, []int{4, 1})
+			So(s.ColMatrix().Sparsity(), ShouldEqual, SparseCooMatrix)
+			So(s.RowMatrix().Equal(M(1, 4, 2, 0, 5, 0)), ShouldBeTrue)
+			m := M(2, 2, 1, 2, 3, 4)
+			So(RowVector(m, 1).Array(), ShouldResemble, []float64{3, 4})
+			So(ColVector(m.SparseCoo(), 0).Sparsity(), ShouldEqual, SparseCooMatrix)
+			So(ColVector(m.SparseCoo(), 0).Array(), ShouldResemble, []float64{1, 3})
+		})
+
+		Convey("Copies are independent", func() {
+			c := s.Copy()
+			c.Scale(2)
+			So(c.Item(2), ShouldEqual, 10)
+			So(s.Item(2), ShouldEqual, 5)
+			var visited []int
+			c.VisitNonzero(func(i int, value float64) bool {
+				visited = append(visited, i)
+				return true
+			})
+			So(visited, ShouldResemble, []int{0, 2})
+		})
+	})
+}
@@ -0,0 +1,53 @@
+package matrix
+
+import (
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+func TestCloneAndView(t *testing.T) {
+	Convey("Given matrices of each storage format", t, func() {
+		values := M(3, 2,
+			1, 0,
+			0, 2,
+			0, 0)
+		for _, m := range []Matrix{values.Copy().M(), values.SparseCoo(), values.SparseDiag()} {
+			Convey("Clone makes an independent copy of "+m.Sparsity().String(), func() {
+				c := m.Clone().M()
+				So(c.Sparsity(), ShouldEqual, m.Sparsity())
+				c.ItemSet(5, 0, 0)
+				So(m.Item(0, 0), ShouldEqual, 1)
+				m.ItemSet(6, 1, 1)
+				So(c.Item(1, 1), ShouldEqual, 2)
+			})
+
+			Convey("View shares storage for "+m.Sparsity().String(), func() {
+				v := m.View().M()
+				So(v.Sparsity(), ShouldEqual, m.Sparsity())
+				v.ItemSet(5, 0, 0)
+				So(m.Item(0, 0), ShouldEqual, 5)
+				m.ItemSet(6, 1, 1)
+				So(v.Item(1, 1), ShouldEqual, 6)
+			})
+
+			Convey("T shares storage for "+m.Sparsity().String(), func() {
+				tr := m.T()
+				tr.ItemSet(5, 1, 1)
+				So(m.Item(1, 1), ShouldEqual, 5)
+			})
+
+			Convey("Row and Col are copies with the right lengths for "+m.Sparsity().String(), func() {
+				So(m.Row(1), ShouldResemble, []float64{0, 2})
+				So(m.Row(2), ShouldResemble, []float64{0, 0})
+				So(m.Col(1), ShouldResemble, []float64{0, 2, 0})
+				So(m.T().Row(1), ShouldResemble, []float64{0, 2, 0})
+				So(m.T().Col(2), ShouldResemble, []float64{0, 0})
+				m.Row(1)[1] = 10
+				m.Col(1)[1] = 10
+				m.T().Row(1)[1] = 10
+				m.T().Col(1)[1] = 10
+				So(m.Item(1, 1), ShouldEqual, 2)
+			})
+		}
+	})
+}
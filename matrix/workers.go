@@ -0,0 +1,74 @@
+package matrix
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// The number of goroutines used by parallel operations, or zero for the
+// default
+var numWorkers atomic.Int64
+
+// The number of rows a worker takes at a time
+const workerBlock = 32
+
+// Set the number of goroutines which parallel operations, such as Dist() and
+// DistTo(), split their work between. With one worker they run on the
+// calling goroutine. n <= 0 restores the default, which is
+// runtime.GOMAXPROCS(0).
+func SetNumWorkers(n int) {
+	numWorkers.Store(int64(max(n, 0)))
+}
+
+// Get the number of goroutines parallel operations use
+func NumWorkers() int {
+	if n := numWorkers.Load(); n > 0 {
+		return int(n)
+	}
+	return runtime.GOMAXPROCS(0)
+}
+
+// Call f(lo, hi) for blocks of consecutive rows covering 0 to n-1. The blocks
+// are handed out in order to up to NumWorkers() goroutines, each taking the
+// next block when it finishes one, so blocks of uneven cost are balanced. f
+// must be safe to call concurrently. If f panics, the panic is raised again
+// on the calling goroutine once the workers stop.
+func parallelRows(n int, f func(lo, hi int)) {
+	workers := min(NumWorkers(), (n+workerBlock-1)/workerBlock)
+	if workers <= 1 {
+		if n > 0 {
+			f(0, n)
+		}
+		return
+	}
+
+	var (
+		next    atomic.Int64
+		wg      sync.WaitGroup
+		failed  atomic.Bool
+		failure interface{}
+	)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() {
+				if r := recover(); r != nil && failed.CompareAndSwap(false, true) {
+					failure = r
+				}
+			}()
+			for !failed.Load() {
+				lo := int(next.Add(workerBlock)) - workerBlock
+				if lo >= n {
+					return
+				}
+				f(lo, min(lo+workerBlock, n))
+			}
+		}()
+	}
+	wg.Wait()
+	if failed.Load() {
+		panic(failure)
+	}
+}
@@ -0,0 +1,52 @@
+package matrix
+
+import (
+	. "github.com/smartystreets/goconvey/convey"
+	"runtime"
+	"testing"
+)
+
+func TestNumWorkers(t *testing.T) {
+	Convey("SetNumWorkers changes the number of workers", t, func() {
+		defer SetNumWorkers(0)
+		So(NumWorkers(), ShouldEqual, runtime.GOMAXPROCS(0))
+		SetNumWorkers(3)
+		So(NumWorkers(), ShouldEqual, 3)
+		SetNumWorkers(-1)
+		So(NumWorkers(), ShouldEqual, runtime.GOMAXPROCS(0))
+	})
+
+	Convey("Given a matrix with many blocks of rows", t, func() {
+		defer SetNumWorkers(0)
+		m := Rand(300, 5).M()
+		refs := Rand(40, 5).M()
+		SetNumWorkers(1)
+		serial := m.Dist(ManhattanDist)
+		serialTo := m.DistTo(refs, EuclideanDist)
+
+		Convey("Parallel distances match the serial ones", func() {
+			SetNumWorkers(4)
+			So(m.Dist(ManhattanDist).Equal(serial), ShouldBeTrue)
+			So(m.DistTo(refs, EuclideanDist).Equal(serialTo), ShouldBeTrue)
+		})
+
+		Convey("Every row is visited once", func() {
+			SetNumWorkers(4)
+			counts := make([]int, 1000)
+			parallelRows(len(counts), func(lo, hi int) {
+				for i := lo; i < hi; i++ {
+					counts[i]++
+				}
+			})
+			for _, c := range counts {
+				So(c, ShouldEqual, 1)
+			}
+		})
+
+		Convey("Panics in a worker reach the caller", func() {
+			SetNumWorkers(4)
+			bad := RegisterDist(func(a, b []float64) float64 { panic("bad distance") })
+			So(func() { m.Dist(bad) }, ShouldPanicWith, "bad distance")
+		})
+	})
+}
@@ -0,0 +1,99 @@
+package matserve
+
+import (
+	"context"
+	"github.com/jesand/numgo/matrix"
+	. "github.com/smartystreets/goconvey/convey"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"net"
+	"testing"
+)
+
+func TestMatserve(t *testing.T) {
+	Convey("Given a server and a client", t, func() {
+		listener := bufconn.Listen(1 << 20)
+		server := NewServer(Server{})
+		go server.Serve(listener)
+		defer server.Stop()
+
+		conn, err := grpc.NewClient("passthrough:///bufnet",
+			grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+				return listener.DialContext(ctx)
+			}),
+			grpc.WithTransportCredentials(insecure.NewCredentials()))
+		So(err, ShouldBeNil)
+		defer conn.Close()
+		client := NewClient(conn)
+		ctx := context.Background()
+
+		a := matrix.M(2, 2,
+			2, 1,
+			1, 3)
+		b := matrix.M(2, 1, 3, 5)
+
+		Convey("MatMul multiplies matrices", func() {
+			result, err := client.MatMul(ctx, a, b, matrix.M(1, 2, 1, 2))
+			So(err, ShouldBeNil)
+			So(result.Equal(a.MProd(b, matrix.M(1, 2, 1, 2))), ShouldBeTrue)
+		})
+
+		Convey("Solve solves linear systems", func() {
+			result, err := client.Solve(ctx, a, b)
+			So(err, ShouldBeNil)
+			So(result.Shape(), ShouldResemble, []int{2, 1})
+			So(result.Item(0, 0), ShouldAlmostEqual, 0.8)
+			So(result.Item(1, 0), ShouldAlmostEqual, 1.4)
+		})
+
+		Convey("Dist finds distances between rows", func() {
+			points := matrix.M(3, 3,
+				0, 1, 3,
+				3, 4, 2,
+				1, 2, 4)
+			result, err := client.Dist(ctx, points, matrix.EuclideanDist)
+			So(err, ShouldBeNil)
+			So(result.Equal(points.Dist(matrix.EuclideanDist)), ShouldBeTrue)
+
+			result, err = client.Dist(ctx, points, matrix.CorrelationDist)
+			So(err, ShouldBeNil)
+			So(result.Equal(points.Dist(matrix.CorrelationDist)), ShouldBeTrue)
+
+			result, err = client.Dist(ctx, points, matrix.ManhattanDist)
+			So(err, ShouldBeNil)
+			So(result.Equal(points.Dist(matrix.ManhattanDist)), ShouldBeTrue)
+
+			_, err = client.Dist(ctx, points, matrix.MinkowskiDist(3))
+			So(status.Code(err), ShouldEqual, codes.InvalidArgument)
+		})
+
+		Convey("Invalid requests fail", func() {
+			_, err := client.MatMul(ctx, a, matrix.M(1, 2, 1, 2))
+			So(status.Code(err), ShouldEqual, codes.InvalidArgument)
+			_, err = client.MatMul(ctx, a)
+			So(status.Code(err), ShouldEqual, codes.InvalidArgument)
+			_, err = client.Solve(ctx, a, nil)
+			So(status.Code(err), ShouldEqual, codes.InvalidArgument)
+		})
+	})
+
+	Convey("Messages round trip through the codec", t, func() {
+		req := &DistRequest{
+			Points: matrix.M(2, 3, 1, 2, 3, 4, 5, 6).T(),
+			Type:   matrix.CorrelationDist,
+		}
+		data, err := Codec{}.Marshal(req)
+		So(err, ShouldBeNil)
+		var result DistRequest
+		So(Codec{}.Unmarshal(data, &result), ShouldBeNil)
+		So(result.Type, ShouldEqual, matrix.CorrelationDist)
+		So(result.Points.Equal(req.Points), ShouldBeTrue)
+
+		So(Codec{}.Unmarshal([]byte{0x0a, 0x02, 0x08, 0x02}, &result), ShouldNotBeNil)
+		_, err = Codec{}.Marshal("not a message")
+		So(err, ShouldNotBeNil)
+	})
+}
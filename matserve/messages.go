@@ -0,0 +1,232 @@
+// The matserve package exposes matrix operations as a gRPC service, so thin
+// clients can offload heavy operations to a server. The service schema is
+// defined in matserve.proto; this package encodes its messages directly, so
+// no generated code is needed.
+package matserve
+
+import (
+	"fmt"
+	"github.com/jesand/numgo/matrix"
+	"google.golang.org/protobuf/encoding/protowire"
+	"math"
+)
+
+// A request to multiply two or more matrices, in order
+type MatMulRequest struct {
+	Matrices []matrix.Matrix
+}
+
+// A request to solve for x, where ax = b
+type SolveRequest struct {
+	A, B matrix.Matrix
+}
+
+// A request for the pairwise distances between the rows of a matrix
+type DistRequest struct {
+	Points matrix.Matrix
+	Type   matrix.DistType
+}
+
+// The result of an operation
+type MatrixReply struct {
+	Result matrix.Matrix
+}
+
+// A message which can be encoded in protobuf wire format
+type message interface {
+	marshal() []byte
+	unmarshal(data []byte) error
+}
+
+// Encodes the service's messages in protobuf wire format, following the
+// schema in matserve.proto. It implements grpc's encoding.Codec, and is named
+// "proto" so clients generated from the schema interoperate with it.
+type Codec struct{}
+
+// Encode a message
+func (Codec) Marshal(v interface{}) ([]byte, error) {
+	msg, ok := v.(message)
+	if !ok {
+		return nil, fmt.Errorf("matserve can't marshal a %T", v)
+	}
+	return msg.marshal(), nil
+}
+
+// Decode a message
+func (Codec) Unmarshal(data []byte, v interface{}) error {
+	msg, ok := v.(message)
+	if !ok {
+		return fmt.Errorf("matserve can't unmarshal a %T", v)
+	}
+	return msg.unmarshal(data)
+}
+
+// Get the name of the codec
+func (Codec) Name() string {
+	return "proto"
+}
+
+func (r *MatMulRequest) marshal() []byte {
+	var data []byte
+	for _, m := range r.Matrices {
+		data = appendMatrix(data, 1, m)
+	}
+	return data
+}
+
+func (r *MatMulRequest) unmarshal(data []byte) error {
+	r.Matrices = nil
+	return visitFields(data, func(num protowire.Number, typ protowire.Type, field []byte) error {
+		if num != 1 {
+			return nil
+		}
+		m, err := parseMatrix(typ, field)
+		r.Matrices = append(r.Matrices, m)
+		return err
+	})
+}
+
+func (r *SolveRequest) marshal() []byte {
+	return appendMatrix(appendMatrix(nil, 1, r.A), 2, r.B)
+}
+
+func (r *SolveRequest) unmarshal(data []byte) error {
+	r.A, r.B = nil, nil
+	return visitFields(data, func(num protowire.Number, typ protowire.Type, field []byte) (err error) {
+		switch num {
+		case 1:
+			r.A, err = parseMatrix(typ, field)
+		case 2:
+			r.B, err = parseMatrix(typ, field)
+		}
+		return err
+	})
+}
+
+func (r *DistRequest) marshal() []byte {
+	data := appendMatrix(nil, 1, r.Points)
+	if r.Type != 0 {
+		data = protowire.AppendTag(data, 2, protowire.VarintType)
+		data = protowire.AppendVarint(data, uint64(r.Type))
+	}
+	return data
+}
+
+func (r *DistRequest) unmarshal(data []byte) error {
+	r.Points, r.Type = nil, 0
+	return visitFields(data, func(num protowire.Number, typ protowire.Type, field []byte) (err error) {
+		switch num {
+		case 1:
+			r.Points, err = parseMatrix(typ, field)
+		case 2:
+			v, n := protowire.ConsumeVarint(field)
+			if typ != protowire.VarintType || n < 0 {
+				return fmt.Errorf("matserve: invalid DistRequest.type")
+			}
+			r.Type = matrix.DistType(v)
+		}
+		return err
+	})
+}
+
+func (r *MatrixReply) marshal() []byte {
+	return appendMatrix(nil, 1, r.Result)
+}
+
+func (r *MatrixReply) unmarshal(data []byte) error {
+	r.Result = nil
+	return visitFields(data, func(num protowire.Number, typ protowire.Type, field []byte) (err error) {
+		if num == 1 {
+			r.Result, err = parseMatrix(typ, field)
+		}
+		return err
+	})
+}
+
+// Append a Matrix message as the given field. Nil matrices are omitted.
+func appendMatrix(data []byte, num protowire.Number, m matrix.Matrix) []byte {
+	if m == nil {
+		return data
+	}
+	var msg, values []byte
+	msg = protowire.AppendTag(msg, 1, protowire.VarintType)
+	msg = protowire.AppendVarint(msg, uint64(m.Rows()))
+	msg = protowire.AppendTag(msg, 2, protowire.VarintType)
+	msg = protowire.AppendVarint(msg, uint64(m.Cols()))
+	for _, v := range m.Array() {
+		values = protowire.AppendFixed64(values, math.Float64bits(v))
+	}
+	msg = protowire.AppendTag(msg, 3, protowire.BytesType)
+	msg = protowire.AppendBytes(msg, values)
+	data = protowire.AppendTag(data, num, protowire.BytesType)
+	return protowire.AppendBytes(data, msg)
+}
+
+// Decode a Matrix message to a dense matrix
+func parseMatrix(typ protowire.Type, data []byte) (matrix.Matrix, error) {
+	if typ != protowire.BytesType {
+		return nil, fmt.Errorf("matserve: invalid Matrix field")
+	}
+	var (
+		rows, cols uint64
+		values     []float64
+	)
+	err := visitFields(data, func(num protowire.Number, typ protowire.Type, field []byte) error {
+		switch num {
+		case 1, 2:
+			v, n := protowire.ConsumeVarint(field)
+			if typ != protowire.VarintType || n < 0 {
+				return fmt.Errorf("matserve: invalid Matrix dimension")
+			}
+			if num == 1 {
+				rows = v
+			} else {
+				cols = v
+			}
+		case 3:
+			if typ != protowire.BytesType && typ != protowire.Fixed64Type {
+				return fmt.Errorf("matserve: invalid Matrix values")
+			}
+			for len(field) > 0 {
+				v, n := protowire.ConsumeFixed64(field)
+				if n < 0 {
+					return protowire.ParseError(n)
+				}
+				values = append(values, math.Float64frombits(v))
+				field = field[n:]
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	} else if rows == 0 || cols == 0 || rows*cols/cols != rows || uint64(len(values)) != rows*cols {
+		return nil, fmt.Errorf("matserve: %dx%d matrix has %d values", rows, cols, len(values))
+	}
+	return matrix.M(int(rows), int(cols), values...), nil
+}
+
+// Call f for each field of a message. The field bytes are the payload of
+// length-delimited fields and the raw encoding of others.
+func visitFields(data []byte, f func(num protowire.Number, typ protowire.Type, field []byte) error) error {
+	for len(data) > 0 {
+		num, typ, n := protowire.ConsumeTag(data)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		data = data[n:]
+		n = protowire.ConsumeFieldValue(num, typ, data)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		field := data[:n]
+		if typ == protowire.BytesType {
+			field, _ = protowire.ConsumeBytes(field)
+		}
+		if err := f(num, typ, field); err != nil {
+			return err
+		}
+		data = data[n:]
+	}
+	return nil
+}
@@ -0,0 +1,167 @@
+package matserve
+
+import (
+	"context"
+	"fmt"
+	"github.com/jesand/numgo/matrix"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// The full name of the gRPC service
+const ServiceName = "matserve.MatrixService"
+
+// The operations of the gRPC service
+type MatrixService interface {
+	MatMul(ctx context.Context, req *MatMulRequest) (*MatrixReply, error)
+	Solve(ctx context.Context, req *SolveRequest) (*MatrixReply, error)
+	Dist(ctx context.Context, req *DistRequest) (*MatrixReply, error)
+}
+
+// Implements MatrixService by running the operations locally. Invalid
+// requests, such as matrices whose shapes don't align, fail with
+// codes.InvalidArgument.
+type Server struct{}
+
+// Multiply two or more matrices, in order
+func (Server) MatMul(ctx context.Context, req *MatMulRequest) (*MatrixReply, error) {
+	if len(req.Matrices) < 2 {
+		return nil, status.Errorf(codes.InvalidArgument, "MatMul needs at least 2 matrices, got %d", len(req.Matrices))
+	}
+	return run(func() matrix.Matrix {
+		return req.Matrices[0].MProd(req.Matrices[1:]...)
+	})
+}
+
+// Solve for x, where ax = b. As with matrix.LDivide, a singular system gives
+// a result filled with NaN.
+func (Server) Solve(ctx context.Context, req *SolveRequest) (*MatrixReply, error) {
+	if req.A == nil || req.B == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "Solve needs matrices a and b")
+	}
+	return run(func() matrix.Matrix {
+		return req.A.LDivide(req.B)
+	})
+}
+
+// Get the pairwise distances between the rows of a matrix
+func (Server) Dist(ctx context.Context, req *DistRequest) (*MatrixReply, error) {
+	if req.Points == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "Dist needs a points matrix")
+	}
+	if req.Type < matrix.EuclideanDist || req.Type > matrix.SqEuclideanDist {
+		// Custom distances are registered separately by each process
+		return nil, status.Errorf(codes.InvalidArgument, "Dist can't use distance type %d", req.Type)
+	}
+	return run(func() matrix.Matrix {
+		return req.Points.Dist(req.Type)
+	})
+}
+
+// Run an operation, turning the panics matrix functions raise for invalid
+// arguments into errors
+func run(op func() matrix.Matrix) (reply *MatrixReply, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			reply, err = nil, status.Errorf(codes.InvalidArgument, "%v", r)
+		}
+	}()
+	return &MatrixReply{Result: op()}, nil
+}
+
+// Create a gRPC server which serves a MatrixService. The server decodes all
+// requests with Codec, so it can't serve services using generated protobuf
+// messages; use a separate server for those.
+func NewServer(srv MatrixService, opts ...grpc.ServerOption) *grpc.Server {
+	s := grpc.NewServer(append(opts, grpc.ForceServerCodec(Codec{}))...)
+	s.RegisterService(&serviceDesc, srv)
+	return s
+}
+
+// The gRPC description of the service
+var serviceDesc = grpc.ServiceDesc{
+	ServiceName: ServiceName,
+	HandlerType: (*MatrixService)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "MatMul",
+			Handler: handler("MatMul", func() message { return &MatMulRequest{} },
+				func(srv MatrixService, ctx context.Context, req interface{}) (*MatrixReply, error) {
+					return srv.MatMul(ctx, req.(*MatMulRequest))
+				}),
+		},
+		{
+			MethodName: "Solve",
+			Handler: handler("Solve", func() message { return &SolveRequest{} },
+				func(srv MatrixService, ctx context.Context, req interface{}) (*MatrixReply, error) {
+					return srv.Solve(ctx, req.(*SolveRequest))
+				}),
+		},
+		{
+			MethodName: "Dist",
+			Handler: handler("Dist", func() message { return &DistRequest{} },
+				func(srv MatrixService, ctx context.Context, req interface{}) (*MatrixReply, error) {
+					return srv.Dist(ctx, req.(*DistRequest))
+				}),
+		},
+	},
+	Metadata: "matserve.proto",
+}
+
+// Create the gRPC handler for a unary method
+func handler(method string, newReq func() message,
+	call func(srv MatrixService, ctx context.Context, req interface{}) (*MatrixReply, error)) grpc.MethodHandler {
+
+	return func(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+		req := newReq()
+		if err := dec(req); err != nil {
+			return nil, err
+		}
+		if interceptor == nil {
+			return call(srv.(MatrixService), ctx, req)
+		}
+		info := &grpc.UnaryServerInfo{
+			Server:     srv,
+			FullMethod: fmt.Sprintf("/%s/%s", ServiceName, method),
+		}
+		return interceptor(ctx, req, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+			return call(srv.(MatrixService), ctx, req)
+		})
+	}
+}
+
+// A client for a MatrixService
+type Client struct {
+	conn grpc.ClientConnInterface
+}
+
+// Create a client which calls the service over conn
+func NewClient(conn grpc.ClientConnInterface) *Client {
+	return &Client{conn: conn}
+}
+
+// Multiply two or more matrices, in order
+func (c *Client) MatMul(ctx context.Context, matrices ...matrix.Matrix) (matrix.Matrix, error) {
+	return c.invoke(ctx, "MatMul", &MatMulRequest{Matrices: matrices})
+}
+
+// Solve for x, where ax = b
+func (c *Client) Solve(ctx context.Context, a, b matrix.Matrix) (matrix.Matrix, error) {
+	return c.invoke(ctx, "Solve", &SolveRequest{A: a, B: b})
+}
+
+// Get the pairwise distances between the rows of a matrix
+func (c *Client) Dist(ctx context.Context, points matrix.Matrix, t matrix.DistType) (matrix.Matrix, error) {
+	return c.invoke(ctx, "Dist", &DistRequest{Points: points, Type: t})
+}
+
+// Call a method of the service
+func (c *Client) invoke(ctx context.Context, method string, req message) (matrix.Matrix, error) {
+	reply := &MatrixReply{}
+	err := c.conn.Invoke(ctx, fmt.Sprintf("/%s/%s", ServiceName, method), req, reply, grpc.ForceCodec(Codec{}))
+	if err != nil {
+		return nil, err
+	}
+	return reply.Result, nil
+}
@@ -0,0 +1,119 @@
+package testmat
+
+import (
+	"fmt"
+	"github.com/jesand/numgo/matrix"
+	"math"
+	"math/rand"
+)
+
+// Create a random n x n linear system A x = b with a known solution, for
+// checking the accuracy of solvers. A has 2-norm condition number cond, with
+// singular values spaced logarithmically from 1 down to 1 / cond, and x has
+// standard normal elements.
+//
+// If sparsity is zero, A is dense: the product of random orthogonal matrices
+// with the diagonal matrix of singular values. Otherwise, about that fraction
+// of A's elements are zero, and A is a sparse csr matrix: it is created by
+// applying random Givens rotations to either side of the diagonal matrix
+// until it is dense enough, which doesn't change its singular values. A has
+// at least n nonzero elements, however large sparsity is.
+//
+// Random values are taken from rng, or from the global source if rng is nil.
+// Seed it to create reproducible systems.
+func MakeSystem(n int, cond, sparsity float64, rng *rand.Rand) (A, x, b matrix.Matrix) {
+	checkOrder("system", n)
+	if cond < 1 {
+		panic(fmt.Sprintf("Can't create a system with condition number %g", cond))
+	}
+	if sparsity < 0 || sparsity >= 1 {
+		panic(fmt.Sprintf("Can't create a system with sparsity %g", sparsity))
+	}
+	if rng == nil {
+		rng = rand.New(rand.NewSource(rand.Int63()))
+	}
+
+	s := make([]float64, n)
+	for i := range s {
+		s[i] = 1
+		if n > 1 {
+			s[i] = math.Pow(cond, -float64(i)/float64(n-1))
+		}
+	}
+	if sparsity == 0 {
+		U, V := randOrthogonal(n, rng), randOrthogonal(n, rng)
+		A = U.MProd(matrix.Diag(s...), V.T())
+	} else {
+		A = rotatedDiag(s, int(math.Ceil((1-sparsity)*float64(n*n))), rng)
+	}
+
+	x = matrix.Dense(n, 1).M()
+	for i := 0; i < n; i++ {
+		x.ItemSet(rng.NormFloat64(), i, 0)
+	}
+	return A, x, A.MProd(x)
+}
+
+// Create a random n x n orthogonal matrix, as the Q factor of a matrix with
+// standard normal elements
+func randOrthogonal(n int, rng *rand.Rand) matrix.Matrix {
+	m := matrix.Dense(n, n).M()
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			m.ItemSet(rng.NormFloat64(), i, j)
+		}
+	}
+	return matrix.QR(m).Q()
+}
+
+// Create a sparse csr matrix with singular values s by applying random
+// Givens rotations to diag(s) until it has at least nnz nonzero elements.
+// Rotating rows (or columns) i and j fills each with the union of their
+// nonzero columns (or rows), so the matrix becomes denser with each step.
+func rotatedDiag(s []float64, nnz int, rng *rand.Rand) matrix.Matrix {
+	n := len(s)
+	m := make([][]float64, n)
+	for i := range m {
+		m[i] = make([]float64, n)
+		m[i][i] = s[i]
+	}
+	count := n
+	for step := 0; count < nnz && n > 1 && step < n*n*n; step++ {
+		i := rng.Intn(n)
+		j := rng.Intn(n - 1)
+		if j >= i {
+			j++
+		}
+		theta := 2 * math.Pi * rng.Float64()
+		c, sn := math.Cos(theta), math.Sin(theta)
+		for k := 0; k < n; k++ {
+			var a, b *float64
+			if step%2 == 0 {
+				a, b = &m[i][k], &m[j][k]
+			} else {
+				a, b = &m[k][i], &m[k][j]
+			}
+			va, vb := *a, *b
+			*a, *b = c*va-sn*vb, sn*va+c*vb
+			count += nonzero(*a) + nonzero(*b) - nonzero(va) - nonzero(vb)
+		}
+	}
+
+	result := matrix.SparseCoo(n, n)
+	for i, row := range m {
+		for j, v := range row {
+			if v != 0 {
+				result.ItemSet(v, i, j)
+			}
+		}
+	}
+	return result.SparseCSR()
+}
+
+// Get 1 if v is nonzero, and 0 otherwise
+func nonzero(v float64) int {
+	if v != 0 {
+		return 1
+	}
+	return 0
+}
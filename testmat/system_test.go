@@ -0,0 +1,59 @@
+package testmat
+
+import (
+	"github.com/jesand/numgo/matrix"
+	. "github.com/smartystreets/goconvey/convey"
+	"math/rand"
+	"testing"
+)
+
+func TestMakeSystem(t *testing.T) {
+	Convey("Given a dense random system", t, func() {
+		A, x, b := MakeSystem(20, 1e4, 0, rand.New(rand.NewSource(1)))
+
+		Convey("b = A x and A has the requested condition number", func() {
+			So(A.Sparsity(), ShouldEqual, matrix.DenseArray)
+			So(x.Shape(), ShouldResemble, []int{20, 1})
+			So(matrix.ApproxEqual(A.MProd(x), b, 1e-12, 1e-12), ShouldBeTrue)
+			So(A.Norm(2)*mustInverse(A).Norm(2), ShouldAlmostEqual, 1e4, 1e-4)
+		})
+
+		Convey("Solvers recover x", func() {
+			So(matrix.ApproxEqual(matrix.LDivide(A, b), x, 1e-8, 1e-8), ShouldBeTrue)
+		})
+	})
+
+	Convey("Given a sparse random system", t, func() {
+		A, x, b := MakeSystem(30, 100, 0.8, rand.New(rand.NewSource(2)))
+
+		Convey("A is sparse with the requested condition number", func() {
+			So(A.Sparsity(), ShouldEqual, matrix.SparseCSRMatrix)
+			So(A.CountNonzero(), ShouldBeGreaterThanOrEqualTo, 180)
+			So(A.CountNonzero(), ShouldBeLessThan, 900)
+			So(A.Norm(2)*mustInverse(A).Norm(2), ShouldAlmostEqual, 100, 1e-8)
+			So(matrix.ApproxEqual(A.MProd(x), b, 1e-12, 1e-12), ShouldBeTrue)
+		})
+	})
+
+	Convey("Seeded systems are reproducible", t, func() {
+		A1, x1, _ := MakeSystem(5, 10, 0.5, rand.New(rand.NewSource(3)))
+		A2, x2, _ := MakeSystem(5, 10, 0.5, rand.New(rand.NewSource(3)))
+		So(A1.Equal(A2), ShouldBeTrue)
+		So(x1.Equal(x2), ShouldBeTrue)
+		So(func() { MakeSystem(1, 1, 0.99, nil) }, ShouldNotPanic)
+	})
+
+	Convey("Invalid arguments panic", t, func() {
+		So(func() { MakeSystem(0, 1, 0, nil) }, ShouldPanic)
+		So(func() { MakeSystem(3, 0.5, 0, nil) }, ShouldPanic)
+		So(func() { MakeSystem(3, 10, 1, nil) }, ShouldPanic)
+	})
+}
+
+func mustInverse(m matrix.Matrix) matrix.Matrix {
+	inv, err := m.Inverse()
+	if err != nil {
+		panic(err)
+	}
+	return inv
+}
@@ -0,0 +1,182 @@
+// The testmat package generates classic test matrices with known properties,
+// for benchmarking and for checking the accuracy of solvers. The Hilbert
+// matrices are notoriously ill-conditioned and have exact integer inverses,
+// the Pascal and Lehmer matrices are symmetric positive definite, magic
+// squares have equal row, column and diagonal sums, and the Wilkinson
+// matrices have pairs of nearly equal eigenvalues. MakeSystem() creates random
+// linear systems with a known solution and condition number.
+package testmat
+
+import (
+	"fmt"
+	"github.com/jesand/numgo/matrix"
+	"math"
+)
+
+// Panic unless n is a valid matrix order
+func checkOrder(name string, n int) {
+	if n < 1 {
+		panic(fmt.Sprintf("Can't create a %s matrix of order %d", name, n))
+	}
+}
+
+// Create a dense n x n matrix whose elements are f(row, col)
+func square(n int, f func(row, col int) float64) matrix.Matrix {
+	result := matrix.Dense(n, n).M()
+	for row := 0; row < n; row++ {
+		for col := 0; col < n; col++ {
+			result.ItemSet(f(row, col), row, col)
+		}
+	}
+	return result
+}
+
+// Get the binomial coefficient n choose k, as a float64
+func choose(n, k int) float64 {
+	if k < 0 || k > n {
+		return 0
+	}
+	result := 1.0
+	for i := 1; i <= k; i++ {
+		result = result * float64(n-k+i) / float64(i)
+	}
+	return math.Round(result)
+}
+
+// Create the n x n Hilbert matrix, with H[i, j] = 1 / (i + j + 1) for
+// zero-based indices. Its condition number grows exponentially with n, so
+// solvers lose about 1.5n digits of accuracy on it.
+func Hilbert(n int) matrix.Matrix {
+	checkOrder("Hilbert", n)
+	return square(n, func(row, col int) float64 {
+		return 1 / float64(row+col+1)
+	})
+}
+
+// Create the exact inverse of the n x n Hilbert matrix, which has integer
+// elements. The elements overflow the precision of a float64 for n > 13.
+func InvHilbert(n int) matrix.Matrix {
+	checkOrder("inverse Hilbert", n)
+	return square(n, func(row, col int) float64 {
+		i, j := row+1, col+1
+		v := float64(i+j-1) * choose(n+i-1, n-j) * choose(n+j-1, n-i) * math.Pow(choose(i+j-2, i-1), 2)
+		if (i+j)%2 == 1 {
+			return -v
+		}
+		return v
+	})
+}
+
+// Create the n x n symmetric Pascal matrix, with P[i, j] = (i+j choose i) for
+// zero-based indices. It is positive definite with determinant 1, and its
+// inverse has integer elements.
+func Pascal(n int) matrix.Matrix {
+	checkOrder("Pascal", n)
+	return square(n, func(row, col int) float64 {
+		return choose(row+col, row)
+	})
+}
+
+// Create the n x n Lehmer matrix, with A[i, j] = min(i, j) / max(i, j) for
+// one-based indices. It is symmetric positive definite, and its inverse is
+// tridiagonal.
+func Lehmer(n int) matrix.Matrix {
+	checkOrder("Lehmer", n)
+	return square(n, func(row, col int) float64 {
+		return float64(min(row, col)+1) / float64(max(row, col)+1)
+	})
+}
+
+// Create an n x n magic square, which contains the integers 1 to n^2 arranged
+// so that every row, every column and both diagonals sum to n(n^2+1)/2. The
+// squares match those of MATLAB's magic(). There is no magic square of order
+// 2.
+func Magic(n int) matrix.Matrix {
+	checkOrder("magic", n)
+	if n == 2 {
+		panic("There is no magic square of order 2")
+	}
+	values := magic(n)
+	result := matrix.Dense(n, n).M()
+	for row := range values {
+		result.RowSet(row, values[row])
+	}
+	return result
+}
+
+// Compute a magic square of order n, for n != 2
+func magic(n int) [][]float64 {
+	m := make([][]float64, n)
+	for i := range m {
+		m[i] = make([]float64, n)
+	}
+	switch {
+	case n%2 == 1:
+		// The siamese method, with the sequence starting in the middle row
+		for i := 1; i <= n; i++ {
+			for j := 1; j <= n; j++ {
+				a := ((i+j-(n+3)/2)%n + n) % n
+				b := ((i+2*j-2)%n + n) % n
+				m[i-1][j-1] = float64(n*a + b + 1)
+			}
+		}
+	case n%4 == 0:
+		// Number the cells in order, and reverse the numbering of the cells
+		// whose row and column are both, or both not, 2 or 3 mod 4
+		inner := func(k int) bool { return k%4 > 1 }
+		for i := 1; i <= n; i++ {
+			for j := 1; j <= n; j++ {
+				v := (i-1)*n + j
+				if inner(i) == inner(j) {
+					v = n*n + 1 - v
+				}
+				m[i-1][j-1] = float64(v)
+			}
+		}
+	default:
+		// Combine four magic squares of order p = n/2 and exchange some of
+		// the cells in the left columns between the top and bottom halves
+		p := n / 2
+		sub := magic(p)
+		offsets := [2][2]int{{0, 2 * p * p}, {3 * p * p, p * p}}
+		for i := 0; i < n; i++ {
+			for j := 0; j < n; j++ {
+				m[i][j] = sub[i%p][j%p] + float64(offsets[i/p][j/p])
+			}
+		}
+		k := (n - 2) / 4
+		var cols []int
+		for j := 0; j < k; j++ {
+			cols = append(cols, j)
+		}
+		for j := n - k + 1; j < n; j++ {
+			cols = append(cols, j)
+		}
+		for i := 0; i < p; i++ {
+			for _, j := range cols {
+				m[i][j], m[i+p][j] = m[i+p][j], m[i][j]
+			}
+		}
+		for _, j := range []int{0, k} {
+			m[k][j], m[k+p][j] = m[k+p][j], m[k][j]
+		}
+	}
+	return m
+}
+
+// Create the n x n Wilkinson matrix W+, a symmetric tridiagonal matrix with
+// ones off the diagonal and |(n-1)/2 - i| on the diagonal. Its largest
+// eigenvalues come in nearly equal pairs, which challenges eigenvalue
+// solvers. The result is a sparse coo matrix.
+func Wilkinson(n int) matrix.Matrix {
+	checkOrder("Wilkinson", n)
+	result := matrix.SparseCoo(n, n)
+	for i := 0; i < n; i++ {
+		result.ItemSet(math.Abs(float64(n-1)/2-float64(i)), i, i)
+		if i+1 < n {
+			result.ItemSet(1, i, i+1)
+			result.ItemSet(1, i+1, i)
+		}
+	}
+	return result
+}
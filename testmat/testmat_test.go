@@ -0,0 +1,107 @@
+package testmat
+
+import (
+	"github.com/jesand/numgo/matrix"
+	. "github.com/smartystreets/goconvey/convey"
+	"testing"
+)
+
+func TestHilbert(t *testing.T) {
+	Convey("Hilbert matrices have the expected elements", t, func() {
+		So(Hilbert(3).Equal(matrix.M(3, 3,
+			1, 1.0/2, 1.0/3,
+			1.0/2, 1.0/3, 1.0/4,
+			1.0/3, 1.0/4, 1.0/5)), ShouldBeTrue)
+		So(func() { Hilbert(0) }, ShouldPanic)
+	})
+
+	Convey("InvHilbert is the inverse of Hilbert", t, func() {
+		So(InvHilbert(3).Equal(matrix.M(3, 3,
+			9, -36, 30,
+			-36, 192, -180,
+			30, -180, 180)), ShouldBeTrue)
+		for _, n := range []int{1, 2, 5, 8} {
+			So(matrix.ApproxEqual(Hilbert(n).MProd(InvHilbert(n)), matrix.Eye(n), 1e-6, 0), ShouldBeTrue)
+		}
+	})
+}
+
+func TestPascal(t *testing.T) {
+	Convey("Pascal matrices hold binomial coefficients", t, func() {
+		So(Pascal(4).Equal(matrix.M(4, 4,
+			1, 1, 1, 1,
+			1, 2, 3, 4,
+			1, 3, 6, 10,
+			1, 4, 10, 20)), ShouldBeTrue)
+	})
+}
+
+func TestLehmer(t *testing.T) {
+	Convey("Lehmer matrices are min(i, j) / max(i, j)", t, func() {
+		So(Lehmer(3).Equal(matrix.M(3, 3,
+			1, 1.0/2, 1.0/3,
+			1.0/2, 1, 2.0/3,
+			1.0/3, 2.0/3, 1)), ShouldBeTrue)
+	})
+}
+
+func TestMagic(t *testing.T) {
+	Convey("Magic squares match MATLAB", t, func() {
+		So(Magic(3).Equal(matrix.M(3, 3,
+			8, 1, 6,
+			3, 5, 7,
+			4, 9, 2)), ShouldBeTrue)
+		So(Magic(4).Equal(matrix.M(4, 4,
+			16, 2, 3, 13,
+			5, 11, 10, 8,
+			9, 7, 6, 12,
+			4, 14, 15, 1)), ShouldBeTrue)
+		So(Magic(6).Equal(matrix.M(6, 6,
+			35, 1, 6, 26, 19, 24,
+			3, 32, 7, 21, 23, 25,
+			31, 9, 2, 22, 27, 20,
+			8, 28, 33, 17, 10, 15,
+			30, 5, 34, 12, 14, 16,
+			4, 36, 29, 13, 18, 11)), ShouldBeTrue)
+		So(func() { Magic(2) }, ShouldPanic)
+	})
+
+	Convey("Magic squares of every kind have equal sums", t, func() {
+		for _, n := range []int{1, 5, 6, 7, 8, 10, 12, 14} {
+			m := Magic(n)
+			want := float64(n*(n*n+1)) / 2
+			seen := make(map[float64]bool)
+			for i := 0; i < n; i++ {
+				var rowSum, colSum float64
+				for j := 0; j < n; j++ {
+					rowSum += m.Item(i, j)
+					colSum += m.Item(j, i)
+					seen[m.Item(i, j)] = true
+				}
+				So(rowSum, ShouldEqual, want)
+				So(colSum, ShouldEqual, want)
+			}
+			So(m.Trace(), ShouldEqual, want)
+			var anti float64
+			for i := 0; i < n; i++ {
+				anti += m.Item(i, n-1-i)
+			}
+			So(anti, ShouldEqual, want)
+			So(len(seen), ShouldEqual, n*n)
+		}
+	})
+}
+
+func TestWilkinson(t *testing.T) {
+	Convey("Wilkinson matrices are symmetric and tridiagonal", t, func() {
+		m := Wilkinson(5)
+		So(m.Sparsity(), ShouldEqual, matrix.SparseCooMatrix)
+		So(m.Equal(matrix.M(5, 5,
+			2, 1, 0, 0, 0,
+			1, 1, 1, 0, 0,
+			0, 1, 0, 1, 0,
+			0, 0, 1, 1, 1,
+			0, 0, 0, 1, 2)), ShouldBeTrue)
+		So(Wilkinson(4).Diag().Array(), ShouldResemble, []float64{1.5, 0.5, 0.5, 1.5})
+	})
+}